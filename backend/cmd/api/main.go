@@ -1,23 +1,39 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 	"publicscannerapi/internal/api/handlers"
 	"publicscannerapi/internal/api/middleware"
+	"publicscannerapi/internal/buildinfo"
 	"publicscannerapi/internal/config"
+	"publicscannerapi/internal/encryption"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/mailer"
+	"publicscannerapi/internal/metrics"
+	"publicscannerapi/internal/models"
 	"publicscannerapi/internal/repository"
 	"publicscannerapi/internal/services"
+	"publicscannerapi/internal/storage"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	logging.Setup(os.Stdout, cfg.App.LogLevel, cfg.App.LogFormat)
 
 	// Initialize database connection
 	db, err := initDatabase(cfg)
@@ -26,39 +42,183 @@ func main() {
 	}
 	defer db.Close()
 
-	log.Println("✅ Database connected successfully")
+	slog.Info("Database connected")
+
+	// Subcommands only need the database and exit without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "seed-admin" {
+		if err := runSeedAdmin(db, os.Args[2:]); err != nil {
+			log.Fatalf("seed-admin: %v", err)
+		}
+		return
+	}
+
+	// Initialize Redis client
+	rdb, err := initRedis(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure Redis: %v", err)
+	}
+	defer rdb.Close()
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	emailVerificationRepo := repository.NewEmailVerificationRepository(db)
 	targetRepo := repository.NewTargetRepository(db)
 	scanRepo := repository.NewScanRepository(db)
 	reportRepo := repository.NewReportRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	noteRepo := repository.NewNoteRepository(db)
+	certRepo := repository.NewCertificateRepository(db)
+	wordlistRepo := repository.NewWordlistRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	scheduleRepo := repository.NewScheduleRepository(db)
+	shareRepo := repository.NewShareRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	serviceAccountRepo := repository.NewServiceAccountRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	overrideRepo := repository.NewSeverityOverrideRepository(db)
+	ackRepo := repository.NewAcknowledgementRepository(db)
+
+	// Initialize file storage
+	fileStorage := storage.NewLocalStorage(cfg.App.StoragePath)
+
+	// Initialize cipher for secrets stored at rest
+	cipher, err := encryption.NewCipher(cfg.App.EncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to configure encryption: %v", err)
+	}
+
+	mail := mailer.New(mailer.SMTPConfig{
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUsername,
+		Password: cfg.Mail.SMTPPassword,
+		From:     cfg.Mail.From,
+	})
 
 	// Initialize services
+	emailVerificationService := services.NewEmailVerificationService(
+		userRepo,
+		emailVerificationRepo,
+		mail,
+		cfg.App.FrontendURL+"/verify-email",
+		cfg.App.EmailVerificationTTL,
+		cfg.App.RequireEmailVerification,
+	)
+	sessionTracker := services.NewSessionTracker(rdb, orgRepo)
+	tokenDenylist := services.NewTokenDenylist(rdb)
 	authService := services.NewAuthService(
 		userRepo,
+		refreshTokenRepo,
+		sessionTracker,
+		tokenDenylist,
+		services.NewLoginLockout(userRepo, rdb, services.LockoutPolicy{
+			MaxAttempts: cfg.RateLimit.LockoutAttempts,
+			Cooldown:    cfg.RateLimit.LockoutDuration,
+		}),
+		emailVerificationService,
 		cfg.JWT.Secret,
 		cfg.JWT.AccessTokenTTL,
 		cfg.JWT.RefreshTokenTTL,
 	)
-	targetService := services.NewTargetService(targetRepo)
-	scanService := services.NewScanService(scanRepo, targetRepo, cfg.Redis.URL())
-	reportService := services.NewReportService(reportRepo, scanRepo, cfg.App.StoragePath)
+	passwordResetService := services.NewPasswordResetService(
+		userRepo,
+		passwordResetRepo,
+		mail,
+		cfg.App.FrontendURL+"/reset-password",
+		cfg.App.PasswordResetTTL,
+	)
+	addressPolicy := services.AddressPolicy{AllowPrivate: cfg.Target.AllowPrivate}
+	targetService := services.NewTargetService(targetRepo, services.TagPolicy{
+		MaxTags:   cfg.Target.MaxTags,
+		MaxLength: cfg.Target.MaxTagLength,
+	}, addressPolicy)
+	certService := services.NewCertificateService(certRepo, cipher)
+	webhookService := services.NewWebhookService(webhookRepo, orgRepo, addressPolicy, cfg.Webhook.ProgressMilestones, cfg.Webhook.MaxAttempts, cfg.Webhook.RetryDelay)
+	scanService := services.NewScanService(scanRepo, targetRepo, certService, overrideRepo, wordlistRepo, campaignRepo, webhookService, rdb, addressPolicy, cfg.Worker.Secret, cfg.Retention.DeletedScans)
+	shareService := services.NewShareService(shareRepo, scanRepo, scanService, cfg.JWT.Secret)
+	orgService := services.NewOrganizationService(orgRepo, userRepo, invitationRepo, sessionTracker, models.PlanLimits{
+		MaxTargets:       cfg.Plan.MaxTargets,
+		MaxScansPerMonth: cfg.Plan.MaxScansPerMonth,
+		MaxStorageBytes:  int64(cfg.Plan.MaxStorageMB) * 1024 * 1024,
+		MaxMembers:       cfg.Plan.MaxMembers,
+		MaxRequests:      cfg.Plan.MaxRequestsPerMonth,
+	})
+	serviceAccountService := services.NewServiceAccountService(orgService, serviceAccountRepo)
+	overrideService := services.NewSeverityOverrideService(orgService, overrideRepo)
+	ackService := services.NewAcknowledgementService(orgService, ackRepo)
+	brandingService := services.NewBrandingService(orgService, orgRepo, fileStorage)
+	reportService := services.NewReportService(reportRepo, scanRepo, brandingService, cfg.App.StoragePath)
+	attachmentService := services.NewAttachmentService(attachmentRepo, scanRepo, fileStorage, cfg.App.AttachmentMaxSize)
+	noteService := services.NewNoteService(noteRepo, scanRepo)
+	wordlistService := services.NewWordlistService(wordlistRepo, fileStorage, cfg.App.WordlistMaxSize)
+	campaignService := services.NewCampaignService(campaignRepo, scanService)
+	scheduleService := services.NewScheduleService(scheduleRepo, targetRepo, scanService)
+	evidenceService := services.NewEvidenceService(scanService, reportService, attachmentRepo, fileStorage)
+
+	// Start background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scanPurger := services.NewScanPurger(scanRepo, fileStorage, cfg.Retention.DeletedScans, cfg.Database.MaintenanceStatementTimeout)
+	go scanPurger.Run(ctx, cfg.Retention.PurgeInterval)
+
+	reportSweeper := services.NewReportSweeper(reportRepo, cfg.App.StoragePath)
+	go reportSweeper.Run(ctx, cfg.Retention.ReportSweepInterval)
+
+	scanScheduler := services.NewScanScheduler(scanService, scheduleService)
+	go scanScheduler.Run(ctx, cfg.Schedule.Interval)
+
+	scanReaper := services.NewScanReaper(scanRepo, webhookService, cfg.Schedule.StaleTimeout, time.Now)
+	go scanReaper.Run(ctx, cfg.Schedule.ReaperInterval)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
+	passwordResetHandler := handlers.NewPasswordResetHandler(passwordResetService)
+	emailVerificationHandler := handlers.NewEmailVerificationHandler(emailVerificationService)
 	targetHandler := handlers.NewTargetHandler(targetService)
 	scanHandler := handlers.NewScanHandler(scanService)
 	reportHandler := handlers.NewReportHandler(reportService)
+	orgHandler := handlers.NewOrganizationHandler(orgService)
+	brandingHandler := handlers.NewBrandingHandler(brandingService)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(serviceAccountService)
+	overrideHandler := handlers.NewSeverityOverrideHandler(overrideService)
+	ackHandler := handlers.NewAcknowledgementHandler(ackService)
+	dashboardHandler := handlers.NewDashboardHandler(scanService)
+	searchHandler := handlers.NewSearchHandler(scanService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	systemHandler := handlers.NewSystemHandler(cfg, services.NewQueueMonitor(rdb, scanRepo))
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService)
+	noteHandler := handlers.NewNoteHandler(noteService)
+	certHandler := handlers.NewCertificateHandler(certService)
+	wordlistHandler := handlers.NewWordlistHandler(wordlistService, scanService)
+	campaignHandler := handlers.NewCampaignHandler(campaignService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	evidenceHandler := handlers.NewEvidenceHandler(evidenceService)
+	adminHandler := handlers.NewAdminHandler(orgService)
+	checkHandler, err := handlers.NewCheckHandler()
+	if err != nil {
+		log.Fatalf("Failed to build checks catalog: %v", err)
+	}
+	shareHandler := handlers.NewShareHandler(shareService)
+	healthHandler := handlers.NewHealthHandler(db, rdb)
 
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	router.Use(middleware.RequestLogger(), middleware.Metrics(), gin.Recovery())
 
 	// CORS middleware (allow frontend to make requests)
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+middleware.RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", middleware.RequestIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -68,87 +228,313 @@ func main() {
 		c.Next()
 	})
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "PublicScanner API",
-		})
+	// Health check endpoints: /health and /health/ready check the database
+	// and Redis, /health/live only that the process answers
+	router.GET("/health", healthHandler.Ready)
+	router.GET("/health/ready", healthHandler.Ready)
+	router.GET("/health/live", healthHandler.Live)
+
+	// Prometheus metrics endpoint
+	metrics.RegisterRunningScans(func() (int, error) {
+		counts, err := scanRepo.CountActive()
+		if err != nil {
+			return 0, err
+		}
+		return counts.Running, nil
 	})
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Build metadata endpoint
+	router.GET("/version", func(c *gin.Context) {
+		c.JSON(200, buildinfo.Get())
+	})
+
+	// Downloads and exports get a longer response budget than the default
+	exportTimeout := middleware.Timeout(cfg.Server.ExportTimeout)
+	streamTimeout := middleware.Timeout(cfg.Server.StreamTimeout)
+
+	// Viewers are read-only on organization data, members may change it
+	// and only admins and owners may delete it
+	requireMember := middleware.RequireRole(userRepo, models.RoleMember)
+	requireAdmin := middleware.RequireRole(userRepo, models.RoleAdmin)
+
+	// Brute-force limits on the unauthenticated auth routes
+	loginKeys := []middleware.RateLimitKeyFunc{middleware.ClientIPKey}
+	if cfg.RateLimit.AuthByEmail {
+		loginKeys = append(loginKeys, middleware.JSONFieldKey("email"))
+	}
+	loginRateLimit := middleware.RateLimit(rdb, "login", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, loginKeys...)
+	registerRateLimit := middleware.RateLimit(rdb, "register", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, middleware.ClientIPKey)
+	forgotPasswordRateLimit := middleware.RateLimit(rdb, "forgot_password", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, loginKeys...)
+	resetPasswordRateLimit := middleware.RateLimit(rdb, "reset_password", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, middleware.ClientIPKey)
+	resendVerificationRateLimit := middleware.RateLimit(rdb, "resend_verification", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, loginKeys...)
+	verifyEmailRateLimit := middleware.RateLimit(rdb, "verify_email", cfg.RateLimit.AuthLimit, cfg.RateLimit.AuthWindow, middleware.ClientIPKey)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Public auth routes
 		auth := v1.Group("/auth")
+		auth.Use(middleware.Timeout(cfg.Server.AuthTimeout))
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
+			auth.POST("/register", registerRateLimit, authHandler.Register)
+			auth.POST("/login", loginRateLimit, authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/forgot-password", forgotPasswordRateLimit, passwordResetHandler.ForgotPassword)
+			auth.POST("/reset-password", resetPasswordRateLimit, passwordResetHandler.ResetPassword)
+			auth.GET("/verify", verifyEmailRateLimit, emailVerificationHandler.Verify)
+			auth.POST("/resend-verification", resendVerificationRateLimit, emailVerificationHandler.ResendVerification)
+			auth.GET("/validate", middleware.AuthMiddleware(cfg.JWT.Secret, serviceAccountService, tokenDenylist), middleware.SessionIdleMiddleware(sessionTracker), authHandler.Validate)
+			auth.POST("/logout", middleware.AuthMiddleware(cfg.JWT.Secret, serviceAccountService, tokenDenylist), authHandler.Logout)
+			auth.POST("/switch-org", middleware.AuthMiddleware(cfg.JWT.Secret, serviceAccountService, tokenDenylist), middleware.SessionIdleMiddleware(sessionTracker), authHandler.SwitchOrganization)
+		}
+
+		// Public read-only scan share links
+		v1.GET("/shared/scans/:token", shareHandler.View)
+
+		// Internal routes for scan workers, authenticated per scan
+		internal := v1.Group("/internal")
+		{
+			internal.GET("/scans/:id", middleware.WorkerAuthMiddleware(cfg.Worker.Secret), scanHandler.WorkerScan)
+			internal.GET("/scans/:id/client-certificate", middleware.WorkerAuthMiddleware(cfg.Worker.Secret), scanHandler.WorkerClientCertificate)
+			internal.GET("/scans/:id/wordlist", middleware.WorkerAuthMiddleware(cfg.Worker.Secret), wordlistHandler.WorkerDownload)
+			internal.POST("/scans/:id/results", middleware.WorkerAuthMiddleware(cfg.Worker.Secret), scanHandler.IngestResults)
 		}
 
 		// Protected routes (require authentication)
 		protected := v1.Group("/")
-		protected.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+		protected.Use(middleware.AuthMiddleware(cfg.JWT.Secret, serviceAccountService, tokenDenylist))
+		protected.Use(middleware.SessionIdleMiddleware(sessionTracker))
+		protected.Use(middleware.AuditMiddleware(auditRepo))
+		protected.Use(middleware.QuotaMiddleware(rdb, cfg.Plan.MaxRequestsPerMonth))
 		{
+			// Checks catalog
+			protected.GET("/checks", checkHandler.Catalog)
+
 			// User routes
 			users := protected.Group("/users")
 			{
 				users.GET("/me", authHandler.GetCurrentUser)
+				users.GET("/me/permissions", orgHandler.MyPermissions)
+				users.POST("/me/password", authHandler.ChangePassword)
 			}
 
 			// Target routes
 			targets := protected.Group("/targets")
+			targets.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
 			{
 				targets.GET("", targetHandler.List)
-				targets.POST("", targetHandler.Create)
+				targets.POST("", requireMember, targetHandler.Create)
+				targets.POST("/batch", requireMember, targetHandler.CreateBatch)
+				targets.POST("/apply", requireMember, targetHandler.Apply)
 				targets.GET("/:id", targetHandler.Get)
-				targets.PATCH("/:id", targetHandler.Update)
-				targets.DELETE("/:id", targetHandler.Delete)
+				targets.GET("/:id/export", targetHandler.Export)
+				targets.GET("/:id/history", targetHandler.History)
+				targets.PATCH("/:id", requireMember, targetHandler.Update)
+				targets.DELETE("/:id", requireAdmin, targetHandler.Delete)
+				targets.POST("/:id/restore", requireAdmin, targetHandler.Restore)
 			}
 
 			// Scan routes
 			scans := protected.Group("/scans")
+			scans.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
 			{
 				scans.GET("", scanHandler.List)
-				scans.POST("", scanHandler.Create)
+				scans.POST("", requireMember, scanHandler.Create)
+				scans.POST("/validate", scanHandler.Validate)
+				scans.POST("/by-tag", requireMember, scanHandler.CreateByTag)
+				scans.POST("/bulk", requireMember, scanHandler.CreateBulk)
+				scans.GET("/by-tag/preview", scanHandler.PreviewByTag)
+				scans.POST("/requeue", requireAdmin, scanHandler.Requeue)
 				scans.GET("/:id", scanHandler.Get)
+				scans.GET("/:id/stream", streamTimeout, scanHandler.Stream)
+				scans.PATCH("/:id", requireMember, scanHandler.Update)
 				scans.GET("/:id/results", scanHandler.GetResults)
-				scans.POST("/:id/cancel", scanHandler.Cancel)
+				scans.GET("/:id/timeline", scanHandler.Timeline)
+				scans.GET("/:id/config-diff", scanHandler.ConfigDiff)
+				scans.GET("/:id/diff", scanHandler.Diff)
+				scans.GET("/:id/reports", reportHandler.ListByScan)
+				scans.GET("/:id/reports/download-all", exportTimeout, reportHandler.DownloadAll)
+				scans.GET("/:id/evidence", exportTimeout, evidenceHandler.Download)
+				scans.POST("/:id/share", requireMember, shareHandler.Create)
+				scans.GET("/:id/shares", shareHandler.List)
+				scans.DELETE("/:id/shares/:shareId", requireAdmin, shareHandler.Revoke)
+				scans.GET("/:id/results/:resultId/attachments", attachmentHandler.List)
+				scans.POST("/:id/results/:resultId/attachments", requireMember, attachmentHandler.Upload)
+				scans.GET("/:id/results/:resultId/attachments/:attachmentId/download", exportTimeout, attachmentHandler.Download)
+				scans.GET("/:id/results/:resultId/export", exportTimeout, reportHandler.ExportCheckResults)
+				scans.GET("/:id/results/:resultId/notes", noteHandler.List)
+				scans.POST("/:id/results/:resultId/notes", requireMember, noteHandler.Create)
+				scans.POST("/:id/cancel", requireMember, scanHandler.Cancel)
+				scans.POST("/:id/resume", requireMember, scanHandler.Resume)
+				scans.DELETE("/:id", requireAdmin, scanHandler.Delete)
+				scans.POST("/:id/restore", requireAdmin, scanHandler.Restore)
 			}
 
 			// Report routes
 			reports := protected.Group("/reports")
+			reports.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
 			{
 				reports.GET("", reportHandler.List)
-				reports.POST("/generate", reportHandler.Generate)
+				reports.POST("/generate", requireMember, reportHandler.Generate)
 				reports.GET("/:id", reportHandler.Get)
-				reports.GET("/:id/download", reportHandler.Download)
-				reports.DELETE("/:id", reportHandler.Delete)
+				reports.GET("/:id/download", exportTimeout, reportHandler.Download)
+				reports.HEAD("/:id/download", reportHandler.DownloadHead)
+				reports.DELETE("/:id", requireAdmin, reportHandler.Delete)
+			}
+
+			// Dashboard routes
+			dashboard := protected.Group("/dashboard")
+			dashboard.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				dashboard.GET("/top-risks", dashboardHandler.TopRisks)
+				dashboard.GET("/failures", dashboardHandler.Failures)
+			}
+
+			// Search routes
+			search := protected.Group("/search")
+			search.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				search.GET("/results", searchHandler.Results)
+			}
+
+			// Webhook routes
+			webhooks := protected.Group("/webhooks")
+			webhooks.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				webhooks.GET("", webhookHandler.List)
+				webhooks.POST("", requireMember, webhookHandler.Create)
+				webhooks.GET("/:id", webhookHandler.Get)
+				webhooks.DELETE("/:id", requireAdmin, webhookHandler.Delete)
+				webhooks.GET("/:id/failures", webhookHandler.ListFailures)
+				webhooks.POST("/:id/failures/:failureId/retry", requireMember, webhookHandler.RetryFailure)
+			}
+
+			// Client certificate routes (mutual-TLS scans)
+			certificates := protected.Group("/certificates")
+			certificates.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				certificates.GET("", certHandler.List)
+				certificates.POST("", requireMember, certHandler.Upload)
+				certificates.GET("/:id", certHandler.Get)
+				certificates.DELETE("/:id", requireAdmin, certHandler.Delete)
+			}
+
+			// Bruteforce wordlist routes
+			wordlists := protected.Group("/wordlists")
+			wordlists.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				wordlists.GET("", wordlistHandler.List)
+				wordlists.POST("", requireMember, wordlistHandler.Upload)
+				wordlists.GET("/:id", wordlistHandler.Get)
+				wordlists.DELETE("/:id", requireAdmin, wordlistHandler.Delete)
+			}
+
+			// Recurring scheduled scan routes
+			schedules := protected.Group("/schedules")
+			schedules.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				schedules.GET("", scheduleHandler.List)
+				schedules.POST("", requireMember, scheduleHandler.Create)
+				schedules.GET("/:id", scheduleHandler.Get)
+				schedules.DELETE("/:id", requireAdmin, scheduleHandler.Delete)
+			}
+
+			// Campaign routes
+			campaigns := protected.Group("/campaigns")
+			campaigns.Use(middleware.RequireOrganization(), middleware.RequireDataAccess(userRepo))
+			{
+				campaigns.GET("", campaignHandler.List)
+				campaigns.POST("", requireMember, campaignHandler.Create)
+				campaigns.GET("/:id", campaignHandler.Get)
+				campaigns.GET("/:id/scans", campaignHandler.ListScans)
+				campaigns.GET("/:id/risk", campaignHandler.Risk)
+				campaigns.GET("/:id/targets", campaignHandler.ListTargets)
+				campaigns.POST("/:id/targets", requireMember, campaignHandler.AssignTargets)
+			}
+
+			// System routes (platform operators only)
+			system := protected.Group("/system")
+			system.Use(middleware.RequireSuperAdmin(userRepo))
+			{
+				system.GET("/config", systemHandler.Config)
+				system.GET("/queue", systemHandler.Queue)
+			}
+
+			// Cross-tenant administration (platform operators only)
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RequireSuperAdmin(userRepo))
+			{
+				admin.GET("/organizations", adminHandler.ListOrganizations)
+			}
+
+			// API keys for a service account of the token's organization,
+			// the same as the service account key route
+			protected.POST("/api-keys", middleware.RequireOrganization(), serviceAccountHandler.IssueKey)
+
+			// Organization routes
+			organizations := protected.Group("/organizations")
+			{
+				organizations.GET("", orgHandler.List)
+				organizations.POST("", orgHandler.Create)
+				organizations.GET("/:id/usage", orgHandler.Usage)
+				organizations.GET("/:id/members", orgHandler.ListMembers)
+				organizations.POST("/:id/members", orgHandler.AddMember)
+				organizations.PATCH("/:id/members/:userId", orgHandler.UpdateMemberRole)
+				organizations.GET("/:id/notifications", orgHandler.GetNotifications)
+				organizations.PATCH("/:id/notifications", orgHandler.UpdateNotifications)
+				organizations.GET("/:id/session-policy", orgHandler.GetSessionPolicy)
+				organizations.PUT("/:id/session-policy", orgHandler.UpdateSessionPolicy)
+				organizations.GET("/:id/branding", brandingHandler.Get)
+				organizations.PATCH("/:id/branding", brandingHandler.Update)
+				organizations.GET("/:id/branding/logo", brandingHandler.GetLogo)
+				organizations.PUT("/:id/branding/logo", brandingHandler.UploadLogo)
+				organizations.DELETE("/:id/branding/logo", brandingHandler.DeleteLogo)
+				organizations.POST("/:id/merge", orgHandler.Merge)
+				organizations.GET("/:id/severity-overrides", overrideHandler.List)
+				organizations.POST("/:id/severity-overrides", overrideHandler.Create)
+				organizations.DELETE("/:id/severity-overrides/:overrideId", overrideHandler.Delete)
+				organizations.GET("/:id/acknowledgements", ackHandler.List)
+				organizations.POST("/:id/acknowledgements", ackHandler.Create)
+				organizations.DELETE("/:id/acknowledgements/:ruleId", ackHandler.Delete)
+				organizations.GET("/:id/service-accounts", serviceAccountHandler.List)
+				organizations.POST("/:id/service-accounts", serviceAccountHandler.Create)
+				organizations.DELETE("/:id/service-accounts/:accountId", serviceAccountHandler.Deactivate)
+				organizations.GET("/:id/service-accounts/:accountId/keys", serviceAccountHandler.ListKeys)
+				organizations.POST("/:id/service-accounts/:accountId/keys", serviceAccountHandler.CreateKey)
+				organizations.DELETE("/:id/service-accounts/:accountId/keys/:keyId", serviceAccountHandler.RevokeKey)
+				organizations.GET("/:id/invitations", orgHandler.ListInvitations)
+				organizations.POST("/:id/invitations", orgHandler.CreateInvitation)
+				organizations.DELETE("/:id/invitations/:invitationId", orgHandler.RevokeInvitation)
+			}
+
+			// Invitation routes
+			invitations := protected.Group("/invitations")
+			{
+				invitations.POST("/accept", orgHandler.AcceptInvitation)
 			}
 		}
 	}
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
-	log.Printf("🚀 Server starting on %s", addr)
-	if err := router.Run(addr); err != nil {
+	slog.Info("Server starting", "addr", addr, "version", cfg.App.Version)
+	server := &http.Server{
+		Addr:    addr,
+		Handler: router,
+		// Only the headers are bounded so slow uploads are not cut off
+		ReadHeaderTimeout: cfg.Server.ReadTimeout,
+		// Route groups move this deadline with middleware.Timeout
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
 
 // initDatabase initializes the database connection
 func initDatabase(cfg *config.Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-	)
-
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", cfg.Database.DSN())
 	if err != nil {
 		return nil, err
 	}
@@ -165,3 +551,23 @@ func initDatabase(cfg *config.Config) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// initRedis creates the shared Redis client
+func initRedis(cfg *config.Config) (*redis.Client, error) {
+	opts, err := redis.ParseURL(cfg.Redis.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	rdb := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		slog.Warn("Redis unavailable", "error", err)
+	} else {
+		slog.Info("Redis connected")
+	}
+
+	return rdb, nil
+}
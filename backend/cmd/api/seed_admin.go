@@ -0,0 +1,54 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/services"
+)
+
+// runSeedAdmin implements the seed-admin subcommand, which creates the first
+// user and organization of a fresh deployment:
+//
+//	api seed-admin --email admin@example.com --password secret123 --org "Acme"
+func runSeedAdmin(db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("seed-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "email address of the admin user (required)")
+	password := fs.String("password", "", "password of the admin user, at least 8 characters (required)")
+	orgName := fs.String("org", "", "name of the organization to create (required)")
+	firstName := fs.String("first-name", "Admin", "first name of the admin user")
+	lastName := fs.String("last-name", "User", "last name of the admin user")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	orgService := services.NewOrganizationService(
+		repository.NewOrganizationRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewInvitationRepository(db),
+		nil,
+		models.PlanLimits{},
+	)
+
+	user, org, err := orgService.Bootstrap(&services.BootstrapRequest{
+		Email:            *email,
+		Password:         *password,
+		FirstName:        *firstName,
+		LastName:         *lastName,
+		OrganizationName: *orgName,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailExists) {
+			return fmt.Errorf("a user with email %s already exists", *email)
+		}
+		return err
+	}
+
+	fmt.Printf("Created user %s (%s)\n", user.Email, user.ID)
+	fmt.Printf("Created organization %q (%s) owned by %s\n", org.Name, org.ID, user.Email)
+	return nil
+}
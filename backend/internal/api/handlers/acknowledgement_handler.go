@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// AcknowledgementHandler handles acknowledgement rule endpoints
+type AcknowledgementHandler struct {
+	ackService *services.AcknowledgementService
+}
+
+// NewAcknowledgementHandler creates a new acknowledgement rule handler
+func NewAcknowledgementHandler(ackService *services.AcknowledgementService) *AcknowledgementHandler {
+	return &AcknowledgementHandler{
+		ackService: ackService,
+	}
+}
+
+// respondAcknowledgementError maps acknowledgement rule errors to HTTP responses
+func respondAcknowledgementError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, services.ErrAcknowledgementRuleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Acknowledgement rule not found"})
+		return
+	}
+	if respondValidationErrors(c, err) {
+		return
+	}
+	respondOrganizationError(c, err, fallback)
+}
+
+// List handles listing an organization's acknowledgement rules
+// GET /api/v1/organizations/:id/acknowledgements
+func (h *AcknowledgementHandler) List(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rules, err := h.ackService.ListRules(organizationID, userID)
+	if err != nil {
+		respondAcknowledgementError(c, err, "Failed to retrieve acknowledgement rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"acknowledgements": rules,
+		"total":            len(rules),
+	})
+}
+
+// Create handles adding an acknowledgement rule
+// POST /api/v1/organizations/:id/acknowledgements
+func (h *AcknowledgementHandler) Create(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateAcknowledgementRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rule, err := h.ackService.CreateRule(organizationID, userID, &req)
+	if err != nil {
+		respondAcknowledgementError(c, err, "Failed to create acknowledgement rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Delete handles removing an acknowledgement rule
+// DELETE /api/v1/organizations/:id/acknowledgements/:ruleId
+func (h *AcknowledgementHandler) Delete(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid acknowledgement rule ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.ackService.DeleteRule(organizationID, userID, ruleID); err != nil {
+		respondAcknowledgementError(c, err, "Failed to delete acknowledgement rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Acknowledgement rule deleted successfully",
+	})
+}
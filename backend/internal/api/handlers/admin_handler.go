@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/services"
+)
+
+// AdminHandler handles cross-tenant endpoints for platform operators
+type AdminHandler struct {
+	orgService *services.OrganizationService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(orgService *services.OrganizationService) *AdminHandler {
+	return &AdminHandler{
+		orgService: orgService,
+	}
+}
+
+// ListOrganizations handles listing every organization with member and
+// scan counts, storage usage and last activity
+// GET /api/v1/admin/organizations?sort=-last_activity&limit=50&offset=0
+func (h *AdminHandler) ListOrganizations(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 200",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset must be a non-negative integer",
+		})
+		return
+	}
+
+	// A leading "-" sorts in descending order
+	sort := c.DefaultQuery("sort", "name")
+	descending := strings.HasPrefix(sort, "-")
+	sort = strings.TrimPrefix(sort, "-")
+
+	organizations, total, err := h.orgService.ListAllOrganizations(sort, descending, limit, offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve organizations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": organizations,
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// AttachmentHandler handles scan result attachment endpoints
+type AttachmentHandler struct {
+	attachmentService *services.AttachmentService
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(attachmentService *services.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// parseResultPath parses the scan and result IDs from the route
+func parseResultPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	resultID, err := uuid.Parse(c.Param("resultId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid result ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return scanID, resultID, true
+}
+
+// respondAttachmentError maps attachment service errors to responses
+func respondAttachmentError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrScanNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+	case err == services.ErrResultNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan result not found"})
+	case err == services.ErrAttachmentNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+	case err == services.ErrAttachmentTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Attachment exceeds size limit"})
+	case errors.Is(err, services.ErrAttachmentTypeBlocked):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process attachment"})
+	}
+}
+
+// Upload handles attaching a file to a scan result
+// POST /api/v1/scans/:id/results/:resultId/attachments
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	scanID, resultID, ok := parseResultPath(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "A multipart file field named \"file\" is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	attachment, err := h.attachmentService.Upload(scanID, resultID, organizationID, userID, fileHeader.Filename, file)
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// List handles listing the attachments of a scan result
+// GET /api/v1/scans/:id/results/:resultId/attachments
+func (h *AttachmentHandler) List(c *gin.Context) {
+	scanID, resultID, ok := parseResultPath(c)
+	if !ok {
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	attachments, err := h.attachmentService.List(scanID, resultID, organizationID)
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attachments": attachments,
+		"total":       len(attachments),
+	})
+}
+
+// Download handles downloading an attachment
+// GET /api/v1/scans/:id/results/:resultId/attachments/:attachmentId/download
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	scanID, resultID, ok := parseResultPath(c)
+	if !ok {
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid attachment ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	attachment, object, err := h.attachmentService.Open(scanID, resultID, attachmentID, organizationID)
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		respondAttachmentError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, attachment.FileName, info.ModTime(), object)
+}
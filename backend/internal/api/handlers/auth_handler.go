@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"publicscannerapi/internal/repository"
 	"publicscannerapi/internal/services"
+	"publicscannerapi/pkg/auth"
 )
 
 // AuthHandler handles authentication endpoints
@@ -28,7 +31,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request data",
+			"error":   "Invalid request data",
 			"details": err.Error(),
 		})
 		return
@@ -59,14 +62,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request data",
+			"error":   "Invalid request data",
 			"details": err.Error(),
 		})
 		return
 	}
 
 	// Authenticate user
-	response, err := h.authService.Login(&req)
+	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
 		if err == services.ErrInvalidCredentials {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -74,12 +77,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			})
 			return
 		}
+		if err == services.ErrAccountLocked {
+			c.JSON(http.StatusLocked, gin.H{
+				"error": "Account is temporarily locked after too many failed logins",
+			})
+			return
+		}
 		if err == services.ErrUserInactive {
 			c.JSON(http.StatusForbidden, gin.H{
 				"error": "Account is inactive",
 			})
 			return
 		}
+		if err == services.ErrEmailNotVerified {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Email address is not verified",
+				"code":  "email_not_verified",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Login failed",
 		})
@@ -105,6 +121,27 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	tokens, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, services.ErrSessionIdle) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Session expired due to inactivity",
+				"code":  "session_idle",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Email address is not verified",
+				"code":  "email_not_verified",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Refresh token was already used; the session has been revoked",
+				"code":  "refresh_token_reused",
+			})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "Invalid or expired refresh token",
 		})
@@ -114,6 +151,62 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, tokens)
 }
 
+// Logout revokes the caller's access token and the refresh tokens of its login
+// POST /api/v1/auth/logout
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims := c.MustGet("token_claims").(*auth.TokenClaims)
+
+	if err := h.authService.Logout(c.Request.Context(), claims); err != nil {
+		if errors.Is(err, services.ErrLogoutUnsupported) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "API keys cannot log out; revoke the key instead",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to log out",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
+
+// SwitchOrganization exchanges the caller's token for a token pair scoped
+// to another organization they belong to
+// POST /api/v1/auth/switch-org
+func (h *AuthHandler) SwitchOrganization(c *gin.Context) {
+	var req services.SwitchOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	claims := c.MustGet("token_claims").(*auth.TokenClaims)
+
+	response, err := h.authService.SwitchOrganization(c.Request.Context(), claims, req.OrganizationID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotOrganizationMember):
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this organization"})
+		case errors.Is(err, services.ErrSwitchUnsupported):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "API keys are bound to their organization and cannot switch"})
+		case errors.Is(err, services.ErrUserInactive):
+			c.JSON(http.StatusForbidden, gin.H{"error": "User account is inactive"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to switch organization"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetCurrentUser returns the currently authenticated user
 // GET /api/v1/users/me
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
@@ -138,3 +231,68 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 		"user": user,
 	})
 }
+
+// ChangePassword replaces the caller's password and logs out their other sessions
+// POST /api/v1/users/me/password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	claims := c.MustGet("token_claims").(*auth.TokenClaims)
+
+	var req services.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ChangePassword(c.Request.Context(), claims, &req); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCredentials):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Current password is incorrect",
+				"code":  "invalid_current_password",
+			})
+		case errors.Is(err, services.ErrAccountLocked):
+			c.JSON(http.StatusLocked, gin.H{
+				"error": "Account is temporarily locked after too many failed attempts",
+			})
+		case errors.Is(err, services.ErrPasswordUnsupported):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Service accounts have no password",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to change password",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully",
+	})
+}
+
+// Validate reports whether the caller's access token is still valid, with its
+// expiry and claims. It reads only the token, never the database.
+// GET /api/v1/auth/validate
+func (h *AuthHandler) Validate(c *gin.Context) {
+	claims := c.MustGet("token_claims").(*auth.TokenClaims)
+
+	response := gin.H{
+		"valid":           true,
+		"user_id":         claims.UserID,
+		"email":           claims.Email,
+		"organization_id": claims.OrganizationID,
+	}
+	if claims.IssuedAt != nil {
+		response["issued_at"] = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		response["expires_at"] = claims.ExpiresAt.Time
+		response["expires_in"] = int64(time.Until(claims.ExpiresAt.Time).Seconds())
+	}
+
+	c.JSON(http.StatusOK, response)
+}
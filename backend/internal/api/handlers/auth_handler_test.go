@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"publicscannerapi/internal/api/middleware"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/services"
+	"publicscannerapi/pkg/auth"
+)
+
+// userColumns mirror the user repository's column list
+var userColumns = []string{
+	"id", "email", "password_hash", "first_name", "last_name", "is_active", "is_superadmin", "is_service_account",
+	"failed_login_attempts", "locked_until", "email_verified", "created_at", "updated_at",
+}
+
+// expectUserByID answers the next lookup of userID with an active,
+// verified user whose password hash is passwordHash
+func expectUserByID(mock sqlmock.Sqlmock, userID uuid.UUID, passwordHash string) {
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(userID.String(), "ada@example.com", passwordHash, "Ada", "Lovelace",
+			true, false, false, 0, nil, true, time.Now(), time.Now()))
+}
+
+func TestAuthHandlerSwitchOrganization(t *testing.T) {
+	db, mock := newTestDB(t)
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	denylist := services.NewTokenDenylist(rdb)
+	handler := NewAuthHandler(services.NewAuthService(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db),
+		nil, denylist, nil, nil, "jwt-secret", 15*time.Minute, 24*time.Hour))
+
+	router := gin.New()
+	router.POST("/auth/switch-org", middleware.AuthMiddleware("jwt-secret", nil, denylist), handler.SwitchOrganization)
+	switchOrg := func(token string, organizationID uuid.UUID) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"organization_id": organizationID})
+		req := httptest.NewRequest(http.MethodPost, "/auth/switch-org", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	userID, sessionID := uuid.New(), uuid.NewString()
+	first, second, other := uuid.New(), uuid.New(), uuid.New()
+	tokens, err := auth.GenerateSessionTokenPair(sessionID, userID, "ada@example.com", &first, "jwt-secret", 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Not a member of other
+	expectUserByID(mock, userID, "")
+	mock.ExpectQuery(`SELECT role\s+FROM organization_members`).WithArgs(userID, other).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+	if w := switchOrg(tokens.AccessToken, other); w.Code != http.StatusForbidden {
+		t.Fatalf("switching to a foreign organization: status = %d, want 403", w.Code)
+	}
+
+	// A member of second
+	expectUserByID(mock, userID, "")
+	mock.ExpectQuery(`SELECT role\s+FROM organization_members`).WithArgs(userID, second).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("member"))
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\)\s+WHERE user_id = \$1 AND session_id = \$2`).
+		WithArgs(userID, sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := switchOrg(tokens.AccessToken, second)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var response services.AuthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	claims, err := auth.ValidateToken(response.Tokens.AccessToken, "jwt-secret")
+	if err != nil {
+		t.Fatalf("new access token: %v", err)
+	}
+	if claims.OrganizationID == nil || *claims.OrganizationID != second {
+		t.Errorf("new token organization = %v, want %s", claims.OrganizationID, second)
+	}
+
+	// The token of the old session no longer works
+	if w := switchOrg(tokens.AccessToken, second); w.Code != http.StatusUnauthorized {
+		t.Errorf("old token after the switch: status = %d, want 401", w.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthHandlerChangePassword(t *testing.T) {
+	db, mock := newTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	handler := NewAuthHandler(services.NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, nil,
+		services.NewLoginLockout(userRepo, nil, services.LockoutPolicy{}), nil, "jwt-secret", 15*time.Minute, 24*time.Hour))
+
+	router := gin.New()
+	router.POST("/users/me/password", middleware.AuthMiddleware("jwt-secret", nil, nil), handler.ChangePassword)
+
+	userID, sessionID := uuid.New(), uuid.NewString()
+	tokens, err := auth.GenerateSessionTokenPair(sessionID, userID, "ada@example.com", nil, "jwt-secret", 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := auth.HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changePassword := func(current, next string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"current_password": current, "new_password": next})
+		req := httptest.NewRequest(http.MethodPost, "/users/me/password", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Too short a new password never reaches the service
+	if w := changePassword("correct horse battery staple", "short"); w.Code != http.StatusBadRequest {
+		t.Errorf("short new password: status = %d, want 400", w.Code)
+	}
+
+	expectUserByID(mock, userID, hash)
+	if w := changePassword("wrong password", "a brand new password"); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong current password: status = %d, want 401", w.Code)
+	}
+
+	// Success changes the hash and logs out every other session
+	expectUserByID(mock, userID, hash)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users\s+SET password_hash = \$2`).
+		WithArgs(userID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\)\s+WHERE user_id = \$1 AND session_id <> \$2`).
+		WithArgs(userID, sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	if w := changePassword("correct horse battery staple", "a brand new password"); w.Code != http.StatusOK {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
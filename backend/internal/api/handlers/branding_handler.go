@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// BrandingHandler handles organization report branding endpoints
+type BrandingHandler struct {
+	brandingService *services.BrandingService
+}
+
+// NewBrandingHandler creates a new branding handler
+func NewBrandingHandler(brandingService *services.BrandingService) *BrandingHandler {
+	return &BrandingHandler{
+		brandingService: brandingService,
+	}
+}
+
+// respondBrandingError maps branding service errors to HTTP responses
+func respondBrandingError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrLogoNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization has no custom logo"})
+	case errors.Is(err, services.ErrLogoTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Logo exceeds size limit"})
+	case errors.Is(err, services.ErrLogoTypeBlocked):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	default:
+		if respondValidationErrors(c, err) {
+			return
+		}
+		respondOrganizationError(c, err, fallback)
+	}
+}
+
+// parseOrganizationID reads the organization ID path parameter, responding
+// with 400 when it is malformed
+func parseOrganizationID(c *gin.Context) (uuid.UUID, bool) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return uuid.Nil, false
+	}
+	return organizationID, true
+}
+
+// Get handles retrieving an organization's report branding
+// GET /api/v1/organizations/:id/branding
+func (h *BrandingHandler) Get(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	branding, err := h.brandingService.GetBranding(organizationID, userID)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to retrieve branding")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// Update handles changing an organization's report texts and colors
+// PATCH /api/v1/organizations/:id/branding
+func (h *BrandingHandler) Update(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	branding, err := h.brandingService.UpdateBranding(organizationID, userID, &req)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to update branding")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// GetLogo handles downloading an organization's report logo
+// GET /api/v1/organizations/:id/branding/logo
+func (h *BrandingHandler) GetLogo(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	object, contentType, err := h.brandingService.OpenLogo(organizationID, userID)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to retrieve logo")
+		return
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		respondBrandingError(c, err, "Failed to retrieve logo")
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(c.Writer, c.Request, "logo", info.ModTime(), object)
+}
+
+// UploadLogo handles replacing an organization's report logo
+// PUT /api/v1/organizations/:id/branding/logo
+func (h *BrandingHandler) UploadLogo(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "A multipart file field named \"file\" is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	branding, err := h.brandingService.UploadLogo(organizationID, userID, file)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to upload logo")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
+
+// DeleteLogo handles removing an organization's report logo
+// DELETE /api/v1/organizations/:id/branding/logo
+func (h *BrandingHandler) DeleteLogo(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	branding, err := h.brandingService.DeleteLogo(organizationID, userID)
+	if err != nil {
+		respondBrandingError(c, err, "Failed to delete logo")
+		return
+	}
+
+	c.JSON(http.StatusOK, branding)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cachedResponse is a JSON body rendered once, with a strong ETag derived
+// from its content. Build one when the underlying data changes and serve it
+// for every request in between; clients revalidating with If-None-Match get
+// a 304 without a body.
+type cachedResponse struct {
+	body []byte
+	etag string
+}
+
+// newCachedResponse renders v as the cached JSON body
+func newCachedResponse(v interface{}) (*cachedResponse, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	return &cachedResponse{
+		body: body,
+		etag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}, nil
+}
+
+// serve writes the cached body, or 304 when the client already holds it
+func (r *cachedResponse) serve(c *gin.Context) {
+	c.Header("ETag", r.etag)
+	// Clients may store the response but must revalidate before reusing it
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), r.etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", r.body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators match their strong counterpart, as RFC 9110 requires for
+// If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// CampaignHandler handles campaign endpoints
+type CampaignHandler struct {
+	campaignService *services.CampaignService
+}
+
+// NewCampaignHandler creates a new campaign handler
+func NewCampaignHandler(campaignService *services.CampaignService) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: campaignService,
+	}
+}
+
+// respondCampaignError maps campaign service errors to responses
+func respondCampaignError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrCampaignNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Campaign not found"})
+	case errors.Is(err, services.ErrCampaignNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "A campaign with this name already exists"})
+	case errors.Is(err, services.ErrInvalidCampaign):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// parseCampaignID reads the :id path parameter, responding 400 when invalid
+func parseCampaignID(c *gin.Context) (uuid.UUID, bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid campaign ID",
+		})
+		return uuid.Nil, false
+	}
+	return campaignID, true
+}
+
+// Create handles creating a campaign
+// POST /api/v1/campaigns
+func (h *CampaignHandler) Create(c *gin.Context) {
+	var req services.CreateCampaignRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	campaign, err := h.campaignService.CreateCampaign(organizationID, userID, &req)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to create campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// List handles listing an organization's campaigns
+// GET /api/v1/campaigns
+func (h *CampaignHandler) List(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	campaigns, err := h.campaignService.ListCampaigns(organizationID)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to retrieve campaigns")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns": campaigns,
+		"total":     len(campaigns),
+	})
+}
+
+// Get handles retrieving a campaign with its severity rollup
+// GET /api/v1/campaigns/:id
+func (h *CampaignHandler) Get(c *gin.Context) {
+	campaignID, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	summary, err := h.campaignService.GetSummary(campaignID, organizationID)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to retrieve campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListScans handles listing a campaign's scans
+// GET /api/v1/campaigns/:id/scans
+func (h *CampaignHandler) ListScans(c *gin.Context) {
+	campaignID, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	// Parse pagination parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	scans, total, err := h.campaignService.ListScans(campaignID, organizationID, limit, offset)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to retrieve scans")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scans":    scans,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": hasMore(offset, len(scans), total),
+	})
+}
+
+// AssignTargets handles bulk-assigning targets to a campaign, by ID or by
+// tag and/or search
+// POST /api/v1/campaigns/:id/targets
+func (h *CampaignHandler) AssignTargets(c *gin.Context) {
+	campaignID, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	var req services.AssignCampaignTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	assignment, err := h.campaignService.AssignTargets(campaignID, organizationID, &req)
+	if err != nil {
+		if respondValidationErrors(c, err) {
+			return
+		}
+		respondCampaignError(c, err, "Failed to assign targets")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// ListTargets handles listing the targets assigned to a campaign
+// GET /api/v1/campaigns/:id/targets
+func (h *CampaignHandler) ListTargets(c *gin.Context) {
+	campaignID, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	targets, err := h.campaignService.ListTargets(campaignID, organizationID)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to retrieve targets")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets": targets,
+		"total":   len(targets),
+	})
+}
+
+// Risk handles computing a campaign's deduplicated risk posture
+// GET /api/v1/campaigns/:id/risk?top=10
+func (h *CampaignHandler) Risk(c *gin.Context) {
+	campaignID, ok := parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if err != nil || top < 1 || top > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "top must be between 1 and 100",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	risk, err := h.campaignService.GetRisk(campaignID, organizationID, top)
+	if err != nil {
+		respondCampaignError(c, err, "Failed to compute campaign risk")
+		return
+	}
+
+	c.JSON(http.StatusOK, risk)
+}
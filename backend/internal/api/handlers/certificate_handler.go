@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// CertificateHandler handles client certificate endpoints
+type CertificateHandler struct {
+	certService *services.CertificateService
+}
+
+// NewCertificateHandler creates a new client certificate handler
+func NewCertificateHandler(certService *services.CertificateService) *CertificateHandler {
+	return &CertificateHandler{
+		certService: certService,
+	}
+}
+
+// Upload handles uploading a client certificate/key pair
+// POST /api/v1/certificates
+func (h *CertificateHandler) Upload(c *gin.Context) {
+	var req services.UploadCertificateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	cert, err := h.certService.UploadCertificate(&req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCertificate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to store client certificate",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cert)
+}
+
+// List handles listing all client certificates for an organization
+// GET /api/v1/certificates
+func (h *CertificateHandler) List(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	certs, err := h.certService.ListCertificates(organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve client certificates",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certs,
+		"total":        len(certs),
+	})
+}
+
+// Get handles retrieving a single client certificate
+// GET /api/v1/certificates/:id
+func (h *CertificateHandler) Get(c *gin.Context) {
+	certID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid certificate ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	cert, err := h.certService.GetCertificate(certID, organizationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Client certificate not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, cert)
+}
+
+// Delete handles deleting a client certificate
+// DELETE /api/v1/certificates/:id
+func (h *CertificateHandler) Delete(c *gin.Context) {
+	certID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid certificate ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	if err := h.certService.DeleteCertificate(certID, organizationID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Client certificate not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Client certificate deleted successfully",
+	})
+}
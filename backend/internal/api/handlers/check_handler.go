@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/services"
+)
+
+// CheckHandler handles the checks catalog endpoint
+type CheckHandler struct {
+	catalog *cachedResponse
+}
+
+// NewCheckHandler creates a new checks catalog handler. The catalog is
+// rendered once here; the check registry is compiled in, so a restart is
+// the only time it can change.
+func NewCheckHandler() (*CheckHandler, error) {
+	checks := services.CheckCatalog()
+	catalog, err := newCachedResponse(gin.H{
+		"checks": checks,
+		"total":  len(checks),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckHandler{catalog: catalog}, nil
+}
+
+// Catalog handles listing the checks a scan may request, answering 304
+// when the client's If-None-Match holds the current ETag
+// GET /api/v1/checks
+func (h *CheckHandler) Catalog(c *gin.Context) {
+	h.catalog.serve(c)
+}
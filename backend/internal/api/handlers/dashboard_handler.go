@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// DashboardHandler handles dashboard aggregation endpoints
+type DashboardHandler struct {
+	scanService *services.ScanService
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(scanService *services.ScanService) *DashboardHandler {
+	return &DashboardHandler{
+		scanService: scanService,
+	}
+}
+
+// TopRisks handles listing the riskiest targets by their latest scan
+// GET /api/v1/dashboard/top-risks
+func (h *DashboardHandler) TopRisks(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 100",
+		})
+		return
+	}
+
+	risks, err := h.scanService.GetTopRisks(organizationID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve top risks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets": risks,
+		"total":   len(risks),
+		"limit":   limit,
+	})
+}
+
+// Failures handles breaking down recent scan failures by failure code and
+// by failing check
+// GET /api/v1/dashboard/failures?days=30
+func (h *DashboardHandler) Failures(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "days must be between 1 and 365",
+		})
+		return
+	}
+
+	stats, err := h.scanService.GetFailureStats(organizationID, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve failure statistics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
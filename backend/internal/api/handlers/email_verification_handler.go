@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/services"
+)
+
+// EmailVerificationHandler handles the email verification endpoints
+type EmailVerificationHandler struct {
+	verificationService *services.EmailVerificationService
+}
+
+// NewEmailVerificationHandler creates a new email verification handler
+func NewEmailVerificationHandler(verificationService *services.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		verificationService: verificationService,
+	}
+}
+
+// Verify marks the email of the link's user as verified
+// GET /api/v1/auth/verify?token=...
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Verification token required",
+		})
+		return
+	}
+
+	if err := h.verificationService.Verify(token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid or expired verification token",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification emails a new verification link. The response is the
+// same whether or not the email is registered or already verified.
+// POST /api/v1/auth/resend-verification
+func (h *EmailVerificationHandler) ResendVerification(c *gin.Context) {
+	var req services.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.verificationService.ResendVerification(&req); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to resend verification email", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to resend verification email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If an unverified account exists for this email, a verification link has been sent",
+	})
+}
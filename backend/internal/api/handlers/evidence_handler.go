@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/services"
+)
+
+// EvidenceHandler handles scan evidence bundle requests
+type EvidenceHandler struct {
+	evidenceService *services.EvidenceService
+}
+
+// NewEvidenceHandler creates a new evidence handler
+func NewEvidenceHandler(evidenceService *services.EvidenceService) *EvidenceHandler {
+	return &EvidenceHandler{
+		evidenceService: evidenceService,
+	}
+}
+
+// Download streams a ZIP with a scan's config, timeline, raw results,
+// reports and attachments, plus a manifest of their checksums
+// GET /api/v1/scans/:id/evidence
+func (h *EvidenceHandler) Download(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	bundle, err := h.evidenceService.LoadBundle(scanID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to collect scan evidence",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("scan_%s_evidence.zip", scanID)))
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+
+	// The archive is streamed, so a failure midway can only truncate it
+	if err := h.evidenceService.WriteArchive(c.Writer, bundle); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to stream evidence bundle", "scan_id", scanID, "error", err)
+		c.Abort()
+	}
+}
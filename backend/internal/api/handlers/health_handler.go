@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/logging"
+)
+
+// healthCheckTimeout bounds each dependency check of the readiness probe
+const healthCheckTimeout = 2 * time.Second
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	db  *sql.DB
+	rdb *redis.Client
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(db *sql.DB, rdb *redis.Client) *HealthHandler {
+	return &HealthHandler{
+		db:  db,
+		rdb: rdb,
+	}
+}
+
+// Live confirms the process is up without touching its dependencies
+// GET /health/live
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"service": "PublicScanner API",
+	})
+}
+
+// Ready checks that the database and Redis are reachable, responding 503
+// with the status of each dependency when one is not
+// GET /health, GET /health/ready
+func (h *HealthHandler) Ready(c *gin.Context) {
+	checks := map[string]func(ctx context.Context) error{
+		"database": h.db.PingContext,
+		"redis": func(ctx context.Context) error {
+			return h.rdb.Ping(ctx).Err()
+		},
+	}
+
+	status, code := "ok", http.StatusOK
+	results := make(map[string]string, len(checks))
+	for name, check := range checks {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+		err := check(ctx)
+		cancel()
+
+		if err != nil {
+			// The error stays in the logs; the probe is unauthenticated
+			logging.FromContext(c.Request.Context()).Warn("Health check failed", "dependency", name, "error", err)
+			results[name] = "unavailable"
+			status, code = "unavailable", http.StatusServiceUnavailable
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	c.JSON(code, gin.H{
+		"status":  status,
+		"service": "PublicScanner API",
+		"checks":  results,
+	})
+}
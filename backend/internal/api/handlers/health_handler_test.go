@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHealthHandlerReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		closeDB, stopRDB bool
+		code             int
+		database, redis  string
+	}{
+		{"healthy", false, false, http.StatusOK, "ok", "ok"},
+		{"database closed", true, false, http.StatusServiceUnavailable, "unavailable", "ok"},
+		{"redis down", false, true, http.StatusServiceUnavailable, "ok", "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			server := miniredis.RunT(t)
+			rdb := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+			defer rdb.Close()
+
+			if tt.closeDB {
+				db.Close()
+			}
+			if tt.stopRDB {
+				server.Close()
+			}
+
+			router := gin.New()
+			handler := NewHealthHandler(db, rdb)
+			router.GET("/health/ready", handler.Ready)
+			router.GET("/health/live", handler.Live)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+			if w.Code != tt.code {
+				t.Errorf("status = %d, want %d", w.Code, tt.code)
+			}
+			var body struct {
+				Checks map[string]string `json:"checks"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Checks["database"] != tt.database || body.Checks["redis"] != tt.redis {
+				t.Errorf("checks = %v, want database %s and redis %s", body.Checks, tt.database, tt.redis)
+			}
+
+			// Liveness ignores the dependencies
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("/health/live status = %d, want 200", w.Code)
+			}
+		})
+	}
+}
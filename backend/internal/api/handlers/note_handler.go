@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// NoteHandler handles scan result note endpoints
+type NoteHandler struct {
+	noteService *services.NoteService
+}
+
+// NewNoteHandler creates a new note handler
+func NewNoteHandler(noteService *services.NoteService) *NoteHandler {
+	return &NoteHandler{
+		noteService: noteService,
+	}
+}
+
+// respondNoteError maps note service errors to responses
+func respondNoteError(c *gin.Context, err error, fallback string) {
+	switch {
+	case err == services.ErrScanNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+	case err == services.ErrResultNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan result not found"})
+	default:
+		if respondValidationErrors(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// Create handles adding a note to a scan result
+// POST /api/v1/scans/:id/results/:resultId/notes
+func (h *NoteHandler) Create(c *gin.Context) {
+	scanID, resultID, ok := parseResultPath(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	note, err := h.noteService.CreateNote(scanID, resultID, organizationID, userID, &req)
+	if err != nil {
+		respondNoteError(c, err, "Failed to create note")
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// List handles listing the notes of a scan result
+// GET /api/v1/scans/:id/results/:resultId/notes
+func (h *NoteHandler) List(c *gin.Context) {
+	scanID, resultID, ok := parseResultPath(c)
+	if !ok {
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	notes, err := h.noteService.ListNotes(scanID, resultID, organizationID)
+	if err != nil {
+		respondNoteError(c, err, "Failed to retrieve notes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notes": notes,
+		"total": len(notes),
+	})
+}
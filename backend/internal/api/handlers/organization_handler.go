@@ -0,0 +1,529 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// OrganizationHandler handles organization endpoints
+type OrganizationHandler struct {
+	orgService *services.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(orgService *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+	}
+}
+
+// Usage handles retrieving an organization's usage summary
+// GET /api/v1/organizations/:id/usage
+func (h *OrganizationHandler) Usage(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	usage, err := h.orgService.GetUsage(organizationID, userID)
+	if err != nil {
+		if err == services.ErrOrganizationNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Organization not found",
+			})
+			return
+		}
+		if err == services.ErrNotOrganizationMember {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "You are not a member of this organization",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve organization usage",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// MyPermissions handles retrieving the caller's role and allowed actions in
+// an organization, by default the one their token is scoped to
+// GET /api/v1/users/me/permissions?org=<id>
+func (h *OrganizationHandler) MyPermissions(c *gin.Context) {
+	var organizationID uuid.UUID
+	if org := c.Query("org"); org != "" {
+		parsed, err := uuid.Parse(org)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid organization ID",
+			})
+			return
+		}
+		organizationID = parsed
+	} else if orgID, ok := c.Get("organization_id"); ok {
+		organizationID = orgID.(uuid.UUID)
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "org query parameter is required",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	permissions, err := h.orgService.GetPermissions(organizationID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve permissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, permissions)
+}
+
+// respondOrganizationError maps organization service errors to responses
+func respondOrganizationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Organization not found"})
+	case errors.Is(err, services.ErrNotOrganizationMember):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this organization"})
+	case errors.Is(err, services.ErrInsufficientRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	case errors.Is(err, services.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+	case errors.Is(err, services.ErrInvitationNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": "Invitation is no longer pending"})
+	case errors.Is(err, services.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+	case errors.Is(err, services.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a member of this organization"})
+	case errors.Is(err, services.ErrInvalidInvitation), errors.Is(err, services.ErrInvalidFilter),
+		errors.Is(err, services.ErrInvalidMerge), errors.Is(err, services.ErrInvalidMember):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// Create handles creating an organization owned by the caller
+// POST /api/v1/organizations
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var req services.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	org, err := h.orgService.CreateOrganization(userID, &req)
+	if err != nil {
+		if respondValidationErrors(c, err) {
+			return
+		}
+		respondOrganizationError(c, err, "Failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// List handles listing the organizations the caller belongs to
+// GET /api/v1/organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	organizations, err := h.orgService.ListOrganizations(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve organizations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": organizations,
+		"total":         len(organizations),
+	})
+}
+
+// ListMembers handles listing an organization's members
+// GET /api/v1/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	members, err := h.orgService.ListMembers(organizationID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve members")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": members,
+		"total":   len(members),
+	})
+}
+
+// AddMember handles adding a registered user to an organization
+// POST /api/v1/organizations/:id/members
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	var req services.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	member, err := h.orgService.AddMember(organizationID, userID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMemberRole handles changing a member's role
+// PATCH /api/v1/organizations/:id/members/:userId
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	memberUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	var req services.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	member, err := h.orgService.UpdateMemberRole(organizationID, userID, memberUserID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update member role")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// GetNotifications handles retrieving an organization's notification preferences
+// GET /api/v1/organizations/:id/notifications
+func (h *OrganizationHandler) GetNotifications(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	prefs, err := h.orgService.GetNotificationPreferences(organizationID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdateNotifications handles changing an organization's notification preferences
+// PATCH /api/v1/organizations/:id/notifications
+func (h *OrganizationHandler) UpdateNotifications(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	var req services.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	prefs, err := h.orgService.UpdateNotificationPreferences(organizationID, userID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// GetSessionPolicy handles retrieving an organization's session policy
+// GET /api/v1/organizations/:id/session-policy
+func (h *OrganizationHandler) GetSessionPolicy(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	policy, err := h.orgService.GetSessionPolicy(organizationID, userID)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve session policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateSessionPolicy handles replacing an organization's session policy
+// PUT /api/v1/organizations/:id/session-policy
+func (h *OrganizationHandler) UpdateSessionPolicy(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	var req services.UpdateSessionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	policy, err := h.orgService.UpdateSessionPolicy(organizationID, userID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to update session policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// ListInvitations handles listing an organization's invitations with status
+// filtering, creation-time sorting and pagination
+// GET /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	// Parse pagination parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	list, err := h.orgService.ListInvitations(organizationID, userID, &services.ListInvitationsRequest{
+		Status: c.Query("status"),
+		Sort:   c.Query("sort"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to retrieve invitations")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// CreateInvitation handles inviting an email address into an organization.
+// The acceptance token is only returned here.
+// POST /api/v1/organizations/:id/invitations
+func (h *OrganizationHandler) CreateInvitation(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	var req services.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	invitation, token, err := h.orgService.CreateInvitation(organizationID, userID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invitation": invitation,
+		"token":      token,
+	})
+}
+
+// RevokeInvitation handles revoking a pending invitation
+// DELETE /api/v1/organizations/:id/invitations/:invitationId
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid invitation ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.orgService.RevokeInvitation(organizationID, invitationID, userID); err != nil {
+		respondOrganizationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation revoked successfully",
+	})
+}
+
+// AcceptInvitation handles accepting an invitation addressed to the caller
+// POST /api/v1/invitations/accept
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	email := c.GetString("user_email")
+
+	invitation, err := h.orgService.AcceptInvitation(req.Token, userID, email)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Invitation accepted",
+		"organization_id": invitation.OrganizationID,
+		"role":            invitation.Role,
+	})
+}
+
+// Merge handles merging another organization into this one
+// POST /api/v1/organizations/:id/merge
+func (h *OrganizationHandler) Merge(c *gin.Context) {
+	organizationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid organization ID",
+		})
+		return
+	}
+
+	var req services.MergeOrganizationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	merge, err := h.orgService.MergeOrganizations(organizationID, userID, &req)
+	if err != nil {
+		respondOrganizationError(c, err, "Failed to merge organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, merge)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/services"
+)
+
+// PasswordResetHandler handles the forgotten password endpoints
+type PasswordResetHandler struct {
+	resetService *services.PasswordResetService
+}
+
+// NewPasswordResetHandler creates a new password reset handler
+func NewPasswordResetHandler(resetService *services.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		resetService: resetService,
+	}
+}
+
+// ForgotPassword emails a password reset link. The response is the same
+// whether or not the email is registered.
+// POST /api/v1/auth/forgot-password
+func (h *PasswordResetHandler) ForgotPassword(c *gin.Context) {
+	var req services.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.resetService.RequestReset(&req); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to request password reset", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to request password reset",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword sets a new password with the token from a reset email
+// POST /api/v1/auth/reset-password
+func (h *PasswordResetHandler) ResetPassword(c *gin.Context) {
+	var req services.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.resetService.ResetPassword(&req); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid or expired reset token",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to reset password",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully",
+	})
+}
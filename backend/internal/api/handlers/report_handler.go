@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"publicscannerapi/internal/logging"
 	"publicscannerapi/internal/services"
 )
 
@@ -52,6 +55,13 @@ func (h *ReportHandler) Generate(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrScanNotFinished) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error() + "; set allow_partial to generate a snapshot",
+				"code":  "scan_not_finished",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -94,7 +104,7 @@ func (h *ReportHandler) List(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	reports, err := h.reportService.ListReports(organizationID, limit, offset)
+	reports, total, err := h.reportService.ListReports(organizationID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve reports",
@@ -103,16 +113,87 @@ func (h *ReportHandler) List(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"reports": reports,
-		"total":   len(reports),
-		"limit":   limit,
-		"offset":  offset,
+		"reports":  reports,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": hasMore(offset, len(reports), total),
+	})
+}
+
+// ListByScan handles listing the reports generated for a scan
+// GET /api/v1/scans/:id/reports?format=pdf
+func (h *ReportHandler) ListByScan(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 100",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset must not be negative",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	reports, total, err := h.reportService.ListByScan(scanID, organizationID, c.Query("format"), limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidFilter):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrScanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to retrieve reports",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports":  reports,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": hasMore(offset, len(reports), total),
 	})
 }
 
-// Download handles downloading a report file
+// Download handles downloading a report file. Range requests are honored
+// (206 Partial Content), so interrupted downloads can be resumed.
 // GET /api/v1/reports/:id/download
 func (h *ReportHandler) Download(c *gin.Context) {
+	h.serveDownload(c)
+}
+
+// DownloadHead reports a download's headers without sending the file
+// HEAD /api/v1/reports/:id/download
+func (h *ReportHandler) DownloadHead(c *gin.Context) {
+	h.serveDownload(c)
+}
+
+// serveDownload loads the requested report, sets the download headers and
+// serves the file. http.ServeContent handles Range, If-Range and conditional
+// requests against the ETag and modtime, and omits the body for HEAD.
+func (h *ReportHandler) serveDownload(c *gin.Context) {
 	reportID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -131,13 +212,127 @@ func (h *ReportHandler) Download(c *gin.Context) {
 		return
 	}
 
+	file, info, err := h.reportService.OpenReportFile(report)
+	if err != nil {
+		if err == services.ErrReportFileMissing {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Report file not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to read report file",
+		})
+		return
+	}
+	defer file.Close()
+
 	// Set appropriate headers
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Transfer-Encoding", "binary")
 	c.Header("Content-Disposition", "attachment; filename="+report.FileName)
 	c.Header("Content-Type", getContentType(report.Format))
+	c.Header("ETag", fmt.Sprintf(`"%s-%x-%x"`, report.ID, info.Size(), info.ModTime().UnixNano()))
+
+	http.ServeContent(c.Writer, c.Request, report.FileName, info.ModTime(), file)
+}
+
+// DownloadAll streams a ZIP of every report file generated for a scan
+// GET /api/v1/scans/:id/reports/download-all
+func (h *ReportHandler) DownloadAll(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	reports, err := h.reportService.ListScanReports(scanID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve reports",
+		})
+		return
+	}
+	if len(reports) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Scan has no reports",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("scan_%s_reports.zip", scanID)))
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+
+	// The archive is streamed, so a failure midway can only truncate it
+	if _, err := h.reportService.WriteReportsArchive(c.Writer, reports); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to stream reports archive", "scan_id", scanID, "error", err)
+		c.Abort()
+	}
+}
+
+// ExportCheckResults streams the results of one check type of a scan as CSV,
+// JSON or NDJSON. The check type shares its path segment with the result ID of the
+// neighbouring result routes, so it is read from that parameter.
+// GET /api/v1/scans/:id/results/:checkType/export?format=csv|json|ndjson
+func (h *ReportHandler) ExportCheckResults(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" && format != "ndjson" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "format must be csv, json or ndjson",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+	checkType := c.Param("resultId")
+
+	scan, results, err := h.reportService.GetCheckResults(scanID, organizationID, checkType)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidFilter):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrScanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to retrieve results",
+			})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("scan_%s_%s.%s", scanID, checkType, format)))
+	c.Header("Content-Type", getContentType(format))
+	c.Status(http.StatusOK)
 
-	c.File(report.FilePath)
+	// The export is streamed, so a failure midway can only truncate it
+	if err := h.reportService.WriteResults(c.Writer, format, scan, results); err != nil {
+		logging.FromContext(c.Request.Context()).Error("Failed to stream results", "scan_id", scanID, "check_type", checkType, "error", err)
+		c.Abort()
+	}
 }
 
 // Delete handles deleting a report
@@ -154,8 +349,14 @@ func (h *ReportHandler) Delete(c *gin.Context) {
 	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
 	if err := h.reportService.DeleteReport(reportID, organizationID); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Report not found",
+		if errors.Is(err, services.ErrReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Report not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete report",
 		})
 		return
 	}
@@ -172,6 +373,8 @@ func getContentType(format string) string {
 		return "application/json"
 	case "csv":
 		return "text/csv"
+	case "ndjson":
+		return "application/x-ndjson"
 	case "pdf":
 		return "application/pdf"
 	case "html":
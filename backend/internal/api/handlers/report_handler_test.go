@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+	"publicscannerapi/internal/services"
+)
+
+var reportColumns = []string{
+	"id", "scan_id", "organization_id", "generated_by", "format", "file_name", "file_path", "file_size", "created_at",
+}
+
+func TestReportHandlerListByScan(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := NewReportHandler(services.NewReportService(repository.NewReportRepository(db), repository.NewScanRepository(db), nil, t.TempDir()))
+	scanID, organizationID := uuid.New(), uuid.New()
+
+	router := gin.New()
+	router.GET("/scans/:id/reports", withOrganization(organizationID), handler.ListByScan)
+
+	// One scan with a PDF and an HTML report
+	reports := map[string]uuid.UUID{"pdf": uuid.New(), "html": uuid.New()}
+	expectReports := func(format string) {
+		mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scanID).
+			WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(completedScanRow(scanID, organizationID)...))
+		rows := sqlmock.NewRows(reportColumns)
+		count := 0
+		for _, f := range []string{"pdf", "html"} {
+			if format == "" || format == f {
+				rows.AddRow(reports[f].String(), scanID.String(), organizationID.String(), uuid.NewString(), f,
+					"report."+f, "/reports/report."+f, 1024, time.Now())
+				count++
+			}
+		}
+		mock.ExpectQuery(`FROM reports\s+WHERE scan_id = \$1`).WithArgs(scanID, format, 50, 0).WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reports WHERE scan_id = \$1`).WithArgs(scanID, format).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(count))
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"", []string{"pdf", "html"}},
+		{"pdf", []string{"pdf"}},
+		{"html", []string{"html"}},
+	}
+
+	for _, tt := range tests {
+		expectReports(tt.format)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scans/"+scanID.String()+"/reports?format="+tt.format, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("format %q: status = %d, body = %s", tt.format, w.Code, w.Body)
+		}
+
+		var page struct {
+			Reports []struct {
+				ID     uuid.UUID `json:"id"`
+				Format string    `json:"format"`
+			} `json:"reports"`
+			Total int `json:"total"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Reports) != len(tt.want) || page.Total != len(tt.want) {
+			t.Fatalf("format %q: %d reports, total %d; want %d", tt.format, len(page.Reports), page.Total, len(tt.want))
+		}
+		for i, report := range page.Reports {
+			if report.Format != tt.want[i] || report.ID != reports[tt.want[i]] {
+				t.Errorf("format %q: report %d is %s %s, want %s %s", tt.format, i, report.Format, report.ID, tt.want[i], reports[tt.want[i]])
+			}
+		}
+	}
+
+	// An unknown format is rejected before anything is looked up
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scans/"+scanID.String()+"/reports?format=docx", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("format docx: status = %d, want 400", w.Code)
+	}
+
+	// Another organization's scan is not found
+	otherScan := uuid.New()
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(otherScan).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(completedScanRow(otherScan, uuid.New())...))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scans/"+otherScan.String()+"/reports", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("another organization's scan: status = %d, want 404", w.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,14 +1,25 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/models"
 	"publicscannerapi/internal/services"
 )
 
+// Polling and keep-alive intervals of the live progress stream
+const (
+	scanStreamPollInterval = time.Second
+	scanStreamKeepAlive    = 15 * time.Second
+)
+
 // ScanHandler handles scan endpoints
 type ScanHandler struct {
 	scanService *services.ScanService
@@ -36,16 +47,19 @@ func (h *ScanHandler) Create(c *gin.Context) {
 
 	// Get user and organization from context
 	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
-	// Check if organization_id exists in context
-	orgID, exists := c.Get("organization_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "No organization found. Please log out and log back in.",
-		})
+	// Inline quick scan of lightweight checks
+	if req.Sync {
+		h.createSync(c, &req, userID, organizationID)
+		return
+	}
+
+	// Multi-URL quick scan: one scan per URL
+	if len(req.URLs) > 0 {
+		h.createMany(c, &req, userID, organizationID)
 		return
 	}
-	organizationID := orgID.(uuid.UUID)
 
 	scan, err := h.scanService.CreateScan(&req, userID, organizationID)
 	if err != nil {
@@ -55,6 +69,12 @@ func (h *ScanHandler) Create(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create scan",
 		})
@@ -64,6 +84,235 @@ func (h *ScanHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, scan)
 }
 
+// Validate handles checking a scan request without creating the scan
+// POST /api/v1/scans/validate
+func (h *ScanHandler) Validate(c *gin.Context) {
+	var req services.CreateScanRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	problems, err := h.scanService.ValidateScanRequest(&req, organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to validate scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  len(problems) == 0,
+		"fields": problems,
+	})
+}
+
+// createSync runs a quick scan inline and responds with its results, or
+// with the queued scan when it fell back to the workers
+func (h *ScanHandler) createSync(c *gin.Context, req *services.CreateScanRequest, userID, organizationID uuid.UUID) {
+	result, err := h.scanService.CreateSyncScan(c.Request.Context(), req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create scan",
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if result.Mode == "async" {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, result)
+}
+
+// createMany expands a multi-URL quick scan into one scan per URL
+func (h *ScanHandler) createMany(c *gin.Context, req *services.CreateScanRequest, userID, organizationID uuid.UUID) {
+	scans, err := h.scanService.CreateScans(req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		// Scans queued before the failure are still reported
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":    "Failed to create scans",
+			"scan_ids": scanIDs(scans),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"scans":    scans,
+		"scan_ids": scanIDs(scans),
+		"total":    len(scans),
+	})
+}
+
+// CreateByTag handles scanning every active target carrying a tag
+// POST /api/v1/scans/by-tag
+func (h *ScanHandler) CreateByTag(c *gin.Context) {
+	var req services.ScanByTagRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	scans, err := h.scanService.CreateScansByTag(&req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		// Scans queued before the failure are still reported
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":    "Failed to create scans",
+			"scan_ids": scanIDs(scans),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"scans":    scans,
+		"scan_ids": scanIDs(scans),
+		"total":    len(scans),
+	})
+}
+
+// CreateBulk handles creating one scan per listed target and URL, reporting
+// the outcome of each so that one bad target does not fail the rest
+// POST /api/v1/scans/bulk
+func (h *ScanHandler) CreateBulk(c *gin.Context) {
+	var req services.BulkScanRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	result, err := h.scanService.CreateScanBatch(&req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create scans",
+		})
+		return
+	}
+
+	if result.Created == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "No scans were created",
+			"items":   result.Items,
+			"created": result.Created,
+			"failed":  result.Failed,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// PreviewByTag handles listing the targets a tag-based scan would cover
+// GET /api/v1/scans/by-tag/preview?tag=external
+func (h *ScanHandler) PreviewByTag(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	targets, err := h.scanService.TargetsForTag(organizationID, c.Query("tag"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve targets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tag":         strings.TrimSpace(c.Query("tag")),
+		"targets":     targets,
+		"total":       len(targets),
+		"max_targets": services.MaxTagScanTargets,
+	})
+}
+
+// scanIDs returns the IDs of scans
+func scanIDs(scans []*models.ScanJob) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(scans))
+	for _, scan := range scans {
+		ids = append(ids, scan.ID)
+	}
+	return ids
+}
+
+// Requeue handles bulk requeueing of failed or stuck scans
+// POST /api/v1/scans/requeue
+func (h *ScanHandler) Requeue(c *gin.Context) {
+	var req services.RequeueScansRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	result, err := h.scanService.RequeueScans(&req, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to requeue scans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // Get handles retrieving a single scan
 // GET /api/v1/scans/:id
 func (h *ScanHandler) Get(c *gin.Context) {
@@ -88,8 +337,134 @@ func (h *ScanHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, scan)
 }
 
-// List handles listing all scans for an organization
-// GET /api/v1/scans
+// Stream pushes a scan's progress as server-sent events. A progress event
+// is sent at once and then whenever the status, progress or step changes,
+// until the scan reaches a final state and the stream is closed.
+// GET /api/v1/scans/:id/stream
+func (h *ScanHandler) Stream(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	progress, err := h.scanService.GetScanProgress(scanID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve scan",
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.SSEvent("progress", progress)
+	c.Writer.Flush()
+
+	poll := time.NewTicker(scanStreamPollInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(scanStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for !progress.Status.IsTerminal() {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			// Comment lines keep idle connections open through proxies
+			if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-poll.C:
+			current, err := h.scanService.GetScanProgress(scanID, organizationID)
+			if err != nil {
+				if !errors.Is(err, services.ErrScanNotFound) {
+					logging.FromContext(c.Request.Context()).Error("Failed to poll scan progress", "scan_id", scanID, "error", err)
+				}
+				c.SSEvent("error", gin.H{"error": "Scan is no longer available"})
+				c.Writer.Flush()
+				return
+			}
+			if current.Status == progress.Status && current.Progress == progress.Progress && current.CurrentStep == progress.CurrentStep {
+				continue
+			}
+
+			progress = current
+			c.SSEvent("progress", progress)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// Update handles editing a scan that is still queued
+// PATCH /api/v1/scans/:id
+func (h *ScanHandler) Update(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	var req services.UpdateScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	scan, err := h.scanService.UpdateScan(scanID, organizationID, &req)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		if err == services.ErrScanNotEditable {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Scan can only be edited while queued",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidScanConfig) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, scan)
+}
+
+// List handles listing the scans of an organization, filtered and sorted
+// GET /api/v1/scans?status=failed&target_id=...&created_after=2024-01-01T00:00:00Z&sort=-created_at
 func (h *ScanHandler) List(c *gin.Context) {
 	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
@@ -97,8 +472,65 @@ func (h *ScanHandler) List(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	scans, err := h.scanService.ListScans(organizationID, limit, offset)
+	filter := services.ListScansFilter{Status: c.Query("status")}
+	if raw := c.Query("has_report"); raw != "" {
+		hasReport, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "has_report must be true or false",
+			})
+			return
+		}
+		filter.HasReport = &hasReport
+	}
+	if raw := c.Query("campaign_id"); raw != "" {
+		campaignID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid campaign ID",
+			})
+			return
+		}
+		filter.CampaignID = &campaignID
+	}
+	if raw := c.Query("target_id"); raw != "" {
+		targetID, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid target ID",
+			})
+			return
+		}
+		filter.TargetID = &targetID
+	}
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+	} {
+		if raw := c.Query(param.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": param.name + " must be an RFC 3339 timestamp",
+				})
+				return
+			}
+			*param.dest = &t
+		}
+	}
+	filter.Sort = c.Query("sort")
+
+	scans, total, err := h.scanService.ListScans(organizationID, filter, limit, offset)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve scans",
 		})
@@ -106,15 +538,23 @@ func (h *ScanHandler) List(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"scans":  scans,
-		"total":  len(scans),
-		"limit":  limit,
-		"offset": offset,
+		"scans":    scans,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": hasMore(offset, len(scans), total),
 	})
 }
 
-// GetResults handles retrieving scan results
-// GET /api/v1/scans/:id/results
+// hasMore reports whether rows remain after a page of count rows starting
+// at offset
+func hasMore(offset, count, total int) bool {
+	return offset+count < total
+}
+
+// GetResults handles retrieving scan results, optionally only those in one
+// triage state
+// GET /api/v1/scans/:id/results?triage=open
 func (h *ScanHandler) GetResults(c *gin.Context) {
 	scanID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -126,7 +566,7 @@ func (h *ScanHandler) GetResults(c *gin.Context) {
 
 	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
-	results, err := h.scanService.GetScanResults(scanID, organizationID)
+	results, err := h.scanService.GetScanResultsByTriageStatus(scanID, organizationID, c.Query("triage"))
 	if err != nil {
 		if err == services.ErrScanNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
@@ -134,6 +574,12 @@ func (h *ScanHandler) GetResults(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve scan results",
 		})
@@ -146,6 +592,297 @@ func (h *ScanHandler) GetResults(c *gin.Context) {
 	})
 }
 
+// Timeline handles retrieving the chronological lifecycle of a scan
+// GET /api/v1/scans/:id/timeline
+func (h *ScanHandler) Timeline(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	events, err := h.scanService.GetScanTimeline(scanID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve scan timeline",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id": scanID,
+		"events":  events,
+	})
+}
+
+// ConfigDiff handles comparing the checks and configuration of two scans
+// GET /api/v1/scans/:id/config-diff?against=<otherScanId>
+func (h *ScanHandler) ConfigDiff(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	againstID, err := uuid.Parse(c.Query("against"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "against must be a scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	diff, err := h.scanService.GetConfigDiff(scanID, againstID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compare scan configurations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// Diff handles comparing the findings of two scans of the same target
+// GET /api/v1/scans/:id/diff?against=<otherScanId>
+func (h *ScanHandler) Diff(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	againstID, err := uuid.Parse(c.Query("against"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "against must be a scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	diff, err := h.scanService.DiffScans(scanID, againstID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		if err == services.ErrScanTargetsDiffer {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Only scans of the same target can be compared",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compare scans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// Delete handles soft-deleting a scan
+// DELETE /api/v1/scans/:id
+func (h *ScanHandler) Delete(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	deletion, err := h.scanService.DeleteScan(scanID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, deletion)
+}
+
+// Restore handles undoing the deletion of a scan within its retention window
+// POST /api/v1/scans/:id/restore
+func (h *ScanHandler) Restore(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	scan, err := h.scanService.RestoreScan(scanID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Deleted scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to restore scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, scan)
+}
+
+// Resume handles re-running the unfinished checks of a failed scan
+// POST /api/v1/scans/:id/resume
+func (h *ScanHandler) Resume(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	resume, err := h.scanService.ResumeScan(scanID, organizationID)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		if err == services.ErrScanNotResumable {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to resume scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resume)
+}
+
+// WorkerScan returns the current status, checks and config of a scan to the
+// worker running it. The scan ID is verified by WorkerAuthMiddleware.
+// GET /api/v1/internal/scans/:id
+func (h *ScanHandler) WorkerScan(c *gin.Context) {
+	scanID := c.MustGet("scan_id").(uuid.UUID)
+
+	task, err := h.scanService.WorkerScan(scanID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// WorkerClientCertificate returns the decrypted client certificate and key
+// of a scan to the worker running it. The scan ID is verified by
+// WorkerAuthMiddleware.
+// GET /api/v1/internal/scans/:id/client-certificate
+func (h *ScanHandler) WorkerClientCertificate(c *gin.Context) {
+	scanID := c.MustGet("scan_id").(uuid.UUID)
+
+	material, err := h.scanService.WorkerClientCertificate(scanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScanNotFound), errors.Is(err, services.ErrNoClientCert):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrScanFinished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load client certificate"})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, material)
+}
+
+// IngestResults handles a worker's report of results, progress and status.
+// The scan ID is verified by WorkerAuthMiddleware.
+// POST /api/v1/internal/scans/:id/results
+func (h *ScanHandler) IngestResults(c *gin.Context) {
+	scanID := c.MustGet("scan_id").(uuid.UUID)
+
+	var req services.IngestResultsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	scan, err := h.scanService.IngestResults(scanID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		case errors.Is(err, services.ErrScanFinished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidScanResult):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record scan results"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scan_id":  scan.ID,
+		"status":   scan.Status,
+		"progress": scan.Progress,
+	})
+}
+
 // Cancel handles cancelling a scan
 // POST /api/v1/scans/:id/cancel
 func (h *ScanHandler) Cancel(c *gin.Context) {
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/api/middleware"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+	"publicscannerapi/internal/services"
+)
+
+// completedScanRow returns the row of a completed headers scan of
+// organizationID
+func completedScanRow(id, organizationID uuid.UUID) []driver.Value {
+	url, now := "https://example.com", time.Now()
+	return repositorytest.ScanJobRow(&models.ScanJob{
+		ID: id, URL: &url, OrganizationID: organizationID, InitiatedBy: uuid.New(),
+		Status: models.ScanStatusCompleted, Progress: 100, Checks: []string{"headers"},
+		StartedAt: &now, CompletedAt: &now,
+	})
+}
+
+// newTestDB returns a mocked database closed when the test ends
+func newTestDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+// newTestScanHandler returns a scan handler whose repositories use db
+func newTestScanHandler(db *sql.DB) *ScanHandler {
+	scanService := services.NewScanService(
+		repository.NewScanRepository(db),
+		repository.NewTargetRepository(db),
+		nil,
+		repository.NewSeverityOverrideRepository(db),
+		repository.NewWordlistRepository(db),
+		repository.NewCampaignRepository(db),
+		nil,
+		nil,
+		services.AddressPolicy{},
+		"",
+		0,
+	)
+	return NewScanHandler(scanService)
+}
+
+// withOrganization sets the organization an authenticated request is scoped to
+func withOrganization(organizationID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("organization_id", organizationID)
+		c.Next()
+	}
+}
+
+func TestScanHandlerListPagination(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := newTestScanHandler(db)
+	organizationID := uuid.New()
+
+	router := gin.New()
+	router.GET("/scans", withOrganization(organizationID), handler.List)
+
+	tests := []struct {
+		query   string
+		rows    int
+		hasMore bool
+	}{
+		{"limit=2", 2, true},
+		{"limit=2&offset=2", 1, false},
+	}
+
+	for _, tt := range tests {
+		rows := sqlmock.NewRows(repositorytest.ScanJobColumns)
+		for i := 0; i < tt.rows; i++ {
+			rows.AddRow(completedScanRow(uuid.New(), organizationID)...)
+		}
+		mock.ExpectQuery(`FROM scan_jobs\s+WHERE .*\s+ORDER BY .*\s+LIMIT`).WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM scan_jobs`).WithArgs(organizationID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/scans?"+tt.query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", tt.query, w.Code, w.Body)
+		}
+
+		var page struct {
+			Scans   []json.RawMessage `json:"scans"`
+			Total   int               `json:"total"`
+			HasMore bool              `json:"has_more"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Scans) != tt.rows || page.Total != 3 || page.HasMore != tt.hasMore {
+			t.Errorf("%s: %d scans, total %d, has_more %v; want %d, 3, %v",
+				tt.query, len(page.Scans), page.Total, page.HasMore, tt.rows, tt.hasMore)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// withUser authenticates a request as userID in organizationID
+func withUser(userID, organizationID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("organization_id", organizationID)
+		c.Next()
+	}
+}
+
+func TestScanHandlerDeleteRequiresAdmin(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := newTestScanHandler(db)
+	userID, organizationID := uuid.New(), uuid.New()
+
+	router := gin.New()
+	router.DELETE("/scans/:id", withUser(userID, organizationID),
+		middleware.RequireRole(repository.NewUserRepository(db), models.RoleAdmin), handler.Delete)
+
+	for _, role := range []models.Role{models.RoleViewer, models.RoleMember} {
+		mock.ExpectQuery(`SELECT role\s+FROM organization_members`).WithArgs(userID, organizationID).
+			WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(string(role)))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/scans/"+uuid.NewString(), nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403", role, w.Code)
+		}
+	}
+
+	// The scan was never looked up, let alone deleted
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// ScheduleHandler handles recurring scheduled scan endpoints
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+}
+
+// NewScheduleHandler creates a new scheduled scan handler
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// Create handles creating a recurring scan of a target
+// POST /api/v1/schedules
+func (h *ScheduleHandler) Create(c *gin.Context) {
+	var req services.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	schedule, err := h.scheduleService.CreateSchedule(&req, userID, organizationID)
+	if err != nil {
+		if respondValidationErrors(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create scheduled scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// List handles listing an organization's scheduled scans
+// GET /api/v1/schedules
+func (h *ScheduleHandler) List(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	schedules, err := h.scheduleService.ListSchedules(organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve scheduled scans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedules": schedules,
+		"total":     len(schedules),
+	})
+}
+
+// Get handles retrieving a single scheduled scan
+// GET /api/v1/schedules/:id
+func (h *ScheduleHandler) Get(c *gin.Context) {
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid schedule ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	schedule, err := h.scheduleService.GetSchedule(scheduleID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrScheduleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled scan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve scheduled scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// Delete handles deleting a scheduled scan; scans it started are kept
+// DELETE /api/v1/schedules/:id
+func (h *ScheduleHandler) Delete(c *gin.Context) {
+	scheduleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid schedule ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	if err := h.scheduleService.DeleteSchedule(scheduleID, organizationID); err != nil {
+		if errors.Is(err, services.ErrScheduleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled scan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete scheduled scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Scheduled scan deleted successfully",
+	})
+}
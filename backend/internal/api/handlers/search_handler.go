@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// SearchHandler handles search endpoints
+type SearchHandler struct {
+	scanService *services.ScanService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(scanService *services.ScanService) *SearchHandler {
+	return &SearchHandler{
+		scanService: scanService,
+	}
+}
+
+// Results handles searching the data of scan results, either with a query
+// or with a path and the value it must hold
+// GET /api/v1/search/results?q=nginx
+// GET /api/v1/search/results?path=open_ports[].port&value=22
+func (h *SearchHandler) Results(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be between 1 and 200",
+		})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "offset must not be negative",
+		})
+		return
+	}
+
+	var page *services.ResultSearchPage
+	if path, ok := c.GetQuery("path"); ok {
+		page, err = h.scanService.SearchResultsByPath(organizationID, path, c.Query("value"), limit, offset)
+	} else {
+		page, err = h.scanService.SearchResults(organizationID, c.Query("q"), limit, offset)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to search scan results",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// ServiceAccountHandler handles service account endpoints
+type ServiceAccountHandler struct {
+	accountService *services.ServiceAccountService
+}
+
+// NewServiceAccountHandler creates a new service account handler
+func NewServiceAccountHandler(accountService *services.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		accountService: accountService,
+	}
+}
+
+// respondServiceAccountError maps service account errors to HTTP responses
+func respondServiceAccountError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrServiceAccountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service account not found"})
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+	default:
+		if respondValidationErrors(c, err) {
+			return
+		}
+		respondOrganizationError(c, err, fallback)
+	}
+}
+
+// parseServiceAccountPath reads the organization and service account IDs
+// from the path, responding with 400 when either is malformed
+func parseServiceAccountPath(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	accountID, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid service account ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return organizationID, accountID, true
+}
+
+// Create handles creating a service account
+// POST /api/v1/organizations/:id/service-accounts
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	account, err := h.accountService.CreateServiceAccount(organizationID, userID, &req)
+	if err != nil {
+		respondServiceAccountError(c, err, "Failed to create service account")
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+// List handles listing an organization's service accounts
+// GET /api/v1/organizations/:id/service-accounts
+func (h *ServiceAccountHandler) List(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	accounts, err := h.accountService.ListServiceAccounts(organizationID, userID)
+	if err != nil {
+		respondServiceAccountError(c, err, "Failed to retrieve service accounts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"service_accounts": accounts,
+		"total":            len(accounts),
+	})
+}
+
+// Deactivate handles disabling a service account and revoking its keys
+// DELETE /api/v1/organizations/:id/service-accounts/:accountId
+func (h *ServiceAccountHandler) Deactivate(c *gin.Context) {
+	organizationID, accountID, ok := parseServiceAccountPath(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.accountService.DeactivateServiceAccount(organizationID, userID, accountID); err != nil {
+		respondServiceAccountError(c, err, "Failed to deactivate service account")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Service account deactivated successfully",
+	})
+}
+
+// CreateKey handles issuing an API key for a service account
+// POST /api/v1/organizations/:id/service-accounts/:accountId/keys
+func (h *ServiceAccountHandler) CreateKey(c *gin.Context) {
+	organizationID, accountID, ok := parseServiceAccountPath(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	key, err := h.accountService.CreateAPIKey(organizationID, userID, accountID, &req)
+	if err != nil {
+		respondServiceAccountError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// IssueKey handles issuing an API key for a service account of the
+// organization in the caller's token
+// POST /api/v1/api-keys
+func (h *ServiceAccountHandler) IssueKey(c *gin.Context) {
+	var req services.IssueAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	key, err := h.accountService.CreateAPIKey(organizationID, userID, req.ServiceAccountID, &req.CreateAPIKeyRequest)
+	if err != nil {
+		respondServiceAccountError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// ListKeys handles listing a service account's API keys
+// GET /api/v1/organizations/:id/service-accounts/:accountId/keys
+func (h *ServiceAccountHandler) ListKeys(c *gin.Context) {
+	organizationID, accountID, ok := parseServiceAccountPath(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	keys, err := h.accountService.ListAPIKeys(organizationID, userID, accountID)
+	if err != nil {
+		respondServiceAccountError(c, err, "Failed to retrieve API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"total": len(keys),
+	})
+}
+
+// RevokeKey handles revoking a service account's API key
+// DELETE /api/v1/organizations/:id/service-accounts/:accountId/keys/:keyId
+func (h *ServiceAccountHandler) RevokeKey(c *gin.Context) {
+	organizationID, accountID, ok := parseServiceAccountPath(c)
+	if !ok {
+		return
+	}
+
+	keyID, err := uuid.Parse(c.Param("keyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid API key ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.accountService.RevokeAPIKey(organizationID, userID, accountID, keyID); err != nil {
+		respondServiceAccountError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
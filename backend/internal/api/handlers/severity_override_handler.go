@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// SeverityOverrideHandler handles severity override rule endpoints
+type SeverityOverrideHandler struct {
+	overrideService *services.SeverityOverrideService
+}
+
+// NewSeverityOverrideHandler creates a new severity override handler
+func NewSeverityOverrideHandler(overrideService *services.SeverityOverrideService) *SeverityOverrideHandler {
+	return &SeverityOverrideHandler{
+		overrideService: overrideService,
+	}
+}
+
+// respondSeverityOverrideError maps severity override errors to HTTP responses
+func respondSeverityOverrideError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrSeverityOverrideNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Severity override not found"})
+	case errors.Is(err, services.ErrSeverityOverrideExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		if respondValidationErrors(c, err) {
+			return
+		}
+		respondOrganizationError(c, err, fallback)
+	}
+}
+
+// List handles listing an organization's severity override rules
+// GET /api/v1/organizations/:id/severity-overrides
+func (h *SeverityOverrideHandler) List(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rules, err := h.overrideService.ListOverrides(organizationID, userID)
+	if err != nil {
+		respondSeverityOverrideError(c, err, "Failed to retrieve severity overrides")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"overrides": rules,
+		"total":     len(rules),
+	})
+}
+
+// Create handles adding a severity override rule
+// POST /api/v1/organizations/:id/severity-overrides
+func (h *SeverityOverrideHandler) Create(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreateSeverityOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	rule, err := h.overrideService.CreateOverride(organizationID, userID, &req)
+	if err != nil {
+		respondSeverityOverrideError(c, err, "Failed to create severity override")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// Delete handles removing a severity override rule
+// DELETE /api/v1/organizations/:id/severity-overrides/:overrideId
+func (h *SeverityOverrideHandler) Delete(c *gin.Context) {
+	organizationID, ok := parseOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	ruleID, err := uuid.Parse(c.Param("overrideId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid severity override ID",
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	if err := h.overrideService.DeleteOverride(organizationID, userID, ruleID); err != nil {
+		respondSeverityOverrideError(c, err, "Failed to delete severity override")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Severity override deleted successfully",
+	})
+}
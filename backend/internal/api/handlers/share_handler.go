@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// ShareHandler handles scan share link endpoints
+type ShareHandler struct {
+	shareService *services.ShareService
+}
+
+// NewShareHandler creates a new scan share handler
+func NewShareHandler(shareService *services.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// Create handles minting a read-only share link for a scan
+// POST /api/v1/scans/:id/share
+func (h *ShareHandler) Create(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	var req services.CreateShareRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	share, token, err := h.shareService.CreateShare(scanID, userID, organizationID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidShareTTL):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrScanNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scan not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share link"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"share": share,
+		"token": token,
+		"path":  "/api/v1/shared/scans/" + token,
+	})
+}
+
+// List handles listing the share links of a scan
+// GET /api/v1/scans/:id/shares
+func (h *ShareHandler) List(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	shares, err := h.shareService.ListShares(scanID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Scan not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve share links",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"shares": shares,
+		"total":  len(shares),
+	})
+}
+
+// Revoke handles revoking a share link
+// DELETE /api/v1/scans/:id/shares/:shareId
+func (h *ShareHandler) Revoke(c *gin.Context) {
+	scanID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scan ID",
+		})
+		return
+	}
+
+	shareID, err := uuid.Parse(c.Param("shareId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid share ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	if err := h.shareService.RevokeShare(scanID, shareID, organizationID); err != nil {
+		if errors.Is(err, services.ErrShareNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Share link not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke share link",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Share link revoked successfully",
+	})
+}
+
+// View handles the public, unauthenticated view of a shared scan
+// GET /api/v1/shared/scans/:token
+func (h *ShareHandler) View(c *gin.Context) {
+	// The token is a credential; keep it out of caches and referrers
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	view, err := h.shareService.GetSharedScan(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrShareNotFound) || errors.Is(err, services.ErrScanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Shared scan not found or link expired",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve shared scan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
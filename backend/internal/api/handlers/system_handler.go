@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/config"
+	"publicscannerapi/internal/services"
+)
+
+// SystemHandler handles operator-facing system endpoints
+type SystemHandler struct {
+	cfg          *config.Config
+	queueMonitor *services.QueueMonitor
+}
+
+// NewSystemHandler creates a new system handler
+func NewSystemHandler(cfg *config.Config, queueMonitor *services.QueueMonitor) *SystemHandler {
+	return &SystemHandler{
+		cfg:          cfg,
+		queueMonitor: queueMonitor,
+	}
+}
+
+// Config handles retrieving the effective configuration with secrets redacted
+// GET /api/v1/system/config
+func (h *SystemHandler) Config(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"config": h.cfg.Sanitized(),
+	})
+}
+
+// Queue handles retrieving the scan queue depth and worker backlog
+// GET /api/v1/system/queue
+func (h *SystemHandler) Queue(c *gin.Context) {
+	stats, err := h.queueMonitor.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Failed to retrieve queue statistics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
 	"publicscannerapi/internal/services"
 )
 
@@ -39,6 +45,9 @@ func (h *TargetHandler) Create(c *gin.Context) {
 
 	target, err := h.targetService.CreateTarget(&req, userID, organizationID)
 	if err != nil {
+		if respondValidationErrors(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create target",
 		})
@@ -48,6 +57,42 @@ func (h *TargetHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, target)
 }
 
+// CreateBatch handles creating several targets at once
+// POST /api/v1/targets/batch
+func (h *TargetHandler) CreateBatch(c *gin.Context) {
+	var req struct {
+		Targets []services.CreateTargetRequest `json:"targets" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Targets) > services.MaxTargetBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("At most %d targets can be created per batch", services.MaxTargetBatchSize),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	result, err := h.targetService.CreateTargetsBatch(req.Targets, userID, organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create targets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
 // Get handles retrieving a single target
 // GET /api/v1/targets/:id
 func (h *TargetHandler) Get(c *gin.Context) {
@@ -72,13 +117,19 @@ func (h *TargetHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, target)
 }
 
-// List handles listing all targets for an organization
-// GET /api/v1/targets
+// List handles listing the targets of an organization, optionally searched
+// GET /api/v1/targets?q=example&tag=prod&tag=staging
 func (h *TargetHandler) List(c *gin.Context) {
 	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
-	targets, err := h.targetService.ListTargets(organizationID)
+	targets, err := h.targetService.ListTargets(organizationID, c.Query("q"), c.QueryArray("tag"))
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve targets",
 		})
@@ -111,10 +162,14 @@ func (h *TargetHandler) Update(c *gin.Context) {
 		return
 	}
 
+	userID := c.MustGet("user_id").(uuid.UUID)
 	organizationID := c.MustGet("organization_id").(uuid.UUID)
 
-	target, err := h.targetService.UpdateTarget(targetID, organizationID, &req)
+	target, err := h.targetService.UpdateTarget(targetID, organizationID, userID, &req)
 	if err != nil {
+		if respondValidationErrors(c, err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Target not found",
 		})
@@ -124,6 +179,39 @@ func (h *TargetHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, target)
 }
 
+// History handles retrieving a target's creation and change history
+// GET /api/v1/targets/:id/history
+func (h *TargetHandler) History(c *gin.Context) {
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid target ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	history, err := h.targetService.GetTargetHistory(targetID, organizationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Target not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve target history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+		"total":   len(history),
+	})
+}
+
 // Delete handles deleting a target
 // DELETE /api/v1/targets/:id
 func (h *TargetHandler) Delete(c *gin.Context) {
@@ -148,3 +236,148 @@ func (h *TargetHandler) Delete(c *gin.Context) {
 		"message": "Target deleted successfully",
 	})
 }
+
+// Restore handles restoring a deleted target
+// POST /api/v1/targets/:id/restore
+func (h *TargetHandler) Restore(c *gin.Context) {
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid target ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	target, err := h.targetService.RestoreTarget(targetID, organizationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Deleted target not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to restore target",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// Export handles exporting a target as a portable spec
+// GET /api/v1/targets/:id/export
+func (h *TargetHandler) Export(c *gin.Context) {
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid target ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	target, err := h.targetService.GetTarget(targetID, organizationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Target not found",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", target.Hostname+".json"))
+	c.JSON(http.StatusOK, target.Spec())
+}
+
+// Apply handles upserting targets from portable specs. The body is a single
+// exported spec, an array of specs, or {"targets": [...]}.
+// POST /api/v1/targets/apply
+func (h *TargetHandler) Apply(c *gin.Context) {
+	var raw json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	specs, err := decodeTargetSpecs(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid target specs",
+			"details": err.Error(),
+		})
+		return
+	}
+	if len(specs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "At least one target spec is required",
+		})
+		return
+	}
+	if len(specs) > services.MaxTargetBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("At most %d targets can be applied at once", services.MaxTargetBatchSize),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	result, err := h.targetService.ApplyTargets(specs, userID, organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to apply targets",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// decodeTargetSpecs accepts a single spec, an array of specs or an object
+// wrapping them in "targets"
+func decodeTargetSpecs(raw json.RawMessage) ([]models.TargetSpec, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var specs []models.TargetSpec
+		err := json.Unmarshal(trimmed, &specs)
+		return specs, err
+	}
+
+	var wrapper struct {
+		Targets *[]models.TargetSpec `json:"targets"`
+	}
+	if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+		return nil, err
+	}
+	if wrapper.Targets != nil {
+		return *wrapper.Targets, nil
+	}
+
+	var spec models.TargetSpec
+	if err := json.Unmarshal(trimmed, &spec); err != nil {
+		return nil, err
+	}
+	return []models.TargetSpec{spec}, nil
+}
+
+// respondValidationErrors writes a 400 listing field-level errors when err
+// carries them, reporting whether it did
+func respondValidationErrors(c *gin.Context, err error) bool {
+	var validationErrs services.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":  "Validation failed",
+		"fields": validationErrs,
+	})
+	return true
+}
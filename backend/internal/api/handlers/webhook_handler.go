@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// WebhookHandler handles webhook endpoints
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Create handles webhook creation. The signing secret is only returned here.
+// POST /api/v1/webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req services.CreateWebhookRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	webhook, err := h.webhookService.CreateWebhook(&req, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebhookEvent) || errors.Is(err, services.ErrInvalidWebhookURL) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	})
+}
+
+// Get handles retrieving a single webhook
+// GET /api/v1/webhooks/:id
+func (h *WebhookHandler) Get(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	webhook, err := h.webhookService.GetWebhook(webhookID, organizationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// List handles listing all webhooks for an organization
+// GET /api/v1/webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	webhooks, err := h.webhookService.ListWebhooks(organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve webhooks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+		"total":    len(webhooks),
+	})
+}
+
+// Delete handles deleting a webhook
+// DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	if err := h.webhookService.DeleteWebhook(webhookID, organizationID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListFailures handles listing a webhook's dead-lettered deliveries. Resolved
+// failures are included with ?include_resolved=true.
+// GET /api/v1/webhooks/:id/failures
+func (h *WebhookHandler) ListFailures(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+	includeResolved := c.Query("include_resolved") == "true"
+
+	failures, err := h.webhookService.ListFailures(webhookID, organizationID, includeResolved)
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve webhook failures",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"failures": failures,
+		"total":    len(failures),
+	})
+}
+
+// RetryFailure handles manually redelivering a dead-lettered event
+// POST /api/v1/webhooks/:id/failures/:failureId/retry
+func (h *WebhookHandler) RetryFailure(c *gin.Context) {
+	webhookID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	failureID, err := uuid.Parse(c.Param("failureId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid failure ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	failure, err := h.webhookService.RetryFailure(webhookID, failureID, organizationID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWebhookNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		case errors.Is(err, services.ErrWebhookFailureNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook failure not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry webhook delivery"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivered": failure.ResolvedAt != nil,
+		"failure":   failure,
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/services"
+)
+
+// WordlistHandler handles bruteforce wordlist endpoints
+type WordlistHandler struct {
+	wordlistService *services.WordlistService
+	scanService     *services.ScanService
+}
+
+// NewWordlistHandler creates a new wordlist handler
+func NewWordlistHandler(wordlistService *services.WordlistService, scanService *services.ScanService) *WordlistHandler {
+	return &WordlistHandler{
+		wordlistService: wordlistService,
+		scanService:     scanService,
+	}
+}
+
+// respondWordlistError maps wordlist service errors to responses
+func respondWordlistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrWordlistNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wordlist not found"})
+	case errors.Is(err, services.ErrWordlistInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": "Wordlist is used by scheduled, queued or running scans"})
+	case errors.Is(err, services.ErrWordlistTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Wordlist exceeds size limit"})
+	case errors.Is(err, services.ErrInvalidWordlist):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// Upload handles uploading a plain-text wordlist as multipart field "file",
+// with an optional "name" field
+// POST /api/v1/wordlists
+func (h *WordlistHandler) Upload(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "A multipart file field named \"file\" is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Failed to read uploaded file",
+		})
+		return
+	}
+	defer file.Close()
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	wordlist, err := h.wordlistService.Upload(organizationID, userID, c.PostForm("name"), fileHeader.Filename, file)
+	if err != nil {
+		respondWordlistError(c, err, "Failed to store wordlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, wordlist)
+}
+
+// List handles listing an organization's wordlists with their size, line
+// count and whether active scans use them
+// GET /api/v1/wordlists
+func (h *WordlistHandler) List(c *gin.Context) {
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	wordlists, err := h.wordlistService.ListWordlists(organizationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve wordlists",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wordlists": wordlists,
+		"total":     len(wordlists),
+	})
+}
+
+// Get handles retrieving a single wordlist
+// GET /api/v1/wordlists/:id
+func (h *WordlistHandler) Get(c *gin.Context) {
+	wordlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wordlist ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	wordlist, err := h.wordlistService.GetWordlist(wordlistID, organizationID)
+	if err != nil {
+		respondWordlistError(c, err, "Failed to retrieve wordlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, wordlist)
+}
+
+// Delete handles deleting a wordlist that no active scan uses
+// DELETE /api/v1/wordlists/:id
+func (h *WordlistHandler) Delete(c *gin.Context) {
+	wordlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wordlist ID",
+		})
+		return
+	}
+
+	organizationID := c.MustGet("organization_id").(uuid.UUID)
+
+	if err := h.wordlistService.DeleteWordlist(wordlistID, organizationID); err != nil {
+		respondWordlistError(c, err, "Failed to delete wordlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Wordlist deleted successfully",
+	})
+}
+
+// WorkerDownload streams the wordlist of a scan to the worker running it.
+// The scan ID is verified by WorkerAuthMiddleware.
+// GET /api/v1/internal/scans/:id/wordlist
+func (h *WordlistHandler) WorkerDownload(c *gin.Context) {
+	scanID := c.MustGet("scan_id").(uuid.UUID)
+
+	wordlistID, organizationID, err := h.scanService.WorkerWordlist(scanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrScanNotFound), errors.Is(err, services.ErrNoWordlist):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrScanFinished):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load wordlist"})
+		}
+		return
+	}
+
+	wordlist, object, err := h.wordlistService.Open(wordlistID, organizationID)
+	if err != nil {
+		respondWordlistError(c, err, "Failed to load wordlist")
+		return
+	}
+	defer object.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	http.ServeContent(c.Writer, c.Request, wordlist.Name, time.Time{}, object)
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// AuditMiddleware records every state-changing request in the audit trail,
+// attributed to the authenticated user or service account. It must run
+// after AuthMiddleware. Failing to write an entry is logged and does not
+// affect the response.
+func AuditMiddleware(auditRepo *repository.AuditRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return
+		}
+
+		userID, ok := c.Get("user_id")
+		if !ok {
+			return
+		}
+		actor := userID.(uuid.UUID)
+
+		entry := &models.AuditLog{
+			ID:        uuid.New(),
+			UserID:    &actor,
+			ActorType: c.GetString("principal_type"),
+			Action:    c.Request.Method + " " + c.FullPath(),
+			Metadata: map[string]interface{}{
+				"status":     c.Writer.Status(),
+				"request_id": c.GetString("request_id"),
+			},
+		}
+		if entry.ActorType == "" {
+			entry.ActorType = models.PrincipalUser
+		}
+		if organizationID, ok := c.Get("organization_id"); ok {
+			orgID := organizationID.(uuid.UUID)
+			entry.OrganizationID = &orgID
+		}
+		if resourceType := auditResourceType(c.FullPath()); resourceType != "" {
+			entry.ResourceType = &resourceType
+		}
+		if resourceID, err := uuid.Parse(c.Param("id")); err == nil {
+			entry.ResourceID = &resourceID
+		}
+		if ip := c.ClientIP(); ip != "" {
+			entry.IPAddress = &ip
+		}
+		if userAgent := c.Request.UserAgent(); userAgent != "" {
+			entry.UserAgent = &userAgent
+		}
+
+		if err := auditRepo.Create(entry); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to write audit log", "action", entry.Action, "error", err)
+		}
+	}
+}
+
+// auditResourceType returns the top-level resource of a route, e.g. "scans"
+// for /api/v1/scans/:id/cancel
+func auditResourceType(route string) string {
+	segments := strings.Split(strings.TrimPrefix(route, "/api/v1/"), "/")
+	if len(segments) == 0 || strings.HasPrefix(segments[0], ":") {
+		return ""
+	}
+	return segments[0]
+}
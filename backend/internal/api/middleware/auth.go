@@ -1,15 +1,30 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/models"
 	"publicscannerapi/pkg/auth"
 )
 
-// AuthMiddleware creates authentication middleware
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// APIKeyAuthenticator resolves API keys to the claims of their principal
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*auth.TokenClaims, error)
+}
+
+// RevocationChecker reports whether an access token was revoked by its JWT ID
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
+// AuthMiddleware creates authentication middleware. Bearer credentials
+// carrying the API key prefix are resolved through apiKeys; anything else
+// must be a JWT access token that revoked does not list.
+func AuthMiddleware(jwtSecret string, apiKeys APIKeyAuthenticator, revoked RevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -34,8 +49,15 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		token := parts[1]
 
 		// Validate token
-		claims, err := auth.ValidateToken(token, jwtSecret)
-		if err != nil {
+		var claims *auth.TokenClaims
+		var err error
+		if auth.IsAPIKey(token) && apiKeys != nil {
+			claims, err = apiKeys.AuthenticateAPIKey(token)
+		} else {
+			claims, err = auth.ValidateToken(token, jwtSecret)
+		}
+		// Refresh tokens only work against /auth/refresh
+		if err != nil || claims.TokenType == auth.TokenTypeRefresh {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid or expired token",
 			})
@@ -43,9 +65,37 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// Tokens revoked by logout are rejected until they expire. Lookup
+		// failures reject the request too, or logged out tokens would work
+		// again whenever the denylist is unreachable.
+		if claims.ID != "" && revoked != nil {
+			isRevoked, err := revoked.IsRevoked(c.Request.Context(), claims.ID)
+			if err != nil {
+				logging.FromContext(c.Request.Context()).Error("Token revocation lookup failed", "token_id", claims.ID, "error", err)
+				c.JSON(http.StatusServiceUnavailable, gin.H{
+					"error": "Unable to verify token",
+				})
+				c.Abort()
+				return
+			}
+			if isRevoked {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "Token has been revoked",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("token_claims", claims)
+		if claims.ServiceAccount {
+			c.Set("principal_type", models.PrincipalServiceAccount)
+		} else {
+			c.Set("principal_type", models.PrincipalUser)
+		}
 		if claims.OrganizationID != nil {
 			c.Set("organization_id", *claims.OrganizationID)
 		}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/services"
+	"publicscannerapi/pkg/auth"
+)
+
+var apiKeyColumns = []string{
+	"id", "user_id", "organization_id", "name", "key_hash", "last_used_at", "expires_at", "is_active", "created_at",
+}
+
+// apiKeyRouter serves one route behind AuthMiddleware, resolving API keys
+// through a service account service whose repository uses mock
+func apiKeyRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	accounts := services.NewServiceAccountService(nil, repository.NewServiceAccountRepository(db))
+
+	router := gin.New()
+	router.GET("/whoami", AuthMiddleware("jwt-secret", accounts, nil), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":         c.MustGet("user_id"),
+			"organization_id": c.MustGet("organization_id"),
+			"principal_type":  c.MustGet("principal_type"),
+		})
+	})
+	return router, mock
+}
+
+func TestAuthMiddlewareAcceptsAPIKey(t *testing.T) {
+	router, mock := apiKeyRouter(t)
+
+	key, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountID, organizationID := uuid.New(), uuid.New()
+	mock.ExpectQuery(`UPDATE api_keys k`).
+		WithArgs(hash, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+			AddRow(uuid.New().String(), accountID.String(), organizationID.String(), "ci", hash, time.Now(), nil, true, time.Now()))
+
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	want := `{"organization_id":"` + organizationID.String() + `","principal_type":"` + string(models.PrincipalServiceAccount) + `","user_id":"` + accountID.String() + `"}`
+	if w.Body.String() != want {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+}
+
+func TestAuthMiddlewareRejectsRevokedAPIKey(t *testing.T) {
+	router, mock := apiKeyRouter(t)
+
+	key, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Revoked keys are filtered out by is_active, so the lookup finds nothing
+	mock.ExpectQuery(`UPDATE api_keys k`).
+		WithArgs(hash, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", w.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthMiddlewareRejectsTokenAfterLogout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer rdb.Close()
+	denylist := services.NewTokenDenylist(rdb)
+	authService := services.NewAuthService(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), nil, denylist,
+		nil, nil, "jwt-secret", 15*time.Minute, 24*time.Hour)
+
+	router := gin.New()
+	router.GET("/whoami", AuthMiddleware("jwt-secret", nil, denylist), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	request := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	userID, sessionID := uuid.New(), uuid.NewString()
+	tokens, err := auth.GenerateSessionTokenPair(sessionID, userID, "ada@example.com", nil, "jwt-secret", 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := request(tokens.AccessToken); code != http.StatusNoContent {
+		t.Fatalf("before logout: status = %d", code)
+	}
+
+	claims, err := auth.ValidateToken(tokens.AccessToken, "jwt-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\)\s+WHERE user_id = \$1 AND session_id = \$2`).
+		WithArgs(userID, sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := authService.Logout(context.Background(), claims); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	if code := request(tokens.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("after logout: status = %d, want 401", code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthMiddlewareFailsClosedWhenDenylistIsUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+
+	router := gin.New()
+	router.GET("/whoami", AuthMiddleware("jwt-secret", nil, services.NewTokenDenylist(rdb)), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	tokens, err := auth.GenerateSessionTokenPair(uuid.NewString(), uuid.New(), "ada@example.com", nil, "jwt-secret", 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Close()
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
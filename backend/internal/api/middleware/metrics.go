@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/metrics"
+)
+
+// Metrics records the duration of every request by method, route template
+// and status, so /api/v1/scans/:id is one series however many scans exist
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		metrics.ObserveRequest(c.Request.Method, c.FullPath(), strconv.Itoa(c.Writer.Status()), time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequireOrganization rejects requests from users whose token carries no
+// organization, so org-scoped handlers can rely on "organization_id" being set
+func RequireOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if orgID, ok := c.Get("organization_id"); !ok || orgID.(uuid.UUID) == uuid.Nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "No organization found. Create or join an organization, then log in again.",
+				"code":  "no_organization",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/logging"
+)
+
+// QuotaMiddleware enforces a monthly API request quota per organization,
+// counted in Redis and reset at the start of each calendar month (UTC).
+// Every response carries the remaining allowance in X-Quota-* headers,
+// mirrored as X-RateLimit-* for clients that only understand those.
+// A limit of 0 disables the quota. It must run after AuthMiddleware.
+func QuotaMiddleware(rdb *redis.Client, monthlyLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID, exists := c.Get("organization_id")
+		if monthlyLimit <= 0 || !exists {
+			c.Next()
+			return
+		}
+
+		now := time.Now().UTC()
+		reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		key := fmt.Sprintf("quota:%s:%s", orgID.(uuid.UUID), now.Format("2006-01"))
+
+		ctx := c.Request.Context()
+		used, err := rdb.Incr(ctx, key).Result()
+		if err != nil {
+			// Fail open: an unavailable Redis must not take the API down
+			logging.FromContext(c.Request.Context()).Error("Quota check failed", "organization_id", orgID, "error", err)
+			c.Next()
+			return
+		}
+		if used == 1 {
+			rdb.ExpireAt(ctx, key, reset.Add(24*time.Hour))
+		}
+
+		remaining := int64(monthlyLimit) - used
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		limit := strconv.Itoa(monthlyLimit)
+		left := strconv.FormatInt(remaining, 10)
+		resetAt := strconv.FormatInt(reset.Unix(), 10)
+		for _, prefix := range []string{"X-Quota-", "X-RateLimit-"} {
+			c.Header(prefix+"Limit", limit)
+			c.Header(prefix+"Remaining", left)
+			c.Header(prefix+"Reset", resetAt)
+		}
+
+		if used > int64(monthlyLimit) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":    "Monthly API request quota exhausted",
+				"limit":    monthlyLimit,
+				"reset_at": reset,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/logging"
+)
+
+// RateLimitKeyFunc returns the value a request is rate limited by, or an
+// empty string when the request has none
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// ClientIPKey limits requests by client address. Behind a reverse proxy
+// the proxy must be listed in TRUSTED_PROXIES, otherwise every request
+// shares the proxy's address.
+func ClientIPKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitBodyLimit caps how much of a request body is read for a key
+const rateLimitBodyLimit = 64 << 10
+
+// JSONFieldKey limits requests by a string field of their JSON body,
+// compared case-insensitively. The body is left intact for the handler.
+func JSONFieldKey(field string) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, rateLimitBodyLimit+1))
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		if err != nil || len(body) > rateLimitBodyLimit {
+			return ""
+		}
+
+		var fields map[string]interface{}
+		if json.Unmarshal(body, &fields) != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			return ""
+		}
+		return field + ":" + value
+	}
+}
+
+// tokenBucketScript takes one token from the bucket at KEYS[1], holding up
+// to ARGV[1] tokens and refilled by one every ARGV[2] milliseconds, at time
+// ARGV[3] in milliseconds. It returns whether a token was taken and, if
+// not, how many milliseconds until the next one.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil or ts > now then
+	tokens = capacity
+	ts = now
+end
+
+local refill = math.floor((now - ts) / interval)
+if refill > 0 then
+	tokens = math.min(capacity, tokens + refill)
+	ts = ts + refill * interval
+end
+if tokens >= capacity then
+	ts = now
+end
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = interval - (now - ts)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], capacity * interval)
+return {allowed, wait}
+`)
+
+// RateLimit allows each key limit requests in a burst, refilled evenly over
+// window, with the buckets kept in Redis so the limit holds across API
+// instances. A request takes a token from the bucket of every key it has
+// and is rejected with 429 and Retry-After when any bucket is empty. scope
+// separates the buckets of different routes. A limit of 0 disables it.
+func RateLimit(rdb *redis.Client, scope string, limit int, window time.Duration, keys ...RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 || window <= 0 {
+			c.Next()
+			return
+		}
+
+		interval := window.Milliseconds() / int64(limit)
+		if interval < 1 {
+			interval = 1
+		}
+		now := time.Now().UnixMilli()
+
+		var retryAfter int64
+		for _, keyFunc := range keys {
+			key := keyFunc(c)
+			if key == "" {
+				continue
+			}
+
+			result, err := tokenBucketScript.Run(c.Request.Context(), rdb, []string{"ratelimit:" + scope + ":" + key}, limit, interval, now).Int64Slice()
+			if err != nil {
+				// Fail open: an unavailable Redis must not take the API down
+				logging.FromContext(c.Request.Context()).Error("Rate limit check failed", "scope", scope, "error", err)
+				continue
+			}
+			if result[0] == 0 && result[1] > retryAfter {
+				retryAfter = result[1]
+			}
+		}
+
+		if retryAfter > 0 {
+			seconds := int64(math.Ceil(float64(retryAfter) / 1000))
+			c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many requests, try again later",
+				"retry_after": seconds,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitRouter serves POST /login limited to limit requests per window
+// by client IP and email, with the buckets in an in-memory Redis
+func rateLimitRouter(t *testing.T, limit int, window time.Duration) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	router := gin.New()
+	router.POST("/login", RateLimit(rdb, "login", limit, window, ClientIPKey, JSONFieldKey("email")), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+// login posts email from addr and returns the response
+func login(router *gin.Engine, addr, email string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"`+email+`"}`))
+	req.RemoteAddr = addr + ":40000"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitRejectsRequestsOverTheLimit(t *testing.T) {
+	router := rateLimitRouter(t, 3, time.Minute)
+
+	for i := 1; i <= 3; i++ {
+		if w := login(router, "198.51.100.1", "user"+strconv.Itoa(i)+"@example.com"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d", i, w.Code)
+		}
+	}
+
+	w := login(router, "198.51.100.1", "user4@example.com")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request 4: status = %d, want 429", w.Code)
+	}
+	// One token comes back every 20 seconds
+	if retryAfter := w.Header().Get("Retry-After"); retryAfter != "20" {
+		t.Errorf("Retry-After = %q, want 20", retryAfter)
+	}
+
+	// Another client has its own bucket...
+	if w := login(router, "198.51.100.2", "someone@example.com"); w.Code != http.StatusNoContent {
+		t.Errorf("another client: status = %d", w.Code)
+	}
+	// ...but not for an email that ran out from elsewhere
+	for i := 0; i < 3; i++ {
+		login(router, "198.51.100.3", "ada@example.com")
+	}
+	if w := login(router, "198.51.100.4", "ADA@example.com"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same email from another client: status = %d, want 429", w.Code)
+	}
+}
+
+func TestRateLimitRefillsOverTheWindow(t *testing.T) {
+	// One token every 200ms
+	router := rateLimitRouter(t, 2, 400*time.Millisecond)
+
+	for i := 1; i <= 2; i++ {
+		login(router, "198.51.100.1", "ada@example.com")
+	}
+	if w := login(router, "198.51.100.1", "ada@example.com"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request 3: status = %d, want 429", w.Code)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	if w := login(router, "198.51.100.1", "ada@example.com"); w.Code != http.StatusNoContent {
+		t.Errorf("after a refill: status = %d, want 204", w.Code)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	router := rateLimitRouter(t, 0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		if w := login(router, "198.51.100.1", "ada@example.com"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d", i+1, w.Code)
+		}
+	}
+}
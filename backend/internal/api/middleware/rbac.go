@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// RequireRole creates middleware that only lets through callers whose role in
+// the active organization is at least minRole. It must run after AuthMiddleware.
+// The resolved role is stored in the context as "role".
+func RequireRole(userRepo *repository.UserRepository, minRole models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := resolveRole(c, userRepo)
+		if !ok {
+			return
+		}
+
+		if !role.AtLeast(minRole) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+// RequireDataAccess creates middleware that refuses members whose role does
+// not cover targets, scans and their results, such as billing. Service
+// accounts are never granted such a role and pass through. It must run after
+// AuthMiddleware.
+func RequireDataAccess(userRepo *repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, _ := c.Get("principal_type"); principal == models.PrincipalServiceAccount {
+			c.Next()
+			return
+		}
+
+		role, ok := resolveRole(c, userRepo)
+		if !ok {
+			return
+		}
+
+		if !role.CanAccessScanData() {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
+// resolveRole looks up the caller's role in the active organization. On
+// failure it writes the error response, aborts and returns false.
+func resolveRole(c *gin.Context, userRepo *repository.UserRepository) (models.Role, bool) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	orgID, exists := c.Get("organization_id")
+	if !exists {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "No organization found. Please log out and log back in.",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	role, err := userRepo.GetUserRole(userID, orgID.(uuid.UUID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotMember) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "You are not a member of this organization",
+			})
+			c.Abort()
+			return "", false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to verify permissions",
+		})
+		c.Abort()
+		return "", false
+	}
+
+	return role, true
+}
+
+// RequireSuperAdmin creates middleware that only lets through platform
+// operators. It must run after AuthMiddleware.
+func RequireSuperAdmin(userRepo *repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet("user_id").(uuid.UUID)
+
+		user, err := userRepo.GetByID(userID)
+		if err != nil || !user.IsActive || !user.IsSuperAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/logging"
+)
+
+func TestRequestLoggerTagsLogsWithRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/ping", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("Handling ping")
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name, inbound string
+		echoed        bool
+	}{
+		{"inbound ID", "trace-1234:abcd", true},
+		{"malformed inbound ID", "not a valid id\n", false},
+		{"no inbound ID", "", false},
+	}
+
+	for _, tt := range tests {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		if tt.inbound != "" {
+			req.Header.Set(RequestIDHeader, tt.inbound)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		requestID := w.Header().Get(RequestIDHeader)
+		if tt.echoed {
+			if requestID != tt.inbound {
+				t.Errorf("%s: %s = %q, want %q", tt.name, RequestIDHeader, requestID, tt.inbound)
+			}
+		} else if _, err := uuid.Parse(requestID); err != nil {
+			t.Errorf("%s: %s = %q, want a generated UUID", tt.name, RequestIDHeader, requestID)
+		}
+
+		// Both the handler's line and the access line carry the ID
+		var messages []string
+		scanner := bufio.NewScanner(&logs)
+		for scanner.Scan() {
+			var line struct {
+				Msg       string `json:"msg"`
+				RequestID string `json:"request_id"`
+				Status    int    `json:"status"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Fatalf("%s: log line %q is not JSON: %v", tt.name, scanner.Text(), err)
+			}
+			if line.RequestID != requestID {
+				t.Errorf("%s: %q logged with request_id %q, want %q", tt.name, line.Msg, line.RequestID, requestID)
+			}
+			if line.Msg == "Request handled" && line.Status != http.StatusNoContent {
+				t.Errorf("%s: logged status %d, want 204", tt.name, line.Status)
+			}
+			messages = append(messages, line.Msg)
+		}
+		if len(messages) != 2 || messages[0] != "Handling ping" || messages[1] != "Request handled" {
+			t.Errorf("%s: logged %q", tt.name, messages)
+		}
+	}
+}
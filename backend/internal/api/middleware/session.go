@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/services"
+	"publicscannerapi/pkg/auth"
+)
+
+// SessionIdleMiddleware rejects tokens whose session has been idle longer
+// than the organization's idle timeout and slides the idle window forward
+// otherwise. It must run after AuthMiddleware.
+func SessionIdleMiddleware(tracker *services.SessionTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := c.MustGet("token_claims").(*auth.TokenClaims)
+
+		if err := tracker.Touch(c.Request.Context(), claims); errors.Is(err, services.ErrSessionIdle) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Session expired due to inactivity",
+				"code":  "session_idle",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
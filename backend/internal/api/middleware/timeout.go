@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"publicscannerapi/internal/logging"
+)
+
+// Timeout creates middleware that gives the routes it guards a response
+// budget of d instead of the server-wide write timeout. The connection's
+// write deadline is moved to now+d, so a longer budget keeps large downloads
+// from being cut off and a shorter one tightens interactive routes, and the
+// request context carries the same deadline for handlers that watch it.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		deadline := time.Now().Add(d)
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(deadline); err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to set write deadline", "route", c.FullPath(), "error", err)
+		}
+
+		ctx, cancel := context.WithDeadline(c.Request.Context(), deadline)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
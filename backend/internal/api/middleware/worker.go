@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"publicscannerapi/pkg/auth"
+)
+
+// WorkerAuthMiddleware authenticates scan workers on internal endpoints. The
+// worker presents the per-scan token it received with its task as a Bearer
+// token, which only grants access to the scan named by the :id parameter.
+// The endpoints are disabled while no worker secret is configured.
+func WorkerAuthMiddleware(workerSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if workerSecret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Worker API is not configured",
+			})
+			c.Abort()
+			return
+		}
+
+		scanID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid scan ID",
+			})
+			c.Abort()
+			return
+		}
+
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || !auth.ValidateWorkerToken(token, scanID, workerSecret) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid worker token for this scan",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("scan_id", scanID)
+		c.Next()
+	}
+}
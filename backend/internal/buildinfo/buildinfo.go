@@ -0,0 +1,29 @@
+// Package buildinfo holds build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X publicscannerapi/internal/buildinfo.Version=1.2.0 \
+//	  -X publicscannerapi/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X publicscannerapi/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+package buildinfo
+
+// Set via -ldflags -X; the defaults identify a local, unstamped build
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info is the build metadata reported by the version endpoint
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get returns the metadata of the running build
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+	}
+}
@@ -1,40 +1,116 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"publicscannerapi/internal/buildinfo"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	App      AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	App       AppConfig
+	Plan      PlanConfig
+	Webhook   WebhookConfig
+	Target    TargetConfig
+	Worker    WorkerConfig
+	Retention RetentionConfig
+	Schedule  ScheduleConfig
+	RateLimit RateLimitConfig
+	Mail      MailConfig
 }
 
+// EnvironmentProduction is the ENVIRONMENT value of production deployments
+const EnvironmentProduction = "production"
+
+// Placeholder secrets used when none is configured; Validate rejects them
+// in production
+const (
+	defaultJWTSecret     = "your-secret-key-change-in-production"
+	defaultEncryptionKey = "your-encryption-key-change-in-production"
+)
+
+// MinJWTSecretLength is the shortest JWT secret accepted in production, in bytes
+const MinJWTSecretLength = 32
+
 type ServerConfig struct {
 	Port         string
-	Environment  string
+	Environment  string // production enables the stricter checks of Validate
 	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	WriteTimeout time.Duration // default response budget of every route
+	// AuthTimeout and ExportTimeout replace WriteTimeout for the auth routes
+	// and for report downloads and result exports respectively
+	AuthTimeout   time.Duration
+	ExportTimeout time.Duration
+	// StreamTimeout is how long a live progress stream may stay open
+	StreamTimeout time.Duration
+	// TrustedProxies are the addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is believed; empty trusts none
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
 	Host     string
 	Port     string
 	User     string
-	Password string
+	Password string `secret:"true"`
 	DBName   string
-	SSLMode  string
+	// SSLMode is the libpq sslmode: disable, require, verify-ca or verify-full
+	SSLMode string
+	// SSLRootCert is the CA certificate file the server certificate is
+	// verified against; empty uses the system roots
+	SSLRootCert string
+	// StatementTimeout aborts any single query running longer; zero disables it
+	StatementTimeout time.Duration
+	// MaintenanceStatementTimeout replaces StatementTimeout for background
+	// maintenance such as purging deleted scans
+	MaintenanceStatementTimeout time.Duration
+}
+
+// sslModes are the sslmode values supported by the Postgres driver
+var sslModes = map[string]bool{"disable": true, "require": true, "verify-ca": true, "verify-full": true}
+
+// DSN returns the Postgres connection string. Values are quoted, so
+// passwords may contain spaces and quotes.
+func (d DatabaseConfig) DSN() string {
+	params := []string{
+		"host=" + dsnValue(d.Host),
+		"port=" + dsnValue(d.Port),
+		"user=" + dsnValue(d.User),
+		"password=" + dsnValue(d.Password),
+		"dbname=" + dsnValue(d.DBName),
+		"sslmode=" + dsnValue(d.SSLMode),
+	}
+	if d.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+dsnValue(d.SSLRootCert))
+	}
+	// Unknown DSN keys are sent as run-time parameters, so every pooled
+	// connection starts with the limit; zero leaves the server default
+	if d.StatementTimeout > 0 {
+		params = append(params, fmt.Sprintf("statement_timeout=%d", d.StatementTimeout.Milliseconds()))
+	}
+
+	return strings.Join(params, " ")
+}
+
+// dsnValue single-quotes a connection string value
+func dsnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
 }
 
 type RedisConfig struct {
 	Host     string
 	Port     string
-	Password string
+	Password string `secret:"true"`
 	DB       int
 }
 
@@ -47,32 +123,128 @@ func (r RedisConfig) URL() string {
 }
 
 type JWTConfig struct {
-	Secret           string
-	AccessTokenTTL   time.Duration
-	RefreshTokenTTL  time.Duration
+	Secret          string `secret:"true"`
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 type AppConfig struct {
-	Name        string
-	Version     string
-	StoragePath string
+	Name              string
+	Version           string
+	StoragePath       string
+	AttachmentMaxSize int64  // bytes
+	WordlistMaxSize   int64  // bytes
+	EncryptionKey     string `secret:"true"` // seals secrets stored at rest, e.g. client certificate keys
+	LogLevel          string // debug, info, warn or error
+	LogFormat         string // json or text
+	// FrontendURL is the base of links to the web app sent by email
+	FrontendURL string
+	// PasswordResetTTL is how long a password reset link stays valid
+	PasswordResetTTL time.Duration
+	// EmailVerificationTTL is how long an email verification link stays valid
+	EmailVerificationTTL time.Duration
+	// RequireEmailVerification refuses logins until the user's email is verified
+	RequireEmailVerification bool
+}
+
+// PlanConfig holds the per-organization plan limits (0 means unlimited)
+type PlanConfig struct {
+	MaxTargets          int
+	MaxScansPerMonth    int
+	MaxStorageMB        int
+	MaxMembers          int
+	MaxRequestsPerMonth int // API requests per organization per month
+}
+
+// WebhookConfig holds webhook delivery settings
+type WebhookConfig struct {
+	ProgressMilestones []int         // progress percentages that fire scan.progress events
+	MaxAttempts        int           // delivery attempts before an event is dead-lettered
+	RetryDelay         time.Duration // initial backoff, doubled after each attempt
+}
+
+// TargetConfig holds target validation settings
+type TargetConfig struct {
+	MaxTags      int // tags per target
+	MaxTagLength int // characters per tag
+	// AllowPrivate lets targets and quick scans point at private, loopback
+	// and link-local addresses
+	AllowPrivate bool
+}
+
+// WorkerConfig holds settings for the internal worker API
+type WorkerConfig struct {
+	// Secret signs the per-scan tokens workers use to report results. It
+	// stays in the API; workers only ever see tokens. Empty disables the
+	// internal worker endpoints.
+	Secret string `secret:"true"`
+}
+
+// RetentionConfig holds data retention settings
+type RetentionConfig struct {
+	DeletedScans  time.Duration // how long soft-deleted scans can be restored before they are purged
+	PurgeInterval time.Duration
+	// ReportSweepInterval is how often report files without a report row are removed
+	ReportSweepInterval time.Duration
+}
+
+// ScheduleConfig holds settings for queueing scheduled scans
+type ScheduleConfig struct {
+	Interval time.Duration // how often due scheduled scans are queued
+	// ReaperInterval is how often queued and running scans are checked for
+	// a worker that stopped reporting
+	ReaperInterval time.Duration
+	// StaleTimeout is how long an active scan may go without an update
+	// before it is failed
+	StaleTimeout time.Duration
+}
+
+// RateLimitConfig holds the brute-force limits of the auth endpoints
+type RateLimitConfig struct {
+	// AuthLimit is how many login or registration attempts a client IP, and
+	// for logins an email address, may make per AuthWindow (0 disables it)
+	AuthLimit  int
+	AuthWindow time.Duration
+	// AuthByEmail also limits login attempts per email address
+	AuthByEmail bool
+	// LockoutAttempts consecutive failed logins lock an email for
+	// LockoutDuration (0 disables the lockout)
+	LockoutAttempts int
+	LockoutDuration time.Duration
+}
+
+// MailConfig holds the SMTP relay used for transactional emails. Without a
+// host emails are written to the log instead.
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string `secret:"true"`
+	From         string // bare sender address
 }
 
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8080"),
-			Environment:  getEnv("ENVIRONMENT", "development"),
-			ReadTimeout:  time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 10)) * time.Second,
-			WriteTimeout: time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
+			Port:           getEnv("PORT", "8080"),
+			Environment:    getEnv("ENVIRONMENT", "development"),
+			ReadTimeout:    time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 10)) * time.Second,
+			WriteTimeout:   time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
+			AuthTimeout:    time.Duration(getEnvAsInt("SERVER_AUTH_TIMEOUT", 5)) * time.Second,
+			ExportTimeout:  time.Duration(getEnvAsInt("SERVER_EXPORT_TIMEOUT", 300)) * time.Second,
+			StreamTimeout:  time.Duration(getEnvAsInt("SERVER_STREAM_TIMEOUT", 3600)) * time.Second,
+			TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", nil),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "publicscanner"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:                        getEnv("DB_HOST", "localhost"),
+			Port:                        getEnv("DB_PORT", "5432"),
+			User:                        getEnv("DB_USER", "postgres"),
+			Password:                    getEnv("DB_PASSWORD", "postgres"),
+			DBName:                      getEnv("DB_NAME", "publicscanner"),
+			SSLMode:                     getEnv("DB_SSLMODE", "disable"),
+			SSLRootCert:                 getEnv("DB_SSLROOTCERT", ""),
+			StatementTimeout:            time.Duration(getEnvAsInt("DB_STATEMENT_TIMEOUT", 30)) * time.Second,
+			MaintenanceStatementTimeout: time.Duration(getEnvAsInt("DB_MAINTENANCE_STATEMENT_TIMEOUT", 600)) * time.Second,
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -81,16 +253,187 @@ func Load() *Config {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			Secret:          getEnv("JWT_SECRET", defaultJWTSecret),
 			AccessTokenTTL:  time.Duration(getEnvAsInt("JWT_ACCESS_TTL", 15)) * time.Minute,
 			RefreshTokenTTL: time.Duration(getEnvAsInt("JWT_REFRESH_TTL", 7*24)) * time.Hour,
 		},
 		App: AppConfig{
-			Name:        "PublicScanner",
-			Version:     "1.0.0",
-			StoragePath: getEnv("STORAGE_PATH", "/opt/publicscannerdata"),
+			Name:                     "PublicScanner",
+			Version:                  buildinfo.Version,
+			StoragePath:              getEnv("STORAGE_PATH", "/opt/publicscannerdata"),
+			AttachmentMaxSize:        int64(getEnvAsInt("ATTACHMENT_MAX_SIZE_MB", 10)) * 1024 * 1024,
+			WordlistMaxSize:          int64(getEnvAsInt("WORDLIST_MAX_SIZE_MB", 50)) * 1024 * 1024,
+			EncryptionKey:            getEnv("ENCRYPTION_KEY", defaultEncryptionKey),
+			LogLevel:                 getEnv("LOG_LEVEL", "info"),
+			LogFormat:                getEnv("LOG_FORMAT", "json"),
+			FrontendURL:              strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+			PasswordResetTTL:         time.Duration(getEnvAsInt("PASSWORD_RESET_TTL", 30)) * time.Minute,
+			EmailVerificationTTL:     time.Duration(getEnvAsInt("EMAIL_VERIFICATION_TTL", 24)) * time.Hour,
+			RequireEmailVerification: getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", false),
+		},
+		Plan: PlanConfig{
+			MaxTargets:          getEnvAsInt("PLAN_MAX_TARGETS", 0),
+			MaxScansPerMonth:    getEnvAsInt("PLAN_MAX_SCANS_PER_MONTH", 0),
+			MaxStorageMB:        getEnvAsInt("PLAN_MAX_STORAGE_MB", 0),
+			MaxMembers:          getEnvAsInt("PLAN_MAX_MEMBERS", 0),
+			MaxRequestsPerMonth: getEnvAsInt("PLAN_MAX_REQUESTS_PER_MONTH", 0),
+		},
+		Webhook: WebhookConfig{
+			ProgressMilestones: getEnvAsIntSlice("WEBHOOK_PROGRESS_MILESTONES", []int{25, 50, 75}),
+			MaxAttempts:        getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 3),
+			RetryDelay:         time.Duration(getEnvAsInt("WEBHOOK_RETRY_DELAY", 1)) * time.Second,
+		},
+		Target: TargetConfig{
+			MaxTags:      getEnvAsInt("TARGET_MAX_TAGS", 20),
+			MaxTagLength: getEnvAsInt("TARGET_MAX_TAG_LENGTH", 50),
+			AllowPrivate: getEnvAsBool("ALLOW_PRIVATE_TARGETS", false),
+		},
+		Worker: WorkerConfig{
+			Secret: getEnv("WORKER_SECRET", ""),
 		},
+		Retention: RetentionConfig{
+			DeletedScans:        time.Duration(getEnvAsInt("SCAN_RETENTION_DAYS", 30)) * 24 * time.Hour,
+			PurgeInterval:       time.Duration(getEnvAsInt("SCAN_PURGE_INTERVAL", 60)) * time.Minute,
+			ReportSweepInterval: time.Duration(getEnvAsInt("REPORT_SWEEP_INTERVAL", 60)) * time.Minute,
+		},
+		Schedule: ScheduleConfig{
+			Interval:       time.Duration(getEnvAsInt("SCAN_SCHEDULER_INTERVAL", 30)) * time.Second,
+			ReaperInterval: time.Duration(getEnvAsInt("SCAN_REAPER_INTERVAL", 60)) * time.Second,
+			StaleTimeout:   time.Duration(getEnvAsInt("SCAN_STALE_TIMEOUT", 60)) * time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			AuthLimit:       getEnvAsInt("AUTH_RATE_LIMIT", 10),
+			AuthWindow:      time.Duration(getEnvAsInt("AUTH_RATE_LIMIT_WINDOW", 60)) * time.Second,
+			AuthByEmail:     getEnvAsBool("AUTH_RATE_LIMIT_BY_EMAIL", true),
+			LockoutAttempts: getEnvAsInt("AUTH_LOCKOUT_ATTEMPTS", 5),
+			LockoutDuration: time.Duration(getEnvAsInt("AUTH_LOCKOUT_DURATION", 15)) * time.Minute,
+		},
+		Mail: MailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("MAIL_FROM", "no-reply@publicscanner.local"),
+		},
+	}
+}
+
+// IsProduction reports whether the server runs in the production environment
+func (c *Config) IsProduction() bool {
+	return c.Server.Environment == EnvironmentProduction
+}
+
+// Validate reports every problem with the configuration that the server
+// must not start with. The database settings are always required and the
+// SSL mode must be one the driver supports; in
+// production the placeholder secrets, a JWT secret shorter than
+// MinJWTSecretLength and an unwritable storage path are rejected as well.
+func (c *Config) Validate() error {
+	var errs []error
+
+	required := []struct {
+		env   string
+		value string
+	}{
+		{"DB_HOST", c.Database.Host},
+		{"DB_PORT", c.Database.Port},
+		{"DB_USER", c.Database.User},
+		{"DB_NAME", c.Database.DBName},
 	}
+	for _, field := range required {
+		if strings.TrimSpace(field.value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.env))
+		}
+	}
+
+	if !sslModes[c.Database.SSLMode] {
+		errs = append(errs, fmt.Errorf("DB_SSLMODE must be disable, require, verify-ca or verify-full, got %q", c.Database.SSLMode))
+	}
+	if c.Database.SSLRootCert != "" {
+		if c.Database.SSLMode == "disable" {
+			errs = append(errs, errors.New("DB_SSLROOTCERT requires DB_SSLMODE other than disable"))
+		} else if _, err := os.Stat(c.Database.SSLRootCert); err != nil {
+			errs = append(errs, fmt.Errorf("DB_SSLROOTCERT: %w", err))
+		}
+	}
+
+	if c.IsProduction() {
+		switch {
+		case c.JWT.Secret == defaultJWTSecret:
+			errs = append(errs, errors.New("JWT_SECRET must be set in production"))
+		case len(c.JWT.Secret) < MinJWTSecretLength:
+			errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes in production", MinJWTSecretLength))
+		}
+
+		if c.App.EncryptionKey == defaultEncryptionKey {
+			errs = append(errs, errors.New("ENCRYPTION_KEY must be set in production"))
+		}
+
+		if err := checkWritable(c.App.StoragePath); err != nil {
+			errs = append(errs, fmt.Errorf("STORAGE_PATH is not writable: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkWritable creates dir if needed and writes a probe file into it
+func checkWritable(dir string) error {
+	if dir == "" {
+		return errors.New("path is empty")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
+
+// redactedValue replaces secret values in sanitized output
+const redactedValue = "[REDACTED]"
+
+// Sanitized returns the effective configuration as a map that is safe to
+// expose. Every field tagged `secret:"true"` is redacted (left empty when
+// unset so operators can still tell whether it was configured), and
+// durations are rendered as strings.
+func (c *Config) Sanitized() map[string]interface{} {
+	return sanitizeStruct(reflect.ValueOf(*c))
+}
+
+func sanitizeStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if value.IsZero() {
+				out[field.Name] = ""
+			} else {
+				out[field.Name] = redactedValue
+			}
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			out[field.Name] = value.Interface().(time.Duration).String()
+		case value.Kind() == reflect.Struct:
+			out[field.Name] = sanitizeStruct(value)
+		default:
+			out[field.Name] = value.Interface()
+		}
+	}
+
+	return out
 }
 
 func getEnv(key, defaultValue string) string {
@@ -107,3 +450,43 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(valueStr, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, part := range strings.Split(valueStr, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// validConfig returns a production configuration Validate accepts
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	return &Config{
+		Server: ServerConfig{Environment: EnvironmentProduction},
+		Database: DatabaseConfig{
+			Host:    "db.internal",
+			Port:    "5432",
+			User:    "scanner",
+			DBName:  "scanner",
+			SSLMode: "require",
+		},
+		JWT: JWTConfig{Secret: strings.Repeat("s", MinJWTSecretLength)},
+		App: AppConfig{StoragePath: t.TempDir(), EncryptionKey: "a-real-encryption-key"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig(t).Validate(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+
+	// A regular file where the storage directory should be
+	notADir := filepath.Join(t.TempDir(), "storage")
+	if err := os.WriteFile(notADir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		invalidate func(*Config)
+		want       string
+	}{
+		{"default JWT secret", func(c *Config) { c.JWT.Secret = defaultJWTSecret }, "JWT_SECRET must be set"},
+		{"short JWT secret", func(c *Config) { c.JWT.Secret = "short" }, "JWT_SECRET must be at least 32 bytes"},
+		{"default encryption key", func(c *Config) { c.App.EncryptionKey = defaultEncryptionKey }, "ENCRYPTION_KEY must be set"},
+		{"empty storage path", func(c *Config) { c.App.StoragePath = "" }, "STORAGE_PATH is not writable"},
+		{"storage path is a file", func(c *Config) { c.App.StoragePath = notADir }, "STORAGE_PATH is not writable"},
+		{"no database host", func(c *Config) { c.Database.Host = "" }, "DB_HOST is required"},
+		{"no database port", func(c *Config) { c.Database.Port = " " }, "DB_PORT is required"},
+		{"no database user", func(c *Config) { c.Database.User = "" }, "DB_USER is required"},
+		{"no database name", func(c *Config) { c.Database.DBName = "" }, "DB_NAME is required"},
+	}
+
+	for _, tt := range tests {
+		config := validConfig(t)
+		tt.invalidate(config)
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateOutsideProduction(t *testing.T) {
+	// Development keeps working on the placeholder secrets
+	config := validConfig(t)
+	config.Server.Environment = "development"
+	config.JWT.Secret = defaultJWTSecret
+	config.App.EncryptionKey = defaultEncryptionKey
+	config.App.StoragePath = ""
+	if err := config.Validate(); err != nil {
+		t.Errorf("development config: %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	config := validConfig(t)
+	config.JWT.Secret = defaultJWTSecret
+	config.Database.Host = ""
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate accepted a default secret and no database host")
+	}
+	for _, want := range []string{"JWT_SECRET", "DB_HOST"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
+
+func TestDatabaseConfigDSN(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DatabaseConfig
+		want   string
+	}{
+		{
+			"password with spaces and quotes",
+			DatabaseConfig{Host: "db.internal", Port: "5432", User: "scanner", Password: `it's a \secret`, DBName: "scanner", SSLMode: "require"},
+			`host='db.internal' port='5432' user='scanner' password='it\'s a \\secret' dbname='scanner' sslmode='require'`,
+		},
+		{
+			"verified with a root certificate",
+			DatabaseConfig{Host: "db.internal", Port: "5432", User: "scanner", DBName: "scanner", SSLMode: "verify-full", SSLRootCert: "/etc/ssl/db ca.pem"},
+			`host='db.internal' port='5432' user='scanner' password='' dbname='scanner' sslmode='verify-full' sslrootcert='/etc/ssl/db ca.pem'`,
+		},
+	}
+
+	for _, tt := range tests {
+		dsn := tt.config.DSN()
+		if dsn != tt.want {
+			t.Errorf("%s: DSN = %s, want %s", tt.name, dsn, tt.want)
+		}
+		// The driver parses it back without connecting
+		if _, err := pq.NewConnector(dsn); err != nil {
+			t.Errorf("%s: the driver rejects %s: %v", tt.name, dsn, err)
+		}
+	}
+}
+
+func TestValidateRejectsUnknownSSLMode(t *testing.T) {
+	for _, mode := range []string{"", "prefer", "REQUIRE", "verify_full"} {
+		config := validConfig(t)
+		config.Database.SSLMode = mode
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "DB_SSLMODE") {
+			t.Errorf("sslmode %q: error = %v, want DB_SSLMODE rejected", mode, err)
+		}
+	}
+
+	// A root certificate needs TLS and a file to read
+	config := validConfig(t)
+	config.Database.SSLMode = "disable"
+	config.Database.SSLRootCert = filepath.Join(t.TempDir(), "ca.pem")
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "DB_SSLROOTCERT") {
+		t.Errorf("root certificate without TLS: error = %v", err)
+	}
+	config.Database.SSLMode = "verify-full"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "DB_SSLROOTCERT") {
+		t.Errorf("missing root certificate: error = %v", err)
+	}
+}
+
+// setSecrets gives every field of v tagged `secret:"true"` a distinct value
+// and returns the values set
+func setSecrets(t *testing.T, v reflect.Value, path string) []string {
+	t.Helper()
+	var secrets []string
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		name := path + "." + field.Name
+		switch {
+		case field.Tag.Get("secret") == "true":
+			if value.Kind() != reflect.String {
+				t.Fatalf("%s: cannot set a %s secret", name, value.Kind())
+			}
+			secret := "secret-value" + strings.ReplaceAll(name, ".", "-")
+			value.SetString(secret)
+			secrets = append(secrets, secret)
+		case value.Kind() == reflect.Struct && field.IsExported():
+			secrets = append(secrets, setSecrets(t, value, name)...)
+		}
+	}
+	return secrets
+}
+
+func TestSanitizedRedactsEverySecret(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Redis = RedisConfig{Host: "redis.internal", Port: "6379"}
+	secrets := setSecrets(t, reflect.ValueOf(cfg).Elem(), "Config")
+	if len(secrets) == 0 {
+		t.Fatal("no secret fields found")
+	}
+
+	sanitized, err := json.Marshal(cfg.Sanitized())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Connection strings carry the passwords too
+	for _, value := range append(secrets, cfg.Redis.URL(), cfg.Database.DSN()) {
+		if strings.Contains(string(sanitized), value) {
+			t.Errorf("sanitized config contains %q: %s", value, sanitized)
+		}
+	}
+	if redacted := strings.Count(string(sanitized), redactedValue); redacted != len(secrets) {
+		t.Errorf("%d values redacted, want %d", redacted, len(secrets))
+	}
+}
@@ -0,0 +1,61 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+var (
+	ErrEmptyKey      = errors.New("encryption key must not be empty")
+	ErrMalformedData = errors.New("malformed ciphertext")
+)
+
+// Cipher seals secrets at rest with AES-256-GCM. Sealed values are the random
+// nonce followed by the ciphertext, so they can be stored in a single column.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher keyed with the SHA-256 digest of key
+func NewCipher(key string) (*Cipher, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	digest := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt seals plaintext
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens a value sealed by Encrypt
+func (c *Cipher) Decrypt(sealed []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrMalformedData
+	}
+
+	return c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
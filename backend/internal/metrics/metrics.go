@@ -0,0 +1,94 @@
+// Package metrics defines the Prometheus metrics exposed at /metrics
+package metrics
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "publicscanner"
+
+var (
+	scansCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_created_total",
+		Help:      "Scans created, counted once for each check they run.",
+	}, []string{"check_type"})
+
+	scansCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_completed_total",
+		Help:      "Scans completed, counted once for each check they ran.",
+	}, []string{"check_type"})
+
+	scansFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_failed_total",
+		Help:      "Scans failed, counted once for each check they ran.",
+	}, []string{"check_type"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ScanCreated counts a newly stored scan under each of its checks
+func ScanCreated(checks []string) {
+	for _, check := range checks {
+		scansCreated.WithLabelValues(check).Inc()
+	}
+}
+
+// ScanCompleted counts a scan that completed under each of its checks
+func ScanCompleted(checks []string) {
+	for _, check := range checks {
+		scansCompleted.WithLabelValues(check).Inc()
+	}
+}
+
+// ScanFailed counts a scan that failed under each of its checks
+func ScanFailed(checks []string) {
+	for _, check := range checks {
+		scansFailed.WithLabelValues(check).Inc()
+	}
+}
+
+// ObserveRequest records how long a request to route took. Requests that
+// matched no route share the route label "unmatched".
+func ObserveRequest(method, route, status string, seconds float64) {
+	if route == "" {
+		route = "unmatched"
+	}
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(seconds)
+}
+
+// RegisterRunningScans exposes the number of running scans, read through
+// count at scrape time. The count comes from the database, so every API
+// instance reports the same total.
+func RegisterRunningScans(count func() (int, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "scans_running",
+		Help:      "Scans currently running.",
+	}, func() float64 {
+		running, err := count()
+		if err != nil {
+			slog.Error("Failed to count running scans for metrics", "error", err)
+			return math.NaN()
+		}
+		return float64(running)
+	})
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AcknowledgementRule marks an organization's results of a check as
+// acknowledged, org-wide, while they carry no triage note of their own. A nil
+// MatchSeverity covers every severity of the check. A rule with a SnoozeUntil
+// time stops applying once it passes; one without it applies until removed.
+type AcknowledgementRule struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	CheckType      string     `json:"check_type" db:"check_type"`
+	MatchSeverity  *string    `json:"match_severity" db:"match_severity"`
+	Reason         string     `json:"reason" db:"reason"`
+	SnoozeUntil    *time.Time `json:"snooze_until" db:"snooze_until"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	// Active is false once the snooze has expired
+	Active bool `json:"active" db:"-"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanResultAttachment is a binary artifact (screenshot, capture, raw
+// output) attached to a scan result
+type ScanResultAttachment struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ResultID    uuid.UUID `json:"result_id" db:"result_id"`
+	ScanID      uuid.UUID `json:"scan_id" db:"scan_id"`
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	FileSize    int64     `json:"file_size" db:"file_size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	UploadedBy  uuid.UUID `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is one entry of an organization's audit trail
+type AuditLog struct {
+	ID             uuid.UUID              `json:"id" db:"id"`
+	UserID         *uuid.UUID             `json:"user_id,omitempty" db:"user_id"`
+	OrganizationID *uuid.UUID             `json:"organization_id,omitempty" db:"organization_id"`
+	ActorType      string                 `json:"actor_type" db:"actor_type"`
+	Action         string                 `json:"action" db:"action"`
+	ResourceType   *string                `json:"resource_type,omitempty" db:"resource_type"`
+	ResourceID     *uuid.UUID             `json:"resource_id,omitempty" db:"resource_id"`
+	IPAddress      *string                `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent      *string                `json:"user_agent,omitempty" db:"user_agent"`
+	Metadata       map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+}
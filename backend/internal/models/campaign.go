@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Campaign groups the scans of a named engagement, e.g. a red team
+// exercise, for engagement-scoped reporting
+type Campaign struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	Name           string     `json:"name" db:"name"`
+	Description    string     `json:"description" db:"description"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	// ScanCount counts the campaign's scans that are not deleted
+	ScanCount int `json:"scan_count" db:"-"`
+	// TargetCount counts the targets assigned to the campaign
+	TargetCount int `json:"target_count" db:"-"`
+}
+
+// CampaignTargetAssignment reports the outcome of a bulk target assignment
+type CampaignTargetAssignment struct {
+	Matched         int `json:"matched"`
+	Added           int `json:"added"`
+	AlreadyAssigned int `json:"already_assigned"`
+}
+
+// CampaignSummary is a campaign with the findings of its completed scans
+// rolled up by severity
+type CampaignSummary struct {
+	Campaign       *Campaign      `json:"campaign"`
+	CompletedScans int            `json:"completed_scans"`
+	Severity       SeverityCounts `json:"severity"`
+	RiskScore      int            `json:"risk_score"`
+}
+
+// CampaignHostRisk is the deduplicated findings of one host in a campaign
+type CampaignHostRisk struct {
+	Host       string         `json:"host"`
+	TargetID   *uuid.UUID     `json:"target_id,omitempty"` // nil for quick scans of a URL
+	TargetName *string        `json:"target_name,omitempty"`
+	Severity   SeverityCounts `json:"severity"`
+	RiskScore  int            `json:"risk_score"`
+}
+
+// CampaignRisk is a campaign's risk posture with each finding counted once
+// per host: for every host and check, only the latest completed result
+// contributes, however many scans of the host the campaign holds
+type CampaignRisk struct {
+	CampaignID uuid.UUID           `json:"campaign_id"`
+	Hosts      int                 `json:"hosts"`
+	Severity   SeverityCounts      `json:"severity"`
+	RiskScore  int                 `json:"risk_score"`
+	TopTargets []*CampaignHostRisk `json:"top_targets"`
+}
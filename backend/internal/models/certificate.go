@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientCertificate is an uploaded certificate/key pair used for mutual-TLS
+// scans. The key is stored encrypted and neither PEM block is ever returned
+// by the API; only identifying metadata is exposed.
+type ClientCertificate struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	OrganizationID uuid.UUID `json:"organization_id" db:"organization_id"`
+	Name           string    `json:"name" db:"name"`
+	Subject        string    `json:"subject" db:"subject"`
+	Issuer         string    `json:"issuer" db:"issuer"`
+	Fingerprint    string    `json:"fingerprint" db:"fingerprint"` // SHA-256 of the leaf certificate
+	NotBefore      time.Time `json:"not_before" db:"not_before"`
+	NotAfter       time.Time `json:"not_after" db:"not_after"`
+	CertificatePEM string    `json:"-" db:"certificate_pem"`
+	EncryptedKey   []byte    `json:"-" db:"encrypted_key"`
+	CreatedBy      uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// ClientCertificateMaterial is a decrypted certificate/key pair handed to workers
+type ClientCertificateMaterial struct {
+	CertificatePEM string `json:"certificate_pem"`
+	PrivateKeyPEM  string `json:"private_key_pem"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationStatus is derived from an invitation's timestamps
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationExpired  InvitationStatus = "expired"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// IsValid reports whether s is a known invitation status
+func (s InvitationStatus) IsValid() bool {
+	switch s {
+	case InvitationPending, InvitationAccepted, InvitationExpired, InvitationRevoked:
+		return true
+	}
+	return false
+}
+
+// Invitation invites an email address to join an organization with a role
+type Invitation struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	OrganizationID uuid.UUID        `json:"organization_id" db:"organization_id"`
+	Email          string           `json:"email" db:"email"`
+	Role           Role             `json:"role" db:"role"`
+	Status         InvitationStatus `json:"status" db:"-"`
+	TokenHash      string           `json:"-" db:"token_hash"`
+	InvitedBy      uuid.UUID        `json:"invited_by" db:"invited_by"`
+	ExpiresAt      time.Time        `json:"expires_at" db:"expires_at"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+}
+
+// InvitationCounts tallies an organization's invitations by status
+type InvitationCounts struct {
+	Pending  int `json:"pending"`
+	Accepted int `json:"accepted"`
+	Expired  int `json:"expired"`
+	Revoked  int `json:"revoked"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Triage states of a scan result. A result without notes is open.
+const (
+	TriageOpen          = "open"
+	TriageAcknowledged  = "acknowledged"
+	TriageResolved      = "resolved"
+	TriageFalsePositive = "false_positive"
+)
+
+// IsValidTriageStatus reports whether status is a known triage state
+func IsValidTriageStatus(status string) bool {
+	switch status {
+	case TriageOpen, TriageAcknowledged, TriageResolved, TriageFalsePositive:
+		return true
+	}
+	return false
+}
+
+// ScanResultNote is an analyst's note on a scan result. The latest note
+// sets the result's triage status.
+type ScanResultNote struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ResultID     uuid.UUID  `json:"result_id" db:"result_id"`
+	ScanID       uuid.UUID  `json:"scan_id" db:"scan_id"`
+	AuthorID     *uuid.UUID `json:"author_id" db:"author_id"`
+	TriageStatus string     `json:"triage_status" db:"triage_status"`
+	Body         string     `json:"body" db:"body"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
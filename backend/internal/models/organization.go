@@ -14,14 +14,41 @@ type Organization struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// OrganizationStats is an organization with platform-wide usage figures for
+// operators. Storage counts report files, result attachments and
+// wordlists; the last activity is the latest scan or audited request.
+type OrganizationStats struct {
+	Organization
+	MemberCount    int        `json:"member_count"`
+	ScanCount      int        `json:"scan_count"`
+	StorageBytes   int64      `json:"storage_bytes"`
+	LastActivityAt *time.Time `json:"last_activity_at"`
+}
+
+// MemberOrganization is an organization with the requesting user's role in it
+type MemberOrganization struct {
+	Organization
+	Role     Role      `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
 type OrganizationMember struct {
 	ID             uuid.UUID `json:"id" db:"id"`
 	OrganizationID uuid.UUID `json:"organization_id" db:"organization_id"`
 	UserID         uuid.UUID `json:"user_id" db:"user_id"`
-	Role           string    `json:"role" db:"role"` // owner, admin, member, viewer
+	Role           string    `json:"role" db:"role"` // owner, admin, member, viewer, billing
 	JoinedAt       time.Time `json:"joined_at" db:"joined_at"`
 }
 
+// MemberDetail is an organization member with the user's contact details
+type MemberDetail struct {
+	OrganizationMember
+	Email            string `json:"email"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	IsServiceAccount bool   `json:"is_service_account"`
+}
+
 type Role string
 
 const (
@@ -29,8 +56,217 @@ const (
 	RoleAdmin  Role = "admin"
 	RoleMember Role = "member"
 	RoleViewer Role = "viewer"
+
+	// RoleBilling sits outside the hierarchy: it can read the organization's
+	// usage and details but never its targets, scans or results.
+	RoleBilling Role = "billing"
 )
 
+// roleRanks orders roles from least to most privileged
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleMember: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok || r == RoleBilling
+}
+
+// AtLeast reports whether r grants at least the privileges of min. The
+// billing role is not ranked, so it only satisfies itself.
+func (r Role) AtLeast(min Role) bool {
+	if r == RoleBilling || min == RoleBilling {
+		return r == min
+	}
+	return r.IsValid() && roleRanks[r] >= roleRanks[min]
+}
+
+// CanAccessScanData reports whether r may read or change targets, scans
+// and their results
+func (r Role) CanAccessScanData() bool {
+	return r.IsValid() && r != RoleBilling
+}
+
+// Permissions lists the actions a role allows in an organization. It mirrors
+// the role checks the API enforces so clients do not have to re-derive them.
+type Permissions struct {
+	ViewUsage             bool `json:"can_view_usage"`
+	ViewScanData          bool `json:"can_view_scan_data"`
+	ManageTargets         bool `json:"can_manage_targets"`
+	CreateScan            bool `json:"can_create_scan"`
+	DeleteScanData        bool `json:"can_delete_scan_data"`
+	RequeueScans          bool `json:"can_requeue_scans"`
+	ManageMembers         bool `json:"can_manage_members"`
+	ManageSettings        bool `json:"can_manage_settings"`
+	ManageServiceAccounts bool `json:"can_manage_service_accounts"`
+	MergeOrganization     bool `json:"can_merge_organization"`
+}
+
+// Permissions returns the actions r allows
+func (r Role) Permissions() Permissions {
+	return Permissions{
+		ViewUsage:             r.IsValid(),
+		ViewScanData:          r.CanAccessScanData(),
+		ManageTargets:         r.AtLeast(RoleMember),
+		CreateScan:            r.AtLeast(RoleMember),
+		DeleteScanData:        r.AtLeast(RoleAdmin),
+		RequeueScans:          r.AtLeast(RoleAdmin),
+		ManageMembers:         r.AtLeast(RoleAdmin),
+		ManageSettings:        r.AtLeast(RoleAdmin),
+		ManageServiceAccounts: r.AtLeast(RoleAdmin),
+		MergeOrganization:     r.AtLeast(RoleOwner),
+	}
+}
+
 type CreateOrganizationRequest struct {
 	Name string `json:"name" binding:"required,min=3,max=100"`
 }
+
+// PlanLimits describes the resource ceilings that apply to an organization.
+// A zero value means the resource is unlimited.
+type PlanLimits struct {
+	MaxTargets       int   `json:"max_targets"`
+	MaxScansPerMonth int   `json:"max_scans_per_month"`
+	MaxStorageBytes  int64 `json:"max_storage_bytes"`
+	MaxMembers       int   `json:"max_members"`
+	MaxRequests      int   `json:"max_requests_per_month"`
+}
+
+// OrganizationUsage summarizes an organization's current footprint
+type OrganizationUsage struct {
+	OrganizationID    uuid.UUID  `json:"organization_id"`
+	Targets           int        `json:"targets"`
+	ScansThisMonth    int        `json:"scans_this_month"`
+	ReportStorageUsed int64      `json:"report_storage_used"`
+	ActiveMembers     int        `json:"active_members"`
+	PeriodStart       time.Time  `json:"period_start"`
+	Limits            PlanLimits `json:"limits"`
+}
+
+// SessionPolicy controls how long sessions of an organization's members may
+// stay idle. Each authenticated request slides the idle window forward, up to
+// the lifetime of the token itself. A nil IdleTimeoutMinutes disables the
+// idle check.
+type SessionPolicy struct {
+	OrganizationID     uuid.UUID `json:"organization_id" db:"id"`
+	IdleTimeoutMinutes *int      `json:"idle_timeout_minutes" db:"idle_timeout_minutes"`
+}
+
+// IdleTimeout returns the idle timeout, or 0 when the check is disabled
+func (p *SessionPolicy) IdleTimeout() time.Duration {
+	if p.IdleTimeoutMinutes == nil {
+		return 0
+	}
+	return time.Duration(*p.IdleTimeoutMinutes) * time.Minute
+}
+
+// NotificationPreferences controls which events an organization is notified
+// about. Organizations without stored preferences get
+// DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	OrganizationID       uuid.UUID  `json:"organization_id" db:"organization_id"`
+	ScanCompleted        bool       `json:"scan_completed" db:"scan_completed"`
+	ScanProgress         bool       `json:"scan_progress" db:"scan_progress"`
+	HighSeverityFindings bool       `json:"high_severity_findings" db:"high_severity_findings"`
+	CertificateExpiry    bool       `json:"certificate_expiry" db:"certificate_expiry"`
+	UpdatedBy            *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// DefaultNotificationPreferences returns the preferences of an organization
+// that never changed them: every notification enabled
+func DefaultNotificationPreferences(organizationID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		OrganizationID:       organizationID,
+		ScanCompleted:        true,
+		ScanProgress:         true,
+		HighSeverityFindings: true,
+		CertificateExpiry:    true,
+	}
+}
+
+// AllowsWebhookEvent reports whether a webhook event may be dispatched
+func (p *NotificationPreferences) AllowsWebhookEvent(event string) bool {
+	switch event {
+	case WebhookEventScanCompleted:
+		return p.ScanCompleted
+	case WebhookEventScanProgress:
+		return p.ScanProgress
+	}
+	return true
+}
+
+// Product branding used in reports of organizations that did not customize it
+const (
+	DefaultBrandingCompanyName  = "PublicScanner"
+	DefaultBrandingPrimaryColor = "#1f6feb"
+	DefaultBrandingFooterText   = "Generated by PublicScanner"
+)
+
+// ReportBranding is the logo, colors and texts an organization puts on its
+// PDF and HTML reports. Nil fields fall back to the product branding; use
+// Effective to resolve them.
+type ReportBranding struct {
+	OrganizationID  uuid.UUID  `json:"organization_id" db:"organization_id"`
+	CompanyName     *string    `json:"company_name" db:"company_name"`
+	PrimaryColor    *string    `json:"primary_color" db:"primary_color"`
+	FooterText      *string    `json:"footer_text" db:"footer_text"`
+	LogoStorageKey  *string    `json:"-" db:"logo_storage_key"`
+	LogoContentType *string    `json:"logo_content_type" db:"logo_content_type"`
+	HasLogo         bool       `json:"has_logo" db:"-"`
+	UpdatedBy       *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// EffectiveBranding is the branding a report is rendered with, after
+// defaults were applied. Logo is empty when the product logo is used.
+type EffectiveBranding struct {
+	CompanyName     string
+	PrimaryColor    string
+	FooterText      string
+	Logo            []byte
+	LogoContentType string
+}
+
+// Effective resolves unset fields to the product branding. The logo content
+// has to be loaded separately.
+func (b *ReportBranding) Effective() *EffectiveBranding {
+	effective := &EffectiveBranding{
+		CompanyName:  DefaultBrandingCompanyName,
+		PrimaryColor: DefaultBrandingPrimaryColor,
+		FooterText:   DefaultBrandingFooterText,
+	}
+	if b.CompanyName != nil {
+		effective.CompanyName = *b.CompanyName
+	}
+	if b.PrimaryColor != nil {
+		effective.PrimaryColor = *b.PrimaryColor
+	}
+	if b.FooterText != nil {
+		effective.FooterText = *b.FooterText
+	}
+	return effective
+}
+
+// OrganizationMerge summarizes what moving a source organization into a
+// destination organization changed
+type OrganizationMerge struct {
+	SourceOrganizationID      uuid.UUID `json:"source_organization_id"`
+	SourceOrganizationName    string    `json:"source_organization_name"`
+	DestinationOrganizationID uuid.UUID `json:"destination_organization_id"`
+	TargetsMoved              int       `json:"targets_moved"`
+	// TargetsMerged counts source targets whose hostname already existed in
+	// the destination; their scans now reference the destination target
+	TargetsMerged int `json:"targets_merged"`
+	ScansMoved    int `json:"scans_moved"`
+	ReportsMoved  int `json:"reports_moved"`
+	MembersAdded  int `json:"members_added"`
+	// MembersMerged counts users who belonged to both organizations; they
+	// keep the higher of their two roles
+	MembersMerged int       `json:"members_merged"`
+	MergedAt      time.Time `json:"merged_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,3 +23,62 @@ type GenerateReportRequest struct {
 	ScanID uuid.UUID `json:"scan_id" binding:"required"`
 	Format string    `json:"format" binding:"required,oneof=pdf html json csv"`
 }
+
+// ReportSchemaVersion is the version of the ReportDocument layout, including
+// ReportResult. Bump it whenever a field is added, removed or changes
+// meaning.
+const ReportSchemaVersion = "1.0"
+
+// ReportDocument is the stable, versioned shape of a JSON report
+type ReportDocument struct {
+	SchemaVersion string          `json:"schema_version"`
+	ScanID        uuid.UUID       `json:"scan_id"`
+	Status        ScanStatus      `json:"status"`
+	StartedAt     *time.Time      `json:"started_at"`
+	CompletedAt   *time.Time      `json:"completed_at"`
+	Checks        []string        `json:"checks"`
+	Results       []*ReportResult `json:"results"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
+
+// ReportResult is one check result in a ReportDocument. It is kept apart
+// from ScanResult so fields added to the API do not change the report
+// without a ReportSchemaVersion bump.
+type ReportResult struct {
+	ID        uuid.UUID       `json:"id"`
+	ScanID    uuid.UUID       `json:"scan_id"`
+	CheckType string          `json:"check_type"`
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	Findings  int             `json:"findings"`
+	Severity  string          `json:"severity"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewReportDocument builds the report document for a scan and its results
+func NewReportDocument(scan *ScanJob, results []*ScanResult) *ReportDocument {
+	reportResults := make([]*ReportResult, len(results))
+	for i, result := range results {
+		reportResults[i] = &ReportResult{
+			ID:        result.ID,
+			ScanID:    result.ScanID,
+			CheckType: result.CheckType,
+			Status:    result.Status,
+			Data:      result.Data,
+			Findings:  result.Findings,
+			Severity:  result.Severity,
+			CreatedAt: result.CreatedAt,
+		}
+	}
+
+	return &ReportDocument{
+		SchemaVersion: ReportSchemaVersion,
+		ScanID:        scan.ID,
+		Status:        scan.Status,
+		StartedAt:     scan.StartedAt,
+		CompletedAt:   scan.CompletedAt,
+		Checks:        scan.Checks,
+		Results:       reportResults,
+		GeneratedAt:   time.Now(),
+	}
+}
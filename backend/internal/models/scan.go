@@ -3,6 +3,9 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,27 +14,324 @@ import (
 type ScanStatus string
 
 const (
-	ScanStatusQueued     ScanStatus = "queued"
-	ScanStatusRunning    ScanStatus = "running"
-	ScanStatusCompleted  ScanStatus = "completed"
-	ScanStatusFailed     ScanStatus = "failed"
-	ScanStatusCancelled  ScanStatus = "cancelled"
+	ScanStatusScheduled ScanStatus = "scheduled" // waiting for its run_at time before being queued
+	ScanStatusQueued    ScanStatus = "queued"
+	ScanStatusRunning   ScanStatus = "running"
+	ScanStatusCompleted ScanStatus = "completed"
+	ScanStatusFailed    ScanStatus = "failed"
+	ScanStatusCancelled ScanStatus = "cancelled"
 )
 
+// IsValid reports whether s is a known scan status
+func (s ScanStatus) IsValid() bool {
+	switch s {
+	case ScanStatusScheduled, ScanStatusQueued, ScanStatusRunning, ScanStatusCompleted, ScanStatusFailed, ScanStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// FailureCode classifies why a scan failed or was cancelled
+type FailureCode string
+
+const (
+	FailureUnreachable     FailureCode = "unreachable"       // the target did not answer
+	FailureTimeout         FailureCode = "timeout"           // the checks ran out of time
+	FailureWorkerError     FailureCode = "worker_error"      // the worker or the queue failed
+	FailureInvalidTarget   FailureCode = "invalid_target"    // the target cannot be scanned, e.g. it was deleted
+	FailureCancelledByUser FailureCode = "cancelled_by_user" // a user cancelled the scan
+)
+
+// FailureCodes lists every failure code
+var FailureCodes = []FailureCode{
+	FailureUnreachable,
+	FailureTimeout,
+	FailureWorkerError,
+	FailureInvalidTarget,
+	FailureCancelledByUser,
+}
+
+// IsValid reports whether c is a known failure code
+func (c FailureCode) IsValid() bool {
+	for _, code := range FailureCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureStats breaks down an organization's unsuccessful scans over a
+// period. Scans that failed before failures were classified count as
+// "unclassified" in ByCode.
+type FailureStats struct {
+	Since          time.Time              `json:"since"`
+	FailedScans    int                    `json:"failed_scans"`
+	CancelledScans int                    `json:"cancelled_scans"`
+	ByCode         map[string]int         `json:"by_code"`
+	FailingChecks  []*FailingCheckSummary `json:"failing_checks"`
+}
+
+// FailingCheckSummary counts the failed or errored results of one check,
+// split by the failure code of the scan they belong to
+type FailingCheckSummary struct {
+	CheckType string         `json:"check_type"`
+	Failures  int            `json:"failures"`
+	ByCode    map[string]int `json:"by_code"`
+}
+
+// ScanFailure is the classified cause recorded when a scan fails
+type ScanFailure struct {
+	Code   FailureCode
+	Reason string // human-readable detail, e.g. the underlying error
+}
+
+// IsTerminal reports whether a scan in status s will not change any more
+func (s ScanStatus) IsTerminal() bool {
+	return s == ScanStatusCompleted || s == ScanStatusFailed || s == ScanStatusCancelled
+}
+
+// Check names understood by the workers
+const (
+	CheckPing       = "ping"
+	CheckPortScan   = "portscan"
+	CheckHeaders    = "headers"
+	CheckSSL        = "ssl"
+	CheckDNS        = "dns"
+	CheckBruteforce = "bruteforce"
+)
+
+// AvailableChecks lists every check a scan may request
+var AvailableChecks = []string{
+	CheckPing,
+	CheckPortScan,
+	CheckHeaders,
+	CheckSSL,
+	CheckDNS,
+	CheckBruteforce,
+}
+
+// checkDescriptions describes each check in the checks catalog
+var checkDescriptions = map[string]string{
+	CheckPing:       "ICMP reachability and round-trip time",
+	CheckPortScan:   "Open TCP ports and the services behind them",
+	CheckHeaders:    "HTTP security headers of the target's responses",
+	CheckSSL:        "TLS certificate validity, protocol versions and ciphers",
+	CheckDNS:        "DNS records and mail security records",
+	CheckBruteforce: "Common and wordlist paths probed for exposed content",
+}
+
+// CheckDescription returns the catalog description of a check
+func CheckDescription(name string) string {
+	return checkDescriptions[name]
+}
+
+// IsIntrusiveCheck reports whether a check sends enough traffic that it
+// should only run against targets the organization is authorized to test
+// aggressively
+func IsIntrusiveCheck(name string) bool {
+	return name == CheckBruteforce
+}
+
+// IsValidCheck reports whether name is a known check
+func IsValidCheck(name string) bool {
+	for _, check := range AvailableChecks {
+		if name == check {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateChecks verifies a scan's check list is non-empty and only names known checks
+func ValidateChecks(checks []string) error {
+	if len(checks) == 0 {
+		return errors.New("at least one check is required")
+	}
+	for _, check := range checks {
+		if !IsValidCheck(check) {
+			return fmt.Errorf("unknown check: %s", check)
+		}
+	}
+	return nil
+}
+
+// Severity levels reported by scan checks, from most to least severe
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// severityRanks orders severities so they can be compared
+var severityRanks = map[string]int{
+	SeverityInfo:     1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// SeverityRank returns the relative weight of a severity (0 if unknown)
+func SeverityRank(severity string) int {
+	return severityRanks[severity]
+}
+
+// Risk score weights applied per finding of each severity
+const (
+	RiskWeightCritical = 10
+	RiskWeightHigh     = 5
+	RiskWeightMedium   = 2
+	RiskWeightLow      = 1
+)
+
+// SeverityCounts is a rollup of findings grouped by severity
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Info     int `json:"info"`
+}
+
+// RiskScore returns the weighted risk score for the rollup
+func (c SeverityCounts) RiskScore() int {
+	return c.Critical*RiskWeightCritical +
+		c.High*RiskWeightHigh +
+		c.Medium*RiskWeightMedium +
+		c.Low*RiskWeightLow
+}
+
+// Total returns the number of findings across all severities
+func (c SeverityCounts) Total() int {
+	return c.Critical + c.High + c.Medium + c.Low + c.Info
+}
+
+// ScanSummary is the findings rollup stored on a scan, kept current as
+// results are written
+type ScanSummary struct {
+	Total      int            `json:"total"`
+	BySeverity SeverityCounts `json:"by_severity"`
+}
+
+// get returns the count of one severity
+func (c SeverityCounts) get(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return c.Critical
+	case SeverityHigh:
+		return c.High
+	case SeverityMedium:
+		return c.Medium
+	case SeverityLow:
+		return c.Low
+	case SeverityInfo:
+		return c.Info
+	}
+	return 0
+}
+
+// add increases the count of one severity
+func (c *SeverityCounts) add(severity string, count int) {
+	switch severity {
+	case SeverityCritical:
+		c.Critical += count
+	case SeverityHigh:
+		c.High += count
+	case SeverityMedium:
+		c.Medium += count
+	case SeverityLow:
+		c.Low += count
+	case SeverityInfo:
+		c.Info += count
+	}
+}
+
+// Validate rejects negative counts
+func (c SeverityCounts) Validate() error {
+	if c.Critical < 0 || c.High < 0 || c.Medium < 0 || c.Low < 0 || c.Info < 0 {
+		return errors.New("findings counts must not be negative")
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a severity -> count object, rejecting keys that are
+// not known severities so typos cannot silently drop findings
+func (c *SeverityCounts) UnmarshalJSON(data []byte) error {
+	var counts map[string]int
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return err
+	}
+
+	decoded := SeverityCounts{}
+	for severity, count := range counts {
+		switch severity {
+		case SeverityCritical:
+			decoded.Critical = count
+		case SeverityHigh:
+			decoded.High = count
+		case SeverityMedium:
+			decoded.Medium = count
+		case SeverityLow:
+			decoded.Low = count
+		case SeverityInfo:
+			decoded.Info = count
+		default:
+			return fmt.Errorf("unknown severity %q", severity)
+		}
+	}
+	if err := decoded.Validate(); err != nil {
+		return err
+	}
+
+	*c = decoded
+	return nil
+}
+
+// IsValidSeverity reports whether severity is one of the known levels
+func IsValidSeverity(severity string) bool {
+	_, ok := severityRanks[severity]
+	return ok
+}
+
 type ScanJob struct {
-	ID             uuid.UUID      `json:"id" db:"id"`
-	TargetID       *uuid.UUID     `json:"target_id,omitempty" db:"target_id"` // Optional: for saved targets
-	URL            *string        `json:"url,omitempty" db:"url"`              // Optional: for quick scans
-	OrganizationID uuid.UUID      `json:"organization_id" db:"organization_id"`
-	InitiatedBy    uuid.UUID      `json:"initiated_by" db:"initiated_by"`
-	Status         ScanStatus     `json:"status" db:"status"`
-	Progress       int            `json:"progress" db:"progress"` // 0-100
-	Checks         []string       `json:"checks" db:"checks"`
-	Config         ScanConfig     `json:"config" db:"config"`
-	StartedAt      *time.Time     `json:"started_at" db:"started_at"`
-	CompletedAt    *time.Time     `json:"completed_at" db:"completed_at"`
-	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	ID             uuid.UUID       `json:"id" db:"id"`
+	TargetID       *uuid.UUID      `json:"target_id,omitempty" db:"target_id"` // Optional: for saved targets
+	URL            *string         `json:"url,omitempty" db:"url"`             // Optional: for quick scans
+	OrganizationID uuid.UUID       `json:"organization_id" db:"organization_id"`
+	InitiatedBy    uuid.UUID       `json:"initiated_by" db:"initiated_by"`
+	Status         ScanStatus      `json:"status" db:"status"`
+	Progress       int             `json:"progress" db:"progress"`                   // 0-100
+	CurrentStep    *string         `json:"current_step,omitempty" db:"current_step"` // check the worker is running
+	Checks         []string        `json:"checks" db:"checks"`
+	Config         ScanConfig      `json:"config" db:"config"`
+	Tags           []string        `json:"tags" db:"tags"`
+	Metadata       json.RawMessage `json:"metadata" db:"metadata"`       // JSONB object
+	RunAt          *time.Time      `json:"run_at,omitempty" db:"run_at"` // one-time scheduled start, set for scheduled scans
+	StartedAt      *time.Time      `json:"started_at" db:"started_at"`
+	CompletedAt    *time.Time      `json:"completed_at" db:"completed_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	PolicyPassed   *bool           `json:"policy_passed" db:"policy_passed"` // nil until evaluated
+	WorstSeverity  *string         `json:"worst_severity,omitempty" db:"worst_severity"`
+	Summary        ScanSummary     `json:"summary" db:"-"` // from findings_total and findings_by_severity
+	Policy         *ScanPolicy     `json:"policy,omitempty" db:"-"`
+	DeletedAt      *time.Time      `json:"deleted_at,omitempty" db:"deleted_at"`   // soft delete, purged after the retention window
+	CampaignID     *uuid.UUID      `json:"campaign_id,omitempty" db:"campaign_id"` // engagement the scan belongs to
+	// FailureCode and FailureReason say why a failed or cancelled scan ended
+	FailureCode   *FailureCode `json:"failure_code,omitempty" db:"failure_code"`
+	FailureReason *string      `json:"failure_reason,omitempty" db:"failure_reason"`
+}
+
+// ScanPolicy is the evaluated severity gate for a scan, used by CI to
+// decide whether a deploy may proceed. It is independent of whether the
+// scan itself executed successfully.
+type ScanPolicy struct {
+	FailOnSeverity string  `json:"fail_on_severity"`
+	WorstSeverity  *string `json:"worst_severity"`
+	Passed         *bool   `json:"passed"`
 }
 
 type ScanConfig struct {
@@ -43,6 +343,52 @@ type ScanConfig struct {
 	PingCheckEnabled    bool   `json:"ping_check_enabled"`
 	Timeout             int    `json:"timeout"` // seconds
 	CustomWordlist      string `json:"custom_wordlist"`
+	FailOnSeverity      string `json:"fail_on_severity,omitempty"` // fail policy at or above this severity
+	// ClientCertificateID references an uploaded certificate/key pair the
+	// worker presents when a target requires mutual TLS
+	ClientCertificateID *uuid.UUID `json:"client_certificate_id,omitempty"`
+	// WordlistID references an uploaded wordlist for the bruteforce check
+	WordlistID *uuid.UUID `json:"wordlist_id,omitempty"`
+	// ExcludePaths and ExcludePorts scope sensitive endpoints out of the
+	// bruteforce and port scan checks. Exclusions always take precedence:
+	// a path listed here is skipped even when the wordlist contains it, and
+	// an excluded port is never probed even though the port scan covers
+	// the full range.
+	ExcludePaths []string `json:"exclude_paths,omitempty"`
+	ExcludePorts []int    `json:"exclude_ports,omitempty"`
+}
+
+// Limits on scan exclusion lists
+const (
+	MaxExcludePaths = 100
+	MaxExcludePorts = 1000
+)
+
+// Validate checks the scan configuration for invalid values
+func (sc ScanConfig) Validate() error {
+	if sc.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if sc.FailOnSeverity != "" && !IsValidSeverity(sc.FailOnSeverity) {
+		return errors.New("fail_on_severity must be one of critical, high, medium, low, info")
+	}
+	if len(sc.ExcludePaths) > MaxExcludePaths {
+		return fmt.Errorf("exclude_paths accepts at most %d entries", MaxExcludePaths)
+	}
+	for _, path := range sc.ExcludePaths {
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t\r\n?#") {
+			return fmt.Errorf("exclude_paths entry %q must be an absolute path without spaces, query or fragment", path)
+		}
+	}
+	if len(sc.ExcludePorts) > MaxExcludePorts {
+		return fmt.Errorf("exclude_ports accepts at most %d entries", MaxExcludePorts)
+	}
+	for _, port := range sc.ExcludePorts {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("exclude_ports entry %d must be between 1 and 65535", port)
+		}
+	}
+	return nil
 }
 
 // Implement sql.Scanner and driver.Valuer for ScanConfig
@@ -66,7 +412,37 @@ type ScanResult struct {
 	Data      json.RawMessage `json:"data" db:"data"` // JSONB
 	Findings  int             `json:"findings" db:"findings"`
 	Severity  string          `json:"severity" db:"severity"`
-	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	// FindingsBySeverity breaks Findings down per severity when the worker reports it
+	FindingsBySeverity *SeverityCounts `json:"findings_by_severity,omitempty" db:"findings_by_severity"`
+	// OriginalSeverity and OriginalFindingsBySeverity keep what the check
+	// reported when a severity override rule changed it
+	OriginalSeverity           *string         `json:"original_severity,omitempty" db:"original_severity"`
+	OriginalFindingsBySeverity *SeverityCounts `json:"original_findings_by_severity,omitempty" db:"original_findings_by_severity"`
+	// TriageStatus is set by the result's latest note; without one it is
+	// acknowledged while an acknowledgement rule matches, open otherwise
+	TriageStatus string `json:"triage_status,omitempty" db:"triage_status"`
+	// AcknowledgedByRule is the acknowledgement rule behind an acknowledged
+	// status not set by a note
+	AcknowledgedByRule *uuid.UUID `json:"acknowledged_by_rule,omitempty" db:"-"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ResultSearchHit is a scan result matched by a search, together with the
+// scan and target it came from
+type ResultSearchHit struct {
+	ScanResult
+	Scan ResultScanContext `json:"scan"`
+}
+
+// ResultScanContext identifies the scan behind a search hit
+type ResultScanContext struct {
+	ID         uuid.UUID  `json:"id"`
+	Status     ScanStatus `json:"status"`
+	TargetID   *uuid.UUID `json:"target_id,omitempty"`
+	TargetName *string    `json:"target_name,omitempty"`
+	Hostname   *string    `json:"hostname,omitempty"`
+	URL        *string    `json:"url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 type CreateScanRequest struct {
@@ -76,6 +452,44 @@ type CreateScanRequest struct {
 	Config   ScanConfig `json:"config"`
 }
 
+// TargetRisk pairs a target with its latest scan and that scan's risk
+type TargetRisk struct {
+	TargetID   uuid.UUID      `json:"target_id"`
+	TargetName string         `json:"target_name"`
+	Hostname   string         `json:"hostname"`
+	Scan       *ScanJob       `json:"scan"`
+	Severity   SeverityCounts `json:"severity"`
+	RiskScore  int            `json:"risk_score"`
+}
+
+// Scan timeline event types
+const (
+	TimelineEventCreated       = "created"
+	TimelineEventStatusChanged = "status_changed"
+	TimelineEventCheckFinished = "check_completed"
+)
+
+// ScanStatusChange is a recorded transition of a scan's status
+type ScanStatusChange struct {
+	ScanID     uuid.UUID   `json:"scan_id" db:"scan_id"`
+	FromStatus *ScanStatus `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   ScanStatus  `json:"to_status" db:"to_status"`
+	Progress   int         `json:"progress" db:"progress"`
+	ChangedAt  time.Time   `json:"changed_at" db:"changed_at"`
+}
+
+// ScanTimelineEvent is one entry of a scan's chronological lifecycle
+type ScanTimelineEvent struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Status     *ScanStatus `json:"status,omitempty"`     // status_changed events
+	CheckType  string      `json:"check_type,omitempty"` // check_completed events
+	Result     string      `json:"result,omitempty"`     // check_completed events: success, failed, error
+	Severity   string      `json:"severity,omitempty"`
+	Findings   int         `json:"findings,omitempty"`
+	Progress   *int        `json:"progress,omitempty"`
+}
+
 type ScanProgress struct {
 	ScanID      uuid.UUID  `json:"scan_id"`
 	Status      ScanStatus `json:"status"`
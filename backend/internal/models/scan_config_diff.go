@@ -0,0 +1,117 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ConfigFieldChange is a single ScanConfig field whose value differs
+// between two scans. Field is the JSON name of the setting.
+type ConfigFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// ScanConfigDiff describes how the settings of a scan differ from those of
+// the scan it is compared against
+type ScanConfigDiff struct {
+	ScanID        uuid.UUID           `json:"scan_id"`
+	AgainstID     uuid.UUID           `json:"against_id"`
+	SameTarget    bool                `json:"same_target"`
+	Identical     bool                `json:"identical"`
+	ChecksAdded   []string            `json:"checks_added"`
+	ChecksRemoved []string            `json:"checks_removed"`
+	ConfigChanges []ConfigFieldChange `json:"config_changes"`
+}
+
+// DiffScanConfig compares the checks and configuration of scan against
+// those of against. Added and removed are relative to against, i.e. a check
+// in ChecksAdded ran in scan but not in against.
+func DiffScanConfig(scan, against *ScanJob) *ScanConfigDiff {
+	diff := &ScanConfigDiff{
+		ScanID:        scan.ID,
+		AgainstID:     against.ID,
+		SameTarget:    SameScanTarget(scan, against),
+		ChecksAdded:   stringsMissingFrom(scan.Checks, against.Checks),
+		ChecksRemoved: stringsMissingFrom(against.Checks, scan.Checks),
+		ConfigChanges: diffScanConfigFields(against.Config, scan.Config),
+	}
+	diff.Identical = len(diff.ChecksAdded) == 0 && len(diff.ChecksRemoved) == 0 && len(diff.ConfigChanges) == 0
+	return diff
+}
+
+// diffScanConfigFields compares every field of two configs, reporting
+// changes in declaration order
+func diffScanConfigFields(from, to ScanConfig) []ConfigFieldChange {
+	changes := []ConfigFieldChange{}
+	fromValue, toValue := reflect.ValueOf(from), reflect.ValueOf(to)
+	t := fromValue.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		a, b := fromValue.Field(i).Interface(), toValue.Field(i).Interface()
+		if isEmptyConfigValue(fromValue.Field(i)) && isEmptyConfigValue(toValue.Field(i)) {
+			continue
+		}
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		changes = append(changes, ConfigFieldChange{
+			Field: configFieldName(field),
+			From:  a,
+			To:    b,
+		})
+	}
+
+	return changes
+}
+
+// isEmptyConfigValue treats nil and empty lists alike so that a config
+// decoded from "[]" does not differ from one that omitted the field
+func isEmptyConfigValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// configFieldName returns the JSON name of a ScanConfig field
+func configFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// stringsMissingFrom returns the values of a that are not in b, in order
+func stringsMissingFrom(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, value := range b {
+		present[value] = true
+	}
+
+	missing := []string{}
+	for _, value := range a {
+		if !present[value] {
+			missing = append(missing, value)
+			present[value] = true
+		}
+	}
+	return missing
+}
+
+// SameScanTarget reports whether two scans ran against the same saved
+// target or, for quick scans, the same URL
+func SameScanTarget(a, b *ScanJob) bool {
+	if a.TargetID != nil && b.TargetID != nil {
+		return *a.TargetID == *b.TargetID
+	}
+	if a.URL != nil && b.URL != nil {
+		return *a.URL == *b.URL
+	}
+	return false
+}
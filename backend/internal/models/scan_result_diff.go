@@ -0,0 +1,245 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a check in a ScanResultDiff
+const (
+	CheckDiffCompared    = "compared"     // both scans ran the check successfully
+	CheckDiffAdded       = "added"        // only the scan ran the check
+	CheckDiffRemoved     = "removed"      // only the scan compared against ran it
+	CheckDiffNotCompared = "not_compared" // the check failed in a scan or its data is unreadable
+)
+
+// ResultFinding is a single finding of a check, identified by a key that
+// stays the same across scans, e.g. "443/tcp" for an open port
+type ResultFinding struct {
+	Key    string      `json:"key"`
+	Detail interface{} `json:"detail"`
+}
+
+// ChangedFinding is a finding present in both scans whose detail differs,
+// e.g. an open port now running another service
+type ChangedFinding struct {
+	Key  string      `json:"key"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// CheckResultDiff describes how the findings of one check differ between
+// two scans
+type CheckResultDiff struct {
+	Status    string           `json:"status"`
+	Added     []ResultFinding  `json:"added"`
+	Removed   []ResultFinding  `json:"removed"`
+	Changed   []ChangedFinding `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// ScanResultDiff describes how the findings of a scan differ from those of
+// the scan it is compared against, grouped by check type
+type ScanResultDiff struct {
+	ScanID    uuid.UUID                   `json:"scan_id"`
+	AgainstID uuid.UUID                   `json:"against_id"`
+	Identical bool                        `json:"identical"`
+	Checks    map[string]*CheckResultDiff `json:"checks"`
+}
+
+// DiffScanResults compares the findings of scan against those of against.
+// Added and removed are relative to against, i.e. an added finding was
+// reported by scan but not by against. Findings are matched by check type
+// and key (see resultFindings).
+func DiffScanResults(scan, against *ScanJob, results, againstResults []*ScanResult) *ScanResultDiff {
+	diff := &ScanResultDiff{
+		ScanID:    scan.ID,
+		AgainstID: against.ID,
+		Identical: true,
+		Checks:    map[string]*CheckResultDiff{},
+	}
+
+	current, previous := resultsByCheck(results), resultsByCheck(againstResults)
+	for checkType := range previous {
+		if _, ok := current[checkType]; !ok {
+			current[checkType] = nil
+		}
+	}
+
+	for checkType, result := range current {
+		check := diffCheckResults(checkType, result, previous[checkType])
+		if check.Status == CheckDiffNotCompared || len(check.Added) > 0 || len(check.Removed) > 0 || len(check.Changed) > 0 {
+			diff.Identical = false
+		}
+		diff.Checks[checkType] = check
+	}
+
+	return diff
+}
+
+// resultsByCheck indexes results by check type, keeping the latest result
+// of a check reported more than once
+func resultsByCheck(results []*ScanResult) map[string]*ScanResult {
+	byCheck := make(map[string]*ScanResult, len(results))
+	for _, result := range results {
+		if existing, ok := byCheck[result.CheckType]; ok && existing.CreatedAt.After(result.CreatedAt) {
+			continue
+		}
+		byCheck[result.CheckType] = result
+	}
+	return byCheck
+}
+
+// diffCheckResults compares the results of one check; either may be nil
+// when only one scan ran the check
+func diffCheckResults(checkType string, result, against *ScanResult) *CheckResultDiff {
+	check := &CheckResultDiff{
+		Status:  CheckDiffCompared,
+		Added:   []ResultFinding{},
+		Removed: []ResultFinding{},
+		Changed: []ChangedFinding{},
+	}
+
+	current, ok := checkFindings(checkType, result)
+	if !ok {
+		check.Status = CheckDiffNotCompared
+		return check
+	}
+	previous, ok := checkFindings(checkType, against)
+	if !ok {
+		check.Status = CheckDiffNotCompared
+		return check
+	}
+
+	switch {
+	case against == nil:
+		check.Status = CheckDiffAdded
+	case result == nil:
+		check.Status = CheckDiffRemoved
+	}
+
+	for key, detail := range current {
+		previousDetail, ok := previous[key]
+		switch {
+		case !ok:
+			check.Added = append(check.Added, ResultFinding{Key: key, Detail: detail})
+		case !reflect.DeepEqual(detail, previousDetail):
+			check.Changed = append(check.Changed, ChangedFinding{Key: key, From: previousDetail, To: detail})
+		default:
+			check.Unchanged++
+		}
+	}
+	for key, detail := range previous {
+		if _, ok := current[key]; !ok {
+			check.Removed = append(check.Removed, ResultFinding{Key: key, Detail: detail})
+		}
+	}
+
+	sort.Slice(check.Added, func(i, j int) bool { return check.Added[i].Key < check.Added[j].Key })
+	sort.Slice(check.Removed, func(i, j int) bool { return check.Removed[i].Key < check.Removed[j].Key })
+	sort.Slice(check.Changed, func(i, j int) bool { return check.Changed[i].Key < check.Changed[j].Key })
+	return check
+}
+
+// checkFindings returns the findings of a check's result, none when the
+// scan did not run the check, and false when the check failed or its data
+// cannot be read
+func checkFindings(checkType string, result *ScanResult) (map[string]interface{}, bool) {
+	if result == nil {
+		return map[string]interface{}{}, true
+	}
+	if result.Status != "success" {
+		return nil, false
+	}
+	findings, err := resultFindings(checkType, result.Data)
+	if err != nil {
+		return nil, false
+	}
+	return findings, true
+}
+
+// resultFindings extracts the findings of a check's result data, keyed by
+// an identifier that stays the same across scans:
+//
+//	portscan    open_ports        "<port>/<protocol>"
+//	headers     missing_headers   header name
+//	ssl         issues            issue text
+//	dns         records           "<type> <value>", plus "zone_transfer"
+//	bruteforce  directories_found path
+//
+// Other checks, such as ping, report no individual findings.
+func resultFindings(checkType string, data json.RawMessage) (map[string]interface{}, error) {
+	findings := map[string]interface{}{}
+	if len(data) == 0 {
+		return findings, nil
+	}
+
+	switch checkType {
+	case CheckPortScan:
+		var parsed struct {
+			OpenPorts []map[string]interface{} `json:"open_ports"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		for _, port := range parsed.OpenPorts {
+			findings[fmt.Sprintf("%v/%v", port["port"], port["protocol"])] = port
+		}
+
+	case CheckHeaders:
+		var parsed struct {
+			MissingHeaders []string `json:"missing_headers"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		for _, header := range parsed.MissingHeaders {
+			findings[header] = header
+		}
+
+	case CheckSSL:
+		var parsed struct {
+			Issues []string `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		for _, issue := range parsed.Issues {
+			findings[issue] = issue
+		}
+
+	case CheckDNS:
+		var parsed struct {
+			Records                map[string][]string `json:"records"`
+			ZoneTransferVulnerable bool                `json:"zone_transfer_vulnerable"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		for recordType, values := range parsed.Records {
+			for _, value := range values {
+				findings[recordType+" "+value] = map[string]interface{}{"type": recordType, "value": value}
+			}
+		}
+		if parsed.ZoneTransferVulnerable {
+			findings["zone_transfer"] = true
+		}
+
+	case CheckBruteforce:
+		var parsed struct {
+			DirectoriesFound []map[string]interface{} `json:"directories_found"`
+		}
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		for _, dir := range parsed.DirectoriesFound {
+			findings[fmt.Sprint(dir["path"])] = dir
+		}
+	}
+
+	return findings, nil
+}
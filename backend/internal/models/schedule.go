@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledScan is a recurring scan of a target. The scheduler starts a scan
+// with its checks and config whenever NextRunAt passes, attributed to the
+// user who created the schedule.
+type ScheduledScan struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	TargetID       uuid.UUID  `json:"target_id" db:"target_id"`
+	Checks         []string   `json:"checks" db:"checks"`
+	Config         ScanConfig `json:"config" db:"config"`
+	CronExpression string     `json:"cron_expression" db:"cron_expression"` // evaluated in UTC
+	NextRunAt      time.Time  `json:"next_run_at" db:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	LastScanID     *uuid.UUID `json:"last_scan_id,omitempty" db:"last_scan_id"`
+	CreatedBy      uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeverityOverride replaces the severity a check reports for an
+// organization's results. A nil MatchSeverity applies the rule to every
+// severity of the check; a rule matching the exact severity takes
+// precedence over it.
+type SeverityOverride struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	CheckType      string     `json:"check_type" db:"check_type"`
+	MatchSeverity  *string    `json:"match_severity" db:"match_severity"`
+	Severity       string     `json:"severity" db:"severity"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// SeverityOverrides resolves the override rules of one organization
+type SeverityOverrides []*SeverityOverride
+
+// resolve returns the severity a check's finding of severity should be
+// recorded with
+func (o SeverityOverrides) resolve(checkType, severity string) string {
+	resolved := severity
+	for _, rule := range o {
+		if rule.CheckType != checkType {
+			continue
+		}
+		if rule.MatchSeverity == nil {
+			resolved = rule.Severity
+		} else if *rule.MatchSeverity == severity {
+			return rule.Severity
+		}
+	}
+	return resolved
+}
+
+// Apply rewrites a result's severity and per-severity breakdown according
+// to the rules, keeping the reported values in the Original fields when
+// anything changed. The risk score follows from the rewritten breakdown.
+func (o SeverityOverrides) Apply(result *ScanResult) {
+	if len(o) == 0 {
+		return
+	}
+
+	changed := false
+	severity := o.resolve(result.CheckType, result.Severity)
+	if severity != result.Severity {
+		original := result.Severity
+		result.OriginalSeverity = &original
+		result.Severity = severity
+		changed = true
+	}
+
+	if result.FindingsBySeverity != nil {
+		original := *result.FindingsBySeverity
+		remapped := SeverityCounts{}
+		for _, level := range []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo} {
+			remapped.add(o.resolve(result.CheckType, level), original.get(level))
+		}
+		if remapped != original {
+			result.OriginalFindingsBySeverity = &original
+			result.FindingsBySeverity = &remapped
+			changed = true
+		}
+	}
+
+	if changed && result.OriginalSeverity == nil {
+		unchanged := result.Severity
+		result.OriginalSeverity = &unchanged
+	}
+}
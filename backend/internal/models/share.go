@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanShare is a revocable, expiring grant of read-only access to a scan
+type ScanShare struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	ScanID         uuid.UUID  `json:"scan_id" db:"scan_id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	CreatedBy      uuid.UUID  `json:"created_by" db:"created_by"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Active reports whether the share still grants access
+func (s *ScanShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// SharedScanView is the sanitized scan returned through a share link. It
+// carries no internal IDs and none of the scan configuration.
+type SharedScanView struct {
+	Target      string              `json:"target"`
+	Status      ScanStatus          `json:"status"`
+	Checks      []string            `json:"checks"`
+	CreatedAt   time.Time           `json:"created_at"`
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	Results     []*SharedScanResult `json:"results"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+}
+
+// SharedScanResult is a check result within a SharedScanView
+type SharedScanResult struct {
+	CheckType string          `json:"check_type"`
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	Findings  int             `json:"findings"`
+	Severity  string          `json:"severity"`
+	CreatedAt time.Time       `json:"created_at"`
+}
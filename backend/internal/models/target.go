@@ -14,11 +14,27 @@ type Target struct {
 	Description    string    `json:"description" db:"description"`
 	Tags           []string  `json:"tags" db:"tags"`
 	IsActive       bool      `json:"is_active" db:"is_active"`
-	CreatedBy      uuid.UUID `json:"created_by" db:"created_by"`
-	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	// A monitored target is scanned with MonitorChecks every
+	// MonitorIntervalHours; NextMonitorRunAt is nil while monitoring is off
+	IsMonitored          bool       `json:"is_monitored" db:"is_monitored"`
+	MonitorIntervalHours int        `json:"monitor_interval_hours" db:"monitor_interval_hours"`
+	NextMonitorRunAt     *time.Time `json:"next_monitor_run_at" db:"next_monitor_run_at"`
+	CreatedBy            uuid.UUID  `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// MonitorChecks are the checks run by the periodic scans of monitored
+// targets. The intrusive bruteforce check is left out.
+var MonitorChecks = []string{CheckPing, CheckPortScan, CheckHeaders, CheckSSL, CheckDNS}
+
+// Bounds of a target's monitor cadence
+const (
+	MinMonitorIntervalHours = 1
+	MaxMonitorIntervalHours = 30 * 24
+)
+
 type CreateTargetRequest struct {
 	Name        string   `json:"name" binding:"required,min=3,max=100"`
 	Hostname    string   `json:"hostname" binding:"required"`
@@ -32,3 +48,122 @@ type UpdateTargetRequest struct {
 	Tags        []string `json:"tags"`
 	IsActive    *bool    `json:"is_active"`
 }
+
+// TargetSpec is the portable form of a target, free of IDs and other
+// organization-specific data, for keeping target definitions in version
+// control. Targets are matched by hostname when a spec is applied.
+type TargetSpec struct {
+	Name        string   `json:"name"`
+	Hostname    string   `json:"hostname"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// Spec returns the portable form of the target
+func (t *Target) Spec() TargetSpec {
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return TargetSpec{
+		Name:        t.Name,
+		Hostname:    t.Hostname,
+		Description: t.Description,
+		Tags:        tags,
+	}
+}
+
+// Target history actions
+const (
+	TargetHistoryCreated = "created"
+	TargetHistoryUpdated = "updated"
+)
+
+// FieldChange is the old and new value of a changed field. From is nil
+// when the target was created.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// TargetHistoryEntry records who created or changed a target and which
+// fields changed
+type TargetHistoryEntry struct {
+	ID             uuid.UUID              `json:"id" db:"id"`
+	TargetID       uuid.UUID              `json:"target_id" db:"target_id"`
+	OrganizationID uuid.UUID              `json:"organization_id" db:"organization_id"`
+	ActorID        *uuid.UUID             `json:"actor_id" db:"actor_id"`
+	Action         string                 `json:"action" db:"action"`
+	Changes        map[string]FieldChange `json:"changes" db:"changes"` // JSONB, keyed by field name
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+}
+
+// targetFields returns the tracked fields of a target by their JSON names
+func targetFields(t *Target) map[string]interface{} {
+	tags := t.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return map[string]interface{}{
+		"name":        t.Name,
+		"hostname":    t.Hostname,
+		"description": t.Description,
+		"tags":        tags,
+		"is_active":   t.IsActive,
+
+		"is_monitored":           t.IsMonitored,
+		"monitor_interval_hours": t.MonitorIntervalHours,
+	}
+}
+
+// NewTargetHistoryEntry describes the change from before to after made by
+// actorID. A nil before records the creation of after. It returns nil when
+// no tracked field changed.
+func NewTargetHistoryEntry(before, after *Target, actorID uuid.UUID) *TargetHistoryEntry {
+	entry := &TargetHistoryEntry{
+		ID:             uuid.New(),
+		TargetID:       after.ID,
+		OrganizationID: after.OrganizationID,
+		ActorID:        &actorID,
+		Action:         TargetHistoryCreated,
+		Changes:        map[string]FieldChange{},
+	}
+
+	afterFields := targetFields(after)
+	if before == nil {
+		for field, value := range afterFields {
+			entry.Changes[field] = FieldChange{To: value}
+		}
+		return entry
+	}
+
+	entry.Action = TargetHistoryUpdated
+	for field, from := range targetFields(before) {
+		to := afterFields[field]
+		if !sameFieldValue(from, to) {
+			entry.Changes[field] = FieldChange{From: from, To: to}
+		}
+	}
+	if len(entry.Changes) == 0 {
+		return nil
+	}
+	return entry
+}
+
+// sameFieldValue compares two tracked field values; tags compare in order
+func sameFieldValue(a, b interface{}) bool {
+	aTags, aIsTags := a.([]string)
+	bTags, bIsTags := b.([]string)
+	if aIsTags || bIsTags {
+		if len(aTags) != len(bTags) {
+			return false
+		}
+		for i := range aTags {
+			if aTags[i] != bTags[i] {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
@@ -13,8 +13,17 @@ type User struct {
 	FirstName    string    `json:"first_name" db:"first_name"`
 	LastName     string    `json:"last_name" db:"last_name"`
 	IsActive     bool      `json:"is_active" db:"is_active"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	IsSuperAdmin bool      `json:"is_superadmin" db:"is_superadmin"` // Platform operator
+	// IsServiceAccount marks non-interactive principals that authenticate with API keys only
+	IsServiceAccount bool `json:"is_service_account" db:"is_service_account"`
+	// FailedLoginAttempts counts consecutive failed logins; reaching the
+	// lockout threshold locks the account until LockedUntil
+	FailedLoginAttempts int        `json:"-" db:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"-" db:"locked_until"`
+	// EmailVerified is set once the user follows the emailed verification link
+	EmailVerified bool      `json:"email_verified" db:"email_verified"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type UserRegistration struct {
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	WebhookEventScanProgress  = "scan.progress"
+	WebhookEventScanCompleted = "scan.completed"
+)
+
+// WebhookEvents lists every event a webhook may subscribe to
+var WebhookEvents = []string{
+	WebhookEventScanProgress,
+	WebhookEventScanCompleted,
+}
+
+type Webhook struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	OrganizationID uuid.UUID `json:"organization_id" db:"organization_id"`
+	Name           string    `json:"name" db:"name"`
+	URL            string    `json:"url" db:"url"`
+	Events         []string  `json:"events" db:"events"`
+	Secret         string    `json:"-" db:"secret"`
+	IsActive       bool      `json:"is_active" db:"is_active"`
+	CreatedBy      uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookPayload is the JSON body delivered to webhook endpoints
+type WebhookPayload struct {
+	ID             uuid.UUID   `json:"id"`
+	Event          string      `json:"event"`
+	OrganizationID uuid.UUID   `json:"organization_id"`
+	OccurredAt     time.Time   `json:"occurred_at"`
+	Data           interface{} `json:"data"`
+}
+
+// ScanProgressEvent is the data of a scan.progress webhook event
+type ScanProgressEvent struct {
+	ScanID    uuid.UUID  `json:"scan_id"`
+	Status    ScanStatus `json:"status"`
+	Milestone int        `json:"milestone"`
+	Progress  int        `json:"progress"`
+}
+
+// ScanCompletedEvent is the data of a scan.completed webhook event, sent
+// when a scan completes or fails. FailureCode and FailureReason are set for
+// failed scans.
+type ScanCompletedEvent struct {
+	ScanID        uuid.UUID    `json:"scan_id"`
+	Status        ScanStatus   `json:"status"`
+	FailureCode   *FailureCode `json:"failure_code,omitempty"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+}
+
+// WebhookAttempt records the outcome of a single delivery attempt
+type WebhookAttempt struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// WebhookFailure is a dead-lettered event whose delivery attempts were all exhausted
+type WebhookFailure struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	WebhookID  uuid.UUID        `json:"webhook_id" db:"webhook_id"`
+	DeliveryID uuid.UUID        `json:"delivery_id" db:"delivery_id"`
+	Event      string           `json:"event" db:"event"`
+	Payload    json.RawMessage  `json:"payload" db:"payload"`
+	Attempts   []WebhookAttempt `json:"attempts" db:"attempts"`
+	LastError  string           `json:"last_error" db:"last_error"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Wordlist is an uploaded bruteforce wordlist, referenced from a scan's
+// config.wordlist_id
+type Wordlist struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id" db:"organization_id"`
+	Name           string     `json:"name" db:"name"`
+	StorageKey     string     `json:"-" db:"storage_key"`
+	SizeBytes      int64      `json:"size_bytes" db:"size_bytes"`
+	LineCount      int        `json:"line_count" db:"line_count"`
+	CreatedBy      *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+
+	// ActiveScans counts the scheduled, queued and running scans that use
+	// the wordlist; it cannot be deleted while this is non-zero
+	ActiveScans int  `json:"active_scans" db:"-"`
+	InUse       bool `json:"in_use" db:"-"`
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrAcknowledgementRuleNotFound = errors.New("acknowledgement rule not found")
+)
+
+// AcknowledgementRepository handles acknowledgement rule database operations
+type AcknowledgementRepository struct {
+	db *sql.DB
+}
+
+// NewAcknowledgementRepository creates a new acknowledgement rule repository
+func NewAcknowledgementRepository(db *sql.DB) *AcknowledgementRepository {
+	return &AcknowledgementRepository{db: db}
+}
+
+// Create stores a new acknowledgement rule
+func (r *AcknowledgementRepository) Create(rule *models.AcknowledgementRule) error {
+	query := `
+		INSERT INTO acknowledgement_rules (id, organization_id, check_type, match_severity, reason, snooze_until, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		rule.ID,
+		rule.OrganizationID,
+		rule.CheckType,
+		rule.MatchSeverity,
+		rule.Reason,
+		rule.SnoozeUntil,
+		rule.CreatedBy,
+	).Scan(&rule.CreatedAt)
+}
+
+// ListByOrganization retrieves an organization's acknowledgement rules,
+// expired snoozes included, marking which are active at now
+func (r *AcknowledgementRepository) ListByOrganization(organizationID uuid.UUID, now time.Time) ([]*models.AcknowledgementRule, error) {
+	query := `
+		SELECT id, organization_id, check_type, match_severity, reason, snooze_until, created_by, created_at
+		FROM acknowledgement_rules
+		WHERE organization_id = $1
+		ORDER BY check_type, match_severity NULLS FIRST, created_at
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*models.AcknowledgementRule{}
+	for rows.Next() {
+		rule := &models.AcknowledgementRule{}
+		err := rows.Scan(
+			&rule.ID,
+			&rule.OrganizationID,
+			&rule.CheckType,
+			&rule.MatchSeverity,
+			&rule.Reason,
+			&rule.SnoozeUntil,
+			&rule.CreatedBy,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rule.Active = rule.SnoozeUntil == nil || rule.SnoozeUntil.After(now)
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// Delete removes an acknowledgement rule of an organization
+func (r *AcknowledgementRepository) Delete(organizationID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM acknowledgement_rules WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAcknowledgementRuleNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+// AttachmentRepository handles scan result attachment database operations
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates a new attachment repository
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create creates a new attachment record
+func (r *AttachmentRepository) Create(attachment *models.ScanResultAttachment) error {
+	query := `
+		INSERT INTO scan_result_attachments (id, result_id, scan_id, file_name, content_type, file_size, storage_key, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		attachment.ID,
+		attachment.ResultID,
+		attachment.ScanID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.FileSize,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+	).Scan(&attachment.CreatedAt)
+}
+
+// GetByID retrieves an attachment by ID
+func (r *AttachmentRepository) GetByID(id uuid.UUID) (*models.ScanResultAttachment, error) {
+	attachment := &models.ScanResultAttachment{}
+	query := `
+		SELECT id, result_id, scan_id, file_name, content_type, file_size, storage_key, uploaded_by, created_at
+		FROM scan_result_attachments
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(query, id).Scan(
+		&attachment.ID,
+		&attachment.ResultID,
+		&attachment.ScanID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.FileSize,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// ListByResult retrieves all attachments of a scan result
+func (r *AttachmentRepository) ListByResult(resultID uuid.UUID) ([]*models.ScanResultAttachment, error) {
+	query := `
+		SELECT id, result_id, scan_id, file_name, content_type, file_size, storage_key, uploaded_by, created_at
+		FROM scan_result_attachments
+		WHERE result_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, resultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.ScanResultAttachment
+	for rows.Next() {
+		attachment := &models.ScanResultAttachment{}
+
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.ResultID,
+			&attachment.ScanID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.FileSize,
+			&attachment.StorageKey,
+			&attachment.UploadedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// ListByScan retrieves all attachments of a scan's results
+func (r *AttachmentRepository) ListByScan(scanID uuid.UUID) ([]*models.ScanResultAttachment, error) {
+	query := `
+		SELECT id, result_id, scan_id, file_name, content_type, file_size, storage_key, uploaded_by, created_at
+		FROM scan_result_attachments
+		WHERE scan_id = $1
+		ORDER BY result_id, created_at ASC
+	`
+
+	rows, err := r.db.Query(query, scanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.ScanResultAttachment
+	for rows.Next() {
+		attachment := &models.ScanResultAttachment{}
+
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.ResultID,
+			&attachment.ScanID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.FileSize,
+			&attachment.StorageKey,
+			&attachment.UploadedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
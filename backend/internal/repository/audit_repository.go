@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"publicscannerapi/internal/models"
+)
+
+// AuditRepository handles audit log database operations
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create appends an entry to the audit trail
+func (r *AuditRepository) Create(entry *models.AuditLog) error {
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_logs (id, user_id, organization_id, actor_type, action, resource_type,
+			resource_id, ip_address, user_agent, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		entry.ID,
+		entry.UserID,
+		entry.OrganizationID,
+		entry.ActorType,
+		entry.Action,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.IPAddress,
+		entry.UserAgent,
+		metadata,
+	).Scan(&entry.CreatedAt)
+}
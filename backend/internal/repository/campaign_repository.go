@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrCampaignNotFound  = errors.New("campaign not found")
+	ErrCampaignNameTaken = errors.New("campaign name already in use")
+)
+
+// CampaignRepository handles campaign database operations
+type CampaignRepository struct {
+	db *sql.DB
+}
+
+// NewCampaignRepository creates a new campaign repository
+func NewCampaignRepository(db *sql.DB) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+// campaignColumns is the column list shared by every campaign query; the
+// scan count skips deleted scans
+const campaignColumns = `
+		c.id, c.organization_id, c.name, c.description, c.created_by, c.created_at, c.updated_at,
+		(SELECT COUNT(*) FROM scan_jobs s WHERE s.campaign_id = c.id AND s.deleted_at IS NULL),
+		(SELECT COUNT(*) FROM campaign_targets ct WHERE ct.campaign_id = c.id)
+`
+
+// scanCampaign reads a campaign row selected with campaignColumns
+func scanCampaign(row rowScanner) (*models.Campaign, error) {
+	campaign := &models.Campaign{}
+	err := row.Scan(
+		&campaign.ID,
+		&campaign.OrganizationID,
+		&campaign.Name,
+		&campaign.Description,
+		&campaign.CreatedBy,
+		&campaign.CreatedAt,
+		&campaign.UpdatedAt,
+		&campaign.ScanCount,
+		&campaign.TargetCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// Create stores a new campaign
+func (r *CampaignRepository) Create(campaign *models.Campaign) error {
+	query := `
+		INSERT INTO campaigns (id, organization_id, name, description, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		campaign.ID,
+		campaign.OrganizationID,
+		campaign.Name,
+		campaign.Description,
+		campaign.CreatedBy,
+	).Scan(&campaign.CreatedAt, &campaign.UpdatedAt)
+	if err != nil {
+		// Check for unique constraint violation
+		if err.Error() == `pq: duplicate key value violates unique constraint "idx_campaigns_org_name"` {
+			return ErrCampaignNameTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID retrieves a campaign by ID
+func (r *CampaignRepository) GetByID(id uuid.UUID) (*models.Campaign, error) {
+	query := `SELECT ` + campaignColumns + `
+		FROM campaigns c
+		WHERE c.id = $1
+	`
+
+	campaign, err := scanCampaign(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// ListByOrganization retrieves an organization's campaigns, newest first
+func (r *CampaignRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.Campaign, error) {
+	query := `SELECT ` + campaignColumns + `
+		FROM campaigns c
+		WHERE c.organization_id = $1
+		ORDER BY c.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	campaigns := []*models.Campaign{}
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, rows.Err()
+}
+
+// SeverityRollup sums the findings of a campaign's completed, non-deleted
+// scans by severity, returning the number of scans counted
+func (r *CampaignRepository) SeverityRollup(campaignID uuid.UUID) (int, models.SeverityCounts, error) {
+	query := `
+		WITH scans AS (
+			SELECT id FROM scan_jobs
+			WHERE campaign_id = $1 AND status = 'completed' AND deleted_at IS NULL
+		),
+		rollup AS (` + severityRollupSelect + `
+			WHERE scan_id IN (SELECT id FROM scans)
+			GROUP BY scan_id
+		)
+		SELECT (SELECT COUNT(*) FROM scans),
+		       COALESCE(SUM(critical), 0), COALESCE(SUM(high), 0), COALESCE(SUM(medium), 0),
+		       COALESCE(SUM(low), 0), COALESCE(SUM(info), 0)
+		FROM rollup
+	`
+
+	var completed int
+	var counts models.SeverityCounts
+	err := r.db.QueryRow(query, campaignID).Scan(
+		&completed,
+		&counts.Critical,
+		&counts.High,
+		&counts.Medium,
+		&counts.Low,
+		&counts.Info,
+	)
+	if err != nil {
+		return 0, models.SeverityCounts{}, err
+	}
+
+	return completed, counts, nil
+}
+
+// CampaignTargetSelection picks an organization's targets for a bulk
+// assignment, either by ID or by filter. Tag and Search narrow each other;
+// an empty selection matches every target.
+type CampaignTargetSelection struct {
+	IDs    []uuid.UUID // explicit targets, not combined with the filters
+	Tag    string      // targets carrying this tag
+	Search string      // case-insensitive substring of the name or hostname
+}
+
+// likePattern builds an ILIKE pattern matching s anywhere, with LIKE
+// wildcards in s escaped
+func likePattern(s string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + escaped + "%"
+}
+
+// AssignTargets associates the organization's targets matching selection
+// with a campaign in one transaction. Targets already assigned are counted
+// but left alone. When selection.IDs names a target outside the
+// organization, nothing is assigned and ErrTargetNotFound is returned.
+func (r *CampaignRepository) AssignTargets(campaignID, organizationID uuid.UUID, selection CampaignTargetSelection) (*models.CampaignTargetAssignment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	search := ""
+	if selection.Search != "" {
+		search = likePattern(selection.Search)
+	}
+
+	// Lock the matched targets so none is deleted before the insert
+	rows, err := tx.Query(`
+		SELECT id FROM targets
+		WHERE organization_id = $1 AND deleted_at IS NULL
+		  AND (cardinality($2::uuid[]) = 0 OR id = ANY($2))
+		  AND ($3 = '' OR $3 = ANY(tags))
+		  AND ($4 = '' OR name ILIKE $4 OR hostname ILIKE $4)
+		FOR SHARE
+	`, organizationID, pq.Array(selection.IDs), selection.Tag, search)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		matched[id] = true
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range selection.IDs {
+		if !matched[id] {
+			return nil, fmt.Errorf("%w: %s", ErrTargetNotFound, id)
+		}
+	}
+
+	assignment := &models.CampaignTargetAssignment{Matched: len(ids)}
+	if len(ids) == 0 {
+		return assignment, nil
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO campaign_targets (campaign_id, target_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`, campaignID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	assignment.Added = int(added)
+	assignment.AlreadyAssigned = assignment.Matched - assignment.Added
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// ListTargets retrieves the targets assigned to a campaign
+func (r *CampaignRepository) ListTargets(campaignID uuid.UUID) ([]*models.Target, error) {
+	query := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE id IN (SELECT target_id FROM campaign_targets WHERE campaign_id = $1)
+		  AND deleted_at IS NULL
+		ORDER BY name, hostname
+	`
+
+	rows, err := r.db.Query(query, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := []*models.Target{}
+	for rows.Next() {
+		target, err := scanTarget(rows)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, rows.Err()
+}
+
+// HostRisks rolls up a campaign's findings by host, riskiest first. A
+// finding's fingerprint is its host and check: when several completed scans
+// of the campaign ran the same check against the same host, only the latest
+// result counts.
+func (r *CampaignRepository) HostRisks(campaignID uuid.UUID) ([]*models.CampaignHostRisk, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (host, sr.check_type)
+			       host, s.target_id, t.name AS target_name,
+			       sr.check_type, sr.findings, sr.severity, sr.findings_by_severity
+			FROM scan_results sr
+			JOIN scan_jobs s ON s.id = sr.scan_id
+			LEFT JOIN targets t ON t.id = s.target_id
+			CROSS JOIN LATERAL (SELECT lower(COALESCE(t.hostname, s.url)) AS host) h
+			WHERE s.campaign_id = $1 AND s.status = 'completed' AND s.deleted_at IS NULL
+			ORDER BY host, sr.check_type, s.completed_at DESC, sr.created_at DESC
+		),
+		r AS (
+			SELECT host, (array_agg(target_id) FILTER (WHERE target_id IS NOT NULL))[1] AS target_id,
+			       (array_agg(target_name) FILTER (WHERE target_name IS NOT NULL))[1] AS target_name,` + severityRollupColumns() + `
+			FROM latest
+			GROUP BY host
+		)
+		SELECT host, target_id, target_name, critical, high, medium, low, info,
+		       ` + riskScoreExpr + ` AS risk_score
+		FROM r
+		ORDER BY risk_score DESC, host
+	`
+
+	rows, err := r.db.Query(query, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hosts := []*models.CampaignHostRisk{}
+	for rows.Next() {
+		host := &models.CampaignHostRisk{}
+		err := rows.Scan(
+			&host.Host,
+			&host.TargetID,
+			&host.TargetName,
+			&host.Severity.Critical,
+			&host.Severity.High,
+			&host.Severity.Medium,
+			&host.Severity.Low,
+			&host.Severity.Info,
+			&host.RiskScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	return hosts, rows.Err()
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrCertificateNotFound = errors.New("client certificate not found")
+)
+
+// CertificateRepository handles client certificate database operations
+type CertificateRepository struct {
+	db *sql.DB
+}
+
+// NewCertificateRepository creates a new client certificate repository
+func NewCertificateRepository(db *sql.DB) *CertificateRepository {
+	return &CertificateRepository{db: db}
+}
+
+// certificateColumns is the column list shared by every client certificate query
+const certificateColumns = `
+		id, organization_id, name, subject, issuer, fingerprint, not_before, not_after,
+		certificate_pem, encrypted_key, created_by, created_at
+`
+
+// scanCertificate reads a client certificate row selected with certificateColumns
+func scanCertificate(row rowScanner) (*models.ClientCertificate, error) {
+	cert := &models.ClientCertificate{}
+
+	err := row.Scan(
+		&cert.ID,
+		&cert.OrganizationID,
+		&cert.Name,
+		&cert.Subject,
+		&cert.Issuer,
+		&cert.Fingerprint,
+		&cert.NotBefore,
+		&cert.NotAfter,
+		&cert.CertificatePEM,
+		&cert.EncryptedKey,
+		&cert.CreatedBy,
+		&cert.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// Create stores a new client certificate
+func (r *CertificateRepository) Create(cert *models.ClientCertificate) error {
+	query := `
+		INSERT INTO client_certificates (id, organization_id, name, subject, issuer, fingerprint,
+			not_before, not_after, certificate_pem, encrypted_key, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		cert.ID,
+		cert.OrganizationID,
+		cert.Name,
+		cert.Subject,
+		cert.Issuer,
+		cert.Fingerprint,
+		cert.NotBefore,
+		cert.NotAfter,
+		cert.CertificatePEM,
+		cert.EncryptedKey,
+		cert.CreatedBy,
+	).Scan(&cert.CreatedAt)
+}
+
+// GetByID retrieves a client certificate by ID
+func (r *CertificateRepository) GetByID(id uuid.UUID) (*models.ClientCertificate, error) {
+	query := `SELECT ` + certificateColumns + `
+		FROM client_certificates
+		WHERE id = $1
+	`
+
+	cert, err := scanCertificate(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// ListByOrganization retrieves all client certificates for an organization
+func (r *CertificateRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.ClientCertificate, error) {
+	query := `SELECT ` + certificateColumns + `
+		FROM client_certificates
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var certs []*models.ClientCertificate
+	for rows.Next() {
+		cert, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	return certs, rows.Err()
+}
+
+// Delete deletes a client certificate
+func (r *CertificateRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM client_certificates WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrCertificateNotFound
+	}
+
+	return nil
+}
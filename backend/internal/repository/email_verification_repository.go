@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var ErrVerificationTokenInvalid = errors.New("email verification token is invalid, used or expired")
+
+// EmailVerificationRepository handles email verification token database operations
+type EmailVerificationRepository struct {
+	db *sql.DB
+}
+
+// NewEmailVerificationRepository creates a new email verification token repository
+func NewEmailVerificationRepository(db *sql.DB) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// Create stores a new verification token, voiding the user's earlier unused
+// ones so that only the latest link works
+func (r *EmailVerificationRepository) Create(token *models.EmailVerificationToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, token.UserID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+	if err := tx.QueryRow(query, token.ID, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Verify uses the unexpired, unused token with tokenHash to mark its user's
+// email as verified, in one transaction, and voids the user's other tokens.
+// ErrVerificationTokenInvalid is returned when no usable token matches.
+func (r *EmailVerificationRepository) Verify(tokenHash string) (uuid.UUID, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var userID uuid.UUID
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err = tx.QueryRow(query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, ErrVerificationTokenInvalid
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE users SET email_verified = true WHERE id = $1`, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
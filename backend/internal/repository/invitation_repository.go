@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationNotOpen  = errors.New("invitation is no longer pending")
+)
+
+// InvitationRepository handles organization invitation database operations
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+// NewInvitationRepository creates a new invitation repository
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+// invitationStatusExpr derives an invitation's status from its timestamps
+const invitationStatusExpr = `
+		CASE
+			WHEN revoked_at IS NOT NULL THEN 'revoked'
+			WHEN accepted_at IS NOT NULL THEN 'accepted'
+			WHEN expires_at <= CURRENT_TIMESTAMP THEN 'expired'
+			ELSE 'pending'
+		END
+`
+
+// invitationColumns is the column list shared by every invitation query
+const invitationColumns = `
+		id, organization_id, email, role, ` + invitationStatusExpr + ` AS status,
+		token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+// scanInvitation reads an invitation row selected with invitationColumns
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.OrganizationID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.Status,
+		&invitation.TokenHash,
+		&invitation.InvitedBy,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.RevokedAt,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// Create creates a new invitation
+func (r *InvitationRepository) Create(invitation *models.Invitation) error {
+	query := `
+		INSERT INTO organization_invitations (id, organization_id, email, role, token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		invitation.ID,
+		invitation.OrganizationID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+	).Scan(&invitation.CreatedAt)
+}
+
+// GetByID retrieves an invitation by ID
+func (r *InvitationRepository) GetByID(id uuid.UUID) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + `
+		FROM organization_invitations
+		WHERE id = $1
+	`
+
+	invitation, err := scanInvitation(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// GetByTokenHash retrieves an invitation by the hash of its token
+func (r *InvitationRepository) GetByTokenHash(tokenHash string) (*models.Invitation, error) {
+	query := `SELECT ` + invitationColumns + `
+		FROM organization_invitations
+		WHERE token_hash = $1
+	`
+
+	invitation, err := scanInvitation(r.db.QueryRow(query, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// InvitationListFilter narrows an invitation listing
+type InvitationListFilter struct {
+	Status    *models.InvitationStatus // nil lists every status
+	Ascending bool                     // oldest first instead of newest first
+}
+
+// ListByOrganization retrieves a page of an organization's invitations
+// matching filter, along with the total number of matches
+func (r *InvitationRepository) ListByOrganization(organizationID uuid.UUID, filter InvitationListFilter, limit, offset int) ([]*models.Invitation, int, error) {
+	where := "organization_id = $1"
+	args := []interface{}{organizationID}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		where += fmt.Sprintf(" AND "+invitationStatusExpr+" = $%d", len(args))
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM organization_invitations WHERE ` + where
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order := "DESC"
+	if filter.Ascending {
+		order = "ASC"
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`SELECT `+invitationColumns+`
+		FROM organization_invitations
+		WHERE %s
+		ORDER BY created_at %s
+		LIMIT $%d OFFSET $%d
+	`, where, order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, total, rows.Err()
+}
+
+// CountByStatus tallies an organization's invitations by status
+func (r *InvitationRepository) CountByStatus(organizationID uuid.UUID) (*models.InvitationCounts, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM (
+			SELECT ` + invitationStatusExpr + ` AS status
+			FROM organization_invitations
+			WHERE organization_id = $1
+		) statuses
+		GROUP BY status
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := &models.InvitationCounts{}
+	for rows.Next() {
+		var status models.InvitationStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+
+		switch status {
+		case models.InvitationPending:
+			counts.Pending = count
+		case models.InvitationAccepted:
+			counts.Accepted = count
+		case models.InvitationExpired:
+			counts.Expired = count
+		case models.InvitationRevoked:
+			counts.Revoked = count
+		}
+	}
+
+	return counts, rows.Err()
+}
+
+// Revoke revokes a pending invitation
+func (r *InvitationRepository) Revoke(id uuid.UUID) error {
+	query := `
+		UPDATE organization_invitations
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL AND accepted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrInvitationNotOpen
+	}
+
+	return nil
+}
+
+// Accept marks a pending invitation accepted and adds the user to the
+// organization with the invited role, in one transaction
+func (r *InvitationRepository) Accept(invitation *models.Invitation, userID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE organization_invitations
+		SET accepted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL AND accepted_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING accepted_at
+	`
+	err = tx.QueryRow(query, invitation.ID).Scan(&invitation.AcceptedAt)
+	if err == sql.ErrNoRows {
+		return ErrInvitationNotOpen
+	}
+	if err != nil {
+		return err
+	}
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+	`
+	if _, err := tx.Exec(memberQuery, invitation.OrganizationID, userID, invitation.Role); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	invitation.Status = models.InvitationAccepted
+	return nil
+}
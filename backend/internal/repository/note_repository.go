@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+// NoteRepository handles scan result note database operations
+type NoteRepository struct {
+	db *sql.DB
+}
+
+// NewNoteRepository creates a new note repository
+func NewNoteRepository(db *sql.DB) *NoteRepository {
+	return &NoteRepository{db: db}
+}
+
+// Create creates a new note on a scan result
+func (r *NoteRepository) Create(note *models.ScanResultNote) error {
+	query := `
+		INSERT INTO scan_result_notes (id, result_id, scan_id, author_id, triage_status, body)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		note.ID,
+		note.ResultID,
+		note.ScanID,
+		note.AuthorID,
+		note.TriageStatus,
+		note.Body,
+	).Scan(&note.CreatedAt)
+}
+
+// ListByResult retrieves the notes of a scan result, oldest first
+func (r *NoteRepository) ListByResult(resultID uuid.UUID) ([]*models.ScanResultNote, error) {
+	query := `
+		SELECT id, result_id, scan_id, author_id, triage_status, body, created_at
+		FROM scan_result_notes
+		WHERE result_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, resultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []*models.ScanResultNote{}
+	for rows.Next() {
+		note := &models.ScanResultNote{}
+		if err := rows.Scan(
+			&note.ID,
+			&note.ResultID,
+			&note.ScanID,
+			&note.AuthorID,
+			&note.TriageStatus,
+			&note.Body,
+			&note.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
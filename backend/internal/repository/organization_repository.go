@@ -0,0 +1,784 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidSort          = errors.New("invalid sort")
+	ErrAlreadyMember        = errors.New("user is already a member of the organization")
+)
+
+// OrganizationRepository handles organization database operations
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// GetByID retrieves an organization by ID
+func (r *OrganizationRepository) GetByID(id uuid.UUID) (*models.Organization, error) {
+	org := &models.Organization{}
+	query := `
+		SELECT id, name, owner_id, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(query, id).Scan(
+		&org.ID,
+		&org.Name,
+		&org.OwnerID,
+		&org.CreatedAt,
+		&org.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// organizationSortColumns maps the sort keys of ListWithStats to their
+// expressions
+var organizationSortColumns = map[string]string{
+	"name":          "o.name",
+	"created_at":    "o.created_at",
+	"members":       "member_count",
+	"scans":         "scan_count",
+	"storage":       "storage_bytes",
+	"last_activity": "last_activity_at",
+}
+
+// ListWithStats retrieves a page of all organizations with their member
+// and scan counts, storage usage and last activity, sorted by sort (one of
+// the organizationSortColumns keys), together with the total number of
+// organizations
+func (r *OrganizationRepository) ListWithStats(sort string, descending bool, limit, offset int) ([]*models.OrganizationStats, int, error) {
+	column, ok := organizationSortColumns[sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %q", ErrInvalidSort, sort)
+	}
+	direction := "ASC NULLS FIRST"
+	if descending {
+		direction = "DESC NULLS LAST"
+	}
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM organizations`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		WITH members AS (
+			SELECT organization_id, COUNT(*) AS n FROM organization_members GROUP BY organization_id
+		),
+		scans AS (
+			SELECT organization_id, COUNT(*) AS n, MAX(created_at) AS last
+			FROM scan_jobs WHERE deleted_at IS NULL
+			GROUP BY organization_id
+		),
+		storage AS (
+			SELECT organization_id, SUM(bytes) AS bytes FROM (
+				SELECT organization_id, file_size AS bytes FROM reports
+				UNION ALL
+				SELECT s.organization_id, a.file_size FROM scan_result_attachments a JOIN scan_jobs s ON s.id = a.scan_id
+				UNION ALL
+				SELECT organization_id, size_bytes FROM wordlists
+			) files
+			GROUP BY organization_id
+		),
+		activity AS (
+			SELECT organization_id, MAX(created_at) AS last FROM audit_logs GROUP BY organization_id
+		)
+		SELECT o.id, o.name, o.owner_id, o.created_at, o.updated_at,
+		       COALESCE(m.n, 0) AS member_count,
+		       COALESCE(sc.n, 0) AS scan_count,
+		       COALESCE(st.bytes, 0)::bigint AS storage_bytes,
+		       GREATEST(sc.last, a.last) AS last_activity_at
+		FROM organizations o
+		LEFT JOIN members m ON m.organization_id = o.id
+		LEFT JOIN scans sc ON sc.organization_id = o.id
+		LEFT JOIN storage st ON st.organization_id = o.id
+		LEFT JOIN activity a ON a.organization_id = o.id
+		ORDER BY %s %s, o.id
+		LIMIT $1 OFFSET $2
+	`, column, direction)
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	organizations := []*models.OrganizationStats{}
+	for rows.Next() {
+		stats := &models.OrganizationStats{}
+		err := rows.Scan(
+			&stats.ID,
+			&stats.Name,
+			&stats.OwnerID,
+			&stats.CreatedAt,
+			&stats.UpdatedAt,
+			&stats.MemberCount,
+			&stats.ScanCount,
+			&stats.StorageBytes,
+			&stats.LastActivityAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		organizations = append(organizations, stats)
+	}
+
+	return organizations, total, rows.Err()
+}
+
+// IsMember reports whether a user belongs to an organization
+func (r *OrganizationRepository) IsMember(organizationID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM organization_members
+			WHERE organization_id = $1 AND user_id = $2
+		)
+	`
+
+	err := r.db.QueryRow(query, organizationID, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetUsage aggregates an organization's resource usage in a single round trip.
+// Scans are counted from periodStart onwards.
+func (r *OrganizationRepository) GetUsage(organizationID uuid.UUID, periodStart time.Time) (*models.OrganizationUsage, error) {
+	usage := &models.OrganizationUsage{
+		OrganizationID: organizationID,
+		PeriodStart:    periodStart,
+	}
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM targets WHERE organization_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM scan_jobs WHERE organization_id = $1 AND created_at >= $2),
+			(SELECT COALESCE(SUM(file_size), 0) FROM reports WHERE organization_id = $1),
+			(SELECT COUNT(*)
+			   FROM organization_members om
+			   JOIN users u ON u.id = om.user_id
+			  WHERE om.organization_id = $1 AND u.is_active = true AND u.is_service_account = false)
+	`
+
+	err := r.db.QueryRow(query, organizationID, periodStart).Scan(
+		&usage.Targets,
+		&usage.ScansThisMonth,
+		&usage.ReportStorageUsed,
+		&usage.ActiveMembers,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// GetNotificationPreferences retrieves an organization's notification
+// preferences, falling back to the defaults when none were stored
+func (r *OrganizationRepository) GetNotificationPreferences(organizationID uuid.UUID) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{}
+	query := `
+		SELECT organization_id, scan_completed, scan_progress, high_severity_findings,
+		       certificate_expiry, updated_by, updated_at
+		FROM organization_notification_preferences
+		WHERE organization_id = $1
+	`
+
+	err := r.db.QueryRow(query, organizationID).Scan(
+		&prefs.OrganizationID,
+		&prefs.ScanCompleted,
+		&prefs.ScanProgress,
+		&prefs.HighSeverityFindings,
+		&prefs.CertificateExpiry,
+		&prefs.UpdatedBy,
+		&prefs.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return models.DefaultNotificationPreferences(organizationID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// UpsertNotificationPreferences stores an organization's notification preferences
+func (r *OrganizationRepository) UpsertNotificationPreferences(prefs *models.NotificationPreferences) error {
+	query := `
+		INSERT INTO organization_notification_preferences (organization_id, scan_completed, scan_progress,
+			high_severity_findings, certificate_expiry, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET scan_completed = EXCLUDED.scan_completed,
+		    scan_progress = EXCLUDED.scan_progress,
+		    high_severity_findings = EXCLUDED.high_severity_findings,
+		    certificate_expiry = EXCLUDED.certificate_expiry,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		prefs.OrganizationID,
+		prefs.ScanCompleted,
+		prefs.ScanProgress,
+		prefs.HighSeverityFindings,
+		prefs.CertificateExpiry,
+		prefs.UpdatedBy,
+	).Scan(&prefs.UpdatedAt)
+}
+
+// GetReportBranding retrieves an organization's report branding, returning
+// an empty branding (product defaults) when none was stored
+func (r *OrganizationRepository) GetReportBranding(organizationID uuid.UUID) (*models.ReportBranding, error) {
+	branding := &models.ReportBranding{}
+	query := `
+		SELECT organization_id, company_name, primary_color, footer_text,
+		       logo_storage_key, logo_content_type, updated_by, updated_at
+		FROM organization_report_branding
+		WHERE organization_id = $1
+	`
+
+	err := r.db.QueryRow(query, organizationID).Scan(
+		&branding.OrganizationID,
+		&branding.CompanyName,
+		&branding.PrimaryColor,
+		&branding.FooterText,
+		&branding.LogoStorageKey,
+		&branding.LogoContentType,
+		&branding.UpdatedBy,
+		&branding.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return &models.ReportBranding{OrganizationID: organizationID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	branding.HasLogo = branding.LogoStorageKey != nil
+	return branding, nil
+}
+
+// UpsertReportBranding stores an organization's report branding
+func (r *OrganizationRepository) UpsertReportBranding(branding *models.ReportBranding) error {
+	query := `
+		INSERT INTO organization_report_branding (organization_id, company_name, primary_color,
+			footer_text, logo_storage_key, logo_content_type, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET company_name = EXCLUDED.company_name,
+		    primary_color = EXCLUDED.primary_color,
+		    footer_text = EXCLUDED.footer_text,
+		    logo_storage_key = EXCLUDED.logo_storage_key,
+		    logo_content_type = EXCLUDED.logo_content_type,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		branding.OrganizationID,
+		branding.CompanyName,
+		branding.PrimaryColor,
+		branding.FooterText,
+		branding.LogoStorageKey,
+		branding.LogoContentType,
+		branding.UpdatedBy,
+	).Scan(&branding.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	branding.HasLogo = branding.LogoStorageKey != nil
+	return nil
+}
+
+// GetSessionPolicy retrieves an organization's session policy
+func (r *OrganizationRepository) GetSessionPolicy(organizationID uuid.UUID) (*models.SessionPolicy, error) {
+	policy := &models.SessionPolicy{}
+	query := `
+		SELECT id, idle_timeout_minutes
+		FROM organizations
+		WHERE id = $1
+	`
+
+	err := r.db.QueryRow(query, organizationID).Scan(&policy.OrganizationID, &policy.IdleTimeoutMinutes)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// UpdateSessionPolicy stores an organization's session policy
+func (r *OrganizationRepository) UpdateSessionPolicy(policy *models.SessionPolicy) error {
+	query := `UPDATE organizations SET idle_timeout_minutes = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	result, err := r.db.Exec(query, policy.OrganizationID, policy.IdleTimeoutMinutes)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrOrganizationNotFound
+	}
+
+	return nil
+}
+
+// CreateWithOwner creates a user, an organization owned by them and the
+// owner membership row in one transaction
+func (r *OrganizationRepository) CreateWithOwner(org *models.Organization, owner *models.User) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	userQuery := `
+		INSERT INTO users (id, email, password_hash, first_name, last_name, is_active, email_verified)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRow(
+		userQuery,
+		owner.ID,
+		owner.Email,
+		owner.PasswordHash,
+		owner.FirstName,
+		owner.LastName,
+		owner.IsActive,
+		owner.EmailVerified,
+	).Scan(&owner.CreatedAt, &owner.UpdatedAt)
+	if err != nil {
+		if err.Error() == `pq: duplicate key value violates unique constraint "users_email_key"` {
+			return ErrEmailExists
+		}
+		return err
+	}
+
+	orgQuery := `
+		INSERT INTO organizations (id, name, owner_id)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`
+	if err := tx.QueryRow(orgQuery, org.ID, org.Name, owner.ID).Scan(&org.CreatedAt, &org.UpdatedAt); err != nil {
+		return err
+	}
+	org.OwnerID = owner.ID
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(memberQuery, org.ID, owner.ID, models.RoleOwner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Create creates an organization owned by org.OwnerID together with the
+// owner membership row in one transaction
+func (r *OrganizationRepository) Create(org *models.Organization) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	orgQuery := `
+		INSERT INTO organizations (id, name, owner_id)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`
+	if err := tx.QueryRow(orgQuery, org.ID, org.Name, org.OwnerID).Scan(&org.CreatedAt, &org.UpdatedAt); err != nil {
+		return err
+	}
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(memberQuery, org.ID, org.OwnerID, models.RoleOwner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListForUser retrieves the organizations a user belongs to with their role
+// in each, in the order they joined
+func (r *OrganizationRepository) ListForUser(userID uuid.UUID) ([]*models.MemberOrganization, error) {
+	query := `
+		SELECT o.id, o.name, o.owner_id, o.created_at, o.updated_at, m.role, m.joined_at
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.organization_id
+		WHERE m.user_id = $1
+		ORDER BY m.joined_at, o.id
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []*models.MemberOrganization{}
+	for rows.Next() {
+		org := &models.MemberOrganization{}
+		err := rows.Scan(
+			&org.ID,
+			&org.Name,
+			&org.OwnerID,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+			&org.Role,
+			&org.JoinedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, org)
+	}
+
+	return organizations, rows.Err()
+}
+
+// ListMembers retrieves an organization's members with their user details,
+// owner first
+func (r *OrganizationRepository) ListMembers(organizationID uuid.UUID) ([]*models.MemberDetail, error) {
+	query := `
+		SELECT m.id, m.organization_id, m.user_id, m.role, m.joined_at,
+		       u.email, u.first_name, u.last_name, u.is_service_account
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.role = 'owner' DESC, m.joined_at, u.email
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*models.MemberDetail{}
+	for rows.Next() {
+		member := &models.MemberDetail{}
+		err := rows.Scan(
+			&member.ID,
+			&member.OrganizationID,
+			&member.UserID,
+			&member.Role,
+			&member.JoinedAt,
+			&member.Email,
+			&member.FirstName,
+			&member.LastName,
+			&member.IsServiceAccount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// AddMember adds a user to an organization with the given role, returning
+// ErrAlreadyMember when they already belong to it
+func (r *OrganizationRepository) AddMember(member *models.OrganizationMember) error {
+	query := `
+		INSERT INTO organization_members (id, organization_id, user_id, role)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+		RETURNING joined_at
+	`
+
+	err := r.db.QueryRow(query, member.ID, member.OrganizationID, member.UserID, member.Role).Scan(&member.JoinedAt)
+	if err == sql.ErrNoRows {
+		return ErrAlreadyMember
+	}
+	return err
+}
+
+// UpdateMemberRole changes a member's role. The owner membership is never
+// changed here; ErrNotMember is returned for non-members and the owner.
+func (r *OrganizationRepository) UpdateMemberRole(organizationID, userID uuid.UUID, role models.Role) (*models.OrganizationMember, error) {
+	query := `
+		UPDATE organization_members
+		SET role = $3
+		WHERE organization_id = $1 AND user_id = $2 AND role <> 'owner'
+		RETURNING id, organization_id, user_id, role, joined_at
+	`
+
+	member := &models.OrganizationMember{}
+	err := r.db.QueryRow(query, organizationID, userID, role).Scan(
+		&member.ID,
+		&member.OrganizationID,
+		&member.UserID,
+		&member.Role,
+		&member.JoinedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotMember
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Merge moves everything a source organization owns into a destination
+// organization and deletes the source, in one transaction. Source targets
+// whose hostname already exists in the destination are folded into the
+// destination target. Members of both organizations keep the higher of
+// their roles, and the source owner joins as an admin. The merge is
+// recorded in the destination's audit log as performed by actorID.
+func (r *OrganizationRepository) Merge(sourceID, destinationID, actorID uuid.UUID) (*models.OrganizationMerge, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	merge := &models.OrganizationMerge{
+		SourceOrganizationID:      sourceID,
+		DestinationOrganizationID: destinationID,
+	}
+
+	// Lock both organizations so concurrent merges cannot interleave
+	rows, err := tx.Query(`SELECT id, name FROM organizations WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`, sourceID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+	found := 0
+	for rows.Next() {
+		var id uuid.UUID
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if id == sourceID {
+			merge.SourceOrganizationName = name
+		}
+		found++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if found != 2 {
+		return nil, ErrOrganizationNotFound
+	}
+
+	// Fold duplicate targets: repoint their scans and schedules, then drop
+	// the source copy
+	duplicates := `
+		SELECT s.id, MIN(d.id::text)::uuid
+		FROM targets s
+		JOIN targets d ON d.organization_id = $2 AND d.deleted_at IS NULL AND LOWER(d.hostname) = LOWER(s.hostname)
+		WHERE s.organization_id = $1
+		GROUP BY s.id
+	`
+	dupRows, err := tx.Query(duplicates, sourceID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+	mapping := map[uuid.UUID]uuid.UUID{}
+	for dupRows.Next() {
+		var sourceTarget, destinationTarget uuid.UUID
+		if err := dupRows.Scan(&sourceTarget, &destinationTarget); err != nil {
+			dupRows.Close()
+			return nil, err
+		}
+		mapping[sourceTarget] = destinationTarget
+	}
+	dupRows.Close()
+	if err := dupRows.Err(); err != nil {
+		return nil, err
+	}
+	for sourceTarget, destinationTarget := range mapping {
+		if _, err := tx.Exec(`UPDATE scan_jobs SET target_id = $2 WHERE target_id = $1`, sourceTarget, destinationTarget); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE scheduled_scans SET target_id = $2 WHERE target_id = $1`, sourceTarget, destinationTarget); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO campaign_targets (campaign_id, target_id)
+			SELECT campaign_id, $2 FROM campaign_targets WHERE target_id = $1
+			ON CONFLICT DO NOTHING
+		`, sourceTarget, destinationTarget); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`DELETE FROM targets WHERE id = $1`, sourceTarget); err != nil {
+			return nil, err
+		}
+	}
+	merge.TargetsMerged = len(mapping)
+
+	// Source campaigns named like a destination campaign are suffixed with
+	// the source organization's name so both survive the move
+	_, err = tx.Exec(`
+		UPDATE campaigns s
+		SET name = s.name || ' (' || (SELECT name FROM organizations WHERE id = $1) || ')'
+		WHERE s.organization_id = $1
+		  AND EXISTS (SELECT 1 FROM campaigns d WHERE d.organization_id = $2 AND d.name = s.name)
+	`, sourceID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Where both organizations override the same check and severity, the
+	// destination's rule wins and the source's is dropped before the move
+	_, err = tx.Exec(`
+		DELETE FROM severity_overrides s
+		WHERE s.organization_id = $1
+		  AND EXISTS (
+			SELECT 1 FROM severity_overrides d
+			WHERE d.organization_id = $2 AND d.check_type = s.check_type
+			  AND COALESCE(d.match_severity, '') = COALESCE(s.match_severity, ''))
+	`, sourceID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := []struct {
+		table string
+		count *int
+	}{
+		{"targets", &merge.TargetsMoved},
+		{"target_history", nil},
+		{"scan_jobs", &merge.ScansMoved},
+		{"scheduled_scans", nil},
+		{"reports", &merge.ReportsMoved},
+		{"scan_shares", nil},
+		{"client_certificates", nil},
+		{"wordlists", nil},
+		{"campaigns", nil},
+		{"webhooks", nil},
+		{"severity_overrides", nil},
+		{"acknowledgement_rules", nil},
+		{"api_keys", nil},
+		{"audit_logs", nil},
+	}
+	for _, move := range moves {
+		result, err := tx.Exec(`UPDATE `+move.table+` SET organization_id = $2 WHERE organization_id = $1`, sourceID, destinationID)
+		if err != nil {
+			return nil, err
+		}
+		if move.count != nil {
+			moved, err := result.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			*move.count = int(moved)
+		}
+	}
+
+	// Shared members keep their higher role; owners of the source join as admins
+	err = tx.QueryRow(`
+		SELECT COUNT(*) FROM organization_members s
+		JOIN organization_members d ON d.user_id = s.user_id AND d.organization_id = $2
+		WHERE s.organization_id = $1
+	`, sourceID, destinationID).Scan(&merge.MembersMerged)
+	if err != nil {
+		return nil, err
+	}
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role, joined_at)
+		SELECT $2, user_id, CASE WHEN role = 'owner' THEN 'admin' ELSE role END, joined_at
+		FROM organization_members
+		WHERE organization_id = $1
+		ON CONFLICT (organization_id, user_id) DO UPDATE
+		SET role = CASE
+			WHEN organization_members.role = 'owner' THEN 'owner'
+			WHEN array_position(ARRAY['billing', 'viewer', 'member', 'admin'], EXCLUDED.role)
+			   > array_position(ARRAY['billing', 'viewer', 'member', 'admin'], organization_members.role)
+			THEN EXCLUDED.role
+			ELSE organization_members.role
+		END
+	`
+	result, err := tx.Exec(memberQuery, sourceID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+	upserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	merge.MembersAdded = int(upserted) - merge.MembersMerged
+
+	// Settings, invitations and the remaining memberships go with the source
+	if _, err := tx.Exec(`DELETE FROM organizations WHERE id = $1`, sourceID); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO audit_logs (user_id, organization_id, actor_type, action, resource_type, resource_id, metadata)
+		VALUES ($1, $2, $3, 'organization.merge', 'organizations', $2,
+			jsonb_build_object(
+				'source_organization_id', $4::uuid,
+				'source_organization_name', $5::text,
+				'targets_moved', $6::int,
+				'targets_merged', $7::int,
+				'scans_moved', $8::int,
+				'reports_moved', $9::int,
+				'members_added', $10::int,
+				'members_merged', $11::int))
+		RETURNING created_at
+	`, actorID, destinationID, models.PrincipalUser, sourceID, merge.SourceOrganizationName,
+		merge.TargetsMoved, merge.TargetsMerged, merge.ScansMoved, merge.ReportsMoved,
+		merge.MembersAdded, merge.MembersMerged,
+	).Scan(&merge.MergedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// mergeMovedTables are the tables whose rows Merge moves to the destination
+var mergeMovedTables = []string{
+	"targets",
+	"target_history",
+	"scan_jobs",
+	"scheduled_scans",
+	"reports",
+	"scan_shares",
+	"client_certificates",
+	"wordlists",
+	"campaigns",
+	"webhooks",
+	"severity_overrides",
+	"acknowledgement_rules",
+	"api_keys",
+	"audit_logs",
+}
+
+func TestOrganizationRepositoryMerge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	sourceID, destinationID, actorID := uuid.New(), uuid.New(), uuid.New()
+	sourceTarget, destinationTarget := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, name FROM organizations WHERE id IN`).
+		WithArgs(sourceID, destinationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(sourceID, "Source").
+			AddRow(destinationID, "Destination"))
+	mock.ExpectQuery(`SELECT s.id, MIN\(d.id::text\)::uuid`).
+		WithArgs(sourceID, destinationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "min"}).AddRow(sourceTarget, destinationTarget))
+
+	// A folded target takes its scans and schedules to the destination target
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE scan_jobs SET target_id = $2 WHERE target_id = $1`)).
+		WithArgs(sourceTarget, destinationTarget).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE scheduled_scans SET target_id = $2 WHERE target_id = $1`)).
+		WithArgs(sourceTarget, destinationTarget).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO campaign_targets`).
+		WithArgs(sourceTarget, destinationTarget).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM targets WHERE id = $1`)).
+		WithArgs(sourceTarget).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectExec(`UPDATE campaigns s`).
+		WithArgs(sourceID, destinationID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Source overrides clashing with a destination rule give way to it
+	mock.ExpectExec(`DELETE FROM severity_overrides s`).
+		WithArgs(sourceID, destinationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for _, table := range mergeMovedTables {
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE `+table+` SET organization_id = $2 WHERE organization_id = $1`)).
+			WithArgs(sourceID, destinationID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM organization_members s`).
+		WithArgs(sourceID, destinationID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO organization_members`).
+		WithArgs(sourceID, destinationID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM organizations WHERE id = $1`)).
+		WithArgs(sourceID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO audit_logs`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectCommit()
+
+	merge, err := NewOrganizationRepository(db).Merge(sourceID, destinationID, actorID)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if merge.TargetsMerged != 1 || merge.ScansMoved != 2 || merge.MembersAdded != 2 || merge.MembersMerged != 1 {
+		t.Errorf("merge = %+v", merge)
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var ErrResetTokenInvalid = errors.New("password reset token is invalid, used or expired")
+
+// PasswordResetRepository handles password reset token database operations
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new password reset token repository
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+// Create stores a new reset token, voiding the user's earlier unused ones
+// so that only the latest link works
+func (r *PasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, token.UserID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+	if err := tx.QueryRow(query, token.ID, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetPassword uses the unexpired, unused token with tokenHash to set the
+// password hash of its user, in one transaction. The user's failed logins
+// and lockout are cleared, their refresh tokens are revoked so every login
+// has to sign in again, and their other reset tokens are voided.
+// ErrResetTokenInvalid is returned when no usable token matches.
+func (r *PasswordResetRepository) ResetPassword(tokenHash, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var userID uuid.UUID
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err = tx.QueryRow(query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return uuid.Nil, ErrResetTokenInvalid
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	query = `
+		UPDATE users
+		SET password_hash = $2, failed_login_attempts = 0, locked_until = NULL
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(query, userID, passwordHash); err != nil {
+		return uuid.Nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
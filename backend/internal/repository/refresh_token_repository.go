@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token was already used")
+)
+
+// RefreshTokenRepository handles refresh token database operations
+type RefreshTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *sql.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create records a newly issued refresh token
+func (r *RefreshTokenRepository) Create(token *models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, session_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(query, token.ID, token.UserID, token.SessionID, token.ExpiresAt).Scan(&token.CreatedAt)
+}
+
+// Rotate revokes the refresh token id and records replacement in its place.
+// Revoking only a token that is still active makes concurrent uses of the
+// same token race for it: exactly one wins and the others see
+// ErrRefreshTokenReused, as does any later use. ErrRefreshTokenNotFound is
+// returned for tokens that were never recorded or belong to another user.
+func (r *RefreshTokenRepository) Rotate(id, userID uuid.UUID, replacement *models.RefreshToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The replacement is inserted first so replaced_by can reference it
+	err = tx.QueryRow(`
+		INSERT INTO refresh_tokens (id, user_id, session_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, replacement.ID, replacement.UserID, replacement.SessionID, replacement.ExpiresAt).Scan(&replacement.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = NOW(), replaced_by = $3
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`, id, userID, replacement.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var revoked bool
+		err := tx.QueryRow(`
+			SELECT revoked_at IS NOT NULL FROM refresh_tokens WHERE id = $1 AND user_id = $2
+		`, id, userID).Scan(&revoked)
+		if err == sql.ErrNoRows {
+			return ErrRefreshTokenNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrRefreshTokenReused
+		}
+		// Recorded and not revoked, so it has expired
+		return ErrRefreshTokenNotFound
+	}
+
+	return tx.Commit()
+}
+
+// RevokeSession revokes every active refresh token of a session, ending
+// the token family, and returns how many were revoked
+func (r *RefreshTokenRepository) RevokeSession(userID uuid.UUID, sessionID string) (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND session_id = $2 AND revoked_at IS NULL
+	`, userID, sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -51,7 +51,7 @@ func (r *ReportRepository) GetByID(id uuid.UUID) (*models.Report, error) {
 	query := `
 		SELECT id, scan_id, organization_id, generated_by, format, file_name, file_path, file_size, created_at
 		FROM reports
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	err := r.db.QueryRow(query, id).Scan(
@@ -81,7 +81,7 @@ func (r *ReportRepository) ListByOrganization(organizationID uuid.UUID, limit, o
 	query := `
 		SELECT id, scan_id, organization_id, generated_by, format, file_name, file_path, file_size, created_at
 		FROM reports
-		WHERE organization_id = $1
+		WHERE organization_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -117,22 +117,33 @@ func (r *ReportRepository) ListByOrganization(organizationID uuid.UUID, limit, o
 	return reports, nil
 }
 
-// ListByScan retrieves all reports for a scan
-func (r *ReportRepository) ListByScan(scanID uuid.UUID) ([]*models.Report, error) {
+// CountByOrganization counts the reports of an organization
+func (r *ReportRepository) CountByOrganization(organizationID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM reports WHERE organization_id = $1 AND deleted_at IS NULL`
+
+	var count int
+	err := r.db.QueryRow(query, organizationID).Scan(&count)
+	return count, err
+}
+
+// ListByScan retrieves the reports of a scan, newest first, in format if
+// it is set. A limit of 0 returns every report.
+func (r *ReportRepository) ListByScan(scanID uuid.UUID, format string, limit, offset int) ([]*models.Report, error) {
 	query := `
 		SELECT id, scan_id, organization_id, generated_by, format, file_name, file_path, file_size, created_at
 		FROM reports
-		WHERE scan_id = $1
+		WHERE scan_id = $1 AND deleted_at IS NULL AND ($2 = '' OR format = $2)
 		ORDER BY created_at DESC
+		LIMIT NULLIF($3, 0) OFFSET $4
 	`
 
-	rows, err := r.db.Query(query, scanID)
+	rows, err := r.db.Query(query, scanID, format, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reports []*models.Report
+	reports := []*models.Report{}
 	for rows.Next() {
 		report := &models.Report{}
 
@@ -154,7 +165,37 @@ func (r *ReportRepository) ListByScan(scanID uuid.UUID) ([]*models.Report, error
 		reports = append(reports, report)
 	}
 
-	return reports, nil
+	return reports, rows.Err()
+}
+
+// CountByScan counts the reports of a scan in format if it is set
+func (r *ReportRepository) CountByScan(scanID uuid.UUID, format string) (int, error) {
+	query := `SELECT COUNT(*) FROM reports WHERE scan_id = $1 AND deleted_at IS NULL AND ($2 = '' OR format = $2)`
+
+	var count int
+	err := r.db.QueryRow(query, scanID, format).Scan(&count)
+	return count, err
+}
+
+// ListFilePaths retrieves the file paths of all reports, including those of
+// soft-deleted scans awaiting their purge
+func (r *ReportRepository) ListFilePaths() ([]string, error) {
+	rows, err := r.db.Query(`SELECT file_path FROM reports`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, rows.Err()
 }
 
 // Delete deletes a report
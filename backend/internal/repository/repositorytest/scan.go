@@ -0,0 +1,71 @@
+// Package repositorytest provides the rows repository queries return, for
+// tests that mock the database with sqlmock
+package repositorytest
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"publicscannerapi/internal/models"
+)
+
+// ScanJobColumns are the columns of the repository's scanColumns, in order
+var ScanJobColumns = []string{
+	"id", "target_id", "url", "organization_id", "initiated_by", "status", "progress", "checks", "config",
+	"started_at", "completed_at", "created_at", "updated_at", "policy_passed", "worst_severity",
+	"tags", "metadata", "deleted_at", "run_at", "campaign_id",
+	"failure_code", "failure_reason", "current_step", "findings_total", "findings_by_severity",
+}
+
+// ScanJobRow returns the row a scan job query selects for scan. A zero
+// CreatedAt or UpdatedAt is selected as the current time.
+func ScanJobRow(scan *models.ScanJob) []driver.Value {
+	now := time.Now()
+	created, updated := scan.CreatedAt, scan.UpdatedAt
+	if created.IsZero() {
+		created = now
+	}
+	if updated.IsZero() {
+		updated = now
+	}
+	config, _ := scan.Config.Value()
+	metadata := []byte(scan.Metadata)
+	if len(metadata) == 0 {
+		metadata = []byte("{}")
+	}
+	bySeverity, _ := json.Marshal(scan.Summary.BySeverity)
+
+	var targetID, campaignID, failureCode interface{}
+	if scan.TargetID != nil {
+		targetID = scan.TargetID.String()
+	}
+	if scan.CampaignID != nil {
+		campaignID = scan.CampaignID.String()
+	}
+	if scan.FailureCode != nil {
+		failureCode = string(*scan.FailureCode)
+	}
+
+	return []driver.Value{
+		scan.ID.String(), targetID, nullable(scan.URL), scan.OrganizationID.String(), scan.InitiatedBy.String(),
+		string(scan.Status), scan.Progress, array(scan.Checks), config,
+		nullable(scan.StartedAt), nullable(scan.CompletedAt), created, updated, nullable(scan.PolicyPassed), nullable(scan.WorstSeverity),
+		array(scan.Tags), metadata, nullable(scan.DeletedAt), nullable(scan.RunAt), campaignID,
+		failureCode, nullable(scan.FailureReason), nullable(scan.CurrentStep), scan.Summary.Total, bySeverity,
+	}
+}
+
+// nullable returns the value value points to, or nil for NULL
+func nullable[T any](value *T) driver.Value {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// array returns values as a Postgres array literal
+func array(values []string) string {
+	return "{" + strings.Join(values, ",") + "}"
+}
@@ -4,6 +4,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -11,7 +15,11 @@ import (
 )
 
 var (
-	ErrScanNotFound = errors.New("scan not found")
+	ErrScanNotFound   = errors.New("scan not found")
+	ErrScanNotQueued  = errors.New("scan is no longer queued")
+	ErrScanNotActive  = errors.New("scan has already finished")
+	ErrScanNotFailed  = errors.New("scan has not failed")
+	ErrResultNotFound = errors.New("scan result not found")
 )
 
 // ScanRepository handles scan database operations
@@ -26,13 +34,41 @@ func NewScanRepository(db *sql.DB) *ScanRepository {
 
 // Create creates a new scan job
 func (r *ScanRepository) Create(scan *models.ScanJob) error {
+	return insertScan(r.db, scan)
+}
+
+// CreateBatch creates several scans in one transaction: either all of them
+// are stored or none is
+func (r *ScanRepository) CreateBatch(scans []*models.ScanJob) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, scan := range scans {
+		if err := insertScan(tx, scan); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertScan stores a new scan job
+func insertScan(q queryRower, scan *models.ScanJob) error {
 	query := `
-		INSERT INTO scan_jobs (id, target_id, url, organization_id, initiated_by, status, progress, checks, config)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO scan_jobs (id, target_id, url, organization_id, initiated_by, status, progress, checks, config, tags, metadata, run_at, campaign_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, '{}'::jsonb), $12, $13)
 		RETURNING created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
+	err := q.QueryRow(
 		query,
 		scan.ID,
 		scan.TargetID,
@@ -43,24 +79,36 @@ func (r *ScanRepository) Create(scan *models.ScanJob) error {
 		scan.Progress,
 		pq.Array(scan.Checks),
 		scan.Config,
+		pq.Array(scan.Tags),
+		nullableJSON(scan.Metadata),
+		scan.RunAt,
+		scan.CampaignID,
 	).Scan(&scan.CreatedAt, &scan.UpdatedAt)
 
 	return err
 }
 
-// GetByID retrieves a scan by ID
-func (r *ScanRepository) GetByID(id uuid.UUID) (*models.ScanJob, error) {
-	scan := &models.ScanJob{}
-	query := `
-		SELECT id, target_id, url, organization_id, initiated_by, status, progress, checks, config,
-		       started_at, completed_at, created_at, updated_at
-		FROM scan_jobs
-		WHERE id = $1
-	`
+// scanColumns is the column list shared by every scan job query
+const scanColumns = `
+		id, target_id, url, organization_id, initiated_by, status, progress, checks, config,
+		started_at, completed_at, created_at, updated_at, policy_passed, worst_severity,
+		tags, COALESCE(metadata, '{}') AS metadata, deleted_at, run_at, campaign_id,
+		failure_code, failure_reason, current_step, findings_total, findings_by_severity
+`
 
-	var checks pq.StringArray
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	err := r.db.QueryRow(query, id).Scan(
+// scanScanJob reads a scan job row selected with scanColumns. Any extra
+// destinations are filled from columns selected after scanColumns.
+func scanScanJob(row rowScanner, extra ...interface{}) (*models.ScanJob, error) {
+	scan := &models.ScanJob{}
+	var checks, tags pq.StringArray
+	var metadata, bySeverity []byte
+
+	dest := []interface{}{
 		&scan.ID,
 		&scan.TargetID,
 		&scan.URL,
@@ -74,75 +122,191 @@ func (r *ScanRepository) GetByID(id uuid.UUID) (*models.ScanJob, error) {
 		&scan.CompletedAt,
 		&scan.CreatedAt,
 		&scan.UpdatedAt,
-	)
+		&scan.PolicyPassed,
+		&scan.WorstSeverity,
+		&tags,
+		&metadata,
+		&scan.DeletedAt,
+		&scan.RunAt,
+		&scan.CampaignID,
+		&scan.FailureCode,
+		&scan.FailureReason,
+		&scan.CurrentStep,
+		&scan.Summary.Total,
+		&bySeverity,
+	}
 
-	if err == sql.ErrNoRows {
-		return nil, ErrScanNotFound
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
 	}
-	if err != nil {
+
+	if err := json.Unmarshal(bySeverity, &scan.Summary.BySeverity); err != nil {
 		return nil, err
 	}
 
 	scan.Checks = checks
+	scan.Tags = tags
+	scan.Metadata = metadata
 
 	return scan, nil
 }
 
-// ListByOrganization retrieves all scans for an organization
-func (r *ScanRepository) ListByOrganization(organizationID uuid.UUID, limit, offset int) ([]*models.ScanJob, error) {
-	query := `
-		SELECT id, target_id, url, organization_id, initiated_by, status, progress, checks, config,
-		       started_at, completed_at, created_at, updated_at
+// scanScanJobs reads all scan job rows selected with scanColumns
+func scanScanJobs(rows *sql.Rows) ([]*models.ScanJob, error) {
+	defer rows.Close()
+
+	var scans []*models.ScanJob
+	for rows.Next() {
+		scan, err := scanScanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		scans = append(scans, scan)
+	}
+
+	return scans, rows.Err()
+}
+
+// GetByID retrieves a scan by ID. Soft-deleted scans are not found.
+func (r *ScanRepository) GetByID(id uuid.UUID) (*models.ScanJob, error) {
+	query := `SELECT ` + scanColumns + `
 		FROM scan_jobs
-		WHERE organization_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	rows, err := r.db.Query(query, organizationID, limit, offset)
+	scan, err := scanScanJob(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrScanNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var scans []*models.ScanJob
-	for rows.Next() {
-		scan := &models.ScanJob{}
-		var checks pq.StringArray
+	return scan, nil
+}
 
-		err := rows.Scan(
-			&scan.ID,
-			&scan.TargetID,
-			&scan.URL,
-			&scan.OrganizationID,
-			&scan.InitiatedBy,
-			&scan.Status,
-			&scan.Progress,
-			&checks,
-			&scan.Config,
-			&scan.StartedAt,
-			&scan.CompletedAt,
-			&scan.CreatedAt,
-			&scan.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
+// ScanListFilter narrows and orders the scans returned by
+// ListByOrganization. Nil fields do not filter.
+type ScanListFilter struct {
+	Status        *models.ScanStatus
+	HasReport     *bool // whether at least one report was generated from the scan
+	CampaignID    *uuid.UUID
+	TargetID      *uuid.UUID
+	CreatedAfter  *time.Time // inclusive
+	CreatedBefore *time.Time // exclusive
+	// Sort is a scanSortColumns key; empty sorts by created_at. Sorting is
+	// descending unless Ascending is set.
+	Sort      string
+	Ascending bool
+}
+
+// scanSortColumns maps the sort keys of ListByOrganization to their columns
+var scanSortColumns = map[string]string{
+	"created_at": "created_at",
+	"status":     "status",
+}
+
+// where builds the WHERE clause of a filtered scan listing. Arguments are
+// numbered after organization_id ($1).
+func (f ScanListFilter) where() (string, []interface{}) {
+	clause := "organization_id = $1 AND deleted_at IS NULL"
+	var args []interface{}
+
+	if f.Status != nil {
+		args = append(args, *f.Status)
+		clause += fmt.Sprintf(" AND status = $%d", len(args)+1)
+	}
+	if f.CampaignID != nil {
+		args = append(args, *f.CampaignID)
+		clause += fmt.Sprintf(" AND campaign_id = $%d", len(args)+1)
+	}
+	if f.TargetID != nil {
+		args = append(args, *f.TargetID)
+		clause += fmt.Sprintf(" AND target_id = $%d", len(args)+1)
+	}
+	if f.CreatedAfter != nil {
+		args = append(args, *f.CreatedAfter)
+		clause += fmt.Sprintf(" AND created_at >= $%d", len(args)+1)
+	}
+	if f.CreatedBefore != nil {
+		args = append(args, *f.CreatedBefore)
+		clause += fmt.Sprintf(" AND created_at < $%d", len(args)+1)
+	}
+	if f.HasReport != nil {
+		// Anti/semi-join against reports; planned like a LEFT JOIN ... IS NULL
+		// without multiplying rows for scans with several reports
+		exists := "EXISTS"
+		if !*f.HasReport {
+			exists = "NOT EXISTS"
 		}
+		clause += " AND " + exists + " (SELECT 1 FROM reports WHERE reports.scan_id = scan_jobs.id)"
+	}
 
-		scan.Checks = checks
-		scans = append(scans, scan)
+	return clause, args
+}
+
+// orderBy builds the ORDER BY clause of a filtered scan listing. Only the
+// columns of scanSortColumns are accepted; the newest scans come first
+// among equal values, and the ID keeps pages stable.
+func (f ScanListFilter) orderBy() (string, error) {
+	sort := f.Sort
+	if sort == "" {
+		sort = "created_at"
+	}
+	column, ok := scanSortColumns[sort]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSort, f.Sort)
 	}
 
-	return scans, nil
+	direction := "DESC"
+	if f.Ascending {
+		direction = "ASC"
+	}
+	return fmt.Sprintf("%s %s, created_at DESC, id", column, direction), nil
+}
+
+// ListByOrganization retrieves the scans of an organization matching filter,
+// in the filter's order. ErrInvalidSort is returned for an unknown sort key.
+func (r *ScanRepository) ListByOrganization(organizationID uuid.UUID, filter ScanListFilter, limit, offset int) ([]*models.ScanJob, error) {
+	orderBy, err := filter.orderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	where, filterArgs := filter.where()
+	args := append([]interface{}{organizationID}, filterArgs...)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`SELECT `+scanColumns+`
+		FROM scan_jobs
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScanJobs(rows)
+}
+
+// CountByOrganization counts the scans of an organization matching filter
+func (r *ScanRepository) CountByOrganization(organizationID uuid.UUID, filter ScanListFilter) (int, error) {
+	where, filterArgs := filter.where()
+	args := append([]interface{}{organizationID}, filterArgs...)
+
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM scan_jobs WHERE `+where, args...).Scan(&count)
+	return count, err
 }
 
 // ListByTarget retrieves all scans for a target
 func (r *ScanRepository) ListByTarget(targetID uuid.UUID) ([]*models.ScanJob, error) {
-	query := `
-		SELECT id, target_id, url, organization_id, initiated_by, status, progress, checks, config,
-		       started_at, completed_at, created_at, updated_at
+	query := `SELECT ` + scanColumns + `
 		FROM scan_jobs
-		WHERE target_id = $1
+		WHERE target_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -150,118 +314,300 @@ func (r *ScanRepository) ListByTarget(targetID uuid.UUID) ([]*models.ScanJob, er
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var scans []*models.ScanJob
+	return scanScanJobs(rows)
+}
+
+// ListByStatus retrieves all scans in a given status across organizations
+func (r *ScanRepository) ListByStatus(status models.ScanStatus) ([]*models.ScanJob, error) {
+	query := `SELECT ` + scanColumns + `
+		FROM scan_jobs
+		WHERE status = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScanJobs(rows)
+}
+
+// ListStale retrieves queued and running scans across organizations that
+// have not been updated since olderThan, oldest first. Progress updates and
+// status changes touch updated_at, so it doubles as the worker heartbeat.
+func (r *ScanRepository) ListStale(olderThan time.Time) ([]*models.ScanJob, error) {
+	query := `SELECT ` + scanColumns + `
+		FROM scan_jobs
+		WHERE status IN ('queued', 'running') AND deleted_at IS NULL AND updated_at < $1
+		ORDER BY updated_at ASC
+	`
+
+	rows, err := r.db.Query(query, olderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScanJobs(rows)
+}
+
+// FailureStats aggregates an organization's failed and cancelled scans
+// created since, by failure code, and its failed or errored check results
+// by check and by the failure code of their scan
+func (r *ScanRepository) FailureStats(organizationID uuid.UUID, since time.Time) (*models.FailureStats, error) {
+	stats := &models.FailureStats{
+		Since:         since,
+		ByCode:        make(map[string]int),
+		FailingChecks: []*models.FailingCheckSummary{},
+	}
+
+	rows, err := r.db.Query(`
+		SELECT status, COALESCE(failure_code, 'unclassified'), COUNT(*)
+		FROM scan_jobs
+		WHERE organization_id = $1 AND deleted_at IS NULL AND created_at >= $2
+		  AND status IN ('failed', 'cancelled')
+		GROUP BY 1, 2
+	`, organizationID, since)
+	if err != nil {
+		return nil, err
+	}
 	for rows.Next() {
-		scan := &models.ScanJob{}
-		var checks pq.StringArray
+		var status, code string
+		var count int
+		if err := rows.Scan(&status, &code, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if status == string(models.ScanStatusFailed) {
+			stats.FailedScans += count
+		} else {
+			stats.CancelledScans += count
+		}
+		stats.ByCode[code] += count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		err := rows.Scan(
-			&scan.ID,
-			&scan.TargetID,
-			&scan.URL,
-			&scan.OrganizationID,
-			&scan.InitiatedBy,
-			&scan.Status,
-			&scan.Progress,
-			&checks,
-			&scan.Config,
-			&scan.StartedAt,
-			&scan.CompletedAt,
-			&scan.CreatedAt,
-			&scan.UpdatedAt,
-		)
-		if err != nil {
+	// Results of scans that did not fail have no failure code
+	rows, err = r.db.Query(`
+		SELECT sr.check_type, COALESCE(s.failure_code, CASE WHEN s.status = 'failed' THEN 'unclassified' ELSE 'none' END), COUNT(*)
+		FROM scan_results sr
+		JOIN scan_jobs s ON s.id = sr.scan_id
+		WHERE s.organization_id = $1 AND s.deleted_at IS NULL AND s.created_at >= $2
+		  AND sr.status IN ('failed', 'error')
+		GROUP BY 1, 2
+		ORDER BY 1
+	`, organizationID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byCheck := make(map[string]*models.FailingCheckSummary)
+	for rows.Next() {
+		var checkType, code string
+		var count int
+		if err := rows.Scan(&checkType, &code, &count); err != nil {
 			return nil, err
 		}
+		summary, ok := byCheck[checkType]
+		if !ok {
+			summary = &models.FailingCheckSummary{CheckType: checkType, ByCode: make(map[string]int)}
+			byCheck[checkType] = summary
+			stats.FailingChecks = append(stats.FailingChecks, summary)
+		}
+		summary.Failures += count
+		summary.ByCode[code] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		scan.Checks = checks
-		scans = append(scans, scan)
+	sort.SliceStable(stats.FailingChecks, func(i, j int) bool {
+		return stats.FailingChecks[i].Failures > stats.FailingChecks[j].Failures
+	})
+
+	return stats, nil
+}
+
+// QueueCounts is the number of active scans per status and the creation
+// time of the oldest queued one
+type QueueCounts struct {
+	Queued         int
+	Running        int
+	OldestQueuedAt *time.Time
+}
+
+// CountActive counts queued and running scans across all organizations
+func (r *ScanRepository) CountActive() (*QueueCounts, error) {
+	counts := &QueueCounts{}
+	query := `
+		SELECT COUNT(*) FILTER (WHERE status = 'queued'),
+		       COUNT(*) FILTER (WHERE status = 'running'),
+		       MIN(created_at) FILTER (WHERE status = 'queued')
+		FROM scan_jobs
+		WHERE status IN ('queued', 'running') AND deleted_at IS NULL
+	`
+
+	err := r.db.QueryRow(query).Scan(&counts.Queued, &counts.Running, &counts.OldestQueuedAt)
+	if err != nil {
+		return nil, err
 	}
 
-	return scans, nil
+	return counts, nil
 }
 
-// UpdateStatus updates a scan's status and progress
-func (r *ScanRepository) UpdateStatus(id uuid.UUID, status string, progress int) error {
+// RequeueFilter selects the scans reset by Requeue
+type RequeueFilter struct {
+	Statuses      []string   // failed and/or queued
+	CreatedAfter  *time.Time // inclusive
+	CreatedBefore *time.Time // exclusive
+	StuckBefore   time.Time  // queued scans must not have been touched since
+}
+
+// Requeue resets matching failed or stuck queued scans of an organization back
+// to queued, discarding any partial results, and returns the reset scans.
+// Running scans are never matched, and the status guard in the UPDATE means
+// concurrent calls cannot reset the same scan twice.
+func (r *ScanRepository) Requeue(organizationID uuid.UUID, filter RequeueFilter) ([]*models.ScanJob, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE scan_jobs
-		SET status = $2, progress = $3
-		WHERE id = $1
-	`
+		SET status = 'queued', progress = 0, started_at = NULL, completed_at = NULL,
+		    policy_passed = NULL, worst_severity = NULL, failure_code = NULL, failure_reason = NULL
+		WHERE organization_id = $1 AND deleted_at IS NULL
+		  AND ((status = 'failed' AND 'failed' = ANY($2))
+		    OR (status = 'queued' AND 'queued' = ANY($2) AND updated_at < $5))
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR created_at < $4)
+		RETURNING ` + scanColumns
 
-	result, err := r.db.Exec(query, id, status, progress)
+	rows, err := tx.Query(query, organizationID, pq.Array(filter.Statuses), filter.CreatedAfter, filter.CreatedBefore, filter.StuckBefore)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rows, err := result.RowsAffected()
+	scans, err := scanScanJobs(rows)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if rows == 0 {
-		return ErrScanNotFound
+
+	ids := make([]uuid.UUID, len(scans))
+	for i, scan := range scans {
+		ids[i] = scan.ID
 	}
 
-	return nil
+	if _, err := tx.Exec(`DELETE FROM scan_results WHERE scan_id = ANY($1)`, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return scans, nil
 }
 
-// Complete marks a scan as completed
-func (r *ScanRepository) Complete(id uuid.UUID) error {
+// SoftDelete marks a scan deleted, together with the reports generated from
+// it. A scan still queued or running is cancelled at the same time so no
+// worker keeps writing to it. Results stay attached to the scan and are
+// hidden with it.
+func (r *ScanRepository) SoftDelete(id uuid.UUID) (time.Time, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
 	query := `
 		UPDATE scan_jobs
-		SET status = 'completed', progress = 100, completed_at = NOW()
-		WHERE id = $1
+		SET deleted_at = NOW(),
+		    status = CASE WHEN status IN ('scheduled', 'queued', 'running') THEN 'cancelled' ELSE status END
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deleted_at
 	`
-
-	result, err := r.db.Exec(query, id)
+	err = tx.QueryRow(query, id).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, ErrScanNotFound
+	}
 	if err != nil {
-		return err
+		return time.Time{}, err
 	}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
+	if _, err := tx.Exec(`UPDATE reports SET deleted_at = $2 WHERE scan_id = $1 AND deleted_at IS NULL`, id, deletedAt); err != nil {
+		return time.Time{}, err
 	}
-	if rows == 0 {
-		return ErrScanNotFound
+
+	if err := tx.Commit(); err != nil {
+		return time.Time{}, err
 	}
 
-	return nil
+	return deletedAt, nil
 }
 
-// Fail marks a scan as failed
-func (r *ScanRepository) Fail(id uuid.UUID) error {
-	query := `
-		UPDATE scan_jobs
-		SET status = 'failed', completed_at = NOW()
-		WHERE id = $1
+// GetDeletedByID retrieves a soft-deleted scan that has not been purged yet
+func (r *ScanRepository) GetDeletedByID(id uuid.UUID) (*models.ScanJob, error) {
+	query := `SELECT ` + scanColumns + `
+		FROM scan_jobs
+		WHERE id = $1 AND deleted_at IS NOT NULL
 	`
 
-	result, err := r.db.Exec(query, id)
+	scan, err := scanScanJob(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrScanNotFound
+	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rows, err := result.RowsAffected()
+	return scan, nil
+}
+
+// Restore undoes SoftDelete, bringing back the reports deleted with the scan.
+// A scan cancelled by the deletion stays cancelled.
+func (r *ScanRepository) Restore(id uuid.UUID) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
-	if rows == 0 {
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	query := `SELECT deleted_at FROM scan_jobs WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+	err = tx.QueryRow(query, id).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
 		return ErrScanNotFound
 	}
+	if err != nil {
+		return err
+	}
 
-	return nil
+	if _, err := tx.Exec(`UPDATE scan_jobs SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE reports SET deleted_at = NULL WHERE scan_id = $1 AND deleted_at = $2`, id, deletedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetResults retrieves scan results for a scan
-func (r *ScanRepository) GetResults(scanID uuid.UUID) ([]*models.ScanResult, error) {
+// CompletedChecks returns the checks of a scan that have a successful
+// result. A check with only failed or errored results still has to run.
+func (r *ScanRepository) CompletedChecks(scanID uuid.UUID) ([]string, error) {
 	query := `
-		SELECT id, scan_id, check_type, status, data, findings, severity, created_at
+		SELECT DISTINCT check_type
 		FROM scan_results
-		WHERE scan_id = $1
-		ORDER BY created_at ASC
+		WHERE scan_id = $1 AND status = 'success'
 	`
 
 	rows, err := r.db.Query(query, scanID)
@@ -270,50 +616,839 @@ func (r *ScanRepository) GetResults(scanID uuid.UUID) ([]*models.ScanResult, err
 	}
 	defer rows.Close()
 
-	var results []*models.ScanResult
+	var checks []string
 	for rows.Next() {
-		result := &models.ScanResult{}
-		var dataJSON []byte
-
-		err := rows.Scan(
-			&result.ID,
-			&result.ScanID,
-			&result.CheckType,
-			&result.Status,
-			&dataJSON,
-			&result.Findings,
-			&result.Severity,
-			&result.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		if err := json.Unmarshal(dataJSON, &result.Data); err != nil {
+		var check string
+		if err := rows.Scan(&check); err != nil {
 			return nil, err
 		}
-
-		results = append(results, result)
+		checks = append(checks, check)
 	}
 
-	return results, nil
+	return checks, rows.Err()
 }
 
-// CreateResult creates a new scan result
-func (r *ScanRepository) CreateResult(result *models.ScanResult) error {
-	dataJSON, err := json.Marshal(result.Data)
+// Resume moves a failed scan back to running so that the checks in rerun
+// can run again. Their unsuccessful results are discarded while the results
+// of completed checks are kept, and progress is set to the share of checks
+// already done. ErrScanNotFailed is returned when the scan is not failed.
+func (r *ScanRepository) Resume(id uuid.UUID, rerun []string, progress int) (*models.ScanJob, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer tx.Rollback()
 
 	query := `
-		INSERT INTO scan_results (id, scan_id, check_type, status, data, findings, severity)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at
-	`
+		UPDATE scan_jobs
+		SET status = 'running', progress = $2, completed_at = NULL,
+		    started_at = COALESCE(started_at, NOW()), policy_passed = NULL, worst_severity = NULL,
+		    failure_code = NULL, failure_reason = NULL
+		WHERE id = $1 AND status = 'failed' AND deleted_at IS NULL
+		RETURNING ` + scanColumns
+
+	scan, err := scanScanJob(tx.QueryRow(query, id, progress))
+	if err == sql.ErrNoRows {
+		return nil, ErrScanNotFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM scan_results WHERE scan_id = $1 AND check_type = ANY($2)`, id, pq.Array(rerun)); err != nil {
+		return nil, err
+	}
+
+	summary, err := recomputeSummary(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	scan.Summary = *summary
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// PurgedScans lists the files left behind by scans removed by PurgeDeleted
+type PurgedScans struct {
+	Count          int
+	ReportFiles    []string // report file paths on disk
+	AttachmentKeys []string // attachment storage keys
+}
+
+// PurgeDeleted permanently removes scans soft-deleted before cutoff. Their
+// results, reports and attachments go with them through ON DELETE CASCADE;
+// the files those rows referenced are returned for the caller to remove.
+// Each statement may run for up to statementTimeout, since a large backlog
+// can outlast the connection's default limit.
+func (r *ScanRepository) PurgeDeleted(cutoff time.Time, statementTimeout time.Duration) (*PurgedScans, error) {
+	tx, err := beginWithStatementTimeout(r.db, statementTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	purged := &PurgedScans{}
+
+	rows, err := tx.Query(`
+		SELECT id FROM scan_jobs
+		WHERE deleted_at < $1
+		FOR UPDATE
+	`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return purged, nil
+	}
+
+	if err := tx.QueryRow(`SELECT COALESCE(array_agg(file_path), '{}') FROM reports WHERE scan_id = ANY($1)`,
+		pq.Array(ids)).Scan(pq.Array(&purged.ReportFiles)); err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRow(`SELECT COALESCE(array_agg(storage_key), '{}') FROM scan_result_attachments WHERE scan_id = ANY($1)`,
+		pq.Array(ids)).Scan(pq.Array(&purged.AttachmentKeys)); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM scan_jobs WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	purged.Count = int(count)
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return purged, nil
+}
+
+// severityRollupSelect aggregates per-scan findings by severity from
+// scan_results. Results carrying findings_by_severity contribute their
+// breakdown; older results fall back to counting all their findings at the
+// result's single severity.
+var severityRollupSelect = `
+		SELECT scan_id,` + severityRollupColumns() + `
+		FROM scan_results
+`
+
+// severityRollupColumns builds one summed column per severity for severityRollupSelect
+func severityRollupColumns() string {
+	severities := []string{
+		models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow, models.SeverityInfo,
+	}
+
+	columns := make([]string, len(severities))
+	for i, severity := range severities {
+		columns[i] = fmt.Sprintf(`
+		       COALESCE(SUM((findings_by_severity->>'%[1]s')::int) FILTER (WHERE findings_by_severity IS NOT NULL), 0)
+		       + COALESCE(SUM(findings) FILTER (WHERE findings_by_severity IS NULL AND severity = '%[1]s'), 0) AS %[1]s`, severity)
+	}
+
+	return strings.Join(columns, ",")
+}
+
+// RecomputeSummary refreshes the findings summary stored on a scan from its
+// results. It runs whenever results are written, so scan listings can show
+// what a scan found without loading its results.
+func (r *ScanRepository) RecomputeSummary(scanID uuid.UUID) (*models.ScanSummary, error) {
+	return recomputeSummary(r.db, scanID)
+}
+
+// recomputeSummary is RecomputeSummary on a connection or transaction
+func recomputeSummary(q queryRower, scanID uuid.UUID) (*models.ScanSummary, error) {
+	query := `
+		WITH rollup AS (` + severityRollupSelect + `
+			WHERE scan_id = $1
+			GROUP BY scan_id
+		),
+		summary AS (
+			SELECT COALESCE(r.critical, 0) AS critical, COALESCE(r.high, 0) AS high,
+			       COALESCE(r.medium, 0) AS medium, COALESCE(r.low, 0) AS low, COALESCE(r.info, 0) AS info
+			FROM (SELECT 1) one
+			LEFT JOIN rollup r ON true
+		)
+		UPDATE scan_jobs
+		SET findings_total = s.critical + s.high + s.medium + s.low + s.info,
+		    findings_by_severity = jsonb_build_object(
+		        'critical', s.critical, 'high', s.high, 'medium', s.medium, 'low', s.low, 'info', s.info)
+		FROM summary s
+		WHERE scan_jobs.id = $1
+		RETURNING s.critical, s.high, s.medium, s.low, s.info
+	`
+
+	counts := models.SeverityCounts{}
+	err := q.QueryRow(query, scanID).Scan(&counts.Critical, &counts.High, &counts.Medium, &counts.Low, &counts.Info)
+	if err == sql.ErrNoRows {
+		return nil, ErrScanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ScanSummary{Total: counts.Total(), BySeverity: counts}, nil
+}
+
+// riskScoreExpr computes models.SeverityCounts.RiskScore in SQL over a rollup aliased r
+var riskScoreExpr = fmt.Sprintf(
+	"(COALESCE(r.critical, 0) * %d + COALESCE(r.high, 0) * %d + COALESCE(r.medium, 0) * %d + COALESCE(r.low, 0) * %d)",
+	models.RiskWeightCritical, models.RiskWeightHigh, models.RiskWeightMedium, models.RiskWeightLow,
+)
+
+// ListTopRisks returns the latest completed scan of each target in an
+// organization, ordered by risk score (highest first)
+func (r *ScanRepository) ListTopRisks(organizationID uuid.UUID, limit int) ([]*models.TargetRisk, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (target_id) ` + scanColumns + `
+			FROM scan_jobs
+			WHERE organization_id = $1 AND target_id IS NOT NULL AND status = 'completed' AND deleted_at IS NULL
+			ORDER BY target_id, created_at DESC
+		),
+		rollup AS (` + severityRollupSelect + `
+			WHERE scan_id IN (SELECT id FROM latest)
+			GROUP BY scan_id
+		)
+		SELECT ` + scanColumns + `,
+		       (SELECT t.name FROM targets t WHERE t.id = latest.target_id),
+		       (SELECT t.hostname FROM targets t WHERE t.id = latest.target_id),
+		       COALESCE(r.critical, 0), COALESCE(r.high, 0), COALESCE(r.medium, 0),
+		       COALESCE(r.low, 0), COALESCE(r.info, 0),
+		       ` + riskScoreExpr + ` AS risk_score
+		FROM latest
+		LEFT JOIN rollup r ON r.scan_id = latest.id
+		ORDER BY risk_score DESC, latest.completed_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(query, organizationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var risks []*models.TargetRisk
+	for rows.Next() {
+		risk := &models.TargetRisk{}
+
+		scan, err := scanScanJob(rows,
+			&risk.TargetName,
+			&risk.Hostname,
+			&risk.Severity.Critical,
+			&risk.Severity.High,
+			&risk.Severity.Medium,
+			&risk.Severity.Low,
+			&risk.Severity.Info,
+			&risk.RiskScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		risk.Scan = scan
+		risk.TargetID = *scan.TargetID
+		risks = append(risks, risk)
+	}
+
+	return risks, rows.Err()
+}
+
+// Cancel marks a scan that has not finished yet as cancelled by a user.
+// ErrScanNotActive is returned when the scan already reached a final state.
+func (r *ScanRepository) Cancel(id uuid.UUID) error {
+	query := `
+		UPDATE scan_jobs
+		SET status = 'cancelled', failure_code = $2, failure_reason = 'Cancelled by user'
+		WHERE id = $1 AND status IN ('scheduled', 'queued', 'running')
+	`
+
+	result, err := r.db.Exec(query, id, string(models.FailureCancelledByUser))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotActive
+	}
+
+	return nil
+}
+
+// UpdateStatus updates a scan's status and progress. The current step is
+// left as it is.
+func (r *ScanRepository) UpdateStatus(id uuid.UUID, status string, progress int) error {
+	query := `
+		UPDATE scan_jobs
+		SET status = $2, progress = $3
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, id, status, progress)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotFound
+	}
+
+	return nil
+}
+
+// Complete marks a scan as completed
+func (r *ScanRepository) Complete(id uuid.UUID) error {
+	query := `
+		UPDATE scan_jobs
+		SET status = 'completed', progress = 100, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotFound
+	}
+
+	return nil
+}
+
+// failureArgs returns the failure_code and failure_reason column values
+// for failure, both NULL when it is nil
+func failureArgs(failure *models.ScanFailure) (interface{}, interface{}) {
+	if failure == nil {
+		return nil, nil
+	}
+	return string(failure.Code), failure.Reason
+}
+
+// Fail marks a scan as failed, recording why
+func (r *ScanRepository) Fail(id uuid.UUID, failure models.ScanFailure) error {
+	query := `
+		UPDATE scan_jobs
+		SET status = 'failed', completed_at = NOW(), failure_code = $2, failure_reason = $3
+		WHERE id = $1
+	`
+
+	code, reason := failureArgs(&failure)
+	result, err := r.db.Exec(query, id, code, reason)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotFound
+	}
+
+	return nil
+}
+
+// Transition moves a scan that has not finished yet to status, stamping
+// started_at when it starts running and completed_at when it finishes.
+// failure is recorded when status is failed and ignored otherwise.
+// ErrScanNotActive is returned when the scan already reached a final state.
+func (r *ScanRepository) Transition(id uuid.UUID, status models.ScanStatus, failure *models.ScanFailure) error {
+	query := `
+		UPDATE scan_jobs
+		SET status = $2::text,
+		    started_at = CASE WHEN $2::text = 'running' THEN COALESCE(started_at, NOW()) ELSE started_at END,
+		    completed_at = CASE WHEN $2::text IN ('completed', 'failed') THEN NOW() ELSE completed_at END,
+		    progress = CASE WHEN $2::text = 'completed' THEN 100 ELSE progress END,
+		    current_step = CASE WHEN $2::text = 'running' THEN current_step ELSE NULL END,
+		    failure_code = $3, failure_reason = $4
+		WHERE id = $1 AND status IN ('queued', 'running')
+	`
+
+	if status != models.ScanStatusFailed {
+		failure = nil
+	}
+	code, reason := failureArgs(failure)
+	result, err := r.db.Exec(query, id, string(status), code, reason)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotActive
+	}
+
+	return nil
+}
+
+// UpdateProgress records the progress and current step of a scan that has
+// not finished yet and returns its progress before and after the update.
+// Progress never moves backwards, and an empty currentStep keeps the step
+// already stored. The row is locked while it is read, so concurrent updates
+// see each other's progress. ErrScanNotActive is returned when the scan
+// already reached a final state.
+func (r *ScanRepository) UpdateProgress(id uuid.UUID, progress int, currentStep string) (previous, current int, err error) {
+	query := `
+		UPDATE scan_jobs
+		SET progress = GREATEST(scan_jobs.progress, $2),
+		    current_step = COALESCE(NULLIF($3, ''), scan_jobs.current_step)
+		FROM (
+			SELECT id, progress FROM scan_jobs
+			WHERE id = $1 AND status IN ('queued', 'running')
+			FOR UPDATE
+		) old
+		WHERE scan_jobs.id = old.id
+		RETURNING old.progress, scan_jobs.progress
+	`
+
+	err = r.db.QueryRow(query, id, progress, currentStep).Scan(&previous, &current)
+	if err == sql.ErrNoRows {
+		return 0, 0, ErrScanNotActive
+	}
+	return previous, current, err
+}
+
+// ClaimDueScheduled moves up to limit scheduled scans whose run_at has
+// passed to queued and returns them. Rows locked by a concurrent claim are
+// skipped, so each scan is claimed once.
+func (r *ScanRepository) ClaimDueScheduled(now time.Time, limit int) ([]*models.ScanJob, error) {
+	query := `
+		UPDATE scan_jobs
+		SET status = 'queued'
+		WHERE id IN (
+			SELECT id FROM scan_jobs
+			WHERE status = 'scheduled' AND run_at <= $1 AND deleted_at IS NULL
+			ORDER BY run_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scanColumns
+
+	rows, err := r.db.Query(query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScanJobs(rows)
+}
+
+// UpdateQueued saves edits to a scan's checks, config, tags and metadata.
+// The update only applies while the scan is still scheduled or queued;
+// otherwise ErrScanNotQueued is returned.
+func (r *ScanRepository) UpdateQueued(scan *models.ScanJob) error {
+	query := `
+		UPDATE scan_jobs
+		SET checks = $2, config = $3, tags = $4, metadata = COALESCE($5, '{}'::jsonb)
+		WHERE id = $1 AND status IN ('scheduled', 'queued')
+		RETURNING updated_at
+	`
 
-	err = r.db.QueryRow(
+	err := r.db.QueryRow(
 		query,
+		scan.ID,
+		pq.Array(scan.Checks),
+		scan.Config,
+		pq.Array(scan.Tags),
+		nullableJSON(scan.Metadata),
+	).Scan(&scan.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return ErrScanNotQueued
+	}
+	return err
+}
+
+// nullableJSON converts an empty raw JSON value to NULL for insertion
+func nullableJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return []byte(data)
+}
+
+// SetPolicyResult stores the outcome of evaluating a scan's severity policy
+func (r *ScanRepository) SetPolicyResult(id uuid.UUID, passed bool, worstSeverity string) error {
+	query := `
+		UPDATE scan_jobs
+		SET policy_passed = $2, worst_severity = NULLIF($3, '')
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, id, passed, worstSeverity)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScanNotFound
+	}
+
+	return nil
+}
+
+// ListStatusHistory retrieves the recorded status transitions of a scan in order
+func (r *ScanRepository) ListStatusHistory(scanID uuid.UUID) ([]*models.ScanStatusChange, error) {
+	query := `
+		SELECT scan_id, from_status, to_status, progress, changed_at
+		FROM scan_status_history
+		WHERE scan_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, scanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*models.ScanStatusChange
+	for rows.Next() {
+		change := &models.ScanStatusChange{}
+		if err := rows.Scan(&change.ScanID, &change.FromStatus, &change.ToStatus, &change.Progress, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// GetResults retrieves scan results for a scan
+func (r *ScanRepository) GetResults(scanID uuid.UUID) ([]*models.ScanResult, error) {
+	return r.GetResultsByTriageStatus(scanID, "")
+}
+
+// GetResultsByTriageStatus retrieves the scan results of a scan in the given
+// triage state, or all of them when triageStatus is empty. Each result's
+// triage status comes from its latest note; a result without one is
+// acknowledged while an active acknowledgement rule of the scan's
+// organization matches it, and open otherwise.
+func (r *ScanRepository) GetResultsByTriageStatus(scanID uuid.UUID, triageStatus string) ([]*models.ScanResult, error) {
+	query := `
+		SELECT sr.id, sr.scan_id, sr.check_type, sr.status, sr.data, sr.findings, sr.severity,
+		       sr.findings_by_severity, sr.original_severity, sr.original_findings_by_severity,
+		       triage.status, CASE WHEN latest.triage_status IS NULL THEN ack.id END, sr.created_at
+		FROM scan_results sr
+		JOIN scan_jobs s ON s.id = sr.scan_id
+		LEFT JOIN LATERAL (
+			SELECT triage_status
+			FROM scan_result_notes
+			WHERE result_id = sr.id
+			ORDER BY created_at DESC
+			LIMIT 1
+		) latest ON true
+		LEFT JOIN LATERAL (
+			SELECT id
+			FROM acknowledgement_rules
+			WHERE organization_id = s.organization_id
+			  AND check_type = sr.check_type
+			  AND (match_severity IS NULL OR match_severity = sr.severity)
+			  AND (snooze_until IS NULL OR snooze_until > NOW())
+			ORDER BY created_at ASC
+			LIMIT 1
+		) ack ON true
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(latest.triage_status, CASE WHEN ack.id IS NOT NULL THEN 'acknowledged' ELSE 'open' END) AS status
+		) triage
+		WHERE sr.scan_id = $1
+		  AND ($2 = '' OR triage.status = $2)
+		ORDER BY sr.created_at ASC
+	`
+
+	rows, err := r.db.Query(query, scanID, triageStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*models.ScanResult
+	for rows.Next() {
+		result := &models.ScanResult{}
+		var dataJSON, bySeverityJSON, originalBySeverityJSON []byte
+
+		err := rows.Scan(
+			&result.ID,
+			&result.ScanID,
+			&result.CheckType,
+			&result.Status,
+			&dataJSON,
+			&result.Findings,
+			&result.Severity,
+			&bySeverityJSON,
+			&result.OriginalSeverity,
+			&originalBySeverityJSON,
+			&result.TriageStatus,
+			&result.AcknowledgedByRule,
+			&result.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(dataJSON, &result.Data); err != nil {
+			return nil, err
+		}
+		if result.FindingsBySeverity, err = decodeSeverityCounts(bySeverityJSON); err != nil {
+			return nil, err
+		}
+		if result.OriginalFindingsBySeverity, err = decodeSeverityCounts(originalBySeverityJSON); err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ResultSearch selects the scan results returned by SearchResults. Exactly
+// one of Contains and Text is set.
+type ResultSearch struct {
+	Contains json.RawMessage // JSON object the result data must contain (@>)
+	Text     string          // words that must all appear among the data's string and numeric values
+}
+
+// ErrInvalidJSONPath is returned for a result data path that cannot be queried
+var ErrInvalidJSONPath = errors.New("invalid JSON path")
+
+// maxJSONPathDepth bounds the number of segments in a result data path
+const maxJSONPathDepth = 10
+
+// jsonPathDocument builds the smallest JSON document holding value at path.
+// Segments are separated by dots and a "[]" suffix marks an array whose
+// elements are searched, so "open_ports[].port" with 22 becomes
+// {"open_ports":[{"port":22}]}.
+func jsonPathDocument(path string, value interface{}) (json.RawMessage, error) {
+	segments := strings.Split(path, ".")
+	if len(segments) > maxJSONPathDepth {
+		return nil, fmt.Errorf("%w: at most %d segments are allowed", ErrInvalidJSONPath, maxJSONPathDepth)
+	}
+
+	document := value
+	for i := len(segments) - 1; i >= 0; i-- {
+		key := strings.TrimSuffix(segments[i], "[]")
+		if key == "" || strings.ContainsAny(key, "[]") {
+			return nil, fmt.Errorf("%w: malformed segment %q", ErrInvalidJSONPath, segments[i])
+		}
+		if key != segments[i] {
+			document = []interface{}{document}
+		}
+		document = map[string]interface{}{key: document}
+	}
+
+	return json.Marshal(document)
+}
+
+// QueryResultsByJSONPath returns the results of an organization's scans
+// whose data holds value at path (see jsonPathDocument). The lookup is a
+// JSONB containment query, so it is served by idx_scan_results_data.
+func (r *ScanRepository) QueryResultsByJSONPath(organizationID uuid.UUID, path string, value interface{}, limit, offset int) ([]*models.ResultSearchHit, int, error) {
+	document, err := jsonPathDocument(path, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r.SearchResults(organizationID, ResultSearch{Contains: document}, limit, offset)
+}
+
+// resultSearchVector is the full-text document of a result's data. It must
+// match the expression of idx_scan_results_data_fts for the index to be used.
+const resultSearchVector = `jsonb_to_tsvector('simple', sr.data, '["string", "numeric"]')`
+
+// SearchResults finds the results of an organization's scans whose data
+// matches search, newest first, and reports the total number of matches.
+// Results of soft-deleted scans are never returned.
+func (r *ScanRepository) SearchResults(organizationID uuid.UUID, search ResultSearch, limit, offset int) ([]*models.ResultSearchHit, int, error) {
+	var match string
+	var arg interface{}
+	if search.Contains != nil {
+		match = "sr.data @> $2::jsonb"
+		arg = []byte(search.Contains)
+	} else {
+		match = resultSearchVector + " @@ plainto_tsquery('simple', $2)"
+		arg = search.Text
+	}
+
+	query := `
+		SELECT sr.id, sr.scan_id, sr.check_type, sr.status, sr.data, sr.findings, sr.severity,
+		       sr.findings_by_severity, sr.original_severity, sr.original_findings_by_severity, sr.created_at,
+		       s.status, s.target_id, t.name, t.hostname, s.url, s.created_at,
+		       COUNT(*) OVER ()
+		FROM scan_results sr
+		JOIN scan_jobs s ON s.id = sr.scan_id
+		LEFT JOIN targets t ON t.id = s.target_id
+		WHERE s.organization_id = $1 AND s.deleted_at IS NULL AND ` + match + `
+		ORDER BY sr.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.Query(query, organizationID, arg, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	hits := []*models.ResultSearchHit{}
+	total := 0
+	for rows.Next() {
+		hit := &models.ResultSearchHit{}
+		var dataJSON, bySeverityJSON, originalBySeverityJSON []byte
+
+		err := rows.Scan(
+			&hit.ID,
+			&hit.ScanID,
+			&hit.CheckType,
+			&hit.Status,
+			&dataJSON,
+			&hit.Findings,
+			&hit.Severity,
+			&bySeverityJSON,
+			&hit.OriginalSeverity,
+			&originalBySeverityJSON,
+			&hit.CreatedAt,
+			&hit.Scan.Status,
+			&hit.Scan.TargetID,
+			&hit.Scan.TargetName,
+			&hit.Scan.Hostname,
+			&hit.Scan.URL,
+			&hit.Scan.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		hit.Data = dataJSON
+		if hit.FindingsBySeverity, err = decodeSeverityCounts(bySeverityJSON); err != nil {
+			return nil, 0, err
+		}
+		if hit.OriginalFindingsBySeverity, err = decodeSeverityCounts(originalBySeverityJSON); err != nil {
+			return nil, 0, err
+		}
+		hit.Scan.ID = hit.ScanID
+
+		hits = append(hits, hit)
+	}
+
+	return hits, total, rows.Err()
+}
+
+// GetResultByID retrieves a single scan result by ID
+func (r *ScanRepository) GetResultByID(id uuid.UUID) (*models.ScanResult, error) {
+	result := &models.ScanResult{}
+	query := `
+		SELECT id, scan_id, check_type, status, data, findings, severity, findings_by_severity,
+		       original_severity, original_findings_by_severity, created_at
+		FROM scan_results
+		WHERE id = $1
+	`
+
+	var dataJSON, bySeverityJSON, originalBySeverityJSON []byte
+	err := r.db.QueryRow(query, id).Scan(
+		&result.ID,
+		&result.ScanID,
+		&result.CheckType,
+		&result.Status,
+		&dataJSON,
+		&result.Findings,
+		&result.Severity,
+		&bySeverityJSON,
+		&result.OriginalSeverity,
+		&originalBySeverityJSON,
+		&result.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrResultNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Data = dataJSON
+	if result.FindingsBySeverity, err = decodeSeverityCounts(bySeverityJSON); err != nil {
+		return nil, err
+	}
+	if result.OriginalFindingsBySeverity, err = decodeSeverityCounts(originalBySeverityJSON); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// resultColumns is the number of columns written per scan result
+const resultColumns = 10
+
+// maxResultsPerInsert keeps a multi-row insert below PostgreSQL's limit of
+// 65535 bind parameters
+const maxResultsPerInsert = 1000
+
+// resultInsertArgs returns the values inserted for a scan result, in
+// column order
+func resultInsertArgs(result *models.ScanResult) ([]interface{}, error) {
+	dataJSON, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var bySeverityJSON []byte
+	if result.FindingsBySeverity != nil {
+		if err := result.FindingsBySeverity.Validate(); err != nil {
+			return nil, err
+		}
+		if bySeverityJSON, err = json.Marshal(result.FindingsBySeverity); err != nil {
+			return nil, err
+		}
+	}
+	var originalBySeverityJSON []byte
+	if result.OriginalFindingsBySeverity != nil {
+		if originalBySeverityJSON, err = json.Marshal(result.OriginalFindingsBySeverity); err != nil {
+			return nil, err
+		}
+	}
+
+	return []interface{}{
 		result.ID,
 		result.ScanID,
 		result.CheckType,
@@ -321,7 +1456,138 @@ func (r *ScanRepository) CreateResult(result *models.ScanResult) error {
 		dataJSON,
 		result.Findings,
 		result.Severity,
-	).Scan(&result.CreatedAt)
+		bySeverityJSON,
+		result.OriginalSeverity,
+		originalBySeverityJSON,
+	}, nil
+}
+
+// CreateResult creates a new scan result and recomputes the scan's summary.
+// A findings_by_severity breakdown, when given, must not contain negative
+// counts.
+func (r *ScanRepository) CreateResult(result *models.ScanResult) error {
+	args, err := resultInsertArgs(result)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO scan_results (id, scan_id, check_type, status, data, findings, severity, findings_by_severity,
+			original_severity, original_findings_by_severity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at
+	`
+
+	if err := r.db.QueryRow(query, args...).Scan(&result.CreatedAt); err != nil {
+		return err
+	}
 
+	_, err = r.RecomputeSummary(result.ScanID)
 	return err
 }
+
+// CreateResults inserts many scan results in one transaction, using
+// multi-row INSERTs of up to maxResultsPerInsert rows instead of a round
+// trip per result. Either every result is stored or none is; the same
+// findings_by_severity rule as CreateResult applies. The summary of every
+// scan written to is recomputed in the same transaction.
+func (r *ScanRepository) CreateResults(results []*models.ScanResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for start := 0; start < len(results); start += maxResultsPerInsert {
+		end := start + maxResultsPerInsert
+		if end > len(results) {
+			end = len(results)
+		}
+		if err := insertResults(tx, results[start:end]); err != nil {
+			return err
+		}
+	}
+
+	recomputed := make(map[uuid.UUID]bool)
+	for _, result := range results {
+		if recomputed[result.ScanID] {
+			continue
+		}
+		if _, err := recomputeSummary(tx, result.ScanID); err != nil {
+			return err
+		}
+		recomputed[result.ScanID] = true
+	}
+
+	return tx.Commit()
+}
+
+// insertResults writes one multi-row INSERT and fills in each result's
+// created_at
+func insertResults(tx *sql.Tx, batch []*models.ScanResult) error {
+	var query strings.Builder
+	query.WriteString(`
+		INSERT INTO scan_results (id, scan_id, check_type, status, data, findings, severity, findings_by_severity,
+			original_severity, original_findings_by_severity)
+		VALUES `)
+
+	args := make([]interface{}, 0, len(batch)*resultColumns)
+	byID := make(map[uuid.UUID]*models.ScanResult, len(batch))
+	for i, result := range batch {
+		values, err := resultInsertArgs(result)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for j := range values {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", len(args)+j+1)
+		}
+		query.WriteString(")")
+		args = append(args, values...)
+		byID[result.ID] = result
+	}
+	query.WriteString(" RETURNING id, created_at")
+
+	rows, err := tx.Query(query.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			return err
+		}
+		if result, ok := byID[id]; ok {
+			result.CreatedAt = createdAt
+		}
+	}
+
+	return rows.Err()
+}
+
+// decodeSeverityCounts decodes a nullable findings_by_severity column
+func decodeSeverityCounts(raw []byte) (*models.SeverityCounts, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	counts := &models.SeverityCounts{}
+	if err := json.Unmarshal(raw, counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
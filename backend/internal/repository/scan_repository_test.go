@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository/repositorytest"
+)
+
+func TestScanListFilterWhere(t *testing.T) {
+	status := models.ScanStatusCompleted
+	campaignID, targetID := uuid.New(), uuid.New()
+	after := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := after.AddDate(0, 1, 0)
+
+	// The filters in the order where adds them
+	filters := []struct {
+		set       func(*ScanListFilter)
+		condition string
+		arg       interface{}
+	}{
+		{func(f *ScanListFilter) { f.Status = &status }, "status =", status},
+		{func(f *ScanListFilter) { f.CampaignID = &campaignID }, "campaign_id =", campaignID},
+		{func(f *ScanListFilter) { f.TargetID = &targetID }, "target_id =", targetID},
+		{func(f *ScanListFilter) { f.CreatedAfter = &after }, "created_at >=", after},
+		{func(f *ScanListFilter) { f.CreatedBefore = &before }, "created_at <", before},
+	}
+
+	for mask := 0; mask < 1<<len(filters); mask++ {
+		var filter ScanListFilter
+		want := "organization_id = $1 AND deleted_at IS NULL"
+		var wantArgs []interface{}
+		for i, f := range filters {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			f.set(&filter)
+			wantArgs = append(wantArgs, f.arg)
+			want += fmt.Sprintf(" AND %s $%d", f.condition, len(wantArgs)+1)
+		}
+
+		clause, args := filter.where()
+		if clause != want || !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("filter %05b: where = %q %v, want %q %v", mask, clause, args, want, wantArgs)
+		}
+	}
+
+	for _, hasReport := range []bool{true, false} {
+		clause, args := ScanListFilter{HasReport: &hasReport}.where()
+		exists := regexp.MustCompile(`AND (NOT )?EXISTS \(SELECT 1 FROM reports WHERE reports.scan_id = scan_jobs.id\)$`).FindStringSubmatch(clause)
+		if exists == nil || (exists[1] == "") != hasReport || len(args) != 0 {
+			t.Errorf("has report %v: where = %q %v", hasReport, clause, args)
+		}
+	}
+}
+
+func TestScanRepositoryListByOrganizationFiltered(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewScanRepository(db)
+
+	organizationID, targetID := uuid.New(), uuid.New()
+	status := models.ScanStatusFailed
+	filter := ScanListFilter{Status: &status, TargetID: &targetID, Sort: "status", Ascending: true}
+
+	query := `WHERE organization_id = \$1 AND deleted_at IS NULL AND status = \$2 AND target_id = \$3\s+` +
+		`ORDER BY status ASC, created_at DESC, id\s+LIMIT \$4 OFFSET \$5`
+	mock.ExpectQuery(query).
+		WithArgs(organizationID, status, targetID, 20, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.ListByOrganization(organizationID, filter, 20, 40); err != nil {
+		t.Fatalf("ListByOrganization: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanRepositoryListByOrganizationRejectsUnknownSort(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewScanRepository(db)
+
+	for _, sort := range []string{"url", "created_at; DROP TABLE scan_jobs", "id"} {
+		_, err := repo.ListByOrganization(uuid.New(), ScanListFilter{Sort: sort}, 20, 0)
+		if !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("sort %q: error = %v, want ErrInvalidSort", sort, err)
+		}
+	}
+	// None of them reached the database
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanRepositoryCreateResultRecomputesSummary(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewScanRepository(db)
+
+	scanID := uuid.New()
+	results := []*models.ScanResult{
+		{ID: uuid.New(), ScanID: scanID, CheckType: "headers", Status: "success", Findings: 3, Severity: models.SeverityHigh,
+			FindingsBySeverity: &models.SeverityCounts{High: 1, Low: 2}},
+		{ID: uuid.New(), ScanID: scanID, CheckType: "ssl", Status: "success", Findings: 1, Severity: models.SeverityMedium},
+		{ID: uuid.New(), ScanID: scanID, CheckType: "dns", Status: "success", Severity: models.SeverityInfo},
+	}
+
+	// The rollup counts a result's breakdown when it has one and its
+	// findings under its severity otherwise
+	var want models.SeverityCounts
+	for _, result := range results {
+		mock.ExpectQuery(`INSERT INTO scan_results`).WithArgs(result.ID, scanID, result.CheckType, result.Status,
+			sqlmock.AnyArg(), result.Findings, result.Severity, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+
+		if result.FindingsBySeverity != nil {
+			want.Critical += result.FindingsBySeverity.Critical
+			want.High += result.FindingsBySeverity.High
+			want.Medium += result.FindingsBySeverity.Medium
+			want.Low += result.FindingsBySeverity.Low
+			want.Info += result.FindingsBySeverity.Info
+		} else {
+			switch result.Severity {
+			case models.SeverityHigh:
+				want.High += result.Findings
+			case models.SeverityMedium:
+				want.Medium += result.Findings
+			case models.SeverityInfo:
+				want.Info += result.Findings
+			}
+		}
+		mock.ExpectQuery(`UPDATE scan_jobs\s+SET findings_total = .*\s+findings_by_severity = jsonb_build_object`).
+			WithArgs(scanID).
+			WillReturnRows(sqlmock.NewRows([]string{"critical", "high", "medium", "low", "info"}).
+				AddRow(want.Critical, want.High, want.Medium, want.Low, want.Info))
+
+		if err := repo.CreateResult(result); err != nil {
+			t.Fatalf("CreateResult(%s): %v", result.CheckType, err)
+		}
+	}
+
+	// The stored summary is what the scan is read back with
+	url := "https://example.com"
+	stored := &models.ScanJob{ID: scanID, URL: &url, OrganizationID: uuid.New(), InitiatedBy: uuid.New(),
+		Status: models.ScanStatusCompleted, Progress: 100, Checks: []string{"headers", "ssl", "dns"},
+		Summary: models.ScanSummary{Total: want.Total(), BySeverity: want}}
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(scanID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(stored)...))
+
+	scan, err := repo.GetByID(scanID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if wantSummary := (models.ScanSummary{Total: 4, BySeverity: want}); scan.Summary != wantSummary {
+		t.Errorf("summary = %+v, want %+v", scan.Summary, wantSummary)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeverityRollupCountsEverySeverity(t *testing.T) {
+	for _, severity := range []string{
+		models.SeverityCritical, models.SeverityHigh, models.SeverityMedium, models.SeverityLow, models.SeverityInfo,
+	} {
+		breakdown := fmt.Sprintf(`SUM((findings_by_severity->>'%s')::int) FILTER (WHERE findings_by_severity IS NOT NULL)`, severity)
+		bare := fmt.Sprintf(`SUM(findings) FILTER (WHERE findings_by_severity IS NULL AND severity = '%s'), 0) AS %s`, severity, severity)
+		if !strings.Contains(severityRollupSelect, breakdown) || !strings.Contains(severityRollupSelect, bare) {
+			t.Errorf("rollup does not sum %s findings from both breakdowns and bare results", severity)
+		}
+	}
+}
+
+func TestScanJobFixtureMatchesScanColumns(t *testing.T) {
+	// Drop function arguments so only the commas between columns are left
+	selected := regexp.MustCompile(`\([^)]*\)`).ReplaceAllString(scanColumns, "")
+	var columns []string
+	for _, column := range strings.Split(selected, ",") {
+		fields := strings.Fields(column)
+		columns = append(columns, fields[len(fields)-1]) // the alias of an expression
+	}
+	if !reflect.DeepEqual(columns, repositorytest.ScanJobColumns) {
+		t.Errorf("repositorytest.ScanJobColumns = %v, want %v", repositorytest.ScanJobColumns, columns)
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"publicscannerapi/internal/models"
+)
+
+var ErrScheduleNotFound = errors.New("scheduled scan not found")
+
+// ScheduleRepository handles scheduled scan database operations
+type ScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewScheduleRepository creates a new scheduled scan repository
+func NewScheduleRepository(db *sql.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// scheduleColumns is the column list shared by every scheduled scan query
+const scheduleColumns = `
+		id, organization_id, target_id, checks, config, cron_expression,
+		next_run_at, last_run_at, last_scan_id, created_by, created_at
+`
+
+// scanSchedule reads a scheduled scan row selected with scheduleColumns
+func scanSchedule(row rowScanner) (*models.ScheduledScan, error) {
+	schedule := &models.ScheduledScan{}
+	var checks pq.StringArray
+
+	err := row.Scan(
+		&schedule.ID,
+		&schedule.OrganizationID,
+		&schedule.TargetID,
+		&checks,
+		&schedule.Config,
+		&schedule.CronExpression,
+		&schedule.NextRunAt,
+		&schedule.LastRunAt,
+		&schedule.LastScanID,
+		&schedule.CreatedBy,
+		&schedule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Checks = checks
+
+	return schedule, nil
+}
+
+// querySchedules runs a query selecting scheduleColumns
+func (r *ScheduleRepository) querySchedules(query string, args ...interface{}) ([]*models.ScheduledScan, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []*models.ScheduledScan
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// Create stores a new scheduled scan
+func (r *ScheduleRepository) Create(schedule *models.ScheduledScan) error {
+	query := `
+		INSERT INTO scheduled_scans (id, organization_id, target_id, checks, config, cron_expression, next_run_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		schedule.ID,
+		schedule.OrganizationID,
+		schedule.TargetID,
+		pq.Array(schedule.Checks),
+		schedule.Config,
+		schedule.CronExpression,
+		schedule.NextRunAt,
+		schedule.CreatedBy,
+	).Scan(&schedule.CreatedAt)
+}
+
+// GetByID retrieves a scheduled scan by ID
+func (r *ScheduleRepository) GetByID(id uuid.UUID) (*models.ScheduledScan, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM scheduled_scans
+		WHERE id = $1
+	`
+
+	schedule, err := scanSchedule(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// ListByOrganization retrieves an organization's scheduled scans, the next
+// to run first
+func (r *ScheduleRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.ScheduledScan, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM scheduled_scans
+		WHERE organization_id = $1
+		ORDER BY next_run_at, created_at
+	`
+
+	return r.querySchedules(query, organizationID)
+}
+
+// ListDue retrieves up to limit scheduled scans whose next run is due at now
+func (r *ScheduleRepository) ListDue(now time.Time, limit int) ([]*models.ScheduledScan, error) {
+	query := `SELECT ` + scheduleColumns + `
+		FROM scheduled_scans
+		WHERE next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+	`
+
+	return r.querySchedules(query, now, limit)
+}
+
+// Claim moves a due schedule's next run from from to next. It reports false
+// when another scheduler already claimed the run, so each run starts once.
+func (r *ScheduleRepository) Claim(id uuid.UUID, from, next time.Time) (bool, error) {
+	query := `
+		UPDATE scheduled_scans
+		SET next_run_at = $3
+		WHERE id = $1 AND next_run_at = $2
+	`
+
+	result, err := r.db.Exec(query, id, from, next)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// RecordRun stores the scan started for a schedule's run
+func (r *ScheduleRepository) RecordRun(id, scanID uuid.UUID, ranAt time.Time) error {
+	query := `
+		UPDATE scheduled_scans
+		SET last_run_at = $2, last_scan_id = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(query, id, ranAt, scanID)
+	return err
+}
+
+// Delete deletes a scheduled scan. Scans it already started are kept.
+func (r *ScheduleRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM scheduled_scans WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrScheduleNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+)
+
+// ServiceAccountRepository handles service account and API key database operations.
+// Service accounts are rows of users flagged is_service_account, so every
+// created_by reference and role check treats them like members.
+type ServiceAccountRepository struct {
+	db *sql.DB
+}
+
+// NewServiceAccountRepository creates a new service account repository
+func NewServiceAccountRepository(db *sql.DB) *ServiceAccountRepository {
+	return &ServiceAccountRepository{db: db}
+}
+
+// serviceAccountColumns is the column list scanned by scanServiceAccount
+const serviceAccountColumns = `u.id, om.organization_id, u.first_name, om.role, u.is_active, u.created_at`
+
+// scanServiceAccount scans a row selected with serviceAccountColumns
+func scanServiceAccount(row rowScanner) (*models.ServiceAccount, error) {
+	account := &models.ServiceAccount{}
+	err := row.Scan(
+		&account.ID,
+		&account.OrganizationID,
+		&account.Name,
+		&account.Role,
+		&account.IsActive,
+		&account.CreatedAt,
+	)
+	return account, err
+}
+
+// Create creates a service account and its membership in one transaction.
+// It has a placeholder email and an unusable password hash.
+func (r *ServiceAccountRepository) Create(account *models.ServiceAccount) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	userQuery := `
+		INSERT INTO users (id, email, password_hash, first_name, last_name, is_active, is_service_account)
+		VALUES ($1, $2, '!', $3, '', true, true)
+		RETURNING is_active, created_at
+	`
+	email := fmt.Sprintf("%s@service-accounts.invalid", account.ID)
+	if err := tx.QueryRow(userQuery, account.ID, email, account.Name).Scan(&account.IsActive, &account.CreatedAt); err != nil {
+		return err
+	}
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(memberQuery, account.OrganizationID, account.ID, account.Role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a service account of an organization
+func (r *ServiceAccountRepository) GetByID(organizationID, id uuid.UUID) (*models.ServiceAccount, error) {
+	query := `
+		SELECT ` + serviceAccountColumns + `
+		FROM users u
+		JOIN organization_members om ON om.user_id = u.id
+		WHERE u.id = $1 AND om.organization_id = $2 AND u.is_service_account = true
+	`
+
+	account, err := scanServiceAccount(r.db.QueryRow(query, id, organizationID))
+	if err == sql.ErrNoRows {
+		return nil, ErrServiceAccountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListByOrganization retrieves all service accounts of an organization
+func (r *ServiceAccountRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.ServiceAccount, error) {
+	query := `
+		SELECT ` + serviceAccountColumns + `
+		FROM users u
+		JOIN organization_members om ON om.user_id = u.id
+		WHERE om.organization_id = $1 AND u.is_service_account = true
+		ORDER BY u.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []*models.ServiceAccount{}
+	for rows.Next() {
+		account, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// Deactivate disables a service account and revokes all of its API keys
+func (r *ServiceAccountRepository) Deactivate(id uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE users SET is_active = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND is_service_account = true
+	`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrServiceAccountNotFound
+	}
+
+	if _, err := tx.Exec(`UPDATE api_keys SET is_active = false WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// apiKeyColumns is the column list scanned by scanAPIKey
+const apiKeyColumns = `id, user_id, organization_id, name, key_hash, last_used_at, expires_at, is_active, created_at`
+
+// scanAPIKey scans a row selected with apiKeyColumns
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.OrganizationID,
+		&key.Name,
+		&key.KeyHash,
+		&key.LastUsedAt,
+		&key.ExpiresAt,
+		&key.IsActive,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+// CreateAPIKey stores a new API key
+func (r *ServiceAccountRepository) CreateAPIKey(key *models.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, organization_id, name, key_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING is_active, created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		key.ID,
+		key.UserID,
+		key.OrganizationID,
+		key.Name,
+		key.KeyHash,
+		key.ExpiresAt,
+	).Scan(&key.IsActive, &key.CreatedAt)
+}
+
+// ListAPIKeys retrieves the API keys of a service account
+func (r *ServiceAccountRepository) ListAPIKeys(userID uuid.UUID) ([]*models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey deactivates an API key of a service account
+func (r *ServiceAccountRepository) RevokeAPIKey(userID, keyID uuid.UUID) error {
+	result, err := r.db.Exec(`UPDATE api_keys SET is_active = false WHERE id = $1 AND user_id = $2`, keyID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// UseAPIKey resolves an active, unexpired key of an active service account
+// by its hash and records that it was used
+func (r *ServiceAccountRepository) UseAPIKey(keyHash string, now time.Time) (*models.APIKey, error) {
+	query := `
+		UPDATE api_keys k
+		SET last_used_at = $2
+		FROM users u
+		WHERE k.key_hash = $1
+		  AND k.is_active = true
+		  AND (k.expires_at IS NULL OR k.expires_at > $2)
+		  AND u.id = k.user_id
+		  AND u.is_active = true
+		  AND u.is_service_account = true
+		RETURNING k.id, k.user_id, k.organization_id, k.name, k.key_hash, k.last_used_at,
+		          k.expires_at, k.is_active, k.created_at
+	`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash, now))
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
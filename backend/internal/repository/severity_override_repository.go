@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrSeverityOverrideNotFound = errors.New("severity override not found")
+	ErrSeverityOverrideExists   = errors.New("severity override already exists")
+)
+
+// SeverityOverrideRepository handles severity override rule database operations
+type SeverityOverrideRepository struct {
+	db *sql.DB
+}
+
+// NewSeverityOverrideRepository creates a new severity override repository
+func NewSeverityOverrideRepository(db *sql.DB) *SeverityOverrideRepository {
+	return &SeverityOverrideRepository{db: db}
+}
+
+// Create stores a new override rule
+func (r *SeverityOverrideRepository) Create(rule *models.SeverityOverride) error {
+	query := `
+		INSERT INTO severity_overrides (id, organization_id, check_type, match_severity, severity, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		rule.ID,
+		rule.OrganizationID,
+		rule.CheckType,
+		rule.MatchSeverity,
+		rule.Severity,
+		rule.CreatedBy,
+	).Scan(&rule.CreatedAt)
+
+	if err != nil {
+		// Check for unique constraint violation
+		if err.Error() == `pq: duplicate key value violates unique constraint "idx_severity_overrides_rule"` {
+			return ErrSeverityOverrideExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ListByOrganization retrieves an organization's override rules
+func (r *SeverityOverrideRepository) ListByOrganization(organizationID uuid.UUID) (models.SeverityOverrides, error) {
+	query := `
+		SELECT id, organization_id, check_type, match_severity, severity, created_by, created_at
+		FROM severity_overrides
+		WHERE organization_id = $1
+		ORDER BY check_type, match_severity NULLS FIRST
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := models.SeverityOverrides{}
+	for rows.Next() {
+		rule := &models.SeverityOverride{}
+		err := rows.Scan(
+			&rule.ID,
+			&rule.OrganizationID,
+			&rule.CheckType,
+			&rule.MatchSeverity,
+			&rule.Severity,
+			&rule.CreatedBy,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// Delete removes an override rule of an organization
+func (r *SeverityOverrideRepository) Delete(organizationID, id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM severity_overrides WHERE id = $1 AND organization_id = $2`, id, organizationID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSeverityOverrideNotFound
+	}
+
+	return nil
+}
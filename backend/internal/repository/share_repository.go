@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrShareNotFound = errors.New("scan share not found")
+)
+
+// ShareRepository handles scan share database operations
+type ShareRepository struct {
+	db *sql.DB
+}
+
+// NewShareRepository creates a new scan share repository
+func NewShareRepository(db *sql.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// shareColumns is the column list shared by every scan share query
+const shareColumns = `
+		id, scan_id, organization_id, created_by, expires_at, revoked_at, created_at
+`
+
+// scanShare reads a scan share row selected with shareColumns
+func scanShare(row rowScanner) (*models.ScanShare, error) {
+	share := &models.ScanShare{}
+
+	err := row.Scan(
+		&share.ID,
+		&share.ScanID,
+		&share.OrganizationID,
+		&share.CreatedBy,
+		&share.ExpiresAt,
+		&share.RevokedAt,
+		&share.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// Create creates a new scan share
+func (r *ShareRepository) Create(share *models.ScanShare) error {
+	query := `
+		INSERT INTO scan_shares (id, scan_id, organization_id, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		share.ID,
+		share.ScanID,
+		share.OrganizationID,
+		share.CreatedBy,
+		share.ExpiresAt,
+	).Scan(&share.CreatedAt)
+}
+
+// GetByID retrieves a scan share by ID
+func (r *ShareRepository) GetByID(id uuid.UUID) (*models.ScanShare, error) {
+	query := `SELECT ` + shareColumns + `
+		FROM scan_shares
+		WHERE id = $1
+	`
+
+	share, err := scanShare(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrShareNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// ListByScan retrieves all shares of a scan
+func (r *ShareRepository) ListByScan(scanID uuid.UUID) ([]*models.ScanShare, error) {
+	query := `SELECT ` + shareColumns + `
+		FROM scan_shares
+		WHERE scan_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, scanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []*models.ScanShare
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, rows.Err()
+}
+
+// Revoke marks a scan share as revoked. Revoking twice is a no-op.
+func (r *ShareRepository) Revoke(id uuid.UUID) error {
+	query := `
+		UPDATE scan_shares
+		SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
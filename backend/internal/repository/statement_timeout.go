@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// beginWithStatementTimeout starts a transaction whose statements may each
+// run for up to timeout, replacing the session's statement_timeout for
+// long-running maintenance work. A zero timeout keeps the session limit.
+func beginWithStatementTimeout(db *sql.DB, timeout time.Duration) (*sql.Tx, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		return tx, nil
+	}
+
+	// SET does not take bind parameters; the value is a formatted integer
+	if _, err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
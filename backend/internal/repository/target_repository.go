@@ -2,7 +2,10 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -23,15 +26,57 @@ func NewTargetRepository(db *sql.DB) *TargetRepository {
 	return &TargetRepository{db: db}
 }
 
-// Create creates a new target
+// targetColumns is the column list shared by every target query
+const targetColumns = `
+		id, organization_id, name, hostname, description, tags, is_active,
+		is_monitored, monitor_interval_hours, next_monitor_run_at,
+		created_by, created_at, updated_at, deleted_at
+`
+
+// scanTarget reads a target row selected with targetColumns
+func scanTarget(row rowScanner) (*models.Target, error) {
+	target := &models.Target{}
+	var tags pq.StringArray
+
+	err := row.Scan(
+		&target.ID,
+		&target.OrganizationID,
+		&target.Name,
+		&target.Hostname,
+		&target.Description,
+		&tags,
+		&target.IsActive,
+		&target.IsMonitored,
+		&target.MonitorIntervalHours,
+		&target.NextMonitorRunAt,
+		&target.CreatedBy,
+		&target.CreatedAt,
+		&target.UpdatedAt,
+		&target.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	target.Tags = tags
+	return target, nil
+}
+
+// Create creates a new target and records its creation in the target history
 func (r *TargetRepository) Create(target *models.Target) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO targets (id, organization_id, name, hostname, description, tags, is_active, created_by)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING created_at, updated_at
+		RETURNING monitor_interval_hours, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		target.ID,
 		target.OrganizationID,
@@ -41,34 +86,222 @@ func (r *TargetRepository) Create(target *models.Target) error {
 		pq.Array(target.Tags),
 		target.IsActive,
 		target.CreatedBy,
-	).Scan(&target.CreatedAt, &target.UpdatedAt)
+	).Scan(&target.MonitorIntervalHours, &target.CreatedAt, &target.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := insertTargetHistory(tx, models.NewTargetHistoryEntry(nil, target, target.CreatedBy)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetByID retrieves a target by ID
-func (r *TargetRepository) GetByID(id uuid.UUID) (*models.Target, error) {
-	target := &models.Target{}
+// insertTargetHistory appends an entry to the target history
+func insertTargetHistory(tx *sql.Tx, entry *models.TargetHistoryEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO target_history (id, target_id, organization_id, actor_id, action, changes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	return tx.QueryRow(
+		query,
+		entry.ID,
+		entry.TargetID,
+		entry.OrganizationID,
+		entry.ActorID,
+		entry.Action,
+		changes,
+	).Scan(&entry.CreatedAt)
+}
+
+// CreateBatch creates several targets in a single transaction, recording
+// each creation in the target history
+func (r *TargetRepository) CreateBatch(targets []*models.Target) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO targets (id, organization_id, name, hostname, description, tags, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING monitor_interval_hours, created_at, updated_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, target := range targets {
+		err := stmt.QueryRow(
+			target.ID,
+			target.OrganizationID,
+			target.Name,
+			target.Hostname,
+			target.Description,
+			pq.Array(target.Tags),
+			target.IsActive,
+			target.CreatedBy,
+		).Scan(&target.MonitorIntervalHours, &target.CreatedAt, &target.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := insertTargetHistory(tx, models.NewTargetHistoryEntry(nil, target, target.CreatedBy)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ExistingHostnames returns which of the given hostnames already exist in an
+// organization. Hostnames are compared case-insensitively and returned lowercased.
+func (r *TargetRepository) ExistingHostnames(organizationID uuid.UUID, hostnames []string) (map[string]bool, error) {
+	lowered := make([]string, len(hostnames))
+	for i, hostname := range hostnames {
+		lowered[i] = strings.ToLower(hostname)
+	}
+
 	query := `
-		SELECT id, organization_id, name, hostname, description, tags, is_active, created_by, created_at, updated_at
+		SELECT DISTINCT LOWER(hostname)
 		FROM targets
-		WHERE id = $1
+		WHERE organization_id = $1 AND LOWER(hostname) = ANY($2) AND deleted_at IS NULL
 	`
 
-	var tags pq.StringArray
-	err := r.db.QueryRow(query, id).Scan(
-		&target.ID,
-		&target.OrganizationID,
-		&target.Name,
-		&target.Hostname,
-		&target.Description,
-		&tags,
-		&target.IsActive,
-		&target.CreatedBy,
-		&target.CreatedAt,
-		&target.UpdatedAt,
-	)
+	rows, err := r.db.Query(query, organizationID, pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var hostname string
+		if err := rows.Scan(&hostname); err != nil {
+			return nil, err
+		}
+		existing[hostname] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// GetByHostnames returns the targets of an organization whose hostname is
+// one of hostnames, keyed by lowercased hostname. Hostnames are compared
+// case-insensitively; if several targets share a hostname the oldest wins.
+func (r *TargetRepository) GetByHostnames(organizationID uuid.UUID, hostnames []string) (map[string]*models.Target, error) {
+	lowered := make([]string, len(hostnames))
+	for i, hostname := range hostnames {
+		lowered[i] = strings.ToLower(hostname)
+	}
+
+	query := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE organization_id = $1 AND LOWER(hostname) = ANY($2) AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, organizationID, pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make(map[string]*models.Target)
+	for rows.Next() {
+		target, err := scanTarget(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		key := strings.ToLower(target.Hostname)
+		if _, exists := targets[key]; !exists {
+			targets[key] = target
+		}
+	}
+
+	return targets, rows.Err()
+}
+
+// Apply creates and updates targets in a single transaction. Creations are
+// recorded in the target history together with the given history entries
+// of the updates.
+func (r *TargetRepository) Apply(creates, updates []*models.Target, history []*models.TargetHistoryEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, target := range creates {
+		err := tx.QueryRow(`
+			INSERT INTO targets (id, organization_id, name, hostname, description, tags, is_active, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING monitor_interval_hours, created_at, updated_at
+		`,
+			target.ID,
+			target.OrganizationID,
+			target.Name,
+			target.Hostname,
+			target.Description,
+			pq.Array(target.Tags),
+			target.IsActive,
+			target.CreatedBy,
+		).Scan(&target.MonitorIntervalHours, &target.CreatedAt, &target.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := insertTargetHistory(tx, models.NewTargetHistoryEntry(nil, target, target.CreatedBy)); err != nil {
+			return err
+		}
+	}
+
+	for _, target := range updates {
+		err := tx.QueryRow(`
+			UPDATE targets
+			SET name = $2, description = $3, tags = $4
+			WHERE id = $1
+			RETURNING updated_at
+		`,
+			target.ID,
+			target.Name,
+			target.Description,
+			pq.Array(target.Tags),
+		).Scan(&target.UpdatedAt)
+		if err == sql.ErrNoRows {
+			return ErrTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range history {
+		if err := insertTargetHistory(tx, entry); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a target by ID; soft-deleted targets are not found
+func (r *TargetRepository) GetByID(id uuid.UUID) (*models.Target, error) {
+	query := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	target, err := scanTarget(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, ErrTargetNotFound
 	}
@@ -76,21 +309,54 @@ func (r *TargetRepository) GetByID(id uuid.UUID) (*models.Target, error) {
 		return nil, err
 	}
 
-	target.Tags = tags
-
 	return target, nil
 }
 
 // ListByOrganization retrieves all targets for an organization
 func (r *TargetRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.Target, error) {
-	query := `
-		SELECT id, organization_id, name, hostname, description, tags, is_active, created_by, created_at, updated_at
+	query := `SELECT ` + targetColumns + `
 		FROM targets
-		WHERE organization_id = $1
+		WHERE organization_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query, organizationID)
+	return r.queryTargets(query, organizationID)
+}
+
+// Search retrieves an organization's targets whose name, hostname or
+// description contains query, case-insensitively, and that carry any of
+// tags. An empty query or tag list does not filter.
+func (r *TargetRepository) Search(organizationID uuid.UUID, query string, tags []string) ([]*models.Target, error) {
+	search := ""
+	if query != "" {
+		search = likePattern(query)
+	}
+
+	sqlQuery := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE organization_id = $1 AND deleted_at IS NULL
+		  AND ($2 = '' OR name ILIKE $2 OR hostname ILIKE $2 OR description ILIKE $2)
+		  AND (cardinality($3::text[]) = 0 OR tags && $3)
+		ORDER BY created_at DESC
+	`
+
+	return r.queryTargets(sqlQuery, organizationID, search, pq.Array(tags))
+}
+
+// ListActiveByTag retrieves an organization's active targets carrying tag
+func (r *TargetRepository) ListActiveByTag(organizationID uuid.UUID, tag string) ([]*models.Target, error) {
+	query := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE organization_id = $1 AND is_active AND deleted_at IS NULL AND $2 = ANY(tags)
+		ORDER BY name, hostname
+	`
+
+	return r.queryTargets(query, organizationID, tag)
+}
+
+// queryTargets runs a query selecting targetColumns and reads every row
+func (r *TargetRepository) queryTargets(query string, args ...interface{}) ([]*models.Target, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -98,42 +364,53 @@ func (r *TargetRepository) ListByOrganization(organizationID uuid.UUID) ([]*mode
 
 	var targets []*models.Target
 	for rows.Next() {
-		target := &models.Target{}
-		var tags pq.StringArray
-
-		err := rows.Scan(
-			&target.ID,
-			&target.OrganizationID,
-			&target.Name,
-			&target.Hostname,
-			&target.Description,
-			&tags,
-			&target.IsActive,
-			&target.CreatedBy,
-			&target.CreatedAt,
-			&target.UpdatedAt,
-		)
+		target, err := scanTarget(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		target.Tags = tags
 		targets = append(targets, target)
 	}
 
-	return targets, nil
+	return targets, rows.Err()
 }
 
-// Update updates a target
-func (r *TargetRepository) Update(target *models.Target) error {
+// ClaimDueMonitored returns up to limit active monitored targets whose next
+// monitor run is due at now, moving each one's next run a full interval past
+// now. Rows locked by a concurrent claim are skipped.
+func (r *TargetRepository) ClaimDueMonitored(now time.Time, limit int) ([]*models.Target, error) {
 	query := `
 		UPDATE targets
-		SET name = $2, hostname = $3, description = $4, tags = $5, is_active = $6
-		WHERE id = $1
+		SET next_monitor_run_at = $1 + make_interval(hours => monitor_interval_hours)
+		WHERE id IN (
+			SELECT id FROM targets
+			WHERE is_monitored AND is_active AND deleted_at IS NULL AND next_monitor_run_at <= $1
+			ORDER BY next_monitor_run_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + targetColumns
+
+	return r.queryTargets(query, now, limit)
+}
+
+// Update updates a target and, when entry is not nil, records the change in
+// the target history
+func (r *TargetRepository) Update(target *models.Target, entry *models.TargetHistoryEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE targets
+		SET name = $2, hostname = $3, description = $4, tags = $5, is_active = $6,
+		    is_monitored = $7, monitor_interval_hours = $8, next_monitor_run_at = $9
+		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		target.ID,
 		target.Name,
@@ -141,17 +418,71 @@ func (r *TargetRepository) Update(target *models.Target) error {
 		target.Description,
 		pq.Array(target.Tags),
 		target.IsActive,
+		target.IsMonitored,
+		target.MonitorIntervalHours,
+		target.NextMonitorRunAt,
 	).Scan(&target.UpdatedAt)
 
 	if err == sql.ErrNoRows {
 		return ErrTargetNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	if entry != nil {
+		if err := insertTargetHistory(tx, entry); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListHistory retrieves a target's history, newest first
+func (r *TargetRepository) ListHistory(targetID uuid.UUID) ([]*models.TargetHistoryEntry, error) {
+	query := `
+		SELECT id, target_id, organization_id, actor_id, action, changes, created_at
+		FROM target_history
+		WHERE target_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, targetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*models.TargetHistoryEntry{}
+	for rows.Next() {
+		entry := &models.TargetHistoryEntry{}
+		var changes []byte
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TargetID,
+			&entry.OrganizationID,
+			&entry.ActorID,
+			&entry.Action,
+			&changes,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	return history, rows.Err()
 }
 
-// Delete deletes a target
+// Delete soft-deletes a target. It disappears from lookups and listings
+// and is no longer monitored, while its scans, history and campaign
+// assignments are kept until Restore brings it back.
 func (r *TargetRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM targets WHERE id = $1`
+	query := `UPDATE targets SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 	result, err := r.db.Exec(query, id)
 	if err != nil {
 		return err
@@ -167,3 +498,42 @@ func (r *TargetRepository) Delete(id uuid.UUID) error {
 
 	return nil
 }
+
+// GetDeletedByID retrieves a soft-deleted target by ID
+func (r *TargetRepository) GetDeletedByID(id uuid.UUID) (*models.Target, error) {
+	query := `SELECT ` + targetColumns + `
+		FROM targets
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	target, err := scanTarget(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// Restore undoes Delete. A monitored target's next run is moved to now if
+// it fell due while the target was deleted.
+func (r *TargetRepository) Restore(id uuid.UUID) (*models.Target, error) {
+	query := `
+		UPDATE targets
+		SET deleted_at = NULL,
+		    next_monitor_run_at = CASE WHEN is_monitored THEN GREATEST(next_monitor_run_at, NOW()) END
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + targetColumns
+
+	target, err := scanTarget(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// targetRowColumns mirror targetColumns
+var targetRowColumns = []string{
+	"id", "organization_id", "name", "hostname", "description", "tags", "is_active",
+	"is_monitored", "monitor_interval_hours", "next_monitor_run_at",
+	"created_by", "created_at", "updated_at", "deleted_at",
+}
+
+// targetRow returns the row of an active target of organizationID
+func targetRow(id, organizationID uuid.UUID, hostname, tags string, deletedAt interface{}) []driver.Value {
+	now := time.Now()
+	return []driver.Value{
+		id.String(), organizationID.String(), hostname, hostname, "", tags, true,
+		false, 24, nil,
+		uuid.NewString(), now, now, deletedAt,
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"example", "%example%"},
+		{"EXAMPLE.com", "%EXAMPLE.com%"},
+		{"50%_off", `%50\%\_off%`},
+		{`back\slash`, `%back\\slash%`},
+	}
+
+	for _, tt := range tests {
+		if got := likePattern(tt.in); got != tt.want {
+			t.Errorf("likePattern(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// searchQuery matches the filters of Search
+const searchQuery = `WHERE organization_id = \$1 AND deleted_at IS NULL\s+` +
+	`AND \(\$2 = '' OR name ILIKE \$2 OR hostname ILIKE \$2 OR description ILIKE \$2\)\s+` +
+	`AND \(cardinality\(\$3::text\[\]\) = 0 OR tags && \$3\)`
+
+func TestTargetRepositorySearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewTargetRepository(db)
+
+	organizationID := uuid.New()
+	api, shop := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name   string
+		query  string
+		tags   []string
+		search string
+	}{
+		{"partial hostname", "Example", nil, "%Example%"},
+		{"tags only", "", []string{"prod", "staging"}, ""},
+		{"hostname and tags", "example.c", []string{"prod"}, "%example.c%"},
+	}
+
+	for _, tt := range tests {
+		// The database does the matching; what it is asked must be
+		// scoped to the organization and combine both filters
+		mock.ExpectQuery(searchQuery).
+			WithArgs(organizationID, tt.search, pq.Array(tt.tags)).
+			WillReturnRows(sqlmock.NewRows(targetRowColumns).
+				AddRow(targetRow(api, organizationID, "api.example.com", "{prod}", nil)...).
+				AddRow(targetRow(shop, organizationID, "shop.example.com", "{prod,staging}", nil)...))
+
+		targets, err := repo.Search(organizationID, tt.query, tt.tags)
+		if err != nil {
+			t.Fatalf("%s: Search: %v", tt.name, err)
+		}
+		if len(targets) != 2 || targets[0].ID != api || targets[1].ID != shop || len(targets[1].Tags) != 2 {
+			t.Errorf("%s: Search returned %v", tt.name, targets)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
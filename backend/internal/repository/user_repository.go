@@ -3,15 +3,17 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"publicscannerapi/internal/models"
 )
 
 var (
-	ErrUserNotFound     = errors.New("user not found")
-	ErrEmailExists      = errors.New("email already exists")
-	ErrInvalidPassword  = errors.New("invalid password")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrEmailExists     = errors.New("email already exists")
+	ErrInvalidPassword = errors.New("invalid password")
+	ErrNotMember       = errors.New("user is not a member of the organization")
 )
 
 // UserRepository handles user database operations
@@ -27,8 +29,8 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 // Create creates a new user
 func (r *UserRepository) Create(user *models.User) error {
 	query := `
-		INSERT INTO users (id, email, password_hash, first_name, last_name, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, email, password_hash, first_name, last_name, is_active, email_verified)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING created_at, updated_at
 	`
 
@@ -40,6 +42,7 @@ func (r *UserRepository) Create(user *models.User) error {
 		user.FirstName,
 		user.LastName,
 		user.IsActive,
+		user.EmailVerified,
 	).Scan(&user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
@@ -57,7 +60,7 @@ func (r *UserRepository) Create(user *models.User) error {
 func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, is_active, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, is_active, is_superadmin, is_service_account, failed_login_attempts, locked_until, email_verified, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -69,6 +72,11 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 		&user.FirstName,
 		&user.LastName,
 		&user.IsActive,
+		&user.IsSuperAdmin,
+		&user.IsServiceAccount,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.EmailVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -87,7 +95,7 @@ func (r *UserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, email, password_hash, first_name, last_name, is_active, created_at, updated_at
+		SELECT id, email, password_hash, first_name, last_name, is_active, is_superadmin, is_service_account, failed_login_attempts, locked_until, email_verified, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -99,6 +107,11 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 		&user.FirstName,
 		&user.LastName,
 		&user.IsActive,
+		&user.IsSuperAdmin,
+		&user.IsServiceAccount,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.EmailVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -137,6 +150,86 @@ func (r *UserRepository) Update(user *models.User) error {
 	return err
 }
 
+// UpdatePassword sets a user's password hash and clears their failed logins
+// and lockout. In the same transaction it revokes the refresh tokens of
+// every session but keepSessionID, so other logins end once their access
+// token expires; an empty keepSessionID revokes them all.
+func (r *UserRepository) UpdatePassword(id uuid.UUID, passwordHash, keepSessionID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET password_hash = $2, failed_login_attempts = 0, locked_until = NULL
+		WHERE id = $1
+	`
+	result, err := tx.Exec(query, id, passwordHash)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+
+	query = `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND session_id <> $2 AND revoked_at IS NULL
+	`
+	if _, err := tx.Exec(query, id, keepSessionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordFailedLogin counts a failed login of a user. The attempt that
+// reaches maxAttempts locks the account until lockUntil and starts the
+// count over; the lock's end is returned then and nil otherwise.
+func (r *UserRepository) RecordFailedLogin(id uuid.UUID, maxAttempts int, lockUntil time.Time) (*time.Time, error) {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = CASE WHEN failed_login_attempts + 1 >= $2 THEN 0 ELSE failed_login_attempts + 1 END,
+		    locked_until = CASE WHEN failed_login_attempts + 1 >= $2 THEN $3 ELSE locked_until END
+		WHERE id = $1
+		RETURNING failed_login_attempts = 0, locked_until
+	`
+
+	var locked bool
+	var lockedUntil *time.Time
+	err := r.db.QueryRow(query, id, maxAttempts, lockUntil).Scan(&locked, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, nil
+	}
+
+	return lockedUntil, nil
+}
+
+// ResetFailedLogins clears a user's failed login count and lock
+func (r *UserRepository) ResetFailedLogins(id uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET failed_login_attempts = 0, locked_until = NULL
+		WHERE id = $1 AND (failed_login_attempts > 0 OR locked_until IS NOT NULL)
+	`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
 // Delete deletes a user
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	query := `DELETE FROM users WHERE id = $1`
@@ -156,13 +249,14 @@ func (r *UserRepository) Delete(id uuid.UUID) error {
 	return nil
 }
 
-// GetUserOrganization retrieves the first organization a user belongs to
+// GetUserOrganization retrieves the first organization a user joined
 func (r *UserRepository) GetUserOrganization(userID uuid.UUID) (*uuid.UUID, error) {
 	var orgID uuid.UUID
 	query := `
 		SELECT organization_id
 		FROM organization_members
 		WHERE user_id = $1
+		ORDER BY joined_at, organization_id
 		LIMIT 1
 	`
 
@@ -176,3 +270,23 @@ func (r *UserRepository) GetUserOrganization(userID uuid.UUID) (*uuid.UUID, erro
 
 	return &orgID, nil
 }
+
+// GetUserRole retrieves a user's role within an organization
+func (r *UserRepository) GetUserRole(userID, organizationID uuid.UUID) (models.Role, error) {
+	var role string
+	query := `
+		SELECT role
+		FROM organization_members
+		WHERE user_id = $1 AND organization_id = $2
+	`
+
+	err := r.db.QueryRow(query, userID, organizationID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", ErrNotMember
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return models.Role(role), nil
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrWebhookNotFound        = errors.New("webhook not found")
+	ErrWebhookFailureNotFound = errors.New("webhook failure not found")
+)
+
+// WebhookRepository handles webhook database operations
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// webhookColumns is the column list shared by every webhook query
+const webhookColumns = `
+		id, organization_id, name, url, events, COALESCE(secret, ''), is_active, created_by, created_at, updated_at
+`
+
+// scanWebhook reads a webhook row selected with webhookColumns
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	var events pq.StringArray
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.OrganizationID,
+		&webhook.Name,
+		&webhook.URL,
+		&events,
+		&webhook.Secret,
+		&webhook.IsActive,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Events = events
+
+	return webhook, nil
+}
+
+// scanWebhooks reads all webhook rows selected with webhookColumns
+func scanWebhooks(rows *sql.Rows) ([]*models.Webhook, error) {
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, organization_id, name, url, events, secret, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		webhook.ID,
+		webhook.OrganizationID,
+		webhook.Name,
+		webhook.URL,
+		pq.Array(webhook.Events),
+		webhook.Secret,
+		webhook.IsActive,
+		webhook.CreatedBy,
+	).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(id uuid.UUID) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE id = $1
+	`
+
+	webhook, err := scanWebhook(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// ListByOrganization retrieves all webhooks for an organization
+func (r *WebhookRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhooks(rows)
+}
+
+// ListActiveForEvent retrieves the active webhooks of an organization subscribed to an event
+func (r *WebhookRepository) ListActiveForEvent(organizationID uuid.UUID, event string) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE organization_id = $1 AND is_active = true AND $2 = ANY(events)
+	`
+
+	rows, err := r.db.Query(query, organizationID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhooks(rows)
+}
+
+// Delete deletes a webhook
+func (r *WebhookRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM webhooks WHERE id = $1`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// webhookFailureColumns is the column list shared by every webhook failure query
+const webhookFailureColumns = `
+		id, webhook_id, delivery_id, event, payload, attempts, COALESCE(last_error, ''), resolved_at, created_at, updated_at
+`
+
+// scanWebhookFailure reads a webhook failure row selected with webhookFailureColumns
+func scanWebhookFailure(row rowScanner) (*models.WebhookFailure, error) {
+	failure := &models.WebhookFailure{}
+	var attempts []byte
+
+	err := row.Scan(
+		&failure.ID,
+		&failure.WebhookID,
+		&failure.DeliveryID,
+		&failure.Event,
+		&failure.Payload,
+		&attempts,
+		&failure.LastError,
+		&failure.ResolvedAt,
+		&failure.CreatedAt,
+		&failure.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(attempts, &failure.Attempts); err != nil {
+		return nil, err
+	}
+
+	return failure, nil
+}
+
+// CreateFailure dead-letters an event whose delivery attempts were exhausted
+func (r *WebhookRepository) CreateFailure(failure *models.WebhookFailure) error {
+	attempts, err := json.Marshal(failure.Attempts)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_failures (id, webhook_id, delivery_id, event, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		failure.ID,
+		failure.WebhookID,
+		failure.DeliveryID,
+		failure.Event,
+		[]byte(failure.Payload),
+		attempts,
+		failure.LastError,
+	).Scan(&failure.CreatedAt, &failure.UpdatedAt)
+}
+
+// GetFailure retrieves a webhook failure by ID
+func (r *WebhookRepository) GetFailure(id uuid.UUID) (*models.WebhookFailure, error) {
+	query := `SELECT ` + webhookFailureColumns + `
+		FROM webhook_failures
+		WHERE id = $1
+	`
+
+	failure, err := scanWebhookFailure(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookFailureNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return failure, nil
+}
+
+// ListFailures retrieves the dead-lettered events of a webhook, newest first
+func (r *WebhookRepository) ListFailures(webhookID uuid.UUID, includeResolved bool) ([]*models.WebhookFailure, error) {
+	query := `SELECT ` + webhookFailureColumns + `
+		FROM webhook_failures
+		WHERE webhook_id = $1 AND ($2 OR resolved_at IS NULL)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, webhookID, includeResolved)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var failures []*models.WebhookFailure
+	for rows.Next() {
+		failure, err := scanWebhookFailure(rows)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, failure)
+	}
+
+	return failures, rows.Err()
+}
+
+// UpdateFailure stores the attempts of a manual retry and marks the failure
+// resolved when the redelivery succeeded
+func (r *WebhookRepository) UpdateFailure(failure *models.WebhookFailure) error {
+	attempts, err := json.Marshal(failure.Attempts)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhook_failures
+		SET attempts = $2, last_error = $3, resolved_at = $4
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err = r.db.QueryRow(query, failure.ID, attempts, failure.LastError, failure.ResolvedAt).Scan(&failure.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrWebhookFailureNotFound
+	}
+
+	return err
+}
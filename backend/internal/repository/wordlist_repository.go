@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+)
+
+var (
+	ErrWordlistNotFound = errors.New("wordlist not found")
+	ErrWordlistInUse    = errors.New("wordlist is referenced by active scans")
+)
+
+// WordlistRepository handles wordlist database operations
+type WordlistRepository struct {
+	db *sql.DB
+}
+
+// NewWordlistRepository creates a new wordlist repository
+func NewWordlistRepository(db *sql.DB) *WordlistRepository {
+	return &WordlistRepository{db: db}
+}
+
+// activeWordlistScans matches the scans that still need wordlist w: scheduled,
+// queued or running and not deleted
+const activeWordlistScans = `
+		SELECT 1 FROM scan_jobs s
+		WHERE s.config->>'wordlist_id' = w.id::text
+		  AND s.status IN ('scheduled', 'queued', 'running')
+		  AND s.deleted_at IS NULL
+`
+
+// wordlistColumns is the column list shared by every wordlist query, aliased as w
+const wordlistColumns = `
+		w.id, w.organization_id, w.name, w.storage_key, w.size_bytes, w.line_count,
+		w.created_by, w.created_at,
+		(SELECT COUNT(*) FROM (` + activeWordlistScans + `) active) AS active_scans
+`
+
+// scanWordlist reads a wordlist row selected with wordlistColumns
+func scanWordlist(row rowScanner) (*models.Wordlist, error) {
+	wordlist := &models.Wordlist{}
+
+	err := row.Scan(
+		&wordlist.ID,
+		&wordlist.OrganizationID,
+		&wordlist.Name,
+		&wordlist.StorageKey,
+		&wordlist.SizeBytes,
+		&wordlist.LineCount,
+		&wordlist.CreatedBy,
+		&wordlist.CreatedAt,
+		&wordlist.ActiveScans,
+	)
+	if err != nil {
+		return nil, err
+	}
+	wordlist.InUse = wordlist.ActiveScans > 0
+
+	return wordlist, nil
+}
+
+// Create stores a new wordlist
+func (r *WordlistRepository) Create(wordlist *models.Wordlist) error {
+	query := `
+		INSERT INTO wordlists (id, organization_id, name, storage_key, size_bytes, line_count, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		query,
+		wordlist.ID,
+		wordlist.OrganizationID,
+		wordlist.Name,
+		wordlist.StorageKey,
+		wordlist.SizeBytes,
+		wordlist.LineCount,
+		wordlist.CreatedBy,
+	).Scan(&wordlist.CreatedAt)
+}
+
+// GetByID retrieves a wordlist by ID
+func (r *WordlistRepository) GetByID(id uuid.UUID) (*models.Wordlist, error) {
+	query := `SELECT ` + wordlistColumns + `
+		FROM wordlists w
+		WHERE w.id = $1
+	`
+
+	wordlist, err := scanWordlist(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrWordlistNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return wordlist, nil
+}
+
+// ListByOrganization retrieves all wordlists for an organization
+func (r *WordlistRepository) ListByOrganization(organizationID uuid.UUID) ([]*models.Wordlist, error) {
+	query := `SELECT ` + wordlistColumns + `
+		FROM wordlists w
+		WHERE w.organization_id = $1
+		ORDER BY w.created_at DESC
+	`
+
+	rows, err := r.db.Query(query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var wordlists []*models.Wordlist
+	for rows.Next() {
+		wordlist, err := scanWordlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		wordlists = append(wordlists, wordlist)
+	}
+
+	return wordlists, rows.Err()
+}
+
+// Delete deletes a wordlist unless an active scan still references it, in
+// which case ErrWordlistInUse is returned
+func (r *WordlistRepository) Delete(id uuid.UUID) error {
+	query := `
+		DELETE FROM wordlists w
+		WHERE w.id = $1 AND NOT EXISTS (` + activeWordlistScans + `)
+	`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// Tell a missing wordlist apart from one that is still in use
+		if _, err := r.GetByID(id); err != nil {
+			return err
+		}
+		return ErrWordlistInUse
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrAcknowledgementRuleNotFound = errors.New("acknowledgement rule not found")
+)
+
+// AcknowledgementService manages an organization's acknowledgement rules
+type AcknowledgementService struct {
+	orgService *OrganizationService
+	ackRepo    *repository.AcknowledgementRepository
+}
+
+// NewAcknowledgementService creates a new acknowledgement rule service
+func NewAcknowledgementService(orgService *OrganizationService, ackRepo *repository.AcknowledgementRepository) *AcknowledgementService {
+	return &AcknowledgementService{
+		orgService: orgService,
+		ackRepo:    ackRepo,
+	}
+}
+
+// ListRules retrieves the organization's acknowledgement rules
+func (s *AcknowledgementService) ListRules(organizationID, userID uuid.UUID) ([]*models.AcknowledgementRule, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.ackRepo.ListByOrganization(organizationID, time.Now())
+}
+
+// CreateAcknowledgementRuleRequest represents a new acknowledgement rule.
+// Omitting match_severity covers every severity of the check; omitting
+// snooze_until acknowledges matching results until the rule is removed.
+type CreateAcknowledgementRuleRequest struct {
+	CheckType     string     `json:"check_type" binding:"required"`
+	MatchSeverity *string    `json:"match_severity"`
+	Reason        string     `json:"reason"`
+	SnoozeUntil   *time.Time `json:"snooze_until"`
+}
+
+// CreateRule adds an acknowledgement rule. It applies to every matching
+// result without a triage note, including results of past scans.
+func (s *AcknowledgementService) CreateRule(organizationID, userID uuid.UUID, req *CreateAcknowledgementRuleRequest) (*models.AcknowledgementRule, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var problems ValidationErrors
+	if !models.IsValidCheck(req.CheckType) {
+		problems.add("check_type", "unknown check: %s", req.CheckType)
+	}
+	if req.MatchSeverity != nil && !models.IsValidSeverity(*req.MatchSeverity) {
+		problems.add("match_severity", "must be one of critical, high, medium, low, info")
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len(reason) > 1000 {
+		problems.add("reason", "must be at most 1000 characters")
+	}
+	if req.SnoozeUntil != nil && !req.SnoozeUntil.After(time.Now()) {
+		problems.add("snooze_until", "must be in the future")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	rule := &models.AcknowledgementRule{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		CheckType:      req.CheckType,
+		MatchSeverity:  req.MatchSeverity,
+		Reason:         reason,
+		SnoozeUntil:    req.SnoozeUntil,
+		CreatedBy:      &userID,
+		Active:         true,
+	}
+	if err := s.ackRepo.Create(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteRule removes an acknowledgement rule; matching results without a
+// triage note of their own are open again
+func (s *AcknowledgementService) DeleteRule(organizationID, userID, ruleID uuid.UUID) error {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.ackRepo.Delete(organizationID, ruleID); err != nil {
+		if errors.Is(err, repository.ErrAcknowledgementRuleNotFound) {
+			return ErrAcknowledgementRuleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// AddressPolicy decides which hosts targets, quick scans and webhooks may
+// point at
+type AddressPolicy struct {
+	// AllowPrivate permits private, loopback and link-local addresses,
+	// e.g. for lab setups. Off by default so the scanners cannot be turned
+	// against internal infrastructure.
+	AllowPrivate bool
+
+	// lookup resolves names to check their addresses; nil uses the system
+	// resolver
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// resolveTimeout bounds the lookup of a target name
+const resolveTimeout = 5 * time.Second
+
+// internalNameSuffixes are domains that only resolve inside a network
+var internalNameSuffixes = []string{".localhost", ".local", ".internal", ".home.arpa"}
+
+// hostnameLabelPattern matches one label of a DNS name
+var hostnameLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net.IP.IsPrivate does not cover
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isInternalIP reports whether ip is not routable on the public internet
+func isInternalIP(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// checkHost validates a bare host, a DNS name or an IP address without
+// brackets, and returns it lowercased without a trailing dot. Unless private
+// addresses are allowed, single-label names such as compose service names
+// and internal-only domains are rejected, and every address a name resolves
+// to must be public.
+func (p AddressPolicy) checkHost(host string) (string, error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !p.AllowPrivate && isInternalIP(ip) {
+			return "", errors.New("is a private, loopback or link-local address")
+		}
+		return host, nil
+	}
+
+	invalid := errors.New("must be a valid DNS name or IP address")
+	if host == "" || len(host) > 253 {
+		return "", invalid
+	}
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		if !hostnameLabelPattern.MatchString(label) {
+			return "", invalid
+		}
+	}
+	// An all-numeric top-level label is a malformed IP address, not a name
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return "", invalid
+	}
+
+	if p.AllowPrivate {
+		return host, nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", errors.New("is a loopback name")
+	}
+	if len(labels) == 1 {
+		return "", errors.New("must be a fully qualified domain name")
+	}
+	for _, suffix := range internalNameSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return "", fmt.Errorf("is an internal name (%s)", strings.TrimPrefix(suffix, "."))
+		}
+	}
+	if err := p.checkResolved(host); err != nil {
+		return "", err
+	}
+
+	return host, nil
+}
+
+// checkResolved looks host up and rejects it when any of its addresses is
+// internal
+func (p AddressPolicy) checkResolved(host string) error {
+	lookup := p.lookup
+	if lookup == nil {
+		lookup = net.DefaultResolver.LookupIPAddr
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	addrs, err := lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return errors.New("could not be resolved")
+	}
+	for _, addr := range addrs {
+		if isInternalIP(addr.IP) {
+			return errors.New("resolves to a private, loopback or link-local address")
+		}
+	}
+	return nil
+}
+
+// errBlockedAddress is returned when a connection would reach an address
+// the policy does not allow
+var errBlockedAddress = errors.New("connection to a private, loopback or link-local address blocked")
+
+// maxRedirects is how many redirects the policy's HTTP client follows
+const maxRedirects = 5
+
+// dialControl rejects connections to internal addresses. It runs after name
+// resolution, so it also covers names that resolve to internal addresses and
+// redirects to them.
+func (p AddressPolicy) dialControl(network, address string, _ syscall.RawConn) error {
+	if p.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return errBlockedAddress
+	}
+	return nil
+}
+
+// dialer returns a dialer for outbound connections made on behalf of a scan
+func (p AddressPolicy) dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: p.dialControl}
+}
+
+// httpClient returns a client for outbound requests made on behalf of a
+// scan. It ignores proxy settings, dials through the policy's dialer and
+// checks every redirect target against the policy before following it.
+func (p AddressPolicy) httpClient(timeout time.Duration) *http.Client {
+	dialer := p.dialer(timeout)
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if _, err := p.normalizeURL(req.URL.String()); err != nil {
+				return fmt.Errorf("redirect blocked: %v", err)
+			}
+			return nil
+		},
+	}
+}
+
+// normalizeHostname validates a target hostname, which is a bare DNS name
+// or IP address without scheme, port or path
+func (p AddressPolicy) normalizeHostname(raw string) (string, error) {
+	hostname := strings.TrimSpace(raw)
+	if strings.HasPrefix(hostname, "[") && strings.HasSuffix(hostname, "]") {
+		hostname = hostname[1 : len(hostname)-1]
+	}
+	if strings.ContainsAny(hostname, "/?#@ \t\r\n") || (strings.Contains(hostname, ":") && net.ParseIP(hostname) == nil) {
+		return "", errors.New("must be a hostname or IP address without scheme, port or path")
+	}
+
+	return p.checkHost(hostname)
+}
+
+// normalizeURL validates a quick scan address, a bare hostname/IP or an
+// http(s) URL with a host, and returns it as a URL with a scheme. Bare
+// hosts default to https.
+func (p AddressPolicy) normalizeURL(raw string) (string, error) {
+	if raw == "" {
+		return "", errors.New("url must not be empty")
+	}
+	if len(raw) > 2048 {
+		return "", errors.New("url must be at most 2048 characters")
+	}
+	if strings.ContainsAny(raw, " \t\r\n") {
+		return "", errors.New("url must not contain whitespace")
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %v", err)
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", errors.New("url scheme must be http or https")
+	}
+	if parsed.Hostname() == "" {
+		return "", errors.New("url must include a host")
+	}
+	if parsed.User != nil {
+		return "", errors.New("url must not include credentials")
+	}
+
+	host, err := p.checkHost(parsed.Hostname())
+	if err != nil {
+		return "", fmt.Errorf("url host %v", err)
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := parsed.Port(); port != "" {
+		host += ":" + port
+	}
+	parsed.Host = host
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// fakeResolver answers lookups from a fixed table
+func fakeResolver(records map[string][]string) func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		ips, ok := records[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		addrs := make([]net.IPAddr, len(ips))
+		for i, ip := range ips {
+			addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+		}
+		return addrs, nil
+	}
+}
+
+func TestAddressPolicyCheckHost(t *testing.T) {
+	policy := AddressPolicy{lookup: fakeResolver(map[string][]string{
+		"example.com":          {"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"},
+		"rebind.example.com":   {"93.184.216.34", "10.0.0.5"},
+		"metadata.example.com": {"169.254.169.254"},
+		"v6.example.com":       {"::1"},
+	})}
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{"example.com", true},
+		{"Example.COM.", true},
+		{"93.184.216.34", true},
+		{"redis", false},
+		{"postgres", false},
+		{"api", false},
+		{"localhost", false},
+		{"db.internal", false},
+		{"printer.local", false},
+		{"router.home.arpa", false},
+		{"rebind.example.com", false},
+		{"metadata.example.com", false},
+		{"v6.example.com", false},
+		{"missing.example.com", false},
+		{"10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		_, err := policy.checkHost(tt.host)
+		if (err == nil) != tt.allowed {
+			t.Errorf("checkHost(%q) error = %v, want allowed = %v", tt.host, err, tt.allowed)
+		}
+	}
+}
+
+func TestAddressPolicyNormalizeURLRejectsServiceNames(t *testing.T) {
+	policy := AddressPolicy{lookup: fakeResolver(nil)}
+
+	for _, raw := range []string{"http://redis:6379", "postgres:5432", "api", "https://api.internal/health"} {
+		if _, err := policy.normalizeURL(raw); err == nil {
+			t.Errorf("normalizeURL(%q) was accepted", raw)
+		}
+	}
+}
+
+func TestAddressPolicyAllowPrivateSkipsResolution(t *testing.T) {
+	policy := AddressPolicy{
+		AllowPrivate: true,
+		lookup: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			t.Fatalf("resolved %q although private addresses are allowed", host)
+			return nil, nil
+		},
+	}
+
+	for _, host := range []string{"redis", "db.internal", "10.0.0.1", "localhost"} {
+		if _, err := policy.checkHost(host); err != nil {
+			t.Errorf("checkHost(%q) = %v with AllowPrivate", host, err)
+		}
+	}
+}
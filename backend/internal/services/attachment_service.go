@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/storage"
+)
+
+var (
+	ErrResultNotFound        = errors.New("scan result not found")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment exceeds size limit")
+	ErrAttachmentTypeBlocked = errors.New("attachment type not allowed")
+)
+
+// allowedAttachmentTypes are the sniffed content types accepted as evidence.
+// Markup types are deliberately excluded so attachments can never be served
+// as active content.
+var allowedAttachmentTypes = map[string]bool{
+	"image/png":                 true,
+	"image/jpeg":                true,
+	"image/gif":                 true,
+	"application/pdf":           true,
+	"application/zip":           true,
+	"application/x-gzip":        true,
+	"application/octet-stream":  true,
+	"text/plain; charset=utf-8": true,
+}
+
+// AttachmentService handles scan result attachments
+type AttachmentService struct {
+	attachmentRepo *repository.AttachmentRepository
+	scanRepo       *repository.ScanRepository
+	storage        storage.Storage
+	maxSize        int64
+}
+
+// NewAttachmentService creates a new attachment service
+func NewAttachmentService(attachmentRepo *repository.AttachmentRepository, scanRepo *repository.ScanRepository, store storage.Storage, maxSize int64) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		scanRepo:       scanRepo,
+		storage:        store,
+		maxSize:        maxSize,
+	}
+}
+
+// getResult loads a result, verifying it belongs to the scan and the scan to the organization
+func (s *AttachmentService) getResult(scanID, resultID, organizationID uuid.UUID) (*models.ScanResult, error) {
+	return getOrganizationResult(s.scanRepo, scanID, resultID, organizationID)
+}
+
+// getOrganizationResult loads a result, verifying it belongs to the scan and
+// the scan to the organization
+func getOrganizationResult(scanRepo *repository.ScanRepository, scanID, resultID, organizationID uuid.UUID) (*models.ScanResult, error) {
+	scan, err := scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+	if scan.OrganizationID != organizationID {
+		return nil, ErrScanNotFound
+	}
+
+	result, err := scanRepo.GetResultByID(resultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrResultNotFound) {
+			return nil, ErrResultNotFound
+		}
+		return nil, err
+	}
+	if result.ScanID != scanID {
+		return nil, ErrResultNotFound
+	}
+
+	return result, nil
+}
+
+// Upload stores an attachment for a scan result
+func (s *AttachmentService) Upload(scanID, resultID, organizationID, userID uuid.UUID, fileName string, r io.Reader) (*models.ScanResultAttachment, error) {
+	if _, err := s.getResult(scanID, resultID, organizationID); err != nil {
+		return nil, err
+	}
+
+	// Sniff the content type from the first bytes
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if !allowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentTypeBlocked, contentType)
+	}
+
+	attachment := &models.ScanResultAttachment{
+		ID:          uuid.New(),
+		ResultID:    resultID,
+		ScanID:      scanID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		UploadedBy:  userID,
+	}
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s", scanID, attachment.ID)
+
+	size, err := s.storage.Save(attachment.StorageKey, buffered, s.maxSize)
+	if err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return nil, ErrAttachmentTooLarge
+		}
+		return nil, err
+	}
+	attachment.FileSize = size
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		// Clean up file if database insert fails
+		_ = s.storage.Delete(attachment.StorageKey)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// List retrieves the attachments of a scan result
+func (s *AttachmentService) List(scanID, resultID, organizationID uuid.UUID) ([]*models.ScanResultAttachment, error) {
+	if _, err := s.getResult(scanID, resultID, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentRepo.ListByResult(resultID)
+}
+
+// Open returns an attachment and its stored content for download
+func (s *AttachmentService) Open(scanID, resultID, attachmentID, organizationID uuid.UUID) (*models.ScanResultAttachment, storage.Object, error) {
+	if _, err := s.getResult(scanID, resultID, organizationID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetByID(attachmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	if attachment.ResultID != resultID {
+		return nil, nil, ErrAttachmentNotFound
+	}
+
+	object, err := s.storage.Open(attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	return attachment, object, nil
+}
+
+// sanitizeFileName strips directories and header-breaking characters from an uploaded name
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,25 +13,66 @@ import (
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserInactive       = errors.New("user account is inactive")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrUserInactive        = errors.New("user account is inactive")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrLogoutUnsupported   = errors.New("only access tokens from a login can be logged out")
+	ErrSwitchUnsupported   = errors.New("only access tokens from a login can switch organization")
+	ErrPasswordUnsupported = errors.New("service accounts have no password")
 )
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo   *repository.UserRepository
-	jwtSecret  string
-	accessTTL  time.Duration
-	refreshTTL time.Duration
+	userRepo    *repository.UserRepository
+	refreshRepo *repository.RefreshTokenRepository
+	sessions    *SessionTracker
+	denylist    *TokenDenylist
+	lockout     *LoginLockout
+	verifier    *EmailVerificationService
+	jwtSecret   string
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret string, accessTTL, refreshTTL time.Duration) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, refreshRepo *repository.RefreshTokenRepository, sessions *SessionTracker, denylist *TokenDenylist, lockout *LoginLockout, verifier *EmailVerificationService, jwtSecret string, accessTTL, refreshTTL time.Duration) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtSecret:  jwtSecret,
-		accessTTL:  accessTTL,
-		refreshTTL: refreshTTL,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		sessions:    sessions,
+		denylist:    denylist,
+		lockout:     lockout,
+		verifier:    verifier,
+		jwtSecret:   jwtSecret,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// issueTokens starts a new session for the user and records its first
+// refresh token
+func (s *AuthService) issueTokens(user *models.User, organizationID *uuid.UUID) (*auth.TokenPair, error) {
+	sessionID := uuid.NewString()
+	tokens, err := auth.GenerateSessionTokenPair(sessionID, user.ID, user.Email, organizationID, s.jwtSecret, s.accessTTL, s.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshRepo.Create(refreshTokenRecord(tokens, user.ID, sessionID)); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// refreshTokenRecord describes the refresh token of tokens for storage
+func refreshTokenRecord(tokens *auth.TokenPair, userID uuid.UUID, sessionID string) *models.RefreshToken {
+	return &models.RefreshToken{
+		ID:        tokens.RefreshTokenID,
+		UserID:    userID,
+		SessionID: sessionID,
+		ExpiresAt: tokens.RefreshExpiresAt,
 	}
 }
 
@@ -47,13 +90,16 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// AuthResponse represents authentication response
+// AuthResponse represents authentication response. Tokens is omitted when
+// a new user must verify their email before logging in.
 type AuthResponse struct {
-	User   *models.User      `json:"user"`
-	Tokens *auth.TokenPair   `json:"tokens"`
+	User   *models.User    `json:"user"`
+	Tokens *auth.TokenPair `json:"tokens,omitempty"`
 }
 
-// Register registers a new user
+// Register registers a new user with an unverified email and sends them a
+// verification link. When verification is required no tokens are issued;
+// the user logs in once verified.
 func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 	// Hash password
 	hashedPassword, err := auth.HashPassword(req.Password)
@@ -77,40 +123,71 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 		return nil, err
 	}
 
-	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, nil, s.jwtSecret, s.accessTTL, s.refreshTTL)
-	if err != nil {
-		return nil, err
+	// The account exists either way; a missing link can be resent
+	if err := s.verifier.SendVerification(user); err != nil {
+		slog.Error("Failed to issue verification token", "user_id", user.ID, "error", err)
 	}
 
 	// Clear password hash from response
 	user.PasswordHash = ""
 
+	if s.verifier.Required() {
+		return &AuthResponse{User: user}, nil
+	}
+
+	// Generate tokens
+	tokens, err := s.issueTokens(user, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AuthResponse{
 		User:   user,
 		Tokens: tokens,
 	}, nil
 }
 
-// Login authenticates a user
-func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
-	// Find user by email
+// Login authenticates a user. Consecutive failures lock the email for a
+// cooldown (see LoginLockout) whether or not it is registered; while locked,
+// logins fail with ErrAccountLocked even with the right password.
+func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error) {
+	// Find user by email. Every path below runs one bcrypt comparison, so the
+	// response time does not reveal whether the email is registered.
 	user, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserNotFound) {
-			return nil, ErrInvalidCredentials
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			return nil, err
 		}
+		user = nil
+	}
+
+	if s.lockout.IsLocked(ctx, user, req.Email, time.Now()) {
+		auth.CheckDummyPassword(req.Password)
+		return nil, ErrAccountLocked
+	}
+
+	// Service accounts have no password and cannot log in
+	if user == nil || user.IsServiceAccount {
+		auth.CheckDummyPassword(req.Password)
+		return nil, s.loginFailed(ctx, user, req.Email)
+	}
+
+	// Verify password
+	if !auth.CheckPassword(user.PasswordHash, req.Password) {
+		return nil, s.loginFailed(ctx, user, req.Email)
+	}
+
+	if err := s.lockout.Reset(user); err != nil {
 		return nil, err
 	}
 
-	// Check if user is active
+	// Check if user is active, only once the password proved who is asking
 	if !user.IsActive {
 		return nil, ErrUserInactive
 	}
 
-	// Verify password
-	if !auth.CheckPassword(user.PasswordHash, req.Password) {
-		return nil, ErrInvalidCredentials
+	if err := s.verifier.Check(user); err != nil {
+		return nil, err
 	}
 
 	// Get user's default organization (first one they're a member of)
@@ -121,7 +198,7 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 	}
 
 	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, organizationID, s.jwtSecret, s.accessTTL, s.refreshTTL)
+	tokens, err := s.issueTokens(user, organizationID)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +212,22 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 	}, nil
 }
 
-// RefreshToken refreshes an access token
+// loginFailed records a failed login and returns the error to report
+func (s *AuthService) loginFailed(ctx context.Context, user *models.User, email string) error {
+	locked, err := s.lockout.RecordFailure(ctx, user, email)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrAccountLocked
+	}
+	return ErrInvalidCredentials
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. The
+// presented token is rotated: it is revoked and the new refresh token takes
+// its place. Presenting a revoked token again means it leaked, so the whole
+// session is revoked and ErrRefreshTokenReused is returned.
 func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error) {
 	// Validate refresh token
 	claims, err := auth.ValidateToken(refreshToken, s.jwtSecret)
@@ -143,6 +235,12 @@ func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error)
 		return nil, err
 	}
 
+	// Access tokens and refresh tokens issued before rotation are not accepted
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil || claims.TokenType != auth.TokenTypeRefresh || claims.SessionID == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
 	// Get user to verify they still exist and are active
 	user, err := s.userRepo.GetByID(claims.UserID)
 	if err != nil {
@@ -153,15 +251,149 @@ func (s *AuthService) RefreshToken(refreshToken string) (*auth.TokenPair, error)
 		return nil, ErrUserInactive
 	}
 
-	// Generate new token pair
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, claims.OrganizationID, s.jwtSecret, s.accessTTL, s.refreshTTL)
+	if err := s.verifier.Check(user); err != nil {
+		return nil, err
+	}
+
+	// An idle session must not be revived by refreshing it
+	if err := s.sessions.Touch(context.Background(), claims); err != nil {
+		return nil, err
+	}
+
+	// Generate new token pair, continuing the session
+	tokens, err := auth.GenerateSessionTokenPair(claims.SessionID, user.ID, user.Email, claims.OrganizationID, s.jwtSecret, s.accessTTL, s.refreshTTL)
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.refreshRepo.Rotate(tokenID, user.ID, refreshTokenRecord(tokens, user.ID, claims.SessionID))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			if _, revokeErr := s.refreshRepo.RevokeSession(user.ID, claims.SessionID); revokeErr != nil {
+				slog.Error("Failed to revoke session after refresh token reuse", "user_id", user.ID, "session_id", claims.SessionID, "error", revokeErr)
+			}
+			slog.Warn("Refresh token reuse detected, session revoked", "user_id", user.ID, "session_id", claims.SessionID)
+			return nil, ErrRefreshTokenReused
+		case errors.Is(err, repository.ErrRefreshTokenNotFound):
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
 	return tokens, nil
 }
 
+// Logout revokes the access token of claims until it expires, together with
+// every refresh token of its login. API keys are revoked through their
+// service account instead.
+func (s *AuthService) Logout(ctx context.Context, claims *auth.TokenClaims) error {
+	if claims.ServiceAccount || claims.ID == "" || claims.ExpiresAt == nil {
+		return ErrLogoutUnsupported
+	}
+
+	if err := s.denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if claims.SessionID != "" {
+		if _, err := s.refreshRepo.RevokeSession(claims.UserID, claims.SessionID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ChangePasswordRequest represents a password change by a logged-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangePassword replaces the caller's password after checking the current
+// one. A wrong current password counts as a failed login, so it cannot be
+// guessed with a stolen access token faster than at the login form. Every
+// other session of the user is logged out; the caller's stays.
+func (s *AuthService) ChangePassword(ctx context.Context, claims *auth.TokenClaims, req *ChangePasswordRequest) error {
+	if claims.ServiceAccount {
+		return ErrPasswordUnsupported
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if user.IsServiceAccount {
+		return ErrPasswordUnsupported
+	}
+
+	if s.lockout.IsLocked(ctx, user, user.Email, time.Now()) {
+		return ErrAccountLocked
+	}
+	if !auth.CheckPassword(user.PasswordHash, req.CurrentPassword) {
+		return s.loginFailed(ctx, user, user.Email)
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(user.ID, hashedPassword, claims.SessionID); err != nil {
+		return err
+	}
+
+	slog.Info("Password changed", "user_id", user.ID)
+	return nil
+}
+
+// SwitchOrganizationRequest names the organization to scope new tokens to
+type SwitchOrganizationRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id" binding:"required"`
+}
+
+// SwitchOrganization starts a session scoped to another organization the
+// user belongs to and ends the current one, as on logout. API keys are
+// bound to their service account's organization and cannot switch.
+func (s *AuthService) SwitchOrganization(ctx context.Context, claims *auth.TokenClaims, organizationID uuid.UUID) (*AuthResponse, error) {
+	if claims.ServiceAccount || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, ErrSwitchUnsupported
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	if _, err := s.userRepo.GetUserRole(user.ID, organizationID); err != nil {
+		if errors.Is(err, repository.ErrNotMember) {
+			return nil, ErrNotOrganizationMember
+		}
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, &organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new tokens are already valid; failing to end the old session
+	// only leaves it to expire on its own
+	if err := s.Logout(ctx, claims); err != nil {
+		slog.Error("Failed to end session after organization switch", "user_id", user.ID, "session_id", claims.SessionID, "error", err)
+	}
+
+	user.PasswordHash = ""
+	return &AuthResponse{
+		User:   user,
+		Tokens: tokens,
+	}, nil
+}
+
 // GetCurrentUser retrieves the current authenticated user
 func (s *AuthService) GetCurrentUser(userID uuid.UUID) (*models.User, error) {
 	user, err := s.userRepo.GetByID(userID)
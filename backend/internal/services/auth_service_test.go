@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var userColumns = []string{
+	"id", "email", "password_hash", "first_name", "last_name", "is_active", "is_superadmin", "is_service_account",
+	"failed_login_attempts", "locked_until", "email_verified", "created_at", "updated_at",
+}
+
+// newTestAuthService returns an auth service whose repositories use mock
+// and whose lockout follows policy, counting unknown emails in memory
+func newTestAuthService(t *testing.T, policy LockoutPolicy) (*AuthService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	userRepo := repository.NewUserRepository(db)
+	rdb := newTestRedis(t)
+	service := NewAuthService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		NewSessionTracker(rdb, repository.NewOrganizationRepository(db)),
+		NewTokenDenylist(rdb),
+		NewLoginLockout(userRepo, rdb, policy),
+		NewEmailVerificationService(userRepo, nil, nil, "", time.Hour, false),
+		"jwt-secret",
+		15*time.Minute,
+		24*time.Hour,
+	)
+	return service, mock
+}
+
+// userRows returns the users table rows of users
+func userRows(users ...*models.User) *sqlmock.Rows {
+	rows := sqlmock.NewRows(userColumns)
+	for _, user := range users {
+		rows.AddRow(user.ID.String(), user.Email, user.PasswordHash, "Ada", "Lovelace", user.IsActive, false, false,
+			user.FailedLoginAttempts, user.LockedUntil, user.EmailVerified, time.Now(), time.Now())
+	}
+	return rows
+}
+
+// expectUser answers the next lookup by email with user, or with no row
+// when user is nil
+func expectUser(mock sqlmock.Sqlmock, email string, user *models.User) {
+	rows := userRows()
+	if user != nil {
+		rows = userRows(user)
+	}
+	mock.ExpectQuery(`FROM users\s+WHERE email = \$1`).WithArgs(email).WillReturnRows(rows)
+}
+
+func TestLoginTakesAsLongForUnknownEmails(t *testing.T) {
+	service, mock := newTestAuthService(t, LockoutPolicy{})
+
+	hash, err := auth.HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: hash, IsActive: true}
+
+	// The fastest of a few attempts filters out scheduling noise
+	fastest := func(email string, known bool) time.Duration {
+		best := time.Duration(1<<63 - 1)
+		for i := 0; i < 3; i++ {
+			if known {
+				expectUser(mock, email, user)
+			} else {
+				expectUser(mock, email, nil)
+			}
+			start := time.Now()
+			_, err := service.Login(context.Background(), &LoginRequest{Email: email, Password: "wrong password"})
+			elapsed := time.Since(start)
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Fatalf("Login(%s) error = %v, want ErrInvalidCredentials", email, err)
+			}
+			if elapsed < best {
+				best = elapsed
+			}
+		}
+		return best
+	}
+
+	auth.CheckDummyPassword("warm up the dummy hash")
+	known := fastest(user.Email, true)
+	unknown := fastest("nobody@example.com", false)
+
+	if unknown < known/2 || unknown > known*2 {
+		t.Errorf("login took %v for an unknown email and %v for a known one", unknown, known)
+	}
+}
+
+// expectRotation expects the refresh token tokenID to be rotated, failing
+// as a reuse when revoked is true
+func expectRotation(mock sqlmock.Sqlmock, tokenID uuid.UUID, user *models.User, revoked bool) {
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID).WillReturnRows(userRows(user))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	if !revoked {
+		mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\), replaced_by`).
+			WithArgs(tokenID, user.ID, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		return
+	}
+	mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\), replaced_by`).
+		WithArgs(tokenID, user.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT revoked_at IS NOT NULL FROM refresh_tokens`).WithArgs(tokenID, user.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"revoked"}).AddRow(true))
+	mock.ExpectRollback()
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	service, mock := newTestAuthService(t, LockoutPolicy{})
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: true}
+
+	sessionID := uuid.NewString()
+	tokens, err := auth.GenerateSessionTokenPair(sessionID, user.ID, user.Email, nil, "jwt-secret", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := auth.ValidateToken(tokens.RefreshToken, "jwt-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenID := uuid.MustParse(claims.ID)
+
+	// The first use rotates the token
+	expectRotation(mock, tokenID, user, false)
+	if _, err := service.RefreshToken(tokens.RefreshToken); err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+
+	// Presenting it again ends the whole session
+	expectRotation(mock, tokenID, user, true)
+	mock.ExpectExec(`UPDATE refresh_tokens\s+SET revoked_at = NOW\(\)\s+WHERE user_id = \$1 AND session_id = \$2`).
+		WithArgs(user.ID, sessionID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := service.RefreshToken(tokens.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("second refresh error = %v, want ErrRefreshTokenReused", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lockoutPolicy locks an account on the third consecutive failure
+var lockoutPolicy = LockoutPolicy{MaxAttempts: 3, Cooldown: 15 * time.Minute}
+
+// lockoutAttempts are the passwords tried against a locking account, with
+// the error each must fail with: the third failure locks the account, after
+// which even the right password is refused
+var lockoutAttempts = []struct {
+	password string
+	err      error
+}{
+	{"wrong password", ErrInvalidCredentials},
+	{"wrong password", ErrInvalidCredentials},
+	{"wrong password", ErrAccountLocked},
+	{"correct horse battery staple", ErrAccountLocked},
+}
+
+func TestLoginLocksAccount(t *testing.T) {
+	service, mock := newTestAuthService(t, lockoutPolicy)
+
+	hash, err := auth.HashPassword("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// stored mirrors the user's row as the lockout updates it
+	stored := models.User{ID: uuid.New(), Email: "ada@example.com", PasswordHash: hash, IsActive: true}
+	// expectLogin expects the user to be looked up as currently stored
+	expectLogin := func() {
+		user := stored
+		expectUser(mock, user.Email, &user)
+	}
+	login := func(password string) error {
+		_, err := service.Login(context.Background(), &LoginRequest{Email: stored.Email, Password: password})
+		return err
+	}
+
+	for i, attempt := range lockoutAttempts {
+		expectLogin()
+		if attempt.password == "wrong password" {
+			stored.FailedLoginAttempts++
+			row := sqlmock.NewRows([]string{"locked", "locked_until"}).AddRow(false, nil)
+			if stored.FailedLoginAttempts == lockoutPolicy.MaxAttempts {
+				lockedUntil := time.Now().Add(lockoutPolicy.Cooldown)
+				stored.FailedLoginAttempts, stored.LockedUntil = 0, &lockedUntil
+				row = sqlmock.NewRows([]string{"locked", "locked_until"}).AddRow(true, lockedUntil)
+			}
+			mock.ExpectQuery(`UPDATE users\s+SET failed_login_attempts`).
+				WithArgs(stored.ID, lockoutPolicy.MaxAttempts, sqlmock.AnyArg()).
+				WillReturnRows(row)
+		}
+		if err := login(attempt.password); !errors.Is(err, attempt.err) {
+			t.Fatalf("attempt %d: error = %v, want %v", i+1, err, attempt.err)
+		}
+	}
+
+	// Once the cooldown is over the right password logs in and clears the
+	// lock
+	expired := time.Now().Add(-time.Second)
+	stored.LockedUntil = &expired
+	expectLogin()
+	mock.ExpectExec(`UPDATE users\s+SET failed_login_attempts = 0, locked_until = NULL`).WithArgs(stored.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT organization_id\s+FROM organization_members`).WithArgs(stored.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id"}))
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	if err := login("correct horse battery staple"); err != nil {
+		t.Fatalf("login after the cooldown: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginLocksUnknownEmail(t *testing.T) {
+	service, mock := newTestAuthService(t, lockoutPolicy)
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	service.lockout = NewLoginLockout(service.userRepo, rdb, lockoutPolicy)
+
+	login := func(password string) error {
+		expectUser(mock, "nobody@example.com", nil)
+		_, err := service.Login(context.Background(), &LoginRequest{Email: "nobody@example.com", Password: password})
+		return err
+	}
+
+	// The same answers as for a registered account
+	for i, attempt := range lockoutAttempts {
+		if err := login(attempt.password); !errors.Is(err, attempt.err) {
+			t.Fatalf("attempt %d: error = %v, want %v", i+1, err, attempt.err)
+		}
+	}
+
+	// After the cooldown failures count from zero again
+	server.FastForward(lockoutPolicy.Cooldown + time.Second)
+	for i := 0; i < lockoutPolicy.MaxAttempts-1; i++ {
+		if err := login("wrong password"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d after the cooldown: error = %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/storage"
+)
+
+var (
+	ErrLogoNotFound    = errors.New("organization has no custom logo")
+	ErrLogoTooLarge    = errors.New("logo exceeds size limit")
+	ErrLogoTypeBlocked = errors.New("logo type not allowed")
+)
+
+// MaxBrandingLogoSize is the largest logo an organization can upload
+const MaxBrandingLogoSize = 1 << 20
+
+const (
+	maxBrandingCompanyNameLength = 100
+	maxBrandingFooterTextLength  = 500
+)
+
+// allowedLogoTypes are the sniffed content types accepted as report logos.
+// Vector formats are excluded because SVG can carry script.
+var allowedLogoTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+}
+
+var brandingColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// BrandingService handles the report branding of organizations
+type BrandingService struct {
+	orgService *OrganizationService
+	orgRepo    *repository.OrganizationRepository
+	storage    storage.Storage
+}
+
+// NewBrandingService creates a new branding service
+func NewBrandingService(orgService *OrganizationService, orgRepo *repository.OrganizationRepository, store storage.Storage) *BrandingService {
+	return &BrandingService{
+		orgService: orgService,
+		orgRepo:    orgRepo,
+		storage:    store,
+	}
+}
+
+// GetBranding returns the organization's stored report branding
+func (s *BrandingService) GetBranding(organizationID, userID uuid.UUID) (*models.ReportBranding, error) {
+	if err := s.orgService.requireMember(organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.orgRepo.GetReportBranding(organizationID)
+}
+
+// UpdateBrandingRequest represents a partial branding update. Omitted fields
+// keep their current value; an empty string resets a field to the product
+// default.
+type UpdateBrandingRequest struct {
+	CompanyName  *string `json:"company_name"`
+	PrimaryColor *string `json:"primary_color"`
+	FooterText   *string `json:"footer_text"`
+}
+
+// UpdateBranding changes the organization's report texts and colors
+func (s *BrandingService) UpdateBranding(organizationID, userID uuid.UUID, req *UpdateBrandingRequest) (*models.ReportBranding, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var problems ValidationErrors
+	companyName := normalizeBrandingText(req.CompanyName)
+	if companyName != nil && utf8.RuneCountInString(*companyName) > maxBrandingCompanyNameLength {
+		problems.add("company_name", "must be at most %d characters", maxBrandingCompanyNameLength)
+	}
+	footerText := normalizeBrandingText(req.FooterText)
+	if footerText != nil && utf8.RuneCountInString(*footerText) > maxBrandingFooterTextLength {
+		problems.add("footer_text", "must be at most %d characters", maxBrandingFooterTextLength)
+	}
+	primaryColor := normalizeBrandingText(req.PrimaryColor)
+	if primaryColor != nil && !brandingColorPattern.MatchString(*primaryColor) {
+		problems.add("primary_color", "must be a hex color like #1f6feb")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	branding, err := s.orgRepo.GetReportBranding(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CompanyName != nil {
+		branding.CompanyName = companyName
+	}
+	if req.PrimaryColor != nil {
+		if primaryColor != nil {
+			lower := strings.ToLower(*primaryColor)
+			primaryColor = &lower
+		}
+		branding.PrimaryColor = primaryColor
+	}
+	if req.FooterText != nil {
+		branding.FooterText = footerText
+	}
+	branding.UpdatedBy = &userID
+
+	if err := s.orgRepo.UpsertReportBranding(branding); err != nil {
+		return nil, err
+	}
+
+	return branding, nil
+}
+
+// normalizeBrandingText trims a submitted value, mapping blank values to nil
+// so they fall back to the product default
+func normalizeBrandingText(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// UploadLogo stores a new report logo for the organization, replacing the
+// previous one
+func (s *BrandingService) UploadLogo(organizationID, userID uuid.UUID, r io.Reader) (*models.ReportBranding, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	// Sniff the content type from the first bytes
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if !allowedLogoTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrLogoTypeBlocked, contentType)
+	}
+
+	branding, err := s.orgRepo.GetReportBranding(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A fresh key per upload keeps the current logo intact until the new
+	// one is recorded
+	key := fmt.Sprintf("branding/%s/logo-%s", organizationID, uuid.New())
+	if _, err := s.storage.Save(key, buffered, MaxBrandingLogoSize); err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return nil, ErrLogoTooLarge
+		}
+		return nil, err
+	}
+
+	previousKey := branding.LogoStorageKey
+	branding.LogoStorageKey = &key
+	branding.LogoContentType = &contentType
+	branding.UpdatedBy = &userID
+
+	if err := s.orgRepo.UpsertReportBranding(branding); err != nil {
+		// Clean up file if database update fails
+		_ = s.storage.Delete(key)
+		return nil, err
+	}
+
+	if previousKey != nil {
+		if err := s.storage.Delete(*previousKey); err != nil {
+			slog.Error("Failed to delete replaced logo", "storage_key", *previousKey, "error", err)
+		}
+	}
+
+	return branding, nil
+}
+
+// DeleteLogo removes the organization's report logo, restoring the product logo
+func (s *BrandingService) DeleteLogo(organizationID, userID uuid.UUID) (*models.ReportBranding, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	branding, err := s.orgRepo.GetReportBranding(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if branding.LogoStorageKey == nil {
+		return nil, ErrLogoNotFound
+	}
+
+	previousKey := *branding.LogoStorageKey
+	branding.LogoStorageKey = nil
+	branding.LogoContentType = nil
+	branding.UpdatedBy = &userID
+
+	if err := s.orgRepo.UpsertReportBranding(branding); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Delete(previousKey); err != nil {
+		slog.Error("Failed to delete removed logo", "storage_key", previousKey, "error", err)
+	}
+
+	return branding, nil
+}
+
+// OpenLogo returns the organization's stored logo and its content type
+func (s *BrandingService) OpenLogo(organizationID, userID uuid.UUID) (storage.Object, string, error) {
+	if err := s.orgService.requireMember(organizationID, userID); err != nil {
+		return nil, "", err
+	}
+
+	branding, err := s.orgRepo.GetReportBranding(organizationID)
+	if err != nil {
+		return nil, "", err
+	}
+	if branding.LogoStorageKey == nil || branding.LogoContentType == nil {
+		return nil, "", ErrLogoNotFound
+	}
+
+	object, err := s.storage.Open(*branding.LogoStorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", ErrLogoNotFound
+		}
+		return nil, "", err
+	}
+
+	return object, *branding.LogoContentType, nil
+}
+
+// ReportBranding resolves the branding a report of the organization is
+// rendered with, loading the custom logo when there is one. A logo that can
+// no longer be read falls back to the product logo rather than failing the
+// report.
+func (s *BrandingService) ReportBranding(organizationID uuid.UUID) (*models.EffectiveBranding, error) {
+	branding, err := s.orgRepo.GetReportBranding(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := branding.Effective()
+	if branding.LogoStorageKey == nil || branding.LogoContentType == nil {
+		return effective, nil
+	}
+
+	object, err := s.storage.Open(*branding.LogoStorageKey)
+	if err != nil {
+		slog.Error("Failed to open organization logo", "organization_id", organizationID, "error", err)
+		return effective, nil
+	}
+	defer object.Close()
+
+	logo, err := io.ReadAll(io.LimitReader(object, MaxBrandingLogoSize))
+	if err != nil {
+		slog.Error("Failed to read organization logo", "organization_id", organizationID, "error", err)
+		return effective, nil
+	}
+
+	effective.Logo = logo
+	effective.LogoContentType = *branding.LogoContentType
+	return effective, nil
+}
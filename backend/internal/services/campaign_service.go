@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrCampaignNotFound  = errors.New("campaign not found")
+	ErrCampaignNameTaken = errors.New("campaign name already in use")
+	ErrInvalidCampaign   = errors.New("invalid campaign")
+)
+
+// CampaignService manages campaigns, the named engagements scans are grouped by
+type CampaignService struct {
+	campaignRepo *repository.CampaignRepository
+	scanService  *ScanService
+}
+
+// NewCampaignService creates a new campaign service
+func NewCampaignService(campaignRepo *repository.CampaignRepository, scanService *ScanService) *CampaignService {
+	return &CampaignService{
+		campaignRepo: campaignRepo,
+		scanService:  scanService,
+	}
+}
+
+// CreateCampaignRequest represents a new campaign
+type CreateCampaignRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// CreateCampaign creates a campaign; names are unique within an organization
+func (s *CampaignService) CreateCampaign(organizationID, userID uuid.UUID, req *CreateCampaignRequest) (*models.Campaign, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, fmt.Errorf("%w: name must be 1 to 100 characters", ErrInvalidCampaign)
+	}
+	if len(req.Description) > 2000 {
+		return nil, fmt.Errorf("%w: description must be at most 2000 characters", ErrInvalidCampaign)
+	}
+
+	campaign := &models.Campaign{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Name:           name,
+		Description:    req.Description,
+		CreatedBy:      &userID,
+	}
+	if err := s.campaignRepo.Create(campaign); err != nil {
+		if errors.Is(err, repository.ErrCampaignNameTaken) {
+			return nil, ErrCampaignNameTaken
+		}
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns retrieves an organization's campaigns
+func (s *CampaignService) ListCampaigns(organizationID uuid.UUID) ([]*models.Campaign, error) {
+	return s.campaignRepo.ListByOrganization(organizationID)
+}
+
+// GetCampaign retrieves a campaign of the organization
+func (s *CampaignService) GetCampaign(campaignID, organizationID uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.GetByID(campaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	// Verify organization access
+	if campaign.OrganizationID != organizationID {
+		return nil, ErrCampaignNotFound
+	}
+
+	return campaign, nil
+}
+
+// GetSummary retrieves a campaign with the findings of its completed scans
+// rolled up by severity
+func (s *CampaignService) GetSummary(campaignID, organizationID uuid.UUID) (*models.CampaignSummary, error) {
+	campaign, err := s.GetCampaign(campaignID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, severity, err := s.campaignRepo.SeverityRollup(campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CampaignSummary{
+		Campaign:       campaign,
+		CompletedScans: completed,
+		Severity:       severity,
+		RiskScore:      severity.RiskScore(),
+	}, nil
+}
+
+// ListScans retrieves a page of a campaign's scans
+func (s *CampaignService) ListScans(campaignID, organizationID uuid.UUID, limit, offset int) ([]*models.ScanJob, int, error) {
+	campaign, err := s.GetCampaign(campaignID, organizationID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.scanService.ListScans(organizationID, ListScansFilter{CampaignID: &campaign.ID}, limit, offset)
+}
+
+// MaxCampaignTargetIDs bounds the explicit target list of one bulk assignment
+const MaxCampaignTargetIDs = 1000
+
+// AssignCampaignTargetsRequest selects targets to assign to a campaign,
+// either as explicit target_ids or by tag and/or search
+type AssignCampaignTargetsRequest struct {
+	TargetIDs []uuid.UUID `json:"target_ids"`
+	Tag       string      `json:"tag"`
+	Search    string      `json:"search"`
+}
+
+// AssignTargets associates the organization's targets selected by req with
+// a campaign in one transaction
+func (s *CampaignService) AssignTargets(campaignID, organizationID uuid.UUID, req *AssignCampaignTargetsRequest) (*models.CampaignTargetAssignment, error) {
+	campaign, err := s.GetCampaign(campaignID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := repository.CampaignTargetSelection{
+		Tag:    strings.TrimSpace(req.Tag),
+		Search: strings.TrimSpace(req.Search),
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range req.TargetIDs {
+		if !seen[id] {
+			seen[id] = true
+			selection.IDs = append(selection.IDs, id)
+		}
+	}
+
+	var problems ValidationErrors
+	hasFilter := selection.Tag != "" || selection.Search != ""
+	switch {
+	case len(selection.IDs) > 0 && hasFilter:
+		problems.add("target_ids", "target_ids cannot be combined with tag or search")
+	case len(selection.IDs) == 0 && !hasFilter:
+		problems.add("target_ids", "either target_ids or a tag or search filter is required")
+	case len(selection.IDs) > MaxCampaignTargetIDs:
+		problems.add("target_ids", "at most %d targets can be assigned at once", MaxCampaignTargetIDs)
+	}
+	if len(selection.Search) > 100 {
+		problems.add("search", "must be at most 100 characters")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.campaignRepo.AssignTargets(campaign.ID, organizationID, selection)
+	if err != nil {
+		if errors.Is(err, repository.ErrTargetNotFound) {
+			problems.add("target_ids", "%v", err)
+			return nil, problems.err()
+		}
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// ListTargets retrieves the targets assigned to a campaign
+func (s *CampaignService) ListTargets(campaignID, organizationID uuid.UUID) ([]*models.Target, error) {
+	campaign, err := s.GetCampaign(campaignID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.campaignRepo.ListTargets(campaign.ID)
+}
+
+// GetRisk computes a campaign's deduplicated risk posture: findings are
+// counted once per host and check, and the top riskiest hosts are listed
+func (s *CampaignService) GetRisk(campaignID, organizationID uuid.UUID, top int) (*models.CampaignRisk, error) {
+	campaign, err := s.GetCampaign(campaignID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err := s.campaignRepo.HostRisks(campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	risk := &models.CampaignRisk{CampaignID: campaign.ID, Hosts: len(hosts)}
+	for _, host := range hosts {
+		risk.Severity.Critical += host.Severity.Critical
+		risk.Severity.High += host.Severity.High
+		risk.Severity.Medium += host.Severity.Medium
+		risk.Severity.Low += host.Severity.Low
+		risk.Severity.Info += host.Severity.Info
+	}
+	risk.RiskScore = risk.Severity.RiskScore()
+
+	if len(hosts) > top {
+		hosts = hosts[:top]
+	}
+	risk.TopTargets = hosts
+
+	return risk, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/encryption"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrCertificateNotFound = errors.New("client certificate not found")
+	ErrInvalidCertificate  = errors.New("invalid client certificate")
+)
+
+// CertificateService manages client certificates for mutual-TLS scans
+type CertificateService struct {
+	certRepo *repository.CertificateRepository
+	cipher   *encryption.Cipher
+}
+
+// NewCertificateService creates a new client certificate service
+func NewCertificateService(certRepo *repository.CertificateRepository, cipher *encryption.Cipher) *CertificateService {
+	return &CertificateService{
+		certRepo: certRepo,
+		cipher:   cipher,
+	}
+}
+
+// UploadCertificateRequest represents a client certificate upload
+type UploadCertificateRequest struct {
+	Name           string `json:"name" binding:"required,max=100"`
+	CertificatePEM string `json:"certificate_pem" binding:"required"`
+	PrivateKeyPEM  string `json:"private_key_pem" binding:"required"`
+}
+
+// UploadCertificate validates a certificate/key pair and stores it with the key encrypted
+func (s *CertificateService) UploadCertificate(req *UploadCertificateRequest, userID, organizationID uuid.UUID) (*models.ClientCertificate, error) {
+	pair, err := tls.X509KeyPair([]byte(req.CertificatePEM), []byte(req.PrivateKeyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return nil, fmt.Errorf("%w: certificate expired on %s", ErrInvalidCertificate, leaf.NotAfter.Format(time.RFC3339))
+	}
+
+	encryptedKey, err := s.cipher.Encrypt([]byte(req.PrivateKeyPEM))
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := sha256.Sum256(leaf.Raw)
+	cert := &models.ClientCertificate{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Name:           req.Name,
+		Subject:        leaf.Subject.String(),
+		Issuer:         leaf.Issuer.String(),
+		Fingerprint:    hex.EncodeToString(fingerprint[:]),
+		NotBefore:      leaf.NotBefore,
+		NotAfter:       leaf.NotAfter,
+		CertificatePEM: req.CertificatePEM,
+		EncryptedKey:   encryptedKey,
+		CreatedBy:      userID,
+	}
+
+	if err := s.certRepo.Create(cert); err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// GetCertificate retrieves a client certificate by ID
+func (s *CertificateService) GetCertificate(certID, organizationID uuid.UUID) (*models.ClientCertificate, error) {
+	cert, err := s.certRepo.GetByID(certID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCertificateNotFound) {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, err
+	}
+
+	// Verify certificate belongs to organization
+	if cert.OrganizationID != organizationID {
+		return nil, ErrCertificateNotFound
+	}
+
+	return cert, nil
+}
+
+// ListCertificates retrieves all client certificates for an organization
+func (s *CertificateService) ListCertificates(organizationID uuid.UUID) ([]*models.ClientCertificate, error) {
+	return s.certRepo.ListByOrganization(organizationID)
+}
+
+// DeleteCertificate deletes a client certificate
+func (s *CertificateService) DeleteCertificate(certID, organizationID uuid.UUID) error {
+	if _, err := s.GetCertificate(certID, organizationID); err != nil {
+		return err
+	}
+
+	return s.certRepo.Delete(certID)
+}
+
+// Material decrypts a certificate/key pair for handing to a worker
+func (s *CertificateService) Material(certID, organizationID uuid.UUID) (*models.ClientCertificateMaterial, error) {
+	cert, err := s.GetCertificate(certID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.cipher.Decrypt(cert.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt client certificate key: %w", err)
+	}
+
+	return &models.ClientCertificateMaterial{
+		CertificatePEM: cert.CertificatePEM,
+		PrivateKeyPEM:  string(key),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import "publicscannerapi/internal/models"
+
+// CheckCatalogEntry describes one check a scan may request
+type CheckCatalogEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Intrusive checks are left out of monitored target scans
+	Intrusive bool `json:"intrusive"`
+	// Sync checks may run inline in a synchronous quick scan
+	Sync bool `json:"sync"`
+	// Monitor checks run in the periodic scans of monitored targets
+	Monitor bool `json:"monitor"`
+}
+
+// CheckCatalog lists every check in models.AvailableChecks. The registry is
+// compiled in, so the catalog only changes between builds.
+func CheckCatalog() []CheckCatalogEntry {
+	monitor := make(map[string]bool, len(models.MonitorChecks))
+	for _, check := range models.MonitorChecks {
+		monitor[check] = true
+	}
+
+	catalog := make([]CheckCatalogEntry, 0, len(models.AvailableChecks))
+	for _, check := range models.AvailableChecks {
+		_, sync := syncChecks[check]
+		catalog = append(catalog, CheckCatalogEntry{
+			Name:        check,
+			Description: models.CheckDescription(check),
+			Intrusive:   models.IsIntrusiveCheck(check),
+			Sync:        sync,
+			Monitor:     monitor[check],
+		})
+	}
+
+	return catalog
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/repository"
+)
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"* * * foo *",
+		"@fortnightly",
+	} {
+		if _, err := ParseCron(expr); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("ParseCron(%q) error = %v, want ErrInvalidCron", expr, err)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// A Sunday
+	after := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 1, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2026, 3, 1, 10, 40, 0, 0, time.UTC)},
+		{"15 9-17 * * mon-fri", time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1 jun *", time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted
+		{"0 0 15 * fri", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := cron.Next(after); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestStartDueSchedulesClaimsTheNextRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewScheduleService(repository.NewScheduleRepository(db), repository.NewTargetRepository(db), nil)
+
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	broken, hourly := uuid.New(), uuid.New()
+
+	columns := []string{
+		"id", "organization_id", "target_id", "checks", "config", "cron_expression",
+		"next_run_at", "last_run_at", "last_scan_id", "created_by", "created_at",
+	}
+	mock.ExpectQuery(`FROM scheduled_scans\s+WHERE next_run_at <= \$1`).WithArgs(now, scheduledBatchSize).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(broken.String(), uuid.NewString(), uuid.NewString(), "{headers}", []byte("{}"), "61 * * * *",
+				due, nil, nil, uuid.NewString(), now).
+			AddRow(hourly.String(), uuid.NewString(), uuid.NewString(), "{headers}", []byte("{}"), "@hourly",
+				due, nil, nil, uuid.NewString(), now))
+	// The invalid schedule is skipped; the hourly one moves to 11:00 and
+	// another scheduler has already claimed it, so no scan starts
+	mock.ExpectExec(`UPDATE scheduled_scans\s+SET next_run_at = \$3`).
+		WithArgs(hourly, due, time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	started, err := service.StartDueSchedules(now)
+	if err != nil {
+		t.Fatalf("StartDueSchedules: %v", err)
+	}
+	if started != 0 {
+		t.Errorf("started %d scans, want 0", started)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/mailer"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+)
+
+// verificationMailTimeout bounds the delivery of one verification email
+const verificationMailTimeout = 30 * time.Second
+
+// EmailVerificationService confirms that registered users own their email
+// address through a single-use link sent to it
+type EmailVerificationService struct {
+	userRepo   *repository.UserRepository
+	verifyRepo *repository.EmailVerificationRepository
+	mailer     mailer.Mailer
+	// verifyURL is the frontend page the token is passed to as ?token=
+	verifyURL string
+	ttl       time.Duration
+	// required refuses logins to unverified accounts
+	required bool
+}
+
+// NewEmailVerificationService creates a new email verification service
+func NewEmailVerificationService(userRepo *repository.UserRepository, verifyRepo *repository.EmailVerificationRepository, m mailer.Mailer, verifyURL string, ttl time.Duration, required bool) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:   userRepo,
+		verifyRepo: verifyRepo,
+		mailer:     m,
+		verifyURL:  verifyURL,
+		ttl:        ttl,
+		required:   required,
+	}
+}
+
+// ResendVerificationRequest represents a request for a new verification link
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// Required reports whether unverified accounts are refused tokens
+func (s *EmailVerificationService) Required() bool {
+	return s.required
+}
+
+// Check returns ErrEmailNotVerified when verification is required and the
+// user has not verified their email
+func (s *EmailVerificationService) Check(user *models.User) error {
+	if s.required && !user.EmailVerified && !user.IsServiceAccount {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// SendVerification issues a verification token for user and emails it in
+// the background, voiding any earlier link
+func (s *EmailVerificationService) SendVerification(user *models.User) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+
+	record := &models.EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.ttl).UTC(),
+	}
+	if err := s.verifyRepo.Create(record); err != nil {
+		return err
+	}
+
+	go s.sendVerificationLink(user, token)
+	return nil
+}
+
+// sendVerificationLink emails token to user
+func (s *EmailVerificationService) sendVerificationLink(user *models.User, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), verificationMailTimeout)
+	defer cancel()
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your PublicScanner email address",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Open this link within %s to confirm the email address of your PublicScanner account:\n\n%s\n\n"+
+			"If you did not create an account, ignore this email.\n",
+			user.FirstName, s.ttl, link),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		slog.Error("Failed to send verification email", "user_id", user.ID, "error", err)
+	}
+}
+
+// ResendVerification emails a new verification link to the unverified,
+// active user with req's email. Like RequestReset it succeeds the same way
+// when there is no such user, so it does not reveal whether the email is
+// registered.
+func (s *EmailVerificationService) ResendVerification(req *ResendVerificationRequest) error {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified || user.IsServiceAccount || !user.IsActive {
+		return nil
+	}
+
+	return s.SendVerification(user)
+}
+
+// Verify marks the email of the token's user as verified and uses up the token
+func (s *EmailVerificationService) Verify(token string) error {
+	userID, err := s.verifyRepo.Verify(hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrVerificationTokenInvalid) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	slog.Info("Email verified", "user_id", userID)
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/mailer"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+func TestLoginRequiresVerifiedEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mails := make(captureMailer, 1)
+	userRepo := repository.NewUserRepository(db)
+	verifier := NewEmailVerificationService(userRepo, repository.NewEmailVerificationRepository(db), mails,
+		"https://app.example.com/verify", 24*time.Hour, true)
+	service := NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, nil,
+		NewLoginLockout(userRepo, nil, LockoutPolicy{}), verifier, "jwt-secret", 15*time.Minute, 24*time.Hour)
+
+	// Registering stores an unverified user and a hashed verification token
+	stored := models.User{Email: "ada@example.com", IsActive: true}
+	var tokenHash string
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs(argFunc(func(v driver.Value) bool { stored.ID, _ = uuid.Parse(v.(string)); return true }),
+			stored.Email,
+			argFunc(func(v driver.Value) bool { stored.PasswordHash, _ = v.(string); return true }),
+			"Ada", "Lovelace", true, false).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE email_verification_tokens SET used_at = NOW\(\) WHERE user_id = \$1 AND used_at IS NULL`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO email_verification_tokens`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(),
+			argFunc(func(v driver.Value) bool { tokenHash, _ = v.(string); return tokenHash != "" }),
+			sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectCommit()
+
+	response, err := service.Register(&RegisterRequest{
+		Email: stored.Email, Password: "correct horse battery staple", FirstName: "Ada", LastName: "Lovelace",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if response.Tokens != nil || response.User.EmailVerified {
+		t.Errorf("registration returned tokens %v for a user verified %v", response.Tokens, response.User.EmailVerified)
+	}
+
+	var msg mailer.Message
+	select {
+	case msg = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no verification email was sent")
+	}
+	match := regexp.MustCompile(`https://app\.example\.com/verify\?token=([0-9a-f]{64})`).FindStringSubmatch(msg.Body)
+	if msg.To != stored.Email || match == nil {
+		t.Fatalf("email to %s without a verification link:\n%s", msg.To, msg.Body)
+	}
+	token := match[1]
+	if hashToken(token) != tokenHash {
+		t.Fatal("the emailed token does not match the stored hash")
+	}
+
+	login := func() error {
+		user := stored
+		expectUser(mock, user.Email, &user)
+		if user.EmailVerified {
+			mock.ExpectQuery(`SELECT organization_id\s+FROM organization_members`).WithArgs(user.ID).
+				WillReturnRows(sqlmock.NewRows([]string{"organization_id"}))
+			mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+				WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		}
+		_, err := service.Login(context.Background(), &LoginRequest{Email: user.Email, Password: "correct horse battery staple"})
+		return err
+	}
+
+	// The right password is not enough before verifying
+	if err := login(); !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("login before verifying: error = %v, want ErrEmailNotVerified", err)
+	}
+
+	verify := func(userID uuid.UUID) error {
+		mock.ExpectBegin()
+		rows := sqlmock.NewRows([]string{"user_id"})
+		if userID != uuid.Nil {
+			rows.AddRow(userID.String())
+		}
+		mock.ExpectQuery(`UPDATE email_verification_tokens\s+SET used_at = NOW\(\)\s+` +
+			`WHERE token_hash = \$1 AND used_at IS NULL AND expires_at > NOW\(\)`).
+			WithArgs(tokenHash).
+			WillReturnRows(rows)
+		if userID == uuid.Nil {
+			mock.ExpectRollback()
+		} else {
+			mock.ExpectExec(`UPDATE users SET email_verified = true WHERE id = \$1`).WithArgs(userID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`UPDATE email_verification_tokens SET used_at = NOW\(\) WHERE user_id = \$1`).WithArgs(userID).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectCommit()
+		}
+		return verifier.Verify(token)
+	}
+
+	if err := verify(stored.ID); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	stored.EmailVerified = true
+	if err := login(); err != nil {
+		t.Fatalf("login after verifying: %v", err)
+	}
+
+	// The link works once
+	if err := verify(uuid.Nil); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("second Verify: error = %v, want ErrInvalidVerificationToken", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/storage"
+)
+
+// EvidenceService assembles the evidence bundle of a scan: everything an
+// auditor needs to review it, in one verifiable archive
+type EvidenceService struct {
+	scanService    *ScanService
+	reportService  *ReportService
+	attachmentRepo *repository.AttachmentRepository
+	storage        storage.Storage
+}
+
+// NewEvidenceService creates a new evidence bundle service
+func NewEvidenceService(scanService *ScanService, reportService *ReportService, attachmentRepo *repository.AttachmentRepository, store storage.Storage) *EvidenceService {
+	return &EvidenceService{
+		scanService:    scanService,
+		reportService:  reportService,
+		attachmentRepo: attachmentRepo,
+		storage:        store,
+	}
+}
+
+// EvidenceBundle holds what goes into a scan's evidence archive. It is
+// loaded before anything is streamed, so lookup errors can still be
+// answered with a status code.
+type EvidenceBundle struct {
+	Scan        *models.ScanJob
+	Results     []*models.ScanResult
+	Timeline    []*models.ScanTimelineEvent
+	Reports     []*models.Report
+	Attachments []*models.ScanResultAttachment
+}
+
+// EvidenceManifest lists every file of an evidence archive with its size
+// and SHA-256 checksum. It is written last, as manifest.json.
+type EvidenceManifest struct {
+	ScanID      uuid.UUID               `json:"scan_id"`
+	GeneratedAt time.Time               `json:"generated_at"`
+	Files       []EvidenceManifestEntry `json:"files"`
+}
+
+// EvidenceManifestEntry describes one file of an evidence archive
+type EvidenceManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// LoadBundle gathers a scan's record, results, timeline, reports and
+// attachments, verifying the scan belongs to the organization
+func (s *EvidenceService) LoadBundle(scanID, organizationID uuid.UUID) (*EvidenceBundle, error) {
+	scan, err := s.scanService.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.scanService.GetScanResults(scan.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	timeline, err := s.scanService.GetScanTimeline(scan.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.reportService.ListScanReports(scan.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByScan(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvidenceBundle{
+		Scan:        scan,
+		Results:     results,
+		Timeline:    timeline,
+		Reports:     reports,
+		Attachments: attachments,
+	}, nil
+}
+
+// evidenceArchive writes ZIP entries while recording their checksums
+type evidenceArchive struct {
+	zip      *zip.Writer
+	manifest *EvidenceManifest
+}
+
+// add writes one entry, hashing the bytes as they are written
+func (a *evidenceArchive) add(path string, modified time.Time, write func(io.Writer) error) error {
+	entry, err := a.zip.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(entry, hash)}
+	if err := write(counter); err != nil {
+		return err
+	}
+
+	a.manifest.Files = append(a.manifest.Files, EvidenceManifestEntry{
+		Path:   path,
+		Size:   counter.n,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	})
+	return nil
+}
+
+// addJSON writes v as an indented JSON entry
+func (a *evidenceArchive) addJSON(path string, modified time.Time, v interface{}) error {
+	return a.add(path, modified, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	})
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// WriteArchive streams bundle to w as a ZIP holding scan.json (the scan
+// with its config), timeline.json, results.ndjson, the report files under
+// reports/, the result attachments under attachments/<result id>/, and
+// finally manifest.json. Report files or attachments missing from storage
+// are skipped and left out of the manifest.
+func (s *EvidenceService) WriteArchive(w io.Writer, bundle *EvidenceBundle) error {
+	now := time.Now().UTC()
+	archive := &evidenceArchive{
+		zip:      zip.NewWriter(w),
+		manifest: &EvidenceManifest{ScanID: bundle.Scan.ID, GeneratedAt: now},
+	}
+
+	if err := archive.addJSON("scan.json", bundle.Scan.UpdatedAt, bundle.Scan); err != nil {
+		return err
+	}
+	if err := archive.addJSON("timeline.json", now, bundle.Timeline); err != nil {
+		return err
+	}
+	err := archive.add("results.ndjson", now, func(w io.Writer) error {
+		return writeNDJSONResults(w, bundle.Results)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, report := range bundle.Reports {
+		file, info, err := s.reportService.OpenReportFile(report)
+		if err != nil {
+			if errors.Is(err, ErrReportFileMissing) {
+				slog.Warn("Skipping report in evidence bundle: file is missing", "report_id", report.ID)
+				continue
+			}
+			return err
+		}
+		err = archive.add("reports/"+report.Format+"/"+report.FileName, info.ModTime(), func(w io.Writer) error {
+			_, err := io.Copy(w, file)
+			return err
+		})
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, attachment := range bundle.Attachments {
+		object, err := s.storage.Open(attachment.StorageKey)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				slog.Warn("Skipping attachment in evidence bundle: object is missing", "attachment_id", attachment.ID)
+				continue
+			}
+			return err
+		}
+		// The attachment ID keeps same-named files of one result apart
+		path := fmt.Sprintf("attachments/%s/%s_%s", attachment.ResultID, attachment.ID, attachment.FileName)
+		err = archive.add(path, attachment.CreatedAt, func(w io.Writer) error {
+			_, err := io.Copy(w, object)
+			return err
+		})
+		object.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	manifest := archive.manifest
+	if err := archive.addJSON("manifest.json", now, manifest); err != nil {
+		return err
+	}
+
+	return archive.zip.Close()
+}
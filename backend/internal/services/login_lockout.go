@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// ErrAccountLocked is returned for logins to an account locked after too
+// many consecutive failures
+var ErrAccountLocked = errors.New("account is temporarily locked")
+
+// LockoutPolicy locks an account after MaxAttempts consecutive failed
+// logins for Cooldown. A MaxAttempts of 0 disables the lockout.
+type LockoutPolicy struct {
+	MaxAttempts int
+	Cooldown    time.Duration
+}
+
+// unknownFailureTTL is how long failed logins to an unregistered email are
+// remembered without a new failure
+const unknownFailureTTL = 24 * time.Hour
+
+// LoginLockout tracks consecutive failed logins. Registered accounts keep
+// their count and lock in the users table. Emails without an account are
+// counted in Redis the same way, so that a lockout does not reveal whether
+// an email is registered.
+type LoginLockout struct {
+	userRepo *repository.UserRepository
+	rdb      *redis.Client
+	policy   LockoutPolicy
+}
+
+// NewLoginLockout creates a new failed login tracker
+func NewLoginLockout(userRepo *repository.UserRepository, rdb *redis.Client, policy LockoutPolicy) *LoginLockout {
+	return &LoginLockout{
+		userRepo: userRepo,
+		rdb:      rdb,
+		policy:   policy,
+	}
+}
+
+// unknownFailuresKey counts failed logins to an unregistered email
+func unknownFailuresKey(email string) string {
+	return fmt.Sprintf("login_failures:%s", strings.ToLower(email))
+}
+
+// unknownLockKey marks an unregistered email as locked
+func unknownLockKey(email string) string {
+	return fmt.Sprintf("login_locked:%s", strings.ToLower(email))
+}
+
+// IsLocked reports whether logins for email are locked at now. user is
+// nil when no account has that email.
+func (l *LoginLockout) IsLocked(ctx context.Context, user *models.User, email string, now time.Time) bool {
+	if l.policy.MaxAttempts <= 0 {
+		return false
+	}
+	if user != nil {
+		return user.LockedUntil != nil && user.LockedUntil.After(now)
+	}
+
+	locked, err := l.rdb.Exists(ctx, unknownLockKey(email)).Result()
+	if err != nil {
+		slog.Error("Failed to check login lock", "error", err)
+		return false
+	}
+	return locked > 0
+}
+
+// RecordFailure counts a failed login for email and reports whether it
+// locked the account
+func (l *LoginLockout) RecordFailure(ctx context.Context, user *models.User, email string) (bool, error) {
+	if l.policy.MaxAttempts <= 0 {
+		return false, nil
+	}
+	if user != nil {
+		lockedUntil, err := l.userRepo.RecordFailedLogin(user.ID, l.policy.MaxAttempts, time.Now().Add(l.policy.Cooldown))
+		if err != nil {
+			return false, err
+		}
+		return lockedUntil != nil, nil
+	}
+
+	key := unknownFailuresKey(email)
+	failures, err := l.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		// Counting unknown emails only hides which emails exist; a Redis
+		// outage must not fail the login
+		slog.Error("Failed to record failed login", "error", err)
+		return false, nil
+	}
+	if failures < int64(l.policy.MaxAttempts) {
+		l.rdb.Expire(ctx, key, unknownFailureTTL)
+		return false, nil
+	}
+
+	pipe := l.rdb.TxPipeline()
+	pipe.Set(ctx, unknownLockKey(email), 1, l.policy.Cooldown)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("Failed to lock login", "error", err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Reset clears the failed login count and lock of an account after a
+// successful login
+func (l *LoginLockout) Reset(user *models.User) error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	return l.userRepo.ResetFailedLogins(user.ID)
+}
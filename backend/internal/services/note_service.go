@@ -0,0 +1,101 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// MaxNoteLength bounds the body of a scan result note
+const MaxNoteLength = 10000
+
+// NoteService handles analyst notes and triage of scan results
+type NoteService struct {
+	noteRepo *repository.NoteRepository
+	scanRepo *repository.ScanRepository
+}
+
+// NewNoteService creates a new note service
+func NewNoteService(noteRepo *repository.NoteRepository, scanRepo *repository.ScanRepository) *NoteService {
+	return &NoteService{
+		noteRepo: noteRepo,
+		scanRepo: scanRepo,
+	}
+}
+
+// CreateNoteRequest represents a scan result note creation request
+type CreateNoteRequest struct {
+	Body string `json:"body"`
+	// TriageStatus moves the result to a new triage state; omitted, the
+	// result keeps its current one
+	TriageStatus string `json:"triage_status"`
+}
+
+// CreateNote adds a note to a scan result
+func (s *NoteService) CreateNote(scanID, resultID, organizationID, userID uuid.UUID, req *CreateNoteRequest) (*models.ScanResultNote, error) {
+	var problems ValidationErrors
+	body := strings.TrimSpace(req.Body)
+	if body == "" && req.TriageStatus == "" {
+		problems.add("body", "must not be blank unless triage_status is set")
+	}
+	if len(body) > MaxNoteLength {
+		problems.add("body", "must be at most %d characters", MaxNoteLength)
+	}
+	if req.TriageStatus != "" && !models.IsValidTriageStatus(req.TriageStatus) {
+		problems.add("triage_status", "must be one of open, acknowledged, resolved, false_positive")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	result, err := getOrganizationResult(s.scanRepo, scanID, resultID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := req.TriageStatus
+	if status == "" {
+		status, err = s.currentTriageStatus(result.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	note := &models.ScanResultNote{
+		ID:           uuid.New(),
+		ResultID:     result.ID,
+		ScanID:       result.ScanID,
+		AuthorID:     &userID,
+		TriageStatus: status,
+		Body:         body,
+	}
+	if err := s.noteRepo.Create(note); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// ListNotes retrieves the notes of a scan result
+func (s *NoteService) ListNotes(scanID, resultID, organizationID uuid.UUID) ([]*models.ScanResultNote, error) {
+	result, err := getOrganizationResult(s.scanRepo, scanID, resultID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.noteRepo.ListByResult(result.ID)
+}
+
+// currentTriageStatus returns the triage status set by a result's latest note
+func (s *NoteService) currentTriageStatus(resultID uuid.UUID) (string, error) {
+	notes, err := s.noteRepo.ListByResult(resultID)
+	if err != nil {
+		return "", err
+	}
+	if len(notes) == 0 {
+		return models.TriageOpen, nil
+	}
+	return notes[len(notes)-1].TriageStatus, nil
+}
@@ -0,0 +1,670 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrNotOrganizationMember = errors.New("not a member of this organization")
+	ErrInsufficientRole      = errors.New("insufficient permissions")
+	ErrInvitationNotFound    = errors.New("invitation not found")
+	ErrInvitationNotOpen     = errors.New("invitation is no longer pending")
+	ErrInvalidInvitation     = errors.New("invalid invitation")
+	ErrInvalidMerge          = errors.New("invalid organization merge")
+	ErrMemberNotFound        = errors.New("member not found")
+	ErrAlreadyMember         = errors.New("user is already a member of the organization")
+	ErrInvalidMember         = errors.New("invalid member")
+)
+
+// invitationTTL is how long an invitation can be accepted
+const invitationTTL = 7 * 24 * time.Hour
+
+// OrganizationService handles organization business logic
+type OrganizationService struct {
+	orgRepo        *repository.OrganizationRepository
+	userRepo       *repository.UserRepository
+	invitationRepo *repository.InvitationRepository
+	sessions       *SessionTracker // cleared when the session policy changes; may be nil
+	limits         models.PlanLimits
+}
+
+// NewOrganizationService creates a new organization service
+func NewOrganizationService(orgRepo *repository.OrganizationRepository, userRepo *repository.UserRepository, invitationRepo *repository.InvitationRepository, sessions *SessionTracker, limits models.PlanLimits) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:        orgRepo,
+		userRepo:       userRepo,
+		invitationRepo: invitationRepo,
+		sessions:       sessions,
+		limits:         limits,
+	}
+}
+
+// requireMember verifies the organization exists and the user belongs to it.
+// Any role passes, billing included, so it only guards usage and
+// organization details; everything else should use requireRole.
+func (s *OrganizationService) requireMember(organizationID, userID uuid.UUID) error {
+	if _, err := s.orgRepo.GetByID(organizationID); err != nil {
+		if errors.Is(err, repository.ErrOrganizationNotFound) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+
+	isMember, err := s.orgRepo.IsMember(organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return ErrNotOrganizationMember
+	}
+
+	return nil
+}
+
+// memberRole verifies the organization exists and returns the user's role in it
+func (s *OrganizationService) memberRole(organizationID, userID uuid.UUID) (models.Role, error) {
+	if err := s.requireMember(organizationID, userID); err != nil {
+		return "", err
+	}
+
+	role, err := s.userRepo.GetUserRole(userID, organizationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotMember) {
+			return "", ErrNotOrganizationMember
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+// requireRole verifies the organization exists and the user holds at least minRole in it
+func (s *OrganizationService) requireRole(organizationID, userID uuid.UUID, minRole models.Role) (models.Role, error) {
+	role, err := s.memberRole(organizationID, userID)
+	if err != nil {
+		return "", err
+	}
+	if !role.AtLeast(minRole) {
+		return "", ErrInsufficientRole
+	}
+
+	return role, nil
+}
+
+// MemberPermissions is a user's role in an organization with the actions it allows
+type MemberPermissions struct {
+	OrganizationID uuid.UUID          `json:"organization_id"`
+	Role           models.Role        `json:"role"`
+	Permissions    models.Permissions `json:"permissions"`
+}
+
+// GetPermissions returns the user's role in the organization and what it allows
+func (s *OrganizationService) GetPermissions(organizationID, userID uuid.UUID) (*MemberPermissions, error) {
+	role, err := s.memberRole(organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemberPermissions{
+		OrganizationID: organizationID,
+		Role:           role,
+		Permissions:    role.Permissions(),
+	}, nil
+}
+
+// ListAllOrganizations retrieves a page of every organization on the
+// platform with usage statistics, for operators. sort is one of name,
+// created_at, members, scans, storage or last_activity.
+func (s *OrganizationService) ListAllOrganizations(sort string, descending bool, limit, offset int) ([]*models.OrganizationStats, int, error) {
+	organizations, total, err := s.orgRepo.ListWithStats(sort, descending, limit, offset)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return nil, 0, fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, sort)
+		}
+		return nil, 0, err
+	}
+
+	return organizations, total, nil
+}
+
+// GetUsage returns the organization's usage for the current calendar month
+func (s *OrganizationService) GetUsage(organizationID, userID uuid.UUID) (*models.OrganizationUsage, error) {
+	if err := s.requireMember(organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	periodStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	usage, err := s.orgRepo.GetUsage(organizationID, periodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.Limits = s.limits
+
+	return usage, nil
+}
+
+// GetNotificationPreferences returns the organization's notification preferences
+func (s *OrganizationService) GetNotificationPreferences(organizationID, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	if _, err := s.requireRole(organizationID, userID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.orgRepo.GetNotificationPreferences(organizationID)
+}
+
+// UpdateNotificationPreferencesRequest represents a partial preferences update.
+// Omitted fields keep their current value.
+type UpdateNotificationPreferencesRequest struct {
+	ScanCompleted        *bool `json:"scan_completed"`
+	ScanProgress         *bool `json:"scan_progress"`
+	HighSeverityFindings *bool `json:"high_severity_findings"`
+	CertificateExpiry    *bool `json:"certificate_expiry"`
+}
+
+// UpdateNotificationPreferences changes the organization's notification preferences
+func (s *OrganizationService) UpdateNotificationPreferences(organizationID, userID uuid.UUID, req *UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	if _, err := s.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.orgRepo.GetNotificationPreferences(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ScanCompleted != nil {
+		prefs.ScanCompleted = *req.ScanCompleted
+	}
+	if req.ScanProgress != nil {
+		prefs.ScanProgress = *req.ScanProgress
+	}
+	if req.HighSeverityFindings != nil {
+		prefs.HighSeverityFindings = *req.HighSeverityFindings
+	}
+	if req.CertificateExpiry != nil {
+		prefs.CertificateExpiry = *req.CertificateExpiry
+	}
+	prefs.UpdatedBy = &userID
+
+	if err := s.orgRepo.UpsertNotificationPreferences(prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// GetSessionPolicy returns the organization's session policy
+func (s *OrganizationService) GetSessionPolicy(organizationID, userID uuid.UUID) (*models.SessionPolicy, error) {
+	if _, err := s.requireRole(organizationID, userID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.orgRepo.GetSessionPolicy(organizationID)
+}
+
+// UpdateSessionPolicyRequest replaces an organization's session policy.
+// A null idle_timeout_minutes disables the idle check.
+type UpdateSessionPolicyRequest struct {
+	IdleTimeoutMinutes *int `json:"idle_timeout_minutes" binding:"omitempty,min=1,max=10080"`
+}
+
+// UpdateSessionPolicy changes the organization's session policy. The new
+// idle timeout applies to existing sessions from their next request.
+func (s *OrganizationService) UpdateSessionPolicy(organizationID, userID uuid.UUID, req *UpdateSessionPolicyRequest) (*models.SessionPolicy, error) {
+	if _, err := s.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	policy := &models.SessionPolicy{
+		OrganizationID:     organizationID,
+		IdleTimeoutMinutes: req.IdleTimeoutMinutes,
+	}
+	if err := s.orgRepo.UpdateSessionPolicy(policy); err != nil {
+		if errors.Is(err, repository.ErrOrganizationNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	// Sessions pick the cached policy up again within a minute anyway
+	if s.sessions != nil {
+		if err := s.sessions.ForgetPolicy(context.Background(), organizationID); err != nil {
+			slog.Error("Failed to clear cached session policy", "organization_id", organizationID, "error", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// CreateInvitationRequest represents an invitation to join an organization
+type CreateInvitationRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  models.Role `json:"role" binding:"required"`
+}
+
+// CreateInvitation invites an email address into the organization and returns
+// the invitation with its acceptance token. Only the token's hash is stored.
+func (s *OrganizationService) CreateInvitation(organizationID, userID uuid.UUID, req *CreateInvitationRequest) (*models.Invitation, string, error) {
+	callerRole, err := s.requireRole(organizationID, userID, models.RoleAdmin)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !req.Role.IsValid() || req.Role == models.RoleOwner {
+		return nil, "", fmt.Errorf("%w: role must be admin, member, viewer or billing", ErrInvalidInvitation)
+	}
+	// Billing is outside the hierarchy; any admin may grant it
+	if req.Role != models.RoleBilling && !callerRole.AtLeast(req.Role) {
+		return nil, "", ErrInsufficientRole
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	invitation := &models.Invitation{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:           req.Role,
+		Status:         models.InvitationPending,
+		TokenHash:      hashToken(token),
+		InvitedBy:      userID,
+		ExpiresAt:      time.Now().Add(invitationTTL).UTC(),
+	}
+
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, "", err
+	}
+
+	return invitation, token, nil
+}
+
+// ListInvitationsRequest pages and filters an organization's invitations
+type ListInvitationsRequest struct {
+	Status string // pending, accepted, expired or revoked; empty lists all
+	Sort   string // created_at (oldest first) or -created_at (default, newest first)
+	Limit  int
+	Offset int
+}
+
+// InvitationList is a page of invitations with organization-wide counts
+type InvitationList struct {
+	Invitations []*models.Invitation     `json:"invitations"`
+	Total       int                      `json:"total"` // invitations matching the filter
+	Counts      *models.InvitationCounts `json:"counts"`
+	Limit       int                      `json:"limit"`
+	Offset      int                      `json:"offset"`
+}
+
+// ListInvitations retrieves a filtered page of an organization's invitations
+func (s *OrganizationService) ListInvitations(organizationID, userID uuid.UUID, req *ListInvitationsRequest) (*InvitationList, error) {
+	if _, err := s.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	filter := repository.InvitationListFilter{}
+	if req.Status != "" {
+		status := models.InvitationStatus(req.Status)
+		if !status.IsValid() {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, req.Status)
+		}
+		filter.Status = &status
+	}
+	switch req.Sort {
+	case "", "-created_at":
+	case "created_at":
+		filter.Ascending = true
+	default:
+		return nil, fmt.Errorf("%w: sort must be created_at or -created_at", ErrInvalidFilter)
+	}
+
+	invitations, total, err := s.invitationRepo.ListByOrganization(organizationID, filter, req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.invitationRepo.CountByStatus(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvitationList{
+		Invitations: invitations,
+		Total:       total,
+		Counts:      counts,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}, nil
+}
+
+// RevokeInvitation revokes a pending invitation
+func (s *OrganizationService) RevokeInvitation(organizationID, invitationID, userID uuid.UUID) error {
+	if _, err := s.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+	if invitation.OrganizationID != organizationID {
+		return ErrInvitationNotFound
+	}
+
+	if err := s.invitationRepo.Revoke(invitation.ID); err != nil {
+		if errors.Is(err, repository.ErrInvitationNotOpen) {
+			return ErrInvitationNotOpen
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AcceptInvitation adds the user to the inviting organization. The invitation
+// must be pending and addressed to the user's email.
+func (s *OrganizationService) AcceptInvitation(token string, userID uuid.UUID, email string) (*models.Invitation, error) {
+	invitation, err := s.invitationRepo.GetByTokenHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrInvitationNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+
+	if !strings.EqualFold(invitation.Email, email) {
+		return nil, ErrInvitationNotFound
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, ErrInvitationNotOpen
+	}
+
+	if err := s.invitationRepo.Accept(invitation, userID); err != nil {
+		if errors.Is(err, repository.ErrInvitationNotOpen) {
+			return nil, ErrInvitationNotOpen
+		}
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// BootstrapRequest describes the first user and organization of a fresh deployment
+type BootstrapRequest struct {
+	Email            string
+	Password         string
+	FirstName        string
+	LastName         string
+	OrganizationName string
+}
+
+// Bootstrap creates an active user, an organization owned by them and the
+// owner membership in one transaction. It is meant for first-run setup, so
+// the operator-supplied email counts as verified.
+func (s *OrganizationService) Bootstrap(req *BootstrapRequest) (*models.User, *models.Organization, error) {
+	var errs ValidationErrors
+	if _, err := mail.ParseAddress(req.Email); err != nil || strings.TrimSpace(req.Email) != req.Email {
+		errs.add("email", "must be a valid email address")
+	}
+	if len(req.Password) < 8 {
+		errs.add("password", "must be at least 8 characters")
+	}
+	if req.FirstName == "" {
+		errs.add("first_name", "is required")
+	}
+	if req.LastName == "" {
+		errs.add("last_name", "is required")
+	}
+	if n := len(strings.TrimSpace(req.OrganizationName)); n < 3 || n > 100 {
+		errs.add("org", "must be between 3 and 100 characters")
+	}
+	if err := errs.err(); err != nil {
+		return nil, nil, err
+	}
+
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := &models.User{
+		ID:            uuid.New(),
+		Email:         req.Email,
+		PasswordHash:  hashedPassword,
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		IsActive:      true,
+		EmailVerified: true,
+	}
+	org := &models.Organization{
+		ID:   uuid.New(),
+		Name: strings.TrimSpace(req.OrganizationName),
+	}
+
+	if err := s.orgRepo.CreateWithOwner(org, user); err != nil {
+		return nil, nil, err
+	}
+
+	user.PasswordHash = ""
+	return user, org, nil
+}
+
+// CreateOrganizationRequest represents an organization creation request
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateOrganization creates an organization owned by the user. Service
+// accounts belong to the organization that created them and cannot own one.
+func (s *OrganizationService) CreateOrganization(userID uuid.UUID, req *CreateOrganizationRequest) (*models.Organization, error) {
+	var errs ValidationErrors
+	name := strings.TrimSpace(req.Name)
+	if n := len(name); n < 3 || n > 100 {
+		errs.add("name", "must be between 3 and 100 characters")
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsServiceAccount {
+		return nil, ErrInsufficientRole
+	}
+
+	org := &models.Organization{
+		ID:      uuid.New(),
+		Name:    name,
+		OwnerID: userID,
+	}
+	if err := s.orgRepo.Create(org); err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// ListOrganizations retrieves the organizations the user belongs to with
+// their role in each
+func (s *OrganizationService) ListOrganizations(userID uuid.UUID) ([]*models.MemberOrganization, error) {
+	return s.orgRepo.ListForUser(userID)
+}
+
+// ListMembers retrieves an organization's members; any member may list them
+func (s *OrganizationService) ListMembers(organizationID, userID uuid.UUID) ([]*models.MemberDetail, error) {
+	if err := s.requireMember(organizationID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.orgRepo.ListMembers(organizationID)
+}
+
+// AddMemberRequest adds a registered user to an organization by email.
+// People without an account are invited instead.
+type AddMemberRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  models.Role `json:"role" binding:"required"`
+}
+
+// grantableRole checks that a caller with callerRole may hand out role. The
+// owner role is never granted, and apart from billing, which sits outside
+// the hierarchy, nobody grants a role above their own.
+func grantableRole(callerRole, role models.Role) error {
+	if !role.IsValid() || role == models.RoleOwner {
+		return fmt.Errorf("%w: role must be admin, member, viewer or billing", ErrInvalidMember)
+	}
+	if role != models.RoleBilling && !callerRole.AtLeast(role) {
+		return ErrInsufficientRole
+	}
+	return nil
+}
+
+// AddMember adds an existing user to the organization. Only admins and the
+// owner may add members.
+func (s *OrganizationService) AddMember(organizationID, userID uuid.UUID, req *AddMemberRequest) (*models.OrganizationMember, error) {
+	callerRole, err := s.requireRole(organizationID, userID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := grantableRole(callerRole, req.Role); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByEmail(strings.TrimSpace(req.Email))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, fmt.Errorf("%w: no user with this email; send an invitation instead", ErrInvalidMember)
+		}
+		return nil, err
+	}
+	if user.IsServiceAccount {
+		return nil, fmt.Errorf("%w: service accounts cannot join other organizations", ErrInvalidMember)
+	}
+
+	member := &models.OrganizationMember{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		UserID:         user.ID,
+		Role:           string(req.Role),
+	}
+	if err := s.orgRepo.AddMember(member); err != nil {
+		if errors.Is(err, repository.ErrAlreadyMember) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// UpdateMemberRoleRequest represents a member role change
+type UpdateMemberRoleRequest struct {
+	Role models.Role `json:"role" binding:"required"`
+}
+
+// UpdateMemberRole changes a member's role. Only admins and the owner may
+// change roles, and only for members whose current role they could have
+// granted. The owner's role cannot be changed.
+func (s *OrganizationService) UpdateMemberRole(organizationID, userID, memberUserID uuid.UUID, req *UpdateMemberRoleRequest) (*models.OrganizationMember, error) {
+	callerRole, err := s.requireRole(organizationID, userID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := grantableRole(callerRole, req.Role); err != nil {
+		return nil, err
+	}
+
+	currentRole, err := s.userRepo.GetUserRole(memberUserID, organizationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotMember) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	if currentRole == models.RoleOwner {
+		return nil, fmt.Errorf("%w: the owner's role cannot be changed", ErrInvalidMember)
+	}
+	if err := grantableRole(callerRole, currentRole); err != nil {
+		return nil, err
+	}
+
+	member, err := s.orgRepo.UpdateMemberRole(organizationID, memberUserID, req.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotMember) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// MergeOrganizationsRequest names the organization merged into the one in the path
+type MergeOrganizationsRequest struct {
+	SourceOrganizationID uuid.UUID `json:"source_organization_id" binding:"required"`
+}
+
+// MergeOrganizations moves the source organization's targets, scans,
+// reports and members into the destination and deletes the source. Only a
+// super-admin or a user who owns both organizations may merge them.
+func (s *OrganizationService) MergeOrganizations(destinationID, userID uuid.UUID, req *MergeOrganizationsRequest) (*models.OrganizationMerge, error) {
+	if req.SourceOrganizationID == destinationID {
+		return nil, fmt.Errorf("%w: an organization cannot be merged into itself", ErrInvalidMerge)
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSuperAdmin {
+		if _, err := s.requireRole(destinationID, userID, models.RoleOwner); err != nil {
+			return nil, err
+		}
+		if _, err := s.requireRole(req.SourceOrganizationID, userID, models.RoleOwner); err != nil {
+			return nil, err
+		}
+	}
+
+	merge, err := s.orgRepo.Merge(req.SourceOrganizationID, destinationID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrganizationNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	return merge, nil
+}
+
+// hashToken returns the stored form of a token handed to a user, such as
+// an invitation or password reset token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
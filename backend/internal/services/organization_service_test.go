@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// newTestOrganizationService returns an organization service whose
+// repositories use mock
+func newTestOrganizationService(t *testing.T) (*OrganizationService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := NewOrganizationService(
+		repository.NewOrganizationRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewInvitationRepository(db),
+		nil,
+		models.PlanLimits{},
+	)
+	return service, mock
+}
+
+// expectCaller expects the membership checks of userID in organizationID,
+// who holds role, or is not a member when role is empty
+func expectCaller(mock sqlmock.Sqlmock, organizationID, userID uuid.UUID, role models.Role) {
+	mock.ExpectQuery(`FROM organizations\s+WHERE id = \$1`).WithArgs(organizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner_id", "created_at", "updated_at"}).
+			AddRow(organizationID.String(), "Example", uuid.NewString(), time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(organizationID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(role != ""))
+	if role != "" {
+		expectRole(mock, organizationID, userID, role)
+	}
+}
+
+// expectRole answers the next role lookup of userID in organizationID
+func expectRole(mock sqlmock.Sqlmock, organizationID, userID uuid.UUID, role models.Role) {
+	rows := sqlmock.NewRows([]string{"role"})
+	if role != "" {
+		rows.AddRow(string(role))
+	}
+	mock.ExpectQuery(`SELECT role\s+FROM organization_members`).WithArgs(userID, organizationID).WillReturnRows(rows)
+}
+
+func TestCreateOrganizationMakesCreatorOwner(t *testing.T) {
+	service, mock := newTestOrganizationService(t)
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: true}
+
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID).WillReturnRows(userRows(user))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO organizations`).WithArgs(sqlmock.AnyArg(), "Analytical Engines", user.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	mock.ExpectExec(`INSERT INTO organization_members`).WithArgs(sqlmock.AnyArg(), user.ID, models.RoleOwner).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	org, err := service.CreateOrganization(user.ID, &CreateOrganizationRequest{Name: "  Analytical Engines "})
+	if err != nil {
+		t.Fatalf("CreateOrganization: %v", err)
+	}
+	if org.OwnerID != user.ID || org.Name != "Analytical Engines" {
+		t.Errorf("organization %q owned by %s, want Analytical Engines owned by %s", org.Name, org.OwnerID, user.ID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGrantableRole(t *testing.T) {
+	tests := []struct {
+		caller, role models.Role
+		want         error
+	}{
+		{models.RoleOwner, models.RoleAdmin, nil},
+		{models.RoleAdmin, models.RoleAdmin, nil},
+		{models.RoleAdmin, models.RoleViewer, nil},
+		{models.RoleAdmin, models.RoleBilling, nil},
+		{models.RoleOwner, models.RoleOwner, ErrInvalidMember},
+		{models.RoleAdmin, models.Role("superuser"), ErrInvalidMember},
+		{models.RoleMember, models.RoleAdmin, ErrInsufficientRole},
+	}
+
+	for _, tt := range tests {
+		if err := grantableRole(tt.caller, tt.role); !errors.Is(err, tt.want) {
+			t.Errorf("grantableRole(%s, %s) = %v, want %v", tt.caller, tt.role, err, tt.want)
+		}
+	}
+}
+
+func TestUpdateMemberRoleConstraints(t *testing.T) {
+	tests := []struct {
+		name                  string
+		caller, current       models.Role
+		role                  models.Role
+		memberLooked, updated bool
+		err                   error
+	}{
+		{name: "member cannot change roles", caller: models.RoleMember, role: models.RoleViewer, err: ErrInsufficientRole},
+		{name: "non-member cannot change roles", role: models.RoleViewer, err: ErrNotOrganizationMember},
+		{name: "nobody is made owner", caller: models.RoleOwner, role: models.RoleOwner, err: ErrInvalidMember},
+		{name: "owner role is fixed", caller: models.RoleAdmin, current: models.RoleOwner, role: models.RoleViewer,
+			memberLooked: true, err: ErrInvalidMember},
+		{name: "unknown member", caller: models.RoleAdmin, role: models.RoleViewer, memberLooked: true, err: ErrMemberNotFound},
+		{name: "admin demotes member", caller: models.RoleAdmin, current: models.RoleMember, role: models.RoleViewer,
+			memberLooked: true, updated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock := newTestOrganizationService(t)
+			organizationID, callerID, memberID := uuid.New(), uuid.New(), uuid.New()
+
+			expectCaller(mock, organizationID, callerID, tt.caller)
+			if tt.memberLooked {
+				expectRole(mock, organizationID, memberID, tt.current)
+			}
+			if tt.updated {
+				mock.ExpectQuery(`UPDATE organization_members\s+SET role = \$3`).
+					WithArgs(organizationID, memberID, tt.role).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "user_id", "role", "joined_at"}).
+						AddRow(uuid.NewString(), organizationID.String(), memberID.String(), string(tt.role), time.Now()))
+			}
+
+			member, err := service.UpdateMemberRole(organizationID, callerID, memberID, &UpdateMemberRoleRequest{Role: tt.role})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if tt.updated && member.Role != string(tt.role) {
+				t.Errorf("role = %s, want %s", member.Role, tt.role)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAddMemberRejectsExistingMembers(t *testing.T) {
+	service, mock := newTestOrganizationService(t)
+	organizationID, callerID := uuid.New(), uuid.New()
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: true}
+
+	expectCaller(mock, organizationID, callerID, models.RoleOwner)
+	expectUser(mock, user.Email, user)
+	mock.ExpectQuery(`INSERT INTO organization_members`).
+		WithArgs(sqlmock.AnyArg(), organizationID, user.ID, string(models.RoleMember)).
+		WillReturnRows(sqlmock.NewRows([]string{"joined_at"}))
+
+	_, err := service.AddMember(organizationID, callerID, &AddMemberRequest{Email: user.Email, Role: models.RoleMember})
+	if !errors.Is(err, ErrAlreadyMember) {
+		t.Fatalf("AddMember error = %v, want ErrAlreadyMember", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/mailer"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// resetMailTimeout bounds the delivery of one password reset email
+const resetMailTimeout = 30 * time.Second
+
+// PasswordResetService lets users who forgot their password set a new one
+// through a single-use link sent by email
+type PasswordResetService struct {
+	userRepo  *repository.UserRepository
+	resetRepo *repository.PasswordResetRepository
+	mailer    mailer.Mailer
+	// resetURL is the frontend page the token is passed to as ?token=
+	resetURL string
+	ttl      time.Duration
+}
+
+// NewPasswordResetService creates a new password reset service
+func NewPasswordResetService(userRepo *repository.UserRepository, resetRepo *repository.PasswordResetRepository, m mailer.Mailer, resetURL string, ttl time.Duration) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:  userRepo,
+		resetRepo: resetRepo,
+		mailer:    m,
+		resetURL:  resetURL,
+		ttl:       ttl,
+	}
+}
+
+// ForgotPasswordRequest represents a password reset link request
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents a password reset with a token from email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// RequestReset emails a reset link to the active user with req's email. It
+// succeeds the same way when there is no such user, and the email is sent
+// in the background, so neither the result nor the response time reveals
+// whether the email is registered.
+func (s *PasswordResetService) RequestReset(req *ForgotPasswordRequest) error {
+	user, err := s.userRepo.GetByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	// Service accounts have no password, inactive users cannot log in
+	if user.IsServiceAccount || !user.IsActive {
+		return nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+
+	record := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.ttl).UTC(),
+	}
+	if err := s.resetRepo.Create(record); err != nil {
+		return err
+	}
+
+	go s.sendResetLink(user, token)
+	return nil
+}
+
+// sendResetLink emails token to user
+func (s *PasswordResetService) sendResetLink(user *models.User, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), resetMailTimeout)
+	defer cancel()
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your PublicScanner password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Someone asked to reset the password of your PublicScanner account.\n"+
+			"Open this link within %s to choose a new password:\n\n%s\n\n"+
+			"The link works once. If you did not ask for it, ignore this email; your password stays the same.\n",
+			user.FirstName, s.ttl, link),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		slog.Error("Failed to send password reset email", "user_id", user.ID, "error", err)
+	}
+}
+
+// ResetPassword sets a new password with a token from a reset email. The
+// token is used up, the account's lockout is lifted and its refresh tokens
+// are revoked, so existing logins end once their access token expires.
+func (s *PasswordResetService) ResetPassword(req *ResetPasswordRequest) error {
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := s.resetRepo.ResetPassword(hashToken(req.Token), hashedPassword)
+	if err != nil {
+		if errors.Is(err, repository.ErrResetTokenInvalid) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	slog.Info("Password reset", "user_id", userID)
+	return nil
+}
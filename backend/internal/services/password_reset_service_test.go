@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/mailer"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+// captureMailer hands every message it is asked to send to a channel
+type captureMailer chan mailer.Message
+
+func (m captureMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m <- msg
+	return nil
+}
+
+// argFunc matches a query argument with a function
+type argFunc func(driver.Value) bool
+
+func (f argFunc) Match(v driver.Value) bool {
+	return f(v)
+}
+
+// expectTokenUse expects the token with tokenHash to be used, succeeding
+// for userID unless userID is uuid.Nil
+func expectTokenUse(mock sqlmock.Sqlmock, tokenHash string, userID uuid.UUID) {
+	mock.ExpectBegin()
+	rows := sqlmock.NewRows([]string{"user_id"})
+	if userID != uuid.Nil {
+		rows.AddRow(userID.String())
+	}
+	mock.ExpectQuery(`UPDATE password_reset_tokens\s+SET used_at = NOW\(\)\s+` +
+		`WHERE token_hash = \$1 AND used_at IS NULL AND expires_at > NOW\(\)`).
+		WithArgs(tokenHash).
+		WillReturnRows(rows)
+}
+
+func TestPasswordResetTokenIsSingleUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mails := make(captureMailer, 1)
+	service := NewPasswordResetService(repository.NewUserRepository(db), repository.NewPasswordResetRepository(db), mails,
+		"https://app.example.com/reset", 30*time.Minute)
+	user := &models.User{ID: uuid.New(), Email: "ada@example.com", IsActive: true}
+
+	// The token is stored hashed, expiring after the TTL
+	var storedHash string
+	expectUser(mock, user.Email, user)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE password_reset_tokens SET used_at = NOW\(\) WHERE user_id = \$1 AND used_at IS NULL`).
+		WithArgs(user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO password_reset_tokens`).
+		WithArgs(sqlmock.AnyArg(), user.ID,
+			argFunc(func(v driver.Value) bool { storedHash, _ = v.(string); return storedHash != "" }),
+			argFunc(func(v driver.Value) bool {
+				expiresAt, ok := v.(time.Time)
+				return ok && time.Until(expiresAt) > 29*time.Minute && time.Until(expiresAt) <= 30*time.Minute
+			})).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectCommit()
+
+	if err := service.RequestReset(&ForgotPasswordRequest{Email: user.Email}); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+
+	var msg mailer.Message
+	select {
+	case msg = <-mails:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reset email was sent")
+	}
+	match := regexp.MustCompile(`https://app\.example\.com/reset\?token=([0-9a-f]{64})`).FindStringSubmatch(msg.Body)
+	if msg.To != user.Email || match == nil {
+		t.Fatalf("email to %s without a reset link:\n%s", msg.To, msg.Body)
+	}
+	token := match[1]
+	if hashToken(token) != storedHash {
+		t.Fatal("the emailed token does not match the stored hash")
+	}
+
+	// The first use sets the password and ends every session
+	expectTokenUse(mock, storedHash, user.ID)
+	mock.ExpectExec(`UPDATE users\s+SET password_hash = \$2, failed_login_attempts = 0, locked_until = NULL`).
+		WithArgs(user.ID, argFunc(func(v driver.Value) bool {
+			hash, _ := v.(string)
+			return auth.CheckPassword(hash, "a brand new password")
+		})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1`).WithArgs(user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE password_reset_tokens SET used_at = NOW\(\) WHERE user_id = \$1`).WithArgs(user.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.ResetPassword(&ResetPasswordRequest{Token: token, NewPassword: "a brand new password"}); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	// A used or expired token matches no row
+	expectTokenUse(mock, storedHash, uuid.Nil)
+	mock.ExpectRollback()
+	err = service.ResetPassword(&ResetPasswordRequest{Token: token, NewPassword: "another new password"})
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("second ResetPassword: error = %v, want ErrInvalidResetToken", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPasswordResetUnknownEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mails := make(captureMailer, 1)
+	service := NewPasswordResetService(repository.NewUserRepository(db), repository.NewPasswordResetRepository(db), mails,
+		"https://app.example.com/reset", 30*time.Minute)
+
+	// Succeeds like for a registered email, without storing or sending anything
+	expectUser(mock, "nobody@example.com", nil)
+	if err := service.RequestReset(&ForgotPasswordRequest{Email: "nobody@example.com"}); err != nil {
+		t.Fatalf("RequestReset: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-mails:
+		t.Errorf("an email was sent to %s", msg.To)
+	default:
+	}
+}
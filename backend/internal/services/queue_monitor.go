@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/repository"
+)
+
+// QueueStats describes how backed up scan processing is
+type QueueStats struct {
+	// QueueLength is the number of task messages waiting in Redis
+	QueueLength int64 `json:"queue_length"`
+	Queued      int   `json:"queued"`
+	Running     int   `json:"running"`
+	// OldestQueuedAge is how long the oldest queued scan has waited, in seconds
+	OldestQueuedAge *float64   `json:"oldest_queued_age_seconds"`
+	OldestQueuedAt  *time.Time `json:"oldest_queued_at"`
+	CheckedAt       time.Time  `json:"checked_at"`
+}
+
+// QueueMonitor reports the scan task backlog
+type QueueMonitor struct {
+	rdb      *redis.Client
+	scanRepo *repository.ScanRepository
+}
+
+// NewQueueMonitor creates a new queue monitor
+func NewQueueMonitor(rdb *redis.Client, scanRepo *repository.ScanRepository) *QueueMonitor {
+	return &QueueMonitor{
+		rdb:      rdb,
+		scanRepo: scanRepo,
+	}
+}
+
+// Stats returns the Redis queue length and the database's view of the backlog
+func (m *QueueMonitor) Stats(ctx context.Context) (*QueueStats, error) {
+	length, err := m.rdb.LLen(ctx, CeleryQueue).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := m.scanRepo.CountActive()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stats := &QueueStats{
+		QueueLength:    length,
+		Queued:         counts.Queued,
+		Running:        counts.Running,
+		OldestQueuedAt: counts.OldestQueuedAt,
+		CheckedAt:      now,
+	}
+	if counts.OldestQueuedAt != nil {
+		age := now.Sub(*counts.OldestQueuedAt).Seconds()
+		stats.OldestQueuedAge = &age
+	}
+
+	return stats, nil
+}
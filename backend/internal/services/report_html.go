@@ -0,0 +1,78 @@
+package services
+
+import (
+	"embed"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"time"
+
+	"publicscannerapi/internal/models"
+)
+
+//go:embed templates/report.html
+var reportTemplates embed.FS
+
+// htmlReportTemplate renders HTML reports. html/template escapes every value
+// for its context, so hostile finding text cannot inject markup.
+var htmlReportTemplate = template.Must(template.New("report.html").Funcs(template.FuncMap{
+	"formatTime":    htmlReportTime,
+	"severityClass": htmlSeverityClass,
+}).ParseFS(reportTemplates, "templates/report.html"))
+
+// htmlReportData is the data the HTML report template is executed with
+type htmlReportData struct {
+	Scan        *models.ScanJob
+	Results     []*models.ScanResult
+	Branding    *models.EffectiveBranding
+	LogoURI     template.URL
+	GeneratedAt time.Time
+}
+
+// generateHTMLReport generates an HTML format report rendered with the
+// organization's branding
+func (s *ReportService) generateHTMLReport(scan *models.ScanJob, results []*models.ScanResult, branding *models.EffectiveBranding) (string, int64, error) {
+	return s.writeReportFile(scan, "html", func(w io.Writer) error {
+		return writeHTMLReport(w, scan, results, branding)
+	})
+}
+
+// writeHTMLReport renders the HTML report of a scan to w
+func writeHTMLReport(w io.Writer, scan *models.ScanJob, results []*models.ScanResult, branding *models.EffectiveBranding) error {
+	data := &htmlReportData{
+		Scan:        scan,
+		Results:     results,
+		Branding:    branding,
+		GeneratedAt: time.Now(),
+	}
+
+	// Logos were sniffed as PNG or JPEG on upload, so the data URI is
+	// trusted; anything else falls back to the company name alone
+	if len(branding.Logo) > 0 && allowedLogoTypes[branding.LogoContentType] {
+		data.LogoURI = template.URL("data:" + branding.LogoContentType + ";base64," + base64.StdEncoding.EncodeToString(branding.Logo))
+	}
+
+	return htmlReportTemplate.Execute(w, data)
+}
+
+// htmlReportTime formats a timestamp, or a dash when it is not set
+func htmlReportTime(t interface{}) string {
+	switch v := t.(type) {
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05 UTC")
+	case *time.Time:
+		if v != nil {
+			return v.UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+	}
+	return "-"
+}
+
+// htmlSeverityClass returns the badge class of a severity. Unknown
+// severities get no class and render as a neutral badge.
+func htmlSeverityClass(severity string) string {
+	if !models.IsValidSeverity(severity) {
+		return ""
+	}
+	return severity
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"publicscannerapi/internal/models"
+)
+
+// pdfLogoTypes maps the accepted logo content types to fpdf image types
+var pdfLogoTypes = map[string]string{
+	"image/png":  "PNG",
+	"image/jpeg": "JPG",
+}
+
+// pdfSummaryColumns are the headers and widths (mm) of the findings summary table
+var pdfSummaryColumns = []struct {
+	title string
+	width float64
+}{
+	{"Check", 60},
+	{"Status", 35},
+	{"Severity", 35},
+	{"Findings", 25},
+	{"Completed", 35},
+}
+
+// generatePDFReport generates a PDF format report rendered with the
+// organization's branding
+func (s *ReportService) generatePDFReport(scan *models.ScanJob, results []*models.ScanResult, branding *models.EffectiveBranding) (string, int64, error) {
+	return s.writeReportFile(scan, "pdf", func(w io.Writer) error {
+		return writePDFReport(w, scan, results, branding)
+	})
+}
+
+// writePDFReport lays out a scan's metadata, a findings summary table and
+// one section per result, and writes the PDF to w
+func writePDFReport(w io.Writer, scan *models.ScanJob, results []*models.ScanResult, branding *models.EffectiveBranding) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Scan report "+scan.ID.String(), true)
+	pdf.SetCreator(branding.CompanyName, true)
+	pdf.AliasNbPages("")
+
+	// Core fonts are cp1252, so user supplied text is translated first
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	red, green, blue := brandingRGB(branding.PrimaryColor)
+
+	logo := ""
+	if imageType, ok := pdfLogoTypes[branding.LogoContentType]; ok && len(branding.Logo) > 0 {
+		logo = "logo"
+		pdf.RegisterImageOptionsReader(logo, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(branding.Logo))
+		if !pdf.Ok() {
+			// An unreadable logo should not fail the report
+			pdf.ClearError()
+			logo = ""
+		}
+	}
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFillColor(red, green, blue)
+		pdf.Rect(0, 0, 210, 6, "F")
+		if logo != "" {
+			pdf.ImageOptions(logo, 10, 10, 0, 12, false, fpdf.ImageOptions{}, 0, "")
+		}
+		pdf.SetXY(10, 10)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.SetTextColor(red, green, blue)
+		pdf.CellFormat(0, 12, tr(branding.CompanyName), "", 1, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(4)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(150, 10, tr(branding.FooterText), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	pdf.AddPage()
+
+	// Scan metadata
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Scan Report", "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	for _, field := range pdfScanMetadata(scan) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(35, 6, field[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(field[1]), "", "L", false)
+	}
+	pdf.Ln(6)
+
+	// Findings summary
+	pdfSectionTitle(pdf, "Findings Summary", red, green, blue)
+	if len(results) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 6, "The scan has no results.", "", 1, "L", false, 0, "")
+	} else {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetFillColor(235, 235, 235)
+		for _, column := range pdfSummaryColumns {
+			pdf.CellFormat(column.width, 7, column.title, "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 10)
+		total := 0
+		for _, result := range results {
+			total += result.Findings
+			row := []string{
+				result.CheckType,
+				result.Status,
+				pdfSeverity(result.Severity),
+				fmt.Sprintf("%d", result.Findings),
+				result.CreatedAt.UTC().Format("2006-01-02 15:04"),
+			}
+			for i, column := range pdfSummaryColumns {
+				pdf.CellFormat(column.width, 7, tr(row[i]), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(130, 7, "Total", "1", 0, "L", false, 0, "")
+		pdf.CellFormat(60, 7, fmt.Sprintf("%d", total), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	// One section per result
+	for _, result := range results {
+		pdfSectionTitle(pdf, result.CheckType, red, green, blue)
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(fmt.Sprintf("Status: %s", result.Status)), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 6, fmt.Sprintf("Severity: %s", pdfSeverity(result.Severity)), "", 1, "L", false, 0, "")
+		pdf.CellFormat(0, 6, fmt.Sprintf("Findings: %d", result.Findings), "", 1, "L", false, 0, "")
+		if counts := result.FindingsBySeverity; counts != nil {
+			pdf.CellFormat(0, 6, fmt.Sprintf("By severity: %d critical, %d high, %d medium, %d low, %d info",
+				counts.Critical, counts.High, counts.Medium, counts.Low, counts.Info), "", 1, "L", false, 0, "")
+		}
+		if result.OriginalSeverity != nil {
+			pdf.SetFont("Helvetica", "I", 9)
+			pdf.CellFormat(0, 6, fmt.Sprintf("Severity overridden; the check reported %s", pdfSeverity(*result.OriginalSeverity)), "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(4)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// pdfSectionTitle writes a section heading underlined in the branding color
+func pdfSectionTitle(pdf *fpdf.Fpdf, title string, red, green, blue int) {
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+	pdf.SetDrawColor(red, green, blue)
+	pdf.Line(10, pdf.GetY(), 200, pdf.GetY())
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(3)
+}
+
+// pdfScanMetadata returns the label/value pairs of the report header
+func pdfScanMetadata(scan *models.ScanJob) [][2]string {
+	target := "-"
+	if scan.URL != nil {
+		target = *scan.URL
+	} else if scan.TargetID != nil {
+		target = "Target " + scan.TargetID.String()
+	}
+
+	fields := [][2]string{
+		{"Scan ID", scan.ID.String()},
+		{"Target", target},
+		{"Status", string(scan.Status)},
+		{"Checks", strings.Join(scan.Checks, ", ")},
+		{"Created", pdfTime(&scan.CreatedAt)},
+		{"Started", pdfTime(scan.StartedAt)},
+		{"Completed", pdfTime(scan.CompletedAt)},
+	}
+	if scan.WorstSeverity != nil {
+		fields = append(fields, [2]string{"Worst severity", pdfSeverity(*scan.WorstSeverity)})
+	}
+	if scan.FailureReason != nil {
+		fields = append(fields, [2]string{"Failure", *scan.FailureReason})
+	}
+	generated := time.Now()
+	return append(fields, [2]string{"Generated", pdfTime(&generated)})
+}
+
+// pdfTime formats an optional timestamp for the report
+func pdfTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// pdfSeverity returns the severity label, or a dash when none was reported
+func pdfSeverity(severity string) string {
+	if severity == "" {
+		return "-"
+	}
+	return severity
+}
+
+// brandingRGB splits a #rrggbb branding color into its components
+func brandingRGB(color string) (int, int, int) {
+	var red, green, blue int
+	if _, err := fmt.Sscanf(color, "#%02x%02x%02x", &red, &green, &blue); err != nil {
+		fmt.Sscanf(models.DefaultBrandingPrimaryColor, "#%02x%02x%02x", &red, &green, &blue)
+	}
+	return red, green, blue
+}
@@ -1,10 +1,14 @@
 package services
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -18,20 +22,24 @@ var (
 	ErrReportNotFound    = errors.New("report not found")
 	ErrInvalidFormat     = errors.New("invalid report format")
 	ErrReportGeneration  = errors.New("failed to generate report")
+	ErrReportFileMissing = errors.New("report file is missing")
+	ErrScanNotFinished   = errors.New("scan has not finished")
 )
 
 // ReportService handles report business logic
 type ReportService struct {
 	reportRepo  *repository.ReportRepository
 	scanRepo    *repository.ScanRepository
+	branding    *BrandingService
 	storagePath string
 }
 
 // NewReportService creates a new report service
-func NewReportService(reportRepo *repository.ReportRepository, scanRepo *repository.ScanRepository, storagePath string) *ReportService {
+func NewReportService(reportRepo *repository.ReportRepository, scanRepo *repository.ScanRepository, branding *BrandingService, storagePath string) *ReportService {
 	return &ReportService{
 		reportRepo:  reportRepo,
 		scanRepo:    scanRepo,
+		branding:    branding,
 		storagePath: storagePath,
 	}
 }
@@ -40,6 +48,8 @@ func NewReportService(reportRepo *repository.ReportRepository, scanRepo *reposit
 type GenerateReportRequest struct {
 	ScanID uuid.UUID `json:"scan_id" binding:"required"`
 	Format string    `json:"format" binding:"required,oneof=json csv pdf html"`
+	// AllowPartial permits a snapshot report of a scan that is still queued or running
+	AllowPartial bool `json:"allow_partial"`
 }
 
 // GenerateReport generates a report for a scan
@@ -57,6 +67,11 @@ func (s *ReportService) GenerateReport(req *GenerateReportRequest, userID, organ
 		return nil, ErrScanNotFound
 	}
 
+	// Reports of unfinished scans are misleading unless explicitly requested
+	if !scan.Status.IsTerminal() && !req.AllowPartial {
+		return nil, fmt.Errorf("%w: scan is %s", ErrScanNotFinished, scan.Status)
+	}
+
 	// Get scan results
 	results, err := s.scanRepo.GetResults(req.ScanID)
 	if err != nil {
@@ -73,11 +88,19 @@ func (s *ReportService) GenerateReport(req *GenerateReportRequest, userID, organ
 	case "csv":
 		filePath, fileSize, err = s.generateCSVReport(scan, results)
 	case "pdf":
-		// TODO: Implement PDF generation
-		return nil, errors.New("PDF reports not yet implemented")
+		var branding *models.EffectiveBranding
+		branding, err = s.branding.ReportBranding(organizationID)
+		if err != nil {
+			return nil, err
+		}
+		filePath, fileSize, err = s.generatePDFReport(scan, results, branding)
 	case "html":
-		// TODO: Implement HTML generation
-		return nil, errors.New("HTML reports not yet implemented")
+		var branding *models.EffectiveBranding
+		branding, err = s.branding.ReportBranding(organizationID)
+		if err != nil {
+			return nil, err
+		}
+		filePath, fileSize, err = s.generateHTMLReport(scan, results, branding)
 	default:
 		return nil, ErrInvalidFormat
 	}
@@ -109,25 +132,22 @@ func (s *ReportService) GenerateReport(req *GenerateReportRequest, userID, organ
 
 // generateJSONReport generates a JSON format report
 func (s *ReportService) generateJSONReport(scan *models.ScanJob, results []*models.ScanResult) (string, int64, error) {
-	// Create report data structure
-	reportData := map[string]interface{}{
-		"scan_id":    scan.ID,
-		"status":     scan.Status,
-		"started_at": scan.StartedAt,
-		"completed_at": scan.CompletedAt,
-		"checks":     scan.Checks,
-		"results":    results,
-		"generated_at": time.Now(),
-	}
+	return s.writeReportFile(scan, "json", func(w io.Writer) error {
+		return writeJSONReport(w, scan, results)
+	})
+}
 
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(reportData, "", "  ")
-	if err != nil {
-		return "", 0, err
-	}
+// generateCSVReport generates a CSV format report
+func (s *ReportService) generateCSVReport(scan *models.ScanJob, results []*models.ScanResult) (string, int64, error) {
+	return s.writeReportFile(scan, "csv", func(w io.Writer) error {
+		return writeCSVReport(w, results)
+	})
+}
 
-	// Create file
-	filename := fmt.Sprintf("scan_%s_%s.json", scan.ID, time.Now().Format("20060102_150405"))
+// writeReportFile creates a report file of the scan under the storage path,
+// fills it through write and returns its path and size
+func (s *ReportService) writeReportFile(scan *models.ScanJob, ext string, write func(io.Writer) error) (string, int64, error) {
+	filename := fmt.Sprintf("scan_%s_%s.%s", scan.ID, time.Now().Format("20060102_150405"), ext)
 	filePath := filepath.Join(s.storagePath, "reports", filename)
 
 	// Ensure directory exists
@@ -135,8 +155,18 @@ func (s *ReportService) generateJSONReport(scan *models.ScanJob, results []*mode
 		return "", 0, err
 	}
 
-	// Write file
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		_ = os.Remove(filePath)
+		return "", 0, err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(filePath)
 		return "", 0, err
 	}
 
@@ -149,30 +179,33 @@ func (s *ReportService) generateJSONReport(scan *models.ScanJob, results []*mode
 	return filePath, info.Size(), nil
 }
 
-// generateCSVReport generates a CSV format report
-func (s *ReportService) generateCSVReport(scan *models.ScanJob, results []*models.ScanResult) (string, int64, error) {
-	// Create file
-	filename := fmt.Sprintf("scan_%s_%s.csv", scan.ID, time.Now().Format("20060102_150405"))
-	filePath := filepath.Join(s.storagePath, "reports", filename)
-
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return "", 0, err
-	}
+// writeJSONReport writes the versioned JSON report document of a scan to w
+func writeJSONReport(w io.Writer, scan *models.ScanJob, results []*models.ScanResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(models.NewReportDocument(scan, results))
+}
 
-	file, err := os.Create(filePath)
-	if err != nil {
-		return "", 0, err
+// writeNDJSONResults writes each result to w as one JSON object per line
+func writeNDJSONResults(w io.Writer, results []*models.ScanResult) error {
+	encoder := json.NewEncoder(w)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
 	}
-	defer file.Close()
+	return nil
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// writeCSVReport writes one CSV row per result to w, with the result data
+// as JSON in the Details column
+func writeCSVReport(w io.Writer, results []*models.ScanResult) error {
+	writer := csv.NewWriter(w)
 
 	// Write header
-	header := []string{"Check Type", "Status", "Findings", "Severity", "Timestamp"}
+	header := []string{"Check Type", "Status", "Findings", "Severity", "Timestamp", "Details"}
 	if err := writer.Write(header); err != nil {
-		return "", 0, err
+		return err
 	}
 
 	// Write results
@@ -183,19 +216,77 @@ func (s *ReportService) generateCSVReport(scan *models.ScanJob, results []*model
 			fmt.Sprintf("%d", result.Findings),
 			result.Severity,
 			result.CreatedAt.Format(time.RFC3339),
+			csvDetails(result.Data),
 		}
 		if err := writer.Write(record); err != nil {
-			return "", 0, err
+			return err
 		}
 	}
 
-	// Get file size
-	info, err := os.Stat(filePath)
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvDetails returns a result's data as compact JSON for the Details column.
+// csv.Writer quotes the embedded quotes and newlines.
+func csvDetails(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return string(data)
+	}
+	return compact.String()
+}
+
+// GetCheckResults returns a scan together with its results of one check type,
+// verifying the scan belongs to the organization
+func (s *ReportService) GetCheckResults(scanID, organizationID uuid.UUID, checkType string) (*models.ScanJob, []*models.ScanResult, error) {
+	if !models.IsValidCheck(checkType) {
+		return nil, nil, fmt.Errorf("%w: unknown check type %q", ErrInvalidFilter, checkType)
+	}
+
+	scan, err := s.scanRepo.GetByID(scanID)
 	if err != nil {
-		return "", 0, err
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, nil, ErrScanNotFound
+		}
+		return nil, nil, err
+	}
+	if scan.OrganizationID != organizationID {
+		return nil, nil, ErrScanNotFound
 	}
 
-	return filePath, info.Size(), nil
+	results, err := s.scanRepo.GetResults(scanID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filtered := make([]*models.ScanResult, 0, len(results))
+	for _, result := range results {
+		if result.CheckType == checkType {
+			filtered = append(filtered, result)
+		}
+	}
+
+	return scan, filtered, nil
+}
+
+// WriteResults streams results of a scan to w in the given format, using the
+// same layout as the stored JSON and CSV reports
+func (s *ReportService) WriteResults(w io.Writer, format string, scan *models.ScanJob, results []*models.ScanResult) error {
+	switch format {
+	case "json":
+		return writeJSONReport(w, scan, results)
+	case "csv":
+		return writeCSVReport(w, results)
+	case "ndjson":
+		return writeNDJSONResults(w, results)
+	default:
+		return ErrInvalidFormat
+	}
 }
 
 // GetReport retrieves a report by ID
@@ -216,25 +307,150 @@ func (s *ReportService) GetReport(reportID, organizationID uuid.UUID) (*models.R
 	return report, nil
 }
 
+// OpenReportFile opens a report's stored file for reading, together with its file info
+func (s *ReportService) OpenReportFile(report *models.Report) (*os.File, os.FileInfo, error) {
+	file, err := os.Open(report.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrReportFileMissing
+		}
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+// ListScanReports retrieves all reports of a scan, verifying the scan belongs
+// to the organization
+func (s *ReportService) ListScanReports(scanID, organizationID uuid.UUID) ([]*models.Report, error) {
+	if err := s.checkScanAccess(scanID, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.reportRepo.ListByScan(scanID, "", 0, 0)
+}
+
+// ListByScan retrieves a page of a scan's reports, newest first, verifying
+// the scan belongs to the organization. A non-empty format (pdf, html, json
+// or csv) only returns reports in that format.
+func (s *ReportService) ListByScan(scanID, organizationID uuid.UUID, format string, limit, offset int) ([]*models.Report, int, error) {
+	switch format {
+	case "", "pdf", "html", "json", "csv":
+	default:
+		return nil, 0, fmt.Errorf("%w: format must be pdf, html, json or csv", ErrInvalidFilter)
+	}
+
+	if err := s.checkScanAccess(scanID, organizationID); err != nil {
+		return nil, 0, err
+	}
+
+	reports, err := s.reportRepo.ListByScan(scanID, format, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.reportRepo.CountByScan(scanID, format)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// checkScanAccess returns ErrScanNotFound unless the scan exists in the
+// organization
+func (s *ReportService) checkScanAccess(scanID, organizationID uuid.UUID) error {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return ErrScanNotFound
+		}
+		return err
+	}
+	if scan.OrganizationID != organizationID {
+		return ErrScanNotFound
+	}
+	return nil
+}
+
+// WriteReportsArchive streams the files of reports into a ZIP written to w.
+// Entries are grouped by format as "<format>/<file name>"; reports whose file
+// is gone are skipped. It returns how many files were added.
+func (s *ReportService) WriteReportsArchive(w io.Writer, reports []*models.Report) (int, error) {
+	archive := zip.NewWriter(w)
+
+	added := 0
+	for _, report := range reports {
+		file, info, err := s.OpenReportFile(report)
+		if err != nil {
+			if errors.Is(err, ErrReportFileMissing) {
+				slog.Warn("Skipping report in archive: file is missing", "report_id", report.ID)
+				continue
+			}
+			return added, err
+		}
+
+		header := &zip.FileHeader{
+			Name:     report.Format + "/" + report.FileName,
+			Method:   zip.Deflate,
+			Modified: info.ModTime(),
+		}
+		entry, err := archive.CreateHeader(header)
+		if err == nil {
+			_, err = io.Copy(entry, file)
+		}
+		file.Close()
+		if err != nil {
+			return added, err
+		}
+		added++
+	}
+
+	return added, archive.Close()
+}
+
 // ListReports retrieves all reports for an organization
-func (s *ReportService) ListReports(organizationID uuid.UUID, limit, offset int) ([]*models.Report, error) {
-	return s.reportRepo.ListByOrganization(organizationID, limit, offset)
+func (s *ReportService) ListReports(organizationID uuid.UUID, limit, offset int) ([]*models.Report, int, error) {
+	reports, err := s.reportRepo.ListByOrganization(organizationID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.reportRepo.CountByOrganization(organizationID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
 }
 
-// DeleteReport deletes a report and its file
+// DeleteReport deletes a report and then its file. The row is removed first
+// because it is authoritative: once it is gone the report no longer exists,
+// and a file left behind by a failed removal is collected by the
+// ReportSweeper.
 func (s *ReportService) DeleteReport(reportID, organizationID uuid.UUID) error {
-	// Get report
 	report, err := s.GetReport(reportID, organizationID)
 	if err != nil {
 		return err
 	}
 
-	// Delete file
+	if err := s.reportRepo.Delete(report.ID); err != nil {
+		if errors.Is(err, repository.ErrReportNotFound) {
+			return ErrReportNotFound
+		}
+		return err
+	}
+
+	// An already-missing file means an earlier attempt got this far
 	if err := os.Remove(report.FilePath); err != nil && !os.IsNotExist(err) {
-		// Log error but continue with database deletion
-		fmt.Printf("Failed to delete report file: %v\n", err)
+		slog.Error("Failed to delete report file", "report_id", report.ID, "path", report.FilePath, "error", err)
 	}
 
-	// Delete from database
-	return s.reportRepo.Delete(reportID)
+	return nil
 }
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+)
+
+// reportFixture returns a completed scan with a headers and an ssl result
+func reportFixture() (*models.ScanJob, []*models.ScanResult) {
+	now := time.Now()
+	url := "https://example.com"
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		URL:            &url,
+		OrganizationID: uuid.New(),
+		Status:         models.ScanStatusCompleted,
+		Progress:       100,
+		Checks:         []string{"headers", "ssl"},
+		StartedAt:      &now,
+		CompletedAt:    &now,
+		CreatedAt:      now,
+	}
+	results := []*models.ScanResult{
+		{
+			ID:        uuid.New(),
+			ScanID:    scan.ID,
+			CheckType: "headers",
+			Status:    "success",
+			Data:      json.RawMessage(`{"missing_headers":["Content-Security-Policy","X-Frame-Options"],"server":"nginx"}`),
+			Findings:  2,
+			Severity:  models.SeverityLow,
+			CreatedAt: now,
+		},
+		{
+			ID:        uuid.New(),
+			ScanID:    scan.ID,
+			CheckType: "ssl",
+			Status:    "success",
+			Data:      json.RawMessage(`{"certificate":{"issuer":"CN=\"Example CA\", O=Example"},"issues":[]}`),
+			Severity:  models.SeverityInfo,
+			CreatedAt: now,
+		},
+	}
+	return scan, results
+}
+
+func TestGeneratePDFReport(t *testing.T) {
+	service := &ReportService{storagePath: t.TempDir()}
+	scan, results := reportFixture()
+
+	path, size, err := service.generatePDFReport(scan, results, (&models.ReportBranding{}).Effective())
+	if err != nil {
+		t.Fatalf("generatePDFReport: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size == 0 || int64(len(content)) != size {
+		t.Errorf("size = %d, file has %d bytes", size, len(content))
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF")) {
+		t.Errorf("file starts with %q, want %%PDF", content[:min(len(content), 8)])
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	scan, results := reportFixture()
+
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf, scan, results, (&models.ReportBranding{}).Effective()); err != nil {
+		t.Fatalf("writeHTMLReport: %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{scan.ID.String(), "<td>headers</td>", "<td>ssl</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
+
+func TestWriteCSVReportRoundTripsDetails(t *testing.T) {
+	_, results := reportFixture()
+	results[1].Data = json.RawMessage(`{
+		"certificate": {"issuer": "CN=\"Example CA\", O=Example", "san": ["a.example.com", "b.example.com"]},
+		"note": "line one\nline two"
+	}`)
+
+	var buf bytes.Buffer
+	if err := writeCSVReport(&buf, results); err != nil {
+		t.Fatalf("writeCSVReport: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading the CSV back: %v", err)
+	}
+	if len(records) != len(results)+1 {
+		t.Fatalf("%d records, want a header and %d rows", len(records), len(results))
+	}
+
+	for i, result := range results {
+		details := records[i+1][5]
+		var got, want interface{}
+		if err := json.Unmarshal([]byte(details), &got); err != nil {
+			t.Fatalf("row %d Details %q is not JSON: %v", i, details, err)
+		}
+		if err := json.Unmarshal(result.Data, &want); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d Details = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestWriteJSONReportKeepsSchemaVersion(t *testing.T) {
+	scan, results := reportFixture()
+	// Fields the API added to results after the report schema was frozen
+	original := models.SeverityHigh
+	rule := uuid.New()
+	results[0].FindingsBySeverity = &models.SeverityCounts{Low: 2}
+	results[0].OriginalSeverity = &original
+	results[0].OriginalFindingsBySeverity = &models.SeverityCounts{High: 2}
+	results[0].TriageStatus = "accepted_risk"
+	results[0].AcknowledgedByRule = &rule
+
+	var buf bytes.Buffer
+	if err := writeJSONReport(&buf, scan, results); err != nil {
+		t.Fatalf("writeJSONReport: %v", err)
+	}
+
+	var document struct {
+		SchemaVersion string                   `json:"schema_version"`
+		Results       []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing these keys means bumping models.ReportSchemaVersion
+	want := []string{"check_type", "created_at", "data", "findings", "id", "scan_id", "severity", "status"}
+	if document.SchemaVersion != "1.0" || len(document.Results) != len(results) {
+		t.Fatalf("schema %s with %d results", document.SchemaVersion, len(document.Results))
+	}
+	for i, result := range document.Results {
+		keys := make([]string, 0, len(result))
+		for key := range result {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("result %d has keys %v, want the schema 1.0 keys %v", i, keys, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"publicscannerapi/internal/repository"
+)
+
+// orphanReportGrace is how old a report file must be before the sweeper may
+// remove it. Reports are written before their row is inserted, so younger
+// files may belong to a report still being generated.
+const orphanReportGrace = time.Hour
+
+// ReportSweeper removes report files that no report row references, such as
+// files left behind when a deletion failed after removing the row
+type ReportSweeper struct {
+	reportRepo *repository.ReportRepository
+	reportDir  string
+}
+
+// NewReportSweeper creates a new sweeper for the reports under storagePath
+func NewReportSweeper(reportRepo *repository.ReportRepository, storagePath string) *ReportSweeper {
+	return &ReportSweeper{
+		reportRepo: reportRepo,
+		reportDir:  filepath.Join(storagePath, "reports"),
+	}
+}
+
+// Run sweeps orphaned report files every interval until ctx is cancelled
+func (s *ReportSweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.sweep(); err != nil {
+				slog.Error("Report sweeper failed", "error", err)
+			}
+		}
+	}
+}
+
+// sweep removes report files older than the grace period that no report
+// references. Reports are written straight into the reports directory, so
+// files are matched to rows by name: the stored paths may spell the storage
+// path differently, e.g. relative before a deployment and absolute after.
+// Candidates are collected before the rows are read, so a file whose row
+// appears in between is kept.
+func (s *ReportSweeper) sweep() error {
+	cutoff := time.Now().Add(-orphanReportGrace)
+
+	entries, err := os.ReadDir(s.reportDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			candidates = append(candidates, entry.Name())
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	paths, err := s.reportRepo.ListFilePaths()
+	if err != nil {
+		return err
+	}
+	referenced := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		referenced[filepath.Base(path)] = true
+	}
+
+	removed := 0
+	for _, name := range candidates {
+		if referenced[name] {
+			continue
+		}
+		path := filepath.Join(s.reportDir, name)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			slog.Error("Report sweeper failed to delete file", "path", path, "error", err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		slog.Info("Report sweeper removed orphaned report files", "count", removed)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"publicscannerapi/internal/repository"
+)
+
+func TestReportSweeperMatchesFilesByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	storage := t.TempDir()
+	reportDir := filepath.Join(storage, "reports")
+	if err := os.MkdirAll(filepath.Join(reportDir, "archive"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * orphanReportGrace)
+	write := func(name string, modTime time.Time) string {
+		path := filepath.Join(reportDir, name)
+		if err := os.WriteFile(path, []byte("report"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	referenced := write("scan_a_20260301_120000.pdf", old)
+	orphan := write("scan_b_20260301_120000.pdf", old)
+	young := write("scan_c_20260301_120000.pdf", time.Now())
+	nested := write(filepath.Join("archive", "scan_d_20260301_120000.pdf"), old)
+
+	// The row was stored while the storage path was spelled differently
+	mock.ExpectQuery(`SELECT file_path FROM reports`).
+		WillReturnRows(sqlmock.NewRows([]string{"file_path"}).AddRow("./storage/reports/scan_a_20260301_120000.pdf"))
+
+	// The sweeper is given the same directory by another path
+	sweeper := NewReportSweeper(repository.NewReportRepository(db), storage+string(filepath.Separator)+".")
+	if err := sweeper.sweep(); err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+
+	for path, kept := range map[string]bool{referenced: true, orphan: false, young: true, nested: true} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("%s: kept = %v, want %v", filepath.Base(path), err == nil, kept)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/storage"
+)
+
+// ScanPurger permanently removes soft-deleted scans once their retention
+// window has passed, together with their results, reports and attachments
+type ScanPurger struct {
+	scanRepo  *repository.ScanRepository
+	storage   storage.Storage
+	retention time.Duration
+	// statementTimeout replaces the database's default statement limit
+	// while purging
+	statementTimeout time.Duration
+}
+
+// NewScanPurger creates a new purger for soft-deleted scans
+func NewScanPurger(scanRepo *repository.ScanRepository, store storage.Storage, retention, statementTimeout time.Duration) *ScanPurger {
+	return &ScanPurger{
+		scanRepo:         scanRepo,
+		storage:          store,
+		retention:        retention,
+		statementTimeout: statementTimeout,
+	}
+}
+
+// Run purges expired scans every interval until ctx is cancelled
+func (p *ScanPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.purge(); err != nil {
+				slog.Error("Scan purger failed", "error", err)
+			}
+		}
+	}
+}
+
+// purge removes scans deleted before the retention cutoff and then the
+// files they referenced. Files are removed only after the rows are gone, so
+// a failure can at worst leave an orphaned file, never a dangling row.
+func (p *ScanPurger) purge() error {
+	purged, err := p.scanRepo.PurgeDeleted(time.Now().Add(-p.retention), p.statementTimeout)
+	if err != nil {
+		return err
+	}
+	if purged.Count == 0 {
+		return nil
+	}
+
+	for _, path := range purged.ReportFiles {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			slog.Error("Scan purger failed to delete report file", "path", path, "error", err)
+		}
+	}
+	for _, key := range purged.AttachmentKeys {
+		if err := p.storage.Delete(key); err != nil {
+			slog.Error("Scan purger failed to delete attachment", "storage_key", key, "error", err)
+		}
+	}
+
+	slog.Info("Scan purger purged deleted scans", "count", purged.Count)
+	return nil
+}
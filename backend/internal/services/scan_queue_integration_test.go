@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"publicscannerapi/internal/models"
+)
+
+// redisTestDB keeps the test's queue apart from the one workers consume
+const redisTestDB = 15
+
+// TestQueueScanRedis pushes a scan task to a real Redis at REDIS_ADDR
+// (host:port) and reads it back off the Celery list
+func TestQueueScanRedis(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set")
+	}
+
+	ctx := context.Background()
+	rdb := redis.NewClient(&redis.Options{Addr: addr, DB: redisTestDB})
+	defer rdb.Close()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatalf("Redis at %s: %v", addr, err)
+	}
+	if err := rdb.Del(ctx, CeleryQueue).Err(); err != nil {
+		t.Fatal(err)
+	}
+	defer rdb.Del(ctx, CeleryQueue)
+
+	service, _ := newTestScanService(t)
+	service.rdb = rdb
+	scan := &models.ScanJob{
+		ID:     uuid.New(),
+		Checks: []string{"headers"},
+	}
+	if err := service.queueScan(scan, "https://example.com"); err != nil {
+		t.Fatalf("queueScan: %v", err)
+	}
+
+	message, err := rdb.RPop(ctx, CeleryQueue).Result()
+	if err != nil {
+		t.Fatalf("reading the task back: %v", err)
+	}
+	var envelope struct {
+		Body       string `json:"body"`
+		Properties struct {
+			DeliveryInfo struct {
+				RoutingKey string `json:"routing_key"`
+			} `json:"delivery_info"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(message), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	var task struct {
+		Task string        `json:"task"`
+		Args []interface{} `json:"args"`
+	}
+	if err := json.Unmarshal([]byte(envelope.Body), &task); err != nil {
+		t.Fatal(err)
+	}
+
+	if task.Task != "tasks.execute_scan" || envelope.Properties.DeliveryInfo.RoutingKey != CeleryQueue {
+		t.Errorf("task %q routed to %q", task.Task, envelope.Properties.DeliveryInfo.RoutingKey)
+	}
+	if len(task.Args) != 4 || task.Args[0] != scan.ID.String() || task.Args[1] != "https://example.com" {
+		t.Errorf("args = %v", task.Args)
+	}
+}
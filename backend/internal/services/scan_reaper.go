@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"publicscannerapi/internal/metrics"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+// ScanReaper fails queued and running scans whose worker stopped reporting,
+// so a dead worker cannot leave a scan active forever
+type ScanReaper struct {
+	scanRepo *repository.ScanRepository
+	webhooks *WebhookService // notified of reaped scans; nil disables webhooks
+	// timeout is how long an active scan may go without an update. A
+	// scan's own config timeout can extend it but never shorten it.
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// NewScanReaper creates a new reaper for stale scans that reads the time
+// from now
+func NewScanReaper(scanRepo *repository.ScanRepository, webhooks *WebhookService, timeout time.Duration, now func() time.Time) *ScanReaper {
+	return &ScanReaper{
+		scanRepo: scanRepo,
+		webhooks: webhooks,
+		timeout:  timeout,
+		now:      now,
+	}
+}
+
+// Run fails stale scans every interval until ctx is cancelled
+func (r *ScanReaper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reap(); err != nil {
+				slog.Error("Scan reaper failed", "error", err)
+			}
+		}
+	}
+}
+
+// scanTimeout returns how long scan may go without an update
+func (r *ScanReaper) scanTimeout(scan *models.ScanJob) time.Duration {
+	if configured := time.Duration(scan.Config.Timeout) * time.Second; configured > r.timeout {
+		return configured
+	}
+	return r.timeout
+}
+
+// reap fails every active scan whose last update is older than its timeout.
+// Scans that finished in the meantime are left alone.
+func (r *ScanReaper) reap() error {
+	now := r.now()
+	scans, err := r.scanRepo.ListStale(now.Add(-r.timeout))
+	if err != nil {
+		return err
+	}
+
+	reaped := 0
+	for _, scan := range scans {
+		timeout := r.scanTimeout(scan)
+		if now.Sub(scan.UpdatedAt) < timeout {
+			continue
+		}
+
+		failure := &models.ScanFailure{
+			Code:   models.FailureTimeout,
+			Reason: fmt.Sprintf("no worker update for %s while %s", timeout, scan.Status),
+		}
+		if err := r.scanRepo.Transition(scan.ID, models.ScanStatusFailed, failure); err != nil {
+			if errors.Is(err, repository.ErrScanNotActive) {
+				continue
+			}
+			return err
+		}
+		metrics.ScanFailed(scan.Checks)
+		if r.webhooks != nil {
+			r.webhooks.ScanFinished(scan, models.ScanStatusFailed, failure)
+		}
+		reaped++
+	}
+
+	if reaped > 0 {
+		slog.Info("Scan reaper failed stale scans", "count", reaped)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+)
+
+func TestScanReaperFailsStaleScans(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reaper := NewScanReaper(repository.NewScanRepository(db), nil, 10*time.Minute, func() time.Time { return now })
+
+	stale := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(), Status: models.ScanStatusRunning}
+	// A long config timeout keeps a quiet scan alive past the default
+	slow := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(), Status: models.ScanStatusRunning,
+		Config: models.ScanConfig{Timeout: 3600}}
+	// Finished between the listing and the transition
+	finished := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(), Status: models.ScanStatusQueued}
+
+	rows := sqlmock.NewRows(repositorytest.ScanJobColumns)
+	for _, scan := range []*models.ScanJob{stale, slow, finished} {
+		scan.UpdatedAt = now.Add(-20 * time.Minute)
+		rows.AddRow(repositorytest.ScanJobRow(scan)...)
+	}
+	mock.ExpectQuery(`updated_at < \$1`).WithArgs(now.Add(-10 * time.Minute)).WillReturnRows(rows)
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = \$2::text`).
+		WithArgs(stale.ID, string(models.ScanStatusFailed), string(models.FailureTimeout), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = \$2::text`).
+		WithArgs(finished.ID, string(models.ScanStatusFailed), string(models.FailureTimeout), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := reaper.reap(); err != nil {
+		t.Fatalf("reap: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanReaperCutoffFollowsClock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reaper := NewScanReaper(repository.NewScanRepository(db), nil, 10*time.Minute, func() time.Time { return now })
+
+	mock.ExpectQuery(`updated_at < \$1`).WithArgs(now.Add(-10 * time.Minute)).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns))
+	mock.ExpectQuery(`updated_at < \$1`).WithArgs(now.Add(50 * time.Minute)).
+		WillReturnError(sql.ErrConnDone)
+
+	if err := reaper.reap(); err != nil {
+		t.Fatalf("reap: %v", err)
+	}
+
+	// Moving the clock moves the cutoff with it
+	now = now.Add(time.Hour)
+	if err := reaper.reap(); err != sql.ErrConnDone {
+		t.Fatalf("reap error = %v, want the query error", err)
+	}
+}
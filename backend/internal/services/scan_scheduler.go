@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ScanScheduler queues one-time scheduled scans once their run_at time
+// arrives, and starts the periodic scans of monitored targets and recurring
+// scheduled scans
+type ScanScheduler struct {
+	scanService     *ScanService
+	scheduleService *ScheduleService
+}
+
+// NewScanScheduler creates a new scheduler for scheduled scans
+func NewScanScheduler(scanService *ScanService, scheduleService *ScheduleService) *ScanScheduler {
+	return &ScanScheduler{
+		scanService:     scanService,
+		scheduleService: scheduleService,
+	}
+}
+
+// Run queues due scans every interval until ctx is cancelled
+func (s *ScanScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(time.Now())
+		}
+	}
+}
+
+// tick queues the scheduled scans and starts the monitor and recurring scans
+// due at now
+func (s *ScanScheduler) tick(now time.Time) {
+	queued, err := s.scanService.EnqueueDueScans(now)
+	if err != nil {
+		slog.Error("Scan scheduler failed", "error", err)
+	} else if queued > 0 {
+		slog.Info("Scan scheduler queued scheduled scans", "count", queued)
+	}
+
+	monitored, err := s.scanService.StartDueMonitorScans(now)
+	if err != nil {
+		slog.Error("Scan scheduler failed", "error", err)
+	} else if monitored > 0 {
+		slog.Info("Scan scheduler started monitor scans", "targets", monitored)
+	}
+
+	recurring, err := s.scheduleService.StartDueSchedules(now)
+	if err != nil {
+		slog.Error("Scan scheduler failed", "error", err)
+	} else if recurring > 0 {
+		slog.Info("Scan scheduler started recurring scans", "count", recurring)
+	}
+}
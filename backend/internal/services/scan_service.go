@@ -1,33 +1,73 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/metrics"
 	"publicscannerapi/internal/models"
 	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
 )
 
 var (
-	ErrTargetNotFound = errors.New("target not found")
-	ErrScanNotFound   = errors.New("scan not found")
+	ErrTargetNotFound    = errors.New("target not found")
+	ErrScanNotFound      = errors.New("scan not found")
+	ErrInvalidScanConfig = errors.New("invalid scan configuration")
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrScanNotEditable   = errors.New("scan can only be edited while scheduled or queued")
+	ErrScanFinished      = errors.New("scan has already finished")
+	ErrInvalidScanResult = errors.New("invalid scan result")
+	ErrScanNotResumable  = errors.New("only failed scans with unfinished checks can be resumed")
+	ErrScanTargetsDiffer = errors.New("scans are of different targets")
+	ErrNoClientCert      = errors.New("scan has no client certificate")
+	ErrNoWordlist        = errors.New("scan has no wordlist")
 )
 
+// requeueStuckAfter is how long a scan must sit in queued before a bulk
+// requeue considers it stuck rather than waiting for a worker
+const requeueStuckAfter = 10 * time.Minute
+
+// queuePushTimeout bounds pushing one task onto the Redis queue
+const queuePushTimeout = 5 * time.Second
+
 // ScanService handles scan business logic
 type ScanService struct {
-	scanRepo   *repository.ScanRepository
-	targetRepo *repository.TargetRepository
-	redisURL   string
+	scanRepo     *repository.ScanRepository
+	targetRepo   *repository.TargetRepository
+	certService  *CertificateService
+	overrideRepo *repository.SeverityOverrideRepository
+	wordlistRepo *repository.WordlistRepository
+	campaignRepo *repository.CampaignRepository
+	webhooks     *WebhookService // notified when scans finish; nil disables webhooks
+	rdb          *redis.Client
+	addresses    AddressPolicy
+	workerSecret string
+	retention    time.Duration // how long deleted scans can be restored
 }
 
 // NewScanService creates a new scan service
-func NewScanService(scanRepo *repository.ScanRepository, targetRepo *repository.TargetRepository, redisURL string) *ScanService {
+func NewScanService(scanRepo *repository.ScanRepository, targetRepo *repository.TargetRepository, certService *CertificateService, overrideRepo *repository.SeverityOverrideRepository, wordlistRepo *repository.WordlistRepository, campaignRepo *repository.CampaignRepository, webhooks *WebhookService, rdb *redis.Client, addresses AddressPolicy, workerSecret string, retention time.Duration) *ScanService {
 	return &ScanService{
-		scanRepo:   scanRepo,
-		targetRepo: targetRepo,
-		redisURL:   redisURL,
+		scanRepo:     scanRepo,
+		targetRepo:   targetRepo,
+		certService:  certService,
+		overrideRepo: overrideRepo,
+		wordlistRepo: wordlistRepo,
+		campaignRepo: campaignRepo,
+		webhooks:     webhooks,
+		rdb:          rdb,
+		addresses:    addresses,
+		workerSecret: workerSecret,
+		retention:    retention,
 	}
 }
 
@@ -35,18 +75,388 @@ func NewScanService(scanRepo *repository.ScanRepository, targetRepo *repository.
 type CreateScanRequest struct {
 	TargetID *uuid.UUID        `json:"target_id,omitempty"` // Optional: for saved target
 	URL      *string           `json:"url,omitempty"`       // Optional: for quick scan
+	URLs     []string          `json:"urls,omitempty"`      // Optional: quick scan of several URLs, one scan each
+	Checks   []string          `json:"checks" binding:"required"`
+	Config   models.ScanConfig `json:"config"`
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata json.RawMessage   `json:"metadata,omitempty"` // Free-form JSON object
+	// Sync runs a quick scan of lightweight checks inline and returns its
+	// results in the response, falling back to the queue on timeout
+	Sync bool `json:"sync,omitempty"`
+	// RunAt schedules a one-time scan: it is stored as scheduled and only
+	// queued once this time arrives
+	RunAt *time.Time `json:"run_at,omitempty"`
+	// CampaignID attaches the scan to one of the organization's campaigns
+	CampaignID *uuid.UUID `json:"campaign_id,omitempty"`
+}
+
+// MaxScheduleAhead bounds how far in the future a one-time scan may be scheduled
+const MaxScheduleAhead = 90 * 24 * time.Hour
+
+// UpdateScanRequest represents edits to a queued scan. Omitted fields are left unchanged.
+type UpdateScanRequest struct {
+	Checks   []string           `json:"checks"`
+	Config   *models.ScanConfig `json:"config"`
+	Tags     []string           `json:"tags"`
+	Metadata json.RawMessage    `json:"metadata"`
+}
+
+// MaxQuickScanURLs is the most URLs a single multi-URL quick scan may expand to
+const MaxQuickScanURLs = 25
+
+// CreateScans creates one scan per URL of a multi-URL quick scan (url plus
+// urls, deduplicated). Every URL is validated before any scan is created.
+func (s *ScanService) CreateScans(req *CreateScanRequest, userID, organizationID uuid.UUID) ([]*models.ScanJob, error) {
+	if req.TargetID != nil {
+		return nil, fmt.Errorf("%w: urls cannot be combined with target_id", ErrInvalidScanConfig)
+	}
+
+	candidates := req.URLs
+	if req.URL != nil {
+		candidates = append([]string{*req.URL}, candidates...)
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for i, candidate := range candidates {
+		normalized, err := s.addresses.normalizeURL(strings.TrimSpace(candidate))
+		if err != nil {
+			return nil, fmt.Errorf("%w: urls[%d]: %v", ErrInvalidScanConfig, i, err)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			urls = append(urls, normalized)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%w: at least one url is required", ErrInvalidScanConfig)
+	}
+	if len(urls) > MaxQuickScanURLs {
+		return nil, fmt.Errorf("%w: at most %d urls can be scanned at once", ErrInvalidScanConfig, MaxQuickScanURLs)
+	}
+
+	scans := make([]*models.ScanJob, 0, len(urls))
+	for _, scanURL := range urls {
+		single := *req
+		single.URL = &scanURL
+		single.URLs = nil
+
+		scan, err := s.CreateScan(&single, userID, organizationID)
+		if err != nil {
+			return scans, err
+		}
+		scans = append(scans, scan)
+	}
+
+	return scans, nil
+}
+
+// MaxTagScanTargets is the most targets a single tag-based scan may cover
+const MaxTagScanTargets = 100
+
+// ScanByTagRequest represents a scan of every active target carrying Tag,
+// one scan per target. Tags labels the created scans.
+type ScanByTagRequest struct {
+	Tag      string            `json:"tag" binding:"required"`
 	Checks   []string          `json:"checks" binding:"required"`
 	Config   models.ScanConfig `json:"config"`
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata json.RawMessage   `json:"metadata,omitempty"`
+	RunAt    *time.Time        `json:"run_at,omitempty"`
+	// CampaignID attaches every created scan to a campaign
+	CampaignID *uuid.UUID `json:"campaign_id,omitempty"`
+}
+
+// TargetsForTag returns the active targets a tag-based scan of tag would
+// cover, without creating anything
+func (s *ScanService) TargetsForTag(organizationID uuid.UUID, tag string) ([]*models.Target, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return nil, fmt.Errorf("%w: tag is required", ErrInvalidScanConfig)
+	}
+
+	targets, err := s.targetRepo.ListActiveByTag(organizationID, tag)
+	if err != nil {
+		return nil, err
+	}
+	if targets == nil {
+		targets = []*models.Target{}
+	}
+
+	return targets, nil
+}
+
+// CreateScansByTag creates one scan for every active target carrying the
+// request's tag. Scans created before a failure are returned with the error.
+func (s *ScanService) CreateScansByTag(req *ScanByTagRequest, userID, organizationID uuid.UUID) ([]*models.ScanJob, error) {
+	targets, err := s.TargetsForTag(organizationID, req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: no active targets are tagged %q", ErrInvalidScanConfig, strings.TrimSpace(req.Tag))
+	}
+	if len(targets) > MaxTagScanTargets {
+		return nil, fmt.Errorf("%w: tag covers %d targets, at most %d can be scanned at once", ErrInvalidScanConfig, len(targets), MaxTagScanTargets)
+	}
+
+	scans := make([]*models.ScanJob, 0, len(targets))
+	for _, target := range targets {
+		targetID := target.ID
+		single := &CreateScanRequest{
+			TargetID:   &targetID,
+			Checks:     req.Checks,
+			Config:     req.Config,
+			Tags:       req.Tags,
+			Metadata:   req.Metadata,
+			RunAt:      req.RunAt,
+			CampaignID: req.CampaignID,
+		}
+
+		scan, err := s.CreateScan(single, userID, organizationID)
+		if err != nil {
+			return scans, err
+		}
+		scans = append(scans, scan)
+	}
+
+	return scans, nil
+}
+
+// MaxBulkScans is the most targets and URLs a single bulk scan request may list
+const MaxBulkScans = 100
+
+// BulkScanRequest represents one scan per listed target and URL, all with
+// the same checks and settings
+type BulkScanRequest struct {
+	TargetIDs  []uuid.UUID       `json:"target_ids"`
+	URLs       []string          `json:"urls"`
+	Checks     []string          `json:"checks" binding:"required"`
+	Config     models.ScanConfig `json:"config"`
+	Tags       []string          `json:"tags,omitempty"`
+	Metadata   json.RawMessage   `json:"metadata,omitempty"`
+	RunAt      *time.Time        `json:"run_at,omitempty"`
+	CampaignID *uuid.UUID        `json:"campaign_id,omitempty"`
+}
+
+// BulkScanItem is the outcome for one target or URL of a bulk scan: the
+// created scan, or why none was created. A scan that was stored but could
+// not be queued is returned failed, with Error set.
+type BulkScanItem struct {
+	TargetID *uuid.UUID      `json:"target_id,omitempty"`
+	URL      *string         `json:"url,omitempty"`
+	Scan     *models.ScanJob `json:"scan,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// BulkScanResult lists the outcome of a bulk scan per target and URL, in
+// request order, targets first
+type BulkScanResult struct {
+	Items   []*BulkScanItem `json:"items"`
+	Created int             `json:"created"`
+	Failed  int             `json:"failed"`
+}
+
+// CreateScanBatch creates one scan per target and URL of req. The shared
+// checks and settings are validated first and an invalid one fails the
+// whole request with ErrInvalidScanConfig. After that a target that is
+// missing, or a URL that is not allowed, only fails its own item. The scans
+// of the other items are stored in one transaction and then queued.
+func (s *ScanService) CreateScanBatch(req *BulkScanRequest, userID, organizationID uuid.UUID) (*BulkScanResult, error) {
+	total := len(req.TargetIDs) + len(req.URLs)
+	if total == 0 {
+		return nil, fmt.Errorf("%w: at least one target_id or url is required", ErrInvalidScanConfig)
+	}
+	if total > MaxBulkScans {
+		return nil, fmt.Errorf("%w: at most %d targets and urls can be scanned at once", ErrInvalidScanConfig, MaxBulkScans)
+	}
+
+	shared := &CreateScanRequest{
+		Checks:     req.Checks,
+		Config:     req.Config,
+		Tags:       req.Tags,
+		Metadata:   req.Metadata,
+		RunAt:      req.RunAt,
+		CampaignID: req.CampaignID,
+	}
+	if err := s.checkScanSettings(shared, organizationID); err != nil {
+		return nil, err
+	}
+
+	items := make([]*BulkScanItem, 0, total)
+	for i := range req.TargetIDs {
+		items = append(items, &BulkScanItem{TargetID: &req.TargetIDs[i]})
+	}
+	for i := range req.URLs {
+		items = append(items, &BulkScanItem{URL: &req.URLs[i]})
+	}
+
+	// Resolve every item before storing anything
+	var scans []*models.ScanJob
+	targetURLs := make(map[uuid.UUID]string, total)
+	itemsByScan := make(map[uuid.UUID]*BulkScanItem, total)
+	seen := make(map[string]bool, total)
+	for _, item := range items {
+		scan := newScanJob(shared, userID, organizationID)
+		targetURL, err := s.setScanTarget(scan, item.TargetID, item.URL)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTargetNotFound):
+				item.Error = "target not found"
+			case errors.Is(err, ErrInvalidScanConfig):
+				item.Error = err.Error()
+			default:
+				return nil, err
+			}
+			continue
+		}
+
+		key := targetURL
+		if scan.TargetID != nil {
+			key = scan.TargetID.String()
+		}
+		if seen[key] {
+			item.Error = "duplicate of an earlier target or url"
+			continue
+		}
+		seen[key] = true
+
+		scans = append(scans, scan)
+		targetURLs[scan.ID] = targetURL
+		itemsByScan[scan.ID] = item
+	}
+
+	if len(scans) > 0 {
+		if err := s.scanRepo.CreateBatch(scans); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, scan := range scans {
+		metrics.ScanCreated(scan.Checks)
+		item := itemsByScan[scan.ID]
+		item.Scan = scan
+		if scan.Status == models.ScanStatusScheduled {
+			continue
+		}
+		if err := s.enqueue(scan, targetURLs[scan.ID]); err != nil {
+			slog.Error("Failed to queue bulk scan", "scan_id", scan.ID, "error", err)
+			scan.Status = models.ScanStatusFailed
+			item.Error = "failed to queue scan"
+		}
+	}
+
+	result := &BulkScanResult{Items: items}
+	for _, item := range items {
+		if item.Error == "" {
+			result.Created++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
 }
 
-// CreateScan creates and queues a new scan
+// CreateScan creates and queues a new scan. A scan with a run_at time is
+// only stored; the ScanScheduler queues it when the time arrives.
 func (s *ScanService) CreateScan(req *CreateScanRequest, userID, organizationID uuid.UUID) (*models.ScanJob, error) {
+	scan, targetURL, err := s.createScanRecord(req, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if scan.Status == models.ScanStatusScheduled {
+		return scan, nil
+	}
+
+	if err := s.enqueue(scan, targetURL); err != nil {
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// enqueue hands a stored scan to the workers, failing the scan if that is
+// not possible
+func (s *ScanService) enqueue(scan *models.ScanJob, targetURL string) error {
+	if err := s.queueScan(scan, targetURL); err != nil {
+		// Mark scan as failed if queuing fails
+		s.failDispatch(scan, err)
+		return fmt.Errorf("failed to queue scan: %w", err)
+	}
+	return nil
+}
+
+// failDispatch fails a stored scan that could not be handed to the workers
+func (s *ScanService) failDispatch(scan *models.ScanJob, err error) {
+	failure := dispatchFailure(err)
+	if s.scanRepo.Fail(scan.ID, failure) == nil {
+		metrics.ScanFailed(scan.Checks)
+		s.scanFinished(scan, models.ScanStatusFailed, &failure)
+	}
+}
+
+// dispatchFailure classifies an error that kept a stored scan from reaching
+// the workers: a scan whose target is gone cannot run, anything else is an
+// infrastructure failure
+func dispatchFailure(err error) models.ScanFailure {
+	if errors.Is(err, ErrTargetNotFound) || errors.Is(err, repository.ErrTargetNotFound) {
+		return models.ScanFailure{Code: models.FailureInvalidTarget, Reason: "target no longer exists"}
+	}
+	return models.ScanFailure{Code: models.FailureWorkerError, Reason: fmt.Sprintf("failed to queue scan: %v", err)}
+}
+
+// createScanRecord validates a scan request and stores the queued scan,
+// returning it with the address the checks run against
+func (s *ScanService) createScanRecord(req *CreateScanRequest, userID, organizationID uuid.UUID) (*models.ScanJob, string, error) {
 	// Validate that at least one of target_id or URL is provided
 	if req.TargetID == nil && req.URL == nil {
-		return nil, errors.New("either target_id or url must be provided")
+		return nil, "", errors.New("either target_id or url must be provided")
 	}
 
-	var targetURL string
+	if err := s.checkScanSettings(req, organizationID); err != nil {
+		return nil, "", err
+	}
+
+	scan := newScanJob(req, userID, organizationID)
+	targetURL, err := s.setScanTarget(scan, req.TargetID, req.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Save to database
+	if err := s.scanRepo.Create(scan); err != nil {
+		return nil, "", err
+	}
+	metrics.ScanCreated(scan.Checks)
+
+	return scan, targetURL, nil
+}
+
+// checkScanSettings validates everything of a scan request but its target
+func (s *ScanService) checkScanSettings(req *CreateScanRequest, organizationID uuid.UUID) error {
+	if err := validateScanSettings(req.Checks, req.Config, req.Metadata); err != nil {
+		return err
+	}
+	if err := s.validateConfigReferences(req.Config, organizationID); err != nil {
+		return err
+	}
+	if problem := runAtProblem(req.RunAt, time.Now()); problem != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidScanConfig, problem)
+	}
+	problem, err := s.campaignProblem(req.CampaignID, organizationID)
+	if err != nil {
+		return err
+	}
+	if problem != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidScanConfig, problem)
+	}
+	return nil
+}
+
+// newScanJob builds the queued, or with run_at scheduled, scan of a request
+// without its target
+func newScanJob(req *CreateScanRequest, userID, organizationID uuid.UUID) *models.ScanJob {
 	scan := &models.ScanJob{
 		ID:             uuid.New(),
 		OrganizationID: organizationID,
@@ -55,142 +465,1423 @@ func (s *ScanService) CreateScan(req *CreateScanRequest, userID, organizationID
 		Progress:       0,
 		Checks:         req.Checks,
 		Config:         req.Config,
+		Tags:           req.Tags,
+		Metadata:       req.Metadata,
+		CampaignID:     req.CampaignID,
+	}
+	if req.RunAt != nil {
+		runAt := req.RunAt.UTC()
+		scan.Status = models.ScanStatusScheduled
+		scan.RunAt = &runAt
 	}
+	return scan
+}
+
+// setScanTarget points scan at a saved target of its organization or at a
+// quick scan URL and returns the address the checks run against
+func (s *ScanService) setScanTarget(scan *models.ScanJob, targetID *uuid.UUID, rawURL *string) (string, error) {
+	var targetURL string
 
 	// Handle target-based scan
-	if req.TargetID != nil {
-		target, err := s.targetRepo.GetByID(*req.TargetID)
+	if targetID != nil {
+		target, err := s.targetRepo.GetByID(*targetID)
 		if err != nil {
 			if errors.Is(err, repository.ErrTargetNotFound) {
-				return nil, ErrTargetNotFound
+				return "", ErrTargetNotFound
 			}
-			return nil, err
+			return "", err
 		}
 
 		// Verify target belongs to organization
-		if target.OrganizationID != organizationID {
-			return nil, errors.New("target not found in organization")
+		if target.OrganizationID != scan.OrganizationID {
+			return "", ErrTargetNotFound
+		}
+
+		// Targets saved before address validation, or while private
+		// targets were allowed, are checked again here
+		if _, err := s.addresses.normalizeHostname(target.Hostname); err != nil {
+			return "", fmt.Errorf("%w: target hostname %v", ErrInvalidScanConfig, err)
 		}
 
-		scan.TargetID = req.TargetID
+		id := *targetID
+		scan.TargetID = &id
 		targetURL = target.Hostname
 	}
 
 	// Handle URL-based quick scan
-	if req.URL != nil {
-		scan.URL = req.URL
-		targetURL = *req.URL
+	if rawURL != nil {
+		normalized, err := s.addresses.normalizeURL(strings.TrimSpace(*rawURL))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidScanConfig, err)
+		}
+		scan.URL = &normalized
+		targetURL = normalized
 	}
 
-	// Save to database
-	if err := s.scanRepo.Create(scan); err != nil {
+	return targetURL, nil
+}
+
+// SyncScanResult is the outcome of a synchronous quick scan. Mode is "sync"
+// when the checks finished inline and "async" when they exceeded the
+// timeout and the scan was queued instead.
+type SyncScanResult struct {
+	Mode    string               `json:"mode"`
+	Scan    *models.ScanJob      `json:"scan"`
+	Results []*models.ScanResult `json:"results,omitempty"`
+}
+
+// CreateSyncScan runs a quick scan of sync-capable checks inline, within
+// SyncScanTimeout. The scan is stored and recorded like a worker-run scan;
+// if the checks do not finish in time, or ctx ends first because the client
+// went away, it is queued for the workers instead.
+func (s *ScanService) CreateSyncScan(ctx context.Context, req *CreateScanRequest, userID, organizationID uuid.UUID) (*SyncScanResult, error) {
+	if problems := s.syncScanProblems(req); len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidScanConfig, problems[0].Message)
+	}
+
+	scan, targetURL, err := s.createScanRecord(req, userID, organizationID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Queue scan with Celery
-	if err := s.queueScan(scan.ID.String(), targetURL, req.Checks, req.Config); err != nil {
-		// Mark scan as failed if queuing fails
-		_ = s.scanRepo.Fail(scan.ID)
-		return nil, fmt.Errorf("failed to queue scan: %w", err)
+	timeout := SyncScanTimeout
+	if configured := time.Duration(scan.Config.Timeout) * time.Second; configured > 0 && configured < timeout {
+		timeout = configured
 	}
 
-	return scan, nil
+	results, ok := runSyncChecks(ctx, scan.Checks, s.addresses, targetURL, timeout)
+	if !ok {
+		if err := s.enqueue(scan, targetURL); err != nil {
+			return nil, err
+		}
+		return &SyncScanResult{Mode: "async", Scan: scan}, nil
+	}
+
+	completed := models.ScanStatusCompleted
+	progress := 100
+	scan, err = s.IngestResults(scan.ID, &IngestResultsRequest{
+		Status:   &completed,
+		Progress: &progress,
+		Results:  results,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.scanRepo.GetResults(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncScanResult{Mode: "sync", Scan: scan, Results: stored}, nil
 }
 
-// queueScan sends a scan task to Celery via Redis
-func (s *ScanService) queueScan(scanID, target string, checks []string, config models.ScanConfig) error {
-	// Celery task format
-	taskID := uuid.New().String()
-	task := map[string]interface{}{
-		"id":      taskID,
-		"task":    "tasks.execute_scan",
-		"args":    []interface{}{scanID, target, checks},
-		"kwargs":  map[string]interface{}{},
-		"retries": 0,
+// syncScanProblems reports why a request cannot run in sync mode
+func (s *ScanService) syncScanProblems(req *CreateScanRequest) ValidationErrors {
+	var problems ValidationErrors
+	if req.URL == nil || req.TargetID != nil || len(req.URLs) > 0 {
+		problems.add("sync", "sync mode is only available for quick scans of a single url")
+	} else if _, err := s.addresses.normalizeURL(strings.TrimSpace(*req.URL)); err != nil {
+		problems.add("url", "%v", err)
+	}
+	for _, check := range req.Checks {
+		if _, ok := syncChecks[check]; !ok {
+			problems.add("checks", "check %q cannot run in sync mode (allowed: %s)", check, strings.Join(SyncCheckNames(), ", "))
+		}
+	}
+	if req.Config.ClientCertificateID != nil {
+		problems.add("config.client_certificate_id", "client certificates are not supported in sync mode")
 	}
+	if req.RunAt != nil {
+		problems.add("run_at", "run_at cannot be combined with sync mode")
+	}
+	return problems
+}
 
-	// Celery message envelope
-	message := map[string]interface{}{
-		"body":         base64Encode(task),
-		"content-type": "application/json",
-		"properties": map[string]interface{}{
-			"correlation_id": taskID,
-			"delivery_info": map[string]interface{}{
-				"exchange":   "",
-				"routing_key": "celery",
-			},
-			"delivery_mode": 2,
-			"delivery_tag":  taskID,
-		},
-		"headers": map[string]interface{}{},
+// runSyncChecks runs checks concurrently against target, reporting false
+// if they did not all finish within timeout or before ctx ended
+func runSyncChecks(ctx context.Context, checks []string, addresses AddressPolicy, target string, timeout time.Duration) ([]IngestResult, bool) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan IngestResult, len(checks))
+	for _, check := range checks {
+		go func(run syncCheckFunc) {
+			done <- run(ctx, addresses, target)
+		}(syncChecks[check])
 	}
 
-	messageJSON, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+	results := make([]IngestResult, 0, len(checks))
+	for range checks {
+		select {
+		case result := <-done:
+			results = append(results, result)
+		case <-ctx.Done():
+			return nil, false
+		}
 	}
 
-	fmt.Printf("Queueing scan task for %s (ID: %s)\n", target, scanID)
+	// A check that gave up because the deadline passed is not a result
+	if ctx.Err() != nil {
+		return nil, false
+	}
 
-	// For now, just log it - Redis integration can be added later if needed
-	// The workers can poll the database for queued scans instead
-	_ = messageJSON
+	return results, true
+}
 
+// validateScanSettings checks the check list, config and metadata of a scan
+func validateScanSettings(checks []string, config models.ScanConfig, metadata json.RawMessage) error {
+	if problems := scanSettingsProblems(checks, config, metadata); len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidScanConfig, problems[0].Message)
+	}
 	return nil
 }
 
-func base64Encode(data interface{}) string {
-	jsonBytes, _ := json.Marshal(data)
-	return string(jsonBytes) // Celery expects JSON string, not base64 for json serializer
+// scanSettingsProblems reports what is wrong with the check list, config
+// and metadata of a scan, at most one problem per field
+func scanSettingsProblems(checks []string, config models.ScanConfig, metadata json.RawMessage) ValidationErrors {
+	var problems ValidationErrors
+	if err := models.ValidateChecks(checks); err != nil {
+		problems.add("checks", "%v", err)
+	}
+	if err := config.Validate(); err != nil {
+		problems.add("config", "%v", err)
+	}
+	if len(metadata) > 0 {
+		var object map[string]interface{}
+		if err := json.Unmarshal(metadata, &object); err != nil {
+			problems.add("metadata", "metadata must be a JSON object")
+		}
+	}
+	return problems
 }
 
-// GetScan retrieves a scan by ID
-func (s *ScanService) GetScan(scanID, organizationID uuid.UUID) (*models.ScanJob, error) {
-	scan, err := s.scanRepo.GetByID(scanID)
+// validateConfigReferences checks that the client certificate and wordlist
+// a config references exist in the organization
+func (s *ScanService) validateConfigReferences(config models.ScanConfig, organizationID uuid.UUID) error {
+	problem, err := s.clientCertificateProblem(config, organizationID)
 	if err != nil {
-		if errors.Is(err, repository.ErrScanNotFound) {
-			return nil, ErrScanNotFound
+		return err
+	}
+	if problem == "" {
+		problem, err = s.wordlistProblem(config, organizationID)
+		if err != nil {
+			return err
 		}
-		return nil, err
+	}
+	if problem != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidScanConfig, problem)
+	}
+	return nil
+}
+
+// clientCertificateProblem reports why the config's client certificate
+// cannot be used, or "" when it can (or none is set)
+func (s *ScanService) clientCertificateProblem(config models.ScanConfig, organizationID uuid.UUID) (string, error) {
+	if config.ClientCertificateID == nil {
+		return "", nil
 	}
 
-	// Verify scan belongs to organization
-	if scan.OrganizationID != organizationID {
-		return nil, ErrScanNotFound
+	// Workers fetch the pair with their worker token, so it cannot be
+	// used without WORKER_SECRET
+	if s.workerSecret == "" {
+		return "client certificates require WORKER_SECRET to be set", nil
 	}
 
-	return scan, nil
-}
+	if _, err := s.certService.GetCertificate(*config.ClientCertificateID, organizationID); err != nil {
+		if errors.Is(err, ErrCertificateNotFound) {
+			return "client certificate not found", nil
+		}
+		return "", err
+	}
 
-// ListScans retrieves all scans for an organization
-func (s *ScanService) ListScans(organizationID uuid.UUID, limit, offset int) ([]*models.ScanJob, error) {
-	return s.scanRepo.ListByOrganization(organizationID, limit, offset)
+	return "", nil
 }
 
-// GetScanResults retrieves results for a scan
-func (s *ScanService) GetScanResults(scanID, organizationID uuid.UUID) ([]*models.ScanResult, error) {
-	// Verify scan exists and belongs to organization
-	scan, err := s.GetScan(scanID, organizationID)
+// campaignProblem reports why a scan cannot join the campaign, or "" when it
+// can (or none is set)
+func (s *ScanService) campaignProblem(campaignID *uuid.UUID, organizationID uuid.UUID) (string, error) {
+	if campaignID == nil {
+		return "", nil
+	}
+
+	campaign, err := s.campaignRepo.GetByID(*campaignID)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			return "campaign not found", nil
+		}
+		return "", err
+	}
+	if campaign.OrganizationID != organizationID {
+		return "campaign not found", nil
 	}
 
-	return s.scanRepo.GetResults(scan.ID)
+	return "", nil
 }
 
-// CancelScan cancels a running scan
-func (s *ScanService) CancelScan(scanID, organizationID uuid.UUID) error {
-	// Verify scan exists and belongs to organization
-	scan, err := s.GetScan(scanID, organizationID)
-	if err != nil {
-		return err
+// wordlistProblem reports why the config's wordlist cannot be used, or ""
+// when it can (or none is set)
+func (s *ScanService) wordlistProblem(config models.ScanConfig, organizationID uuid.UUID) (string, error) {
+	if config.WordlistID == nil {
+		return "", nil
+	}
+	if s.workerSecret == "" {
+		return "wordlists require WORKER_SECRET to be set", nil
 	}
 
-	// Can only cancel queued or running scans
-	if scan.Status != "queued" && scan.Status != "running" {
-		return errors.New("scan cannot be cancelled")
+	wordlist, err := s.wordlistRepo.GetByID(*config.WordlistID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWordlistNotFound) {
+			return "wordlist not found", nil
+		}
+		return "", err
+	}
+	if wordlist.OrganizationID != organizationID {
+		return "wordlist not found", nil
 	}
 
-	// Update status to cancelled
-	return s.scanRepo.UpdateStatus(scan.ID, "cancelled", scan.Progress)
+	return "", nil
+}
+
+// runAtProblem reports why run_at cannot schedule a scan, or "" when it can
+func runAtProblem(runAt *time.Time, now time.Time) string {
+	if runAt == nil {
+		return ""
+	}
+	if !runAt.After(now) {
+		return "run_at must be in the future"
+	}
+	if runAt.After(now.Add(MaxScheduleAhead)) {
+		return fmt.Sprintf("run_at must be within %d days", int(MaxScheduleAhead.Hours()/24))
+	}
+	return ""
+}
+
+// ValidateScanRequest runs the checks CreateScan applies to a request
+// without storing anything, and returns every problem found. The error is
+// only set when validation itself could not be completed.
+func (s *ScanService) ValidateScanRequest(req *CreateScanRequest, organizationID uuid.UUID) (ValidationErrors, error) {
+	problems := ValidationErrors{}
+
+	if req.Sync {
+		problems = append(problems, s.syncScanProblems(req)...)
+	}
+
+	switch {
+	case req.TargetID != nil && len(req.URLs) > 0:
+		problems.add("urls", "urls cannot be combined with target_id")
+	case req.TargetID != nil:
+		target, err := s.targetRepo.GetByID(*req.TargetID)
+		if err != nil && !errors.Is(err, repository.ErrTargetNotFound) {
+			return nil, err
+		}
+		if err != nil || target.OrganizationID != organizationID {
+			problems.add("target_id", "target not found")
+		} else if _, err := s.addresses.normalizeHostname(target.Hostname); err != nil {
+			problems.add("target_id", "target hostname %v", err)
+		}
+	case req.URL == nil && len(req.URLs) == 0:
+		problems.add("url", "either target_id or url must be provided")
+	}
+	if req.URL != nil && !req.Sync {
+		if _, err := s.addresses.normalizeURL(strings.TrimSpace(*req.URL)); err != nil {
+			problems.add("url", "%v", err)
+		}
+	}
+	for i, candidate := range req.URLs {
+		if _, err := s.addresses.normalizeURL(strings.TrimSpace(candidate)); err != nil {
+			problems.add(fmt.Sprintf("urls[%d]", i), "%v", err)
+		}
+	}
+	if count := len(req.URLs); count > MaxQuickScanURLs {
+		problems.add("urls", "at most %d urls can be scanned at once", MaxQuickScanURLs)
+	}
+
+	problems = append(problems, scanSettingsProblems(req.Checks, req.Config, req.Metadata)...)
+
+	problem, err := s.clientCertificateProblem(req.Config, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if problem != "" {
+		problems.add("config.client_certificate_id", "%s", problem)
+	}
+	problem, err = s.wordlistProblem(req.Config, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if problem != "" {
+		problems.add("config.wordlist_id", "%s", problem)
+	}
+	problem, err = s.campaignProblem(req.CampaignID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if problem != "" {
+		problems.add("campaign_id", "%s", problem)
+	}
+	if problem := runAtProblem(req.RunAt, time.Now()); problem != "" {
+		problems.add("run_at", "%s", problem)
+	}
+
+	return problems, nil
+}
+
+// UpdateScan edits a scan that has not started yet. The task already in the
+// queue keeps the checks and config it was sent with, but a worker picking
+// up a scan that is still queued loads them again through WorkerScan, so
+// saving the row is enough to update the pending task.
+func (s *ScanService) UpdateScan(scanID, organizationID uuid.UUID, req *UpdateScanRequest) (*models.ScanJob, error) {
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scan.Status != models.ScanStatusQueued && scan.Status != models.ScanStatusScheduled {
+		return nil, ErrScanNotEditable
+	}
+
+	if req.Checks != nil {
+		scan.Checks = req.Checks
+	}
+	if req.Config != nil {
+		scan.Config = *req.Config
+	}
+	if req.Tags != nil {
+		scan.Tags = req.Tags
+	}
+	if req.Metadata != nil {
+		scan.Metadata = req.Metadata
+	}
+
+	if err := validateScanSettings(scan.Checks, scan.Config, scan.Metadata); err != nil {
+		return nil, err
+	}
+	if err := s.validateConfigReferences(scan.Config, organizationID); err != nil {
+		return nil, err
+	}
+
+	if err := s.scanRepo.UpdateQueued(scan); err != nil {
+		if errors.Is(err, repository.ErrScanNotQueued) {
+			return nil, ErrScanNotEditable
+		}
+		return nil, err
+	}
+
+	return scan, nil
+}
+
+// queueScan sends a scan task to Celery via Redis
+func (s *ScanService) queueScan(scan *models.ScanJob, target string) error {
+	scanID := scan.ID.String()
+
+	// The worker token only lets the worker report results for this scan
+	kwargs := map[string]interface{}{}
+	if s.workerSecret != "" {
+		kwargs["worker_token"] = auth.GenerateWorkerToken(scan.ID, s.workerSecret)
+	}
+
+	// Celery task format
+	taskID := uuid.New().String()
+	task := map[string]interface{}{
+		"id":      taskID,
+		"task":    "tasks.execute_scan",
+		"args":    []interface{}{scanID, target, scan.Checks, scan.Config},
+		"kwargs":  kwargs,
+		"retries": 0,
+	}
+
+	// Celery message envelope
+	message := map[string]interface{}{
+		"body":         base64Encode(task),
+		"content-type": "application/json",
+		"properties": map[string]interface{}{
+			"correlation_id": taskID,
+			"delivery_info": map[string]interface{}{
+				"exchange":    "",
+				"routing_key": CeleryQueue,
+			},
+			"delivery_mode": 2,
+			"delivery_tag":  taskID,
+		},
+		"headers": map[string]interface{}{},
+	}
+
+	messageJSON, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), queuePushTimeout)
+	defer cancel()
+
+	if err := s.rdb.LPush(ctx, CeleryQueue, messageJSON).Err(); err != nil {
+		return fmt.Errorf("failed to push scan task to redis: %w", err)
+	}
+
+	slog.Info("Queued scan task", "task_id", taskID, "target", target, "scan_id", scanID)
+	return nil
+}
+
+// CeleryQueue is the Redis list scan tasks are pushed to
+const CeleryQueue = "celery"
+
+func base64Encode(data interface{}) string {
+	jsonBytes, _ := json.Marshal(data)
+	return string(jsonBytes) // Celery expects JSON string, not base64 for json serializer
+}
+
+// WorkerTask is what a worker needs to run a scan it picked up
+type WorkerTask struct {
+	ID     uuid.UUID         `json:"id"`
+	Status models.ScanStatus `json:"status"`
+	Checks []string          `json:"checks"`
+	Config models.ScanConfig `json:"config"`
+}
+
+// WorkerScan returns the current checks and config of a scan for the worker
+// that picked up its task, which may predate an edit of the scan. The scan
+// is identified by its worker token, so no organization is checked.
+func (s *ScanService) WorkerScan(scanID uuid.UUID) (*WorkerTask, error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+
+	return &WorkerTask{ID: scan.ID, Status: scan.Status, Checks: scan.Checks, Config: scan.Config}, nil
+}
+
+// WorkerClientCertificate decrypts the client certificate of an unfinished
+// scan for the worker running it. The pair never travels in the task
+// message; the worker fetches it with its worker token when it starts.
+func (s *ScanService) WorkerClientCertificate(scanID uuid.UUID) (*models.ClientCertificateMaterial, error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+	if scan.Status.IsTerminal() {
+		return nil, ErrScanFinished
+	}
+	if scan.Config.ClientCertificateID == nil {
+		return nil, ErrNoClientCert
+	}
+
+	material, err := s.certService.Material(*scan.Config.ClientCertificateID, scan.OrganizationID)
+	if err != nil {
+		if errors.Is(err, ErrCertificateNotFound) {
+			return nil, ErrNoClientCert
+		}
+		return nil, err
+	}
+	return material, nil
+}
+
+// WorkerWordlist returns the wordlist and organization of an unfinished scan
+// for the worker running it, which downloads the wordlist through the
+// wordlist service
+func (s *ScanService) WorkerWordlist(scanID uuid.UUID) (wordlistID, organizationID uuid.UUID, err error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return uuid.Nil, uuid.Nil, ErrScanNotFound
+		}
+		return uuid.Nil, uuid.Nil, err
+	}
+	if scan.Status.IsTerminal() {
+		return uuid.Nil, uuid.Nil, ErrScanFinished
+	}
+	if scan.Config.WordlistID == nil {
+		return uuid.Nil, uuid.Nil, ErrNoWordlist
+	}
+
+	return *scan.Config.WordlistID, scan.OrganizationID, nil
+}
+
+// GetScan retrieves a scan by ID
+func (s *ScanService) GetScan(scanID, organizationID uuid.UUID) (*models.ScanJob, error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+
+	// Verify scan belongs to organization
+	if scan.OrganizationID != organizationID {
+		return nil, ErrScanNotFound
+	}
+
+	setPolicy(scan)
+
+	return scan, nil
+}
+
+// getScanPair retrieves two scans of an organization for comparison
+func (s *ScanService) getScanPair(scanID, againstID, organizationID uuid.UUID) (*models.ScanJob, *models.ScanJob, error) {
+	scans := make([]*models.ScanJob, 0, 2)
+	for _, id := range []uuid.UUID{scanID, againstID} {
+		scan, err := s.scanRepo.GetByID(id)
+		if err != nil {
+			if errors.Is(err, repository.ErrScanNotFound) {
+				return nil, nil, ErrScanNotFound
+			}
+			return nil, nil, err
+		}
+		if scan.OrganizationID != organizationID {
+			return nil, nil, ErrScanNotFound
+		}
+		scans = append(scans, scan)
+	}
+
+	return scans[0], scans[1], nil
+}
+
+// GetConfigDiff compares the checks and configuration of a scan against
+// another scan of the same organization
+func (s *ScanService) GetConfigDiff(scanID, againstID, organizationID uuid.UUID) (*models.ScanConfigDiff, error) {
+	scan, against, err := s.getScanPair(scanID, againstID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.DiffScanConfig(scan, against), nil
+}
+
+// DiffScans compares the findings of a scan against another scan of the
+// same target in the same organization. Findings of scans of different
+// targets are not comparable and ErrScanTargetsDiffer is returned.
+func (s *ScanService) DiffScans(scanID, againstID, organizationID uuid.UUID) (*models.ScanResultDiff, error) {
+	scan, against, err := s.getScanPair(scanID, againstID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !models.SameScanTarget(scan, against) {
+		return nil, ErrScanTargetsDiffer
+	}
+
+	results, err := s.scanRepo.GetResults(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+	againstResults, err := s.scanRepo.GetResults(against.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.DiffScanResults(scan, against, results, againstResults), nil
+}
+
+// evaluatePolicy applies a completed scan's fail_on_severity gate to its
+// stored results and persists the outcome
+func (s *ScanService) evaluatePolicy(scan *models.ScanJob) error {
+	threshold := scan.Config.FailOnSeverity
+	if threshold == "" {
+		return nil
+	}
+
+	results, err := s.scanRepo.GetResults(scan.ID)
+	if err != nil {
+		return err
+	}
+
+	worst := ""
+	for _, result := range results {
+		if result.Findings > 0 && models.SeverityRank(result.Severity) > models.SeverityRank(worst) {
+			worst = result.Severity
+		}
+	}
+
+	passed := worst == "" || models.SeverityRank(worst) < models.SeverityRank(threshold)
+	if err := s.scanRepo.SetPolicyResult(scan.ID, passed, worst); err != nil {
+		return err
+	}
+
+	scan.PolicyPassed = &passed
+	if worst != "" {
+		scan.WorstSeverity = &worst
+	}
+	return nil
+}
+
+// setPolicy fills in the policy summary of a scan with a fail_on_severity
+// gate from the outcome stored when it completed
+func setPolicy(scan *models.ScanJob) {
+	if scan.Config.FailOnSeverity == "" {
+		return
+	}
+
+	scan.Policy = &models.ScanPolicy{
+		FailOnSeverity: scan.Config.FailOnSeverity,
+		WorstSeverity:  scan.WorstSeverity,
+		Passed:         scan.PolicyPassed,
+	}
+}
+
+// ListScansFilter narrows and orders a scan listing; zero values do not
+// filter
+type ListScansFilter struct {
+	Status        string
+	HasReport     *bool
+	CampaignID    *uuid.UUID
+	TargetID      *uuid.UUID
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Sort          string // created_at or status, "-" prefixed for descending; default -created_at
+}
+
+// ListScans retrieves the scans of an organization matching filter
+func (s *ScanService) ListScans(organizationID uuid.UUID, filter ListScansFilter, limit, offset int) ([]*models.ScanJob, int, error) {
+	repoFilter := repository.ScanListFilter{
+		HasReport:     filter.HasReport,
+		CampaignID:    filter.CampaignID,
+		TargetID:      filter.TargetID,
+		CreatedAfter:  filter.CreatedAfter,
+		CreatedBefore: filter.CreatedBefore,
+	}
+	if filter.Status != "" {
+		status := models.ScanStatus(filter.Status)
+		if !status.IsValid() {
+			return nil, 0, fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, filter.Status)
+		}
+		repoFilter.Status = &status
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, 0, fmt.Errorf("%w: created_after must be before created_before", ErrInvalidFilter)
+	}
+	if filter.Sort != "" {
+		repoFilter.Sort = strings.TrimPrefix(filter.Sort, "-")
+		repoFilter.Ascending = !strings.HasPrefix(filter.Sort, "-")
+	}
+
+	scans, err := s.scanRepo.ListByOrganization(organizationID, repoFilter, limit, offset)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return nil, 0, fmt.Errorf("%w: sort must be created_at, -created_at, status or -status", ErrInvalidFilter)
+		}
+		return nil, 0, err
+	}
+
+	total, err := s.scanRepo.CountByOrganization(organizationID, repoFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return scans, total, nil
+}
+
+// maxSearchQueryLength bounds the q parameter of a result search
+const maxSearchQueryLength = 500
+
+// ResultSearchPage is one page of result search hits
+type ResultSearchPage struct {
+	Query   string                    `json:"query"`
+	Mode    string                    `json:"mode"` // "contains", "text" or "path"
+	Results []*models.ResultSearchHit `json:"results"`
+	Total   int                       `json:"total"`
+	Limit   int                       `json:"limit"`
+	Offset  int                       `json:"offset"`
+}
+
+// SearchResults searches the result data of an organization's scans. A
+// query that is a JSON object matches results whose data contains it
+// (e.g. {"open_ports":[{"port":22}]}); anything else is a full-text search
+// over the data's string and numeric values (e.g. nginx).
+func (s *ScanService) SearchResults(organizationID uuid.UUID, q string, limit, offset int) (*ResultSearchPage, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, fmt.Errorf("%w: q is required", ErrInvalidFilter)
+	}
+	if len(q) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidFilter, maxSearchQueryLength)
+	}
+
+	page := &ResultSearchPage{Query: q, Limit: limit, Offset: offset}
+	search := repository.ResultSearch{Text: q}
+	page.Mode = "text"
+	if strings.HasPrefix(q, "{") {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(q), &object); err != nil {
+			return nil, fmt.Errorf("%w: q looks like JSON but is not a valid object", ErrInvalidFilter)
+		}
+		search = repository.ResultSearch{Contains: json.RawMessage(q)}
+		page.Mode = "contains"
+	}
+
+	results, total, err := s.scanRepo.SearchResults(organizationID, search, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	page.Results = results
+	page.Total = total
+
+	return page, nil
+}
+
+// SearchResultsByPath returns the results of an organization's scans whose
+// data holds value at path, e.g. open_ports[].port = 22. The value is read as
+// JSON when it parses (22, true, "22") and as a plain string otherwise.
+func (s *ScanService) SearchResultsByPath(organizationID uuid.UUID, path, value string, limit, offset int) (*ResultSearchPage, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("%w: path is required", ErrInvalidFilter)
+	}
+	if len(path) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: path must be at most %d characters", ErrInvalidFilter, maxSearchQueryLength)
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		parsed = value
+	}
+
+	results, total, err := s.scanRepo.QueryResultsByJSONPath(organizationID, path, parsed, limit, offset)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidJSONPath) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		return nil, err
+	}
+
+	return &ResultSearchPage{
+		Query:   path + "=" + value,
+		Mode:    "path",
+		Results: results,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
+
+// RequeueScansRequest filters the scans to requeue
+type RequeueScansRequest struct {
+	Statuses      []string   `json:"statuses" binding:"required,min=1"` // failed and/or queued
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+}
+
+// RequeueScansResult counts the scans a bulk requeue published to the
+// workers and lists those it could not, which are failed again
+type RequeueScansResult struct {
+	Requeued      int         `json:"requeued"`
+	Failed        int         `json:"failed"`
+	FailedScanIDs []uuid.UUID `json:"failed_scan_ids"`
+}
+
+// RequeueScans resets failed and stuck queued scans back to queued and
+// republishes them to the workers
+func (s *ScanService) RequeueScans(req *RequeueScansRequest, organizationID uuid.UUID) (*RequeueScansResult, error) {
+	for _, status := range req.Statuses {
+		if status != string(models.ScanStatusFailed) && status != string(models.ScanStatusQueued) {
+			return nil, fmt.Errorf("%w: only failed and queued scans can be requeued", ErrInvalidFilter)
+		}
+	}
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return nil, fmt.Errorf("%w: created_after must be before created_before", ErrInvalidFilter)
+	}
+
+	scans, err := s.scanRepo.Requeue(organizationID, repository.RequeueFilter{
+		Statuses:      req.Statuses,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		StuckBefore:   time.Now().Add(-requeueStuckAfter),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RequeueScansResult{FailedScanIDs: []uuid.UUID{}}
+	for _, scan := range scans {
+		target, err := s.scanTarget(scan)
+		if err == nil {
+			err = s.queueScan(scan, target)
+		}
+		if err != nil {
+			slog.Error("Failed to requeue scan", "scan_id", scan.ID, "error", err)
+			s.failDispatch(scan, err)
+			result.Failed++
+			result.FailedScanIDs = append(result.FailedScanIDs, scan.ID)
+			continue
+		}
+		result.Requeued++
+	}
+
+	return result, nil
+}
+
+// scheduledBatchSize is the most scheduled scans queued per scheduler pass
+const scheduledBatchSize = 100
+
+// EnqueueDueScans queues the scheduled scans whose run_at has passed and
+// returns how many were claimed. A scan that cannot be handed to the
+// workers is failed like any other scan that fails to queue.
+func (s *ScanService) EnqueueDueScans(now time.Time) (int, error) {
+	scans, err := s.scanRepo.ClaimDueScheduled(now, scheduledBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, scan := range scans {
+		target, err := s.scanTarget(scan)
+		if err == nil {
+			err = s.queueScan(scan, target)
+		}
+		if err != nil {
+			slog.Error("Failed to queue scheduled scan", "scan_id", scan.ID, "error", err)
+			s.failDispatch(scan, err)
+		}
+	}
+
+	return len(scans), nil
+}
+
+// MonitorScanTag labels the scans started for monitored targets
+const MonitorScanTag = "monitor"
+
+// StartDueMonitorScans queues a scan of MonitorChecks for every monitored
+// target whose next run has come, on behalf of the target's creator, and
+// returns how many targets were claimed. Claiming moves each target's next
+// run forward, so a scan that fails to start waits for the next interval.
+func (s *ScanService) StartDueMonitorScans(now time.Time) (int, error) {
+	targets, err := s.targetRepo.ClaimDueMonitored(now, scheduledBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, target := range targets {
+		targetID := target.ID
+		req := &CreateScanRequest{
+			TargetID: &targetID,
+			Checks:   models.MonitorChecks,
+			Tags:     []string{MonitorScanTag},
+		}
+		if _, err := s.CreateScan(req, target.CreatedBy, target.OrganizationID); err != nil {
+			slog.Error("Failed to start monitor scan", "target_id", target.ID, "error", err)
+		}
+	}
+
+	return len(targets), nil
+}
+
+// scanTarget resolves the address a scan runs against
+func (s *ScanService) scanTarget(scan *models.ScanJob) (string, error) {
+	if scan.URL != nil {
+		return *scan.URL, nil
+	}
+	if scan.TargetID == nil {
+		return "", ErrTargetNotFound
+	}
+
+	target, err := s.targetRepo.GetByID(*scan.TargetID)
+	if err != nil {
+		return "", err
+	}
+
+	return target.Hostname, nil
+}
+
+// GetFailureStats aggregates the organization's scan failures of the last
+// days days by failure code, together with its most failing checks
+func (s *ScanService) GetFailureStats(organizationID uuid.UUID, days int) (*models.FailureStats, error) {
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	return s.scanRepo.FailureStats(organizationID, since)
+}
+
+// GetTopRisks returns each target's latest scan ranked by risk score
+func (s *ScanService) GetTopRisks(organizationID uuid.UUID, limit int) ([]*models.TargetRisk, error) {
+	return s.scanRepo.ListTopRisks(organizationID, limit)
+}
+
+// GetScanProgress returns the current status and progress of a scan
+func (s *ScanService) GetScanProgress(scanID, organizationID uuid.UUID) (*models.ScanProgress, error) {
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &models.ScanProgress{
+		ScanID:    scan.ID,
+		Status:    scan.Status,
+		Progress:  scan.Progress,
+		UpdatedAt: scan.UpdatedAt,
+	}
+	if scan.CurrentStep != nil {
+		progress.CurrentStep = *scan.CurrentStep
+	}
+
+	return progress, nil
+}
+
+// GetScanResults retrieves results for a scan
+func (s *ScanService) GetScanResults(scanID, organizationID uuid.UUID) ([]*models.ScanResult, error) {
+	// Verify scan exists and belongs to organization
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanRepo.GetResults(scan.ID)
+}
+
+// GetScanResultsByTriageStatus retrieves the scan results in the given
+// triage state, or all of them when triageStatus is empty
+func (s *ScanService) GetScanResultsByTriageStatus(scanID, organizationID uuid.UUID, triageStatus string) ([]*models.ScanResult, error) {
+	if triageStatus != "" && !models.IsValidTriageStatus(triageStatus) {
+		return nil, fmt.Errorf("%w: unknown triage status %q", ErrInvalidFilter, triageStatus)
+	}
+
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanRepo.GetResultsByTriageStatus(scan.ID, triageStatus)
+}
+
+// GetScanTimeline assembles the chronological lifecycle of a scan from its
+// recorded status transitions and the completion of each check
+func (s *ScanService) GetScanTimeline(scanID, organizationID uuid.UUID) ([]*models.ScanTimelineEvent, error) {
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.scanRepo.ListStatusHistory(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.scanRepo.GetResults(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*models.ScanTimelineEvent{{
+		Type:       models.TimelineEventCreated,
+		OccurredAt: scan.CreatedAt,
+	}}
+
+	if len(history) > 0 {
+		for _, change := range history {
+			status := change.ToStatus
+			progress := change.Progress
+			events = append(events, &models.ScanTimelineEvent{
+				Type:       models.TimelineEventStatusChanged,
+				OccurredAt: change.ChangedAt,
+				Status:     &status,
+				Progress:   &progress,
+			})
+		}
+	} else {
+		// Scans that predate status history only carry their timestamps
+		events = append(events, legacyStatusEvents(scan)...)
+	}
+
+	for _, result := range results {
+		events = append(events, &models.ScanTimelineEvent{
+			Type:       models.TimelineEventCheckFinished,
+			OccurredAt: result.CreatedAt,
+			CheckType:  result.CheckType,
+			Result:     result.Status,
+			Severity:   result.Severity,
+			Findings:   result.Findings,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	return events, nil
+}
+
+// legacyStatusEvents derives status events from a scan's own timestamps
+func legacyStatusEvents(scan *models.ScanJob) []*models.ScanTimelineEvent {
+	status := func(s models.ScanStatus) *models.ScanStatus { return &s }
+
+	events := []*models.ScanTimelineEvent{{
+		Type:       models.TimelineEventStatusChanged,
+		OccurredAt: scan.CreatedAt,
+		Status:     status(models.ScanStatusQueued),
+	}}
+	if scan.StartedAt != nil {
+		events = append(events, &models.ScanTimelineEvent{
+			Type:       models.TimelineEventStatusChanged,
+			OccurredAt: *scan.StartedAt,
+			Status:     status(models.ScanStatusRunning),
+		})
+	}
+	if scan.CompletedAt != nil {
+		events = append(events, &models.ScanTimelineEvent{
+			Type:       models.TimelineEventStatusChanged,
+			OccurredAt: *scan.CompletedAt,
+			Status:     status(scan.Status),
+		})
+	}
+
+	return events
+}
+
+// IngestResultsRequest is a worker's report on a scan: check results,
+// progress and status, all optional
+type IngestResultsRequest struct {
+	Status   *models.ScanStatus `json:"status,omitempty"` // running, completed or failed
+	Progress *int               `json:"progress,omitempty" binding:"omitempty,min=0,max=100"`
+	// CurrentStep names the check the worker is running; it must be one
+	// of the scan's checks
+	CurrentStep string         `json:"current_step,omitempty"`
+	Results     []IngestResult `json:"results,omitempty" binding:"max=10000,dive"` // stored in one batch
+	// FailureCode and FailureReason classify a failed status; the code
+	// defaults to worker_error
+	FailureCode   *models.FailureCode `json:"failure_code,omitempty"`
+	FailureReason string              `json:"failure_reason,omitempty" binding:"max=1000"`
+}
+
+// workerFailure returns the failure a worker reported with a failed status
+func workerFailure(req *IngestResultsRequest) (*models.ScanFailure, error) {
+	if req.Status == nil || *req.Status != models.ScanStatusFailed {
+		if req.FailureCode != nil || req.FailureReason != "" {
+			return nil, fmt.Errorf("%w: failure_code and failure_reason require status failed", ErrInvalidScanResult)
+		}
+		return nil, nil
+	}
+
+	failure := &models.ScanFailure{Code: models.FailureWorkerError, Reason: req.FailureReason}
+	if req.FailureCode != nil {
+		if !req.FailureCode.IsValid() || *req.FailureCode == models.FailureCancelledByUser {
+			return nil, fmt.Errorf("%w: unknown failure_code %q", ErrInvalidScanResult, *req.FailureCode)
+		}
+		failure.Code = *req.FailureCode
+	}
+
+	return failure, nil
+}
+
+// IngestResult is the outcome of one check reported by a worker
+type IngestResult struct {
+	CheckType          string                 `json:"check_type" binding:"required"`
+	Status             string                 `json:"status" binding:"required,oneof=success failed error"`
+	Data               json.RawMessage        `json:"data,omitempty"`
+	Findings           int                    `json:"findings" binding:"min=0"`
+	Severity           string                 `json:"severity,omitempty"` // defaults to info
+	FindingsBySeverity *models.SeverityCounts `json:"findings_by_severity,omitempty"`
+}
+
+// IngestResults records a worker's report on a scan. The whole report is
+// validated before anything is written: results must belong to checks the
+// scan runs, and the scan may only move forward to running, completed or
+// failed. Reports for a scan that already finished (including one cancelled
+// while the worker was busy) are rejected with ErrScanFinished.
+func (s *ScanService) IngestResults(scanID uuid.UUID, req *IngestResultsRequest) (*models.ScanJob, error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+	if scan.Status.IsTerminal() {
+		return nil, ErrScanFinished
+	}
+
+	if req.Status != nil {
+		switch *req.Status {
+		case models.ScanStatusRunning, models.ScanStatusCompleted, models.ScanStatusFailed:
+		default:
+			return nil, fmt.Errorf("%w: status must be running, completed or failed", ErrInvalidScanResult)
+		}
+	}
+	failure, err := workerFailure(req)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make(map[string]bool, len(scan.Checks))
+	for _, check := range scan.Checks {
+		checks[check] = true
+	}
+
+	if req.CurrentStep != "" && !checks[req.CurrentStep] {
+		return nil, fmt.Errorf("%w: current_step: scan does not run check %q", ErrInvalidScanResult, req.CurrentStep)
+	}
+
+	results := make([]*models.ScanResult, 0, len(req.Results))
+	for i, item := range req.Results {
+		if !checks[item.CheckType] {
+			return nil, fmt.Errorf("%w: results[%d]: scan does not run check %q", ErrInvalidScanResult, i, item.CheckType)
+		}
+		severity := item.Severity
+		if severity == "" {
+			severity = models.SeverityInfo
+		}
+		if !models.IsValidSeverity(severity) {
+			return nil, fmt.Errorf("%w: results[%d]: unknown severity %q", ErrInvalidScanResult, i, severity)
+		}
+		if item.FindingsBySeverity != nil {
+			if err := item.FindingsBySeverity.Validate(); err != nil {
+				return nil, fmt.Errorf("%w: results[%d]: %v", ErrInvalidScanResult, i, err)
+			}
+		}
+		data := item.Data
+		if len(data) == 0 {
+			data = json.RawMessage("{}")
+		}
+
+		results = append(results, &models.ScanResult{
+			ID:                 uuid.New(),
+			ScanID:             scan.ID,
+			CheckType:          item.CheckType,
+			Status:             item.Status,
+			Data:               data,
+			Findings:           item.Findings,
+			Severity:           severity,
+			FindingsBySeverity: item.FindingsBySeverity,
+		})
+	}
+
+	// The organization's override rules replace reported severities
+	if len(results) > 0 {
+		overrides, err := s.overrideRepo.ListByOrganization(scan.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			overrides.Apply(result)
+		}
+	}
+
+	// A worker reporting results has evidently started the scan
+	if scan.Status == models.ScanStatusQueued && (len(results) > 0 || req.Progress != nil || req.CurrentStep != "") {
+		if err := s.transition(scan, models.ScanStatusRunning, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.scanRepo.CreateResults(results); err != nil {
+		return nil, err
+	}
+
+	// Completing a scan takes its progress to 100, firing the milestones
+	// still ahead of it
+	completing := req.Status != nil && *req.Status == models.ScanStatusCompleted
+	if req.Progress != nil || req.CurrentStep != "" || completing {
+		progress := 0
+		if req.Progress != nil {
+			progress = *req.Progress
+		}
+		if completing {
+			progress = 100
+		}
+		previous, current, err := s.scanRepo.UpdateProgress(scan.ID, progress, req.CurrentStep)
+		if err != nil {
+			if errors.Is(err, repository.ErrScanNotActive) {
+				return nil, ErrScanFinished
+			}
+			return nil, err
+		}
+		if s.webhooks != nil {
+			s.webhooks.ScanProgressed(scan, previous, current)
+		}
+	}
+
+	if req.Status != nil && (*req.Status != models.ScanStatusRunning || scan.Status == models.ScanStatusQueued) {
+		if err := s.transition(scan, *req.Status, failure); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.scanRepo.GetByID(scan.ID)
+}
+
+// transition moves an unfinished scan to status, recording failure when
+// the scan failed. A completed scan has its severity policy evaluated
+// against its results. A scan that completed or failed is counted and its
+// webhooks are notified.
+func (s *ScanService) transition(scan *models.ScanJob, status models.ScanStatus, failure *models.ScanFailure) error {
+	if err := s.scanRepo.Transition(scan.ID, status, failure); err != nil {
+		if errors.Is(err, repository.ErrScanNotActive) {
+			return ErrScanFinished
+		}
+		return err
+	}
+
+	switch status {
+	case models.ScanStatusCompleted:
+		if err := s.evaluatePolicy(scan); err != nil {
+			return err
+		}
+		metrics.ScanCompleted(scan.Checks)
+	case models.ScanStatusFailed:
+		metrics.ScanFailed(scan.Checks)
+	default:
+		return nil
+	}
+	s.scanFinished(scan, status, failure)
+	return nil
+}
+
+// scanFinished dispatches scan.completed for a scan that completed or failed
+func (s *ScanService) scanFinished(scan *models.ScanJob, status models.ScanStatus, failure *models.ScanFailure) {
+	if s.webhooks != nil {
+		s.webhooks.ScanFinished(scan, status, failure)
+	}
+}
+
+// ScanDeletion describes a soft-deleted scan and how long it can be restored
+type ScanDeletion struct {
+	ScanID     uuid.UUID `json:"scan_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+// DeleteScan soft-deletes a scan. It disappears from listings at once, is
+// cancelled if still active, and is purged with its results and reports once
+// the retention window has passed. Until then RestoreScan brings it back.
+func (s *ScanService) DeleteScan(scanID, organizationID uuid.UUID) (*ScanDeletion, error) {
+	scan, err := s.scanRepo.GetByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+	if scan.OrganizationID != organizationID {
+		return nil, ErrScanNotFound
+	}
+
+	deletedAt, err := s.scanRepo.SoftDelete(scan.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+
+	return &ScanDeletion{
+		ScanID:     scan.ID,
+		DeletedAt:  deletedAt,
+		PurgeAfter: deletedAt.Add(s.retention),
+	}, nil
+}
+
+// RestoreScan undoes DeleteScan while the scan is within its retention window
+func (s *ScanService) RestoreScan(scanID, organizationID uuid.UUID) (*models.ScanJob, error) {
+	scan, err := s.scanRepo.GetDeletedByID(scanID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+	if scan.OrganizationID != organizationID {
+		return nil, ErrScanNotFound
+	}
+	if time.Since(*scan.DeletedAt) > s.retention {
+		return nil, ErrScanNotFound
+	}
+
+	if err := s.scanRepo.Restore(scan.ID); err != nil {
+		if errors.Is(err, repository.ErrScanNotFound) {
+			return nil, ErrScanNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetScan(scan.ID, organizationID)
+}
+
+// ScanResume describes a resumed scan: the checks sent back to the workers
+// and those skipped because they already completed
+type ScanResume struct {
+	Scan            *models.ScanJob `json:"scan"`
+	ResumedChecks   []string        `json:"resumed_checks"`
+	CompletedChecks []string        `json:"completed_checks"`
+}
+
+// ResumeScan re-runs the checks of a failed scan that did not complete. The
+// results of completed checks are kept, and only the remaining checks are
+// queued for the workers.
+func (s *ScanService) ResumeScan(scanID, organizationID uuid.UUID) (*ScanResume, error) {
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if scan.Status != models.ScanStatusFailed {
+		return nil, ErrScanNotResumable
+	}
+
+	done, err := s.scanRepo.CompletedChecks(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+	completed := make(map[string]bool, len(done))
+	for _, check := range done {
+		completed[check] = true
+	}
+
+	resume := &ScanResume{ResumedChecks: []string{}, CompletedChecks: []string{}}
+	for _, check := range scan.Checks {
+		if completed[check] {
+			resume.CompletedChecks = append(resume.CompletedChecks, check)
+		} else {
+			resume.ResumedChecks = append(resume.ResumedChecks, check)
+		}
+	}
+	if len(resume.ResumedChecks) == 0 {
+		return nil, ErrScanNotResumable
+	}
+
+	progress := len(resume.CompletedChecks) * 100 / len(scan.Checks)
+	scan, err = s.scanRepo.Resume(scan.ID, resume.ResumedChecks, progress)
+	if err != nil {
+		if errors.Is(err, repository.ErrScanNotFailed) {
+			return nil, ErrScanNotResumable
+		}
+		return nil, err
+	}
+
+	target, err := s.scanTarget(scan)
+	if err != nil {
+		s.failDispatch(scan, err)
+		return nil, err
+	}
+
+	// The workers only receive the checks that still have to run
+	remaining := *scan
+	remaining.Checks = resume.ResumedChecks
+	if err := s.enqueue(&remaining, target); err != nil {
+		return nil, err
+	}
+
+	resume.Scan = scan
+	return resume, nil
+}
+
+// CancelScan cancels a running scan
+func (s *ScanService) CancelScan(scanID, organizationID uuid.UUID) error {
+	// Verify scan exists and belongs to organization
+	scan, err := s.GetScan(scanID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	// Can only cancel scheduled, queued or running scans
+	if scan.Status != models.ScanStatusScheduled && scan.Status != "queued" && scan.Status != "running" {
+		return errors.New("scan cannot be cancelled")
+	}
+
+	// Update status to cancelled
+	if err := s.scanRepo.Cancel(scan.ID); err != nil {
+		if errors.Is(err, repository.ErrScanNotActive) {
+			return errors.New("scan cannot be cancelled")
+		}
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,565 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"publicscannerapi/internal/metrics"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+)
+
+// newTestScanService returns a scan service whose repositories use mock
+func newTestScanService(t testing.TB) (*ScanService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := NewScanService(
+		repository.NewScanRepository(db),
+		repository.NewTargetRepository(db),
+		nil,
+		repository.NewSeverityOverrideRepository(db),
+		repository.NewWordlistRepository(db),
+		repository.NewCampaignRepository(db),
+		nil,
+		nil,
+		AddressPolicy{},
+		"",
+		0,
+	)
+	return service, mock
+}
+
+func TestScanServiceCurrentStepRoundTrip(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	url := "https://example.com"
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		URL:            &url,
+		OrganizationID: uuid.New(),
+		InitiatedBy:    uuid.New(),
+		Status:         models.ScanStatusRunning,
+		Progress:       20,
+		Checks:         []string{"headers", "ssl"},
+	}
+	selectScan := regexp.QuoteMeta(`FROM scan_jobs
+		WHERE id = $1 AND deleted_at IS NULL`)
+
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	mock.ExpectQuery(`UPDATE scan_jobs\s+SET progress = GREATEST`).
+		WithArgs(scan.ID, 50, "ssl").
+		WillReturnRows(sqlmock.NewRows([]string{"previous", "progress"}).AddRow(20, 50))
+
+	step := "ssl"
+	scan.Progress, scan.CurrentStep = 50, &step
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	progress := 50
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Progress: &progress, CurrentStep: "ssl"}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+
+	got, err := service.GetScanProgress(scan.ID, scan.OrganizationID)
+	if err != nil {
+		t.Fatalf("GetScanProgress: %v", err)
+	}
+	if got.CurrentStep != "ssl" || got.Progress != 50 {
+		t.Errorf("progress = %+v, want step ssl at 50", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanServiceRejectsUnknownStep(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		InitiatedBy:    uuid.New(),
+		Status:         models.ScanStatusRunning,
+		Checks:         []string{"headers"},
+	}
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	_, err := service.IngestResults(scan.ID, &IngestResultsRequest{CurrentStep: "portscan"})
+	if err == nil || !strings.Contains(err.Error(), "current_step") {
+		t.Fatalf("IngestResults error = %v, want a current_step error", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestRedis returns a client of an in-memory Redis server
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestQueueScanLeavesClientCertificateOutOfTheTask(t *testing.T) {
+	service, _ := newTestScanService(t)
+	service.rdb = newTestRedis(t)
+	service.workerSecret = "worker-secret"
+
+	certID := uuid.New()
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		Checks:         []string{"headers", "ssl"},
+		Config:         models.ScanConfig{ClientCertificateID: &certID},
+	}
+	if err := service.queueScan(scan, "https://example.com"); err != nil {
+		t.Fatalf("queueScan: %v", err)
+	}
+
+	message, err := service.rdb.RPop(context.Background(), CeleryQueue).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope struct {
+		Body string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(message), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	var task struct {
+		Args   []json.RawMessage      `json:"args"`
+		Kwargs map[string]interface{} `json:"kwargs"`
+	}
+	if err := json.Unmarshal([]byte(envelope.Body), &task); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(task.Kwargs) != 1 || task.Kwargs["worker_token"] == nil {
+		t.Errorf("kwargs = %v, want only the worker token", task.Kwargs)
+	}
+	if !strings.Contains(string(task.Args[3]), certID.String()) {
+		t.Errorf("config %s does not reference the certificate", task.Args[3])
+	}
+	if strings.Contains(message, "PRIVATE KEY") || strings.Contains(message, "certificate_pem") {
+		t.Errorf("task carries certificate material: %s", message)
+	}
+}
+
+func TestWorkerClientCertificateRefusesUnusableScans(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	certID := uuid.New()
+	tests := []struct {
+		name string
+		scan *models.ScanJob
+		want error
+	}{
+		{"finished", &models.ScanJob{Status: models.ScanStatusCompleted, Config: models.ScanConfig{ClientCertificateID: &certID}}, ErrScanFinished},
+		{"no certificate", &models.ScanJob{Status: models.ScanStatusRunning}, ErrNoClientCert},
+	}
+
+	for _, tt := range tests {
+		tt.scan.ID, tt.scan.OrganizationID, tt.scan.InitiatedBy = uuid.New(), uuid.New(), uuid.New()
+		mock.ExpectQuery(`FROM scan_jobs`).WithArgs(tt.scan.ID).
+			WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(tt.scan)...))
+
+		if _, err := service.WorkerClientCertificate(tt.scan.ID); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestClientCertificateRequiresWorkerSecret(t *testing.T) {
+	service, _ := newTestScanService(t)
+
+	certID := uuid.New()
+	err := service.validateConfigReferences(models.ScanConfig{ClientCertificateID: &certID}, uuid.New())
+	if !errors.Is(err, ErrInvalidScanConfig) || !strings.Contains(err.Error(), "WORKER_SECRET") {
+		t.Fatalf("error = %v, want a WORKER_SECRET config error", err)
+	}
+}
+
+// ingestRoundTrip stands in for the network latency of one statement
+const ingestRoundTrip = 200 * time.Microsecond
+
+// benchmarkResults returns n results of scan to ingest
+func benchmarkResults(n int) []IngestResult {
+	items := make([]IngestResult, n)
+	for i := range items {
+		items[i] = IngestResult{CheckType: "headers", Status: "success", Findings: 1, Severity: models.SeverityLow}
+	}
+	return items
+}
+
+// BenchmarkIngestResults compares storing a worker's report of 100 results
+// through IngestResults, which inserts them in one statement, with storing
+// them one row at a time
+func BenchmarkIngestResults(b *testing.B) {
+	const n = 100
+	summary := []string{"critical", "high", "medium", "low", "info"}
+
+	b.Run("bulk", func(b *testing.B) {
+		service, mock := newTestScanService(b)
+		scan := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(), Status: models.ScanStatusRunning, Checks: []string{"headers"}}
+		req := &IngestResultsRequest{Results: benchmarkResults(n)}
+
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			mock.ExpectQuery(`FROM scan_jobs`).WillDelayFor(ingestRoundTrip).
+				WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+			mock.ExpectQuery(`FROM severity_overrides`).WillDelayFor(ingestRoundTrip).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO scan_results`).WillDelayFor(ingestRoundTrip).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+			mock.ExpectQuery(`UPDATE scan_jobs`).WillDelayFor(ingestRoundTrip).
+				WillReturnRows(sqlmock.NewRows(summary).AddRow(0, 0, 0, n, 0))
+			mock.ExpectCommit()
+			mock.ExpectQuery(`FROM scan_jobs`).WillDelayFor(ingestRoundTrip).
+				WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+			b.StartTimer()
+
+			if _, err := service.IngestResults(scan.ID, req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per_row", func(b *testing.B) {
+		service, mock := newTestScanService(b)
+		scanID := uuid.New()
+
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			results := make([]*models.ScanResult, n)
+			for j := range results {
+				results[j] = &models.ScanResult{ID: uuid.New(), ScanID: scanID, CheckType: "headers", Status: "success",
+					Data: []byte("{}"), Findings: 1, Severity: models.SeverityLow}
+				mock.ExpectQuery(`INSERT INTO scan_results`).WillDelayFor(ingestRoundTrip).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+				mock.ExpectQuery(`UPDATE scan_jobs`).WillDelayFor(ingestRoundTrip).
+					WillReturnRows(sqlmock.NewRows(summary).AddRow(0, 0, 0, j+1, 0))
+			}
+			b.StartTimer()
+
+			for _, result := range results {
+				if err := service.scanRepo.CreateResult(result); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// scrapeScansCreated returns the publicscanner_scans_created_total sample
+// of checkType served at /metrics, or -1 if there is none
+func scrapeScansCreated(t *testing.T, checkType string) float64 {
+	t.Helper()
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d", w.Code)
+	}
+
+	prefix := `publicscanner_scans_created_total{check_type="` + checkType + `"} `
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if value, ok := strings.CutPrefix(line, prefix); ok {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("sample %q: %v", line, err)
+			}
+			return n
+		}
+	}
+	return -1
+}
+
+func TestCreateScanCountsInMetrics(t *testing.T) {
+	service, mock := newTestScanService(t)
+	service.rdb = newTestRedis(t)
+
+	before := max(scrapeScansCreated(t, "headers"), 0)
+
+	mock.ExpectQuery(`INSERT INTO scan_jobs`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	// An address literal keeps DNS out of the test
+	url := "https://93.184.215.14"
+	if _, err := service.CreateScan(&CreateScanRequest{URL: &url, Checks: []string{"headers"}}, uuid.New(), uuid.New()); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	if after := scrapeScansCreated(t, "headers"); after != before+1 {
+		t.Errorf("scans_created_total{check_type=\"headers\"} = %v after the scan, want %v", after, before+1)
+	}
+}
+
+// scanResultColumns mirror the columns GetResults selects
+var scanResultColumns = []string{
+	"id", "scan_id", "check_type", "status", "data", "findings", "severity",
+	"findings_by_severity", "original_severity", "original_findings_by_severity",
+	"triage_status", "acknowledged_by_rule", "created_at",
+}
+
+// expectResults answers the next results query of scanID with successful
+// results of the given data by check type
+func expectResults(mock sqlmock.Sqlmock, scanID uuid.UUID, data map[string]string) {
+	rows := sqlmock.NewRows(scanResultColumns)
+	for checkType, result := range data {
+		rows.AddRow(uuid.NewString(), scanID.String(), checkType, "success", []byte(result), 1, models.SeverityLow,
+			[]byte("{}"), nil, []byte("{}"), "open", nil, time.Now())
+	}
+	mock.ExpectQuery(`FROM scan_results sr`).WithArgs(scanID, "").WillReturnRows(rows)
+}
+
+func TestDiffScans(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	url := "https://example.com"
+	organizationID := uuid.New()
+	scan := &models.ScanJob{ID: uuid.New(), URL: &url, OrganizationID: organizationID, InitiatedBy: uuid.New(),
+		Status: models.ScanStatusCompleted, Checks: []string{"portscan", "headers"}}
+	against := *scan
+	against.ID = uuid.New()
+
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(against.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(&against)...))
+	expectResults(mock, scan.ID, map[string]string{
+		"portscan": `{"open_ports":[{"port":443,"protocol":"tcp"},{"port":8080,"protocol":"tcp"}]}`,
+		"headers":  `{"missing_headers":["X-Frame-Options"]}`,
+	})
+	expectResults(mock, against.ID, map[string]string{
+		"portscan": `{"open_ports":[{"port":443,"protocol":"tcp"},{"port":22,"protocol":"tcp"}]}`,
+		"headers":  `{"missing_headers":["X-Frame-Options"]}`,
+	})
+
+	diff, err := service.DiffScans(scan.ID, against.ID, organizationID)
+	if err != nil {
+		t.Fatalf("DiffScans: %v", err)
+	}
+	if diff.Identical {
+		t.Error("diff of scans with different ports is identical")
+	}
+
+	keys := func(findings []models.ResultFinding) string {
+		var keys []string
+		for _, finding := range findings {
+			keys = append(keys, finding.Key)
+		}
+		return strings.Join(keys, ",")
+	}
+	ports, headers := diff.Checks["portscan"], diff.Checks["headers"]
+	if ports == nil || keys(ports.Added) != "8080/tcp" || keys(ports.Removed) != "22/tcp" || ports.Unchanged != 1 {
+		t.Errorf("portscan diff = %+v, want 8080/tcp added, 22/tcp removed and one unchanged", ports)
+	}
+	if headers == nil || len(headers.Added)+len(headers.Removed)+len(headers.Changed) != 0 || headers.Unchanged != 1 {
+		t.Errorf("headers diff = %+v, want one unchanged finding", headers)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffScansRefusesOtherTargets(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	url, otherURL := "https://example.com", "https://example.org"
+	organizationID := uuid.New()
+	scan := &models.ScanJob{ID: uuid.New(), URL: &url, OrganizationID: organizationID, InitiatedBy: uuid.New()}
+	other := &models.ScanJob{ID: uuid.New(), URL: &otherURL, OrganizationID: organizationID, InitiatedBy: uuid.New()}
+	foreign := &models.ScanJob{ID: uuid.New(), URL: &url, OrganizationID: uuid.New(), InitiatedBy: uuid.New()}
+
+	tests := []struct {
+		against *models.ScanJob
+		err     error
+	}{
+		{other, ErrScanTargetsDiffer},
+		{foreign, ErrScanNotFound},
+	}
+
+	for _, tt := range tests {
+		mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scan.ID).
+			WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+		mock.ExpectQuery(`FROM scan_jobs`).WithArgs(tt.against.ID).
+			WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(tt.against)...))
+
+		if _, err := service.DiffScans(scan.ID, tt.against.ID, organizationID); !errors.Is(err, tt.err) {
+			t.Errorf("DiffScans against %s: error = %v, want %v", *tt.against.URL, err, tt.err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateScanBatchReportsEachTarget(t *testing.T) {
+	service, mock := newTestScanService(t)
+	service.rdb = newTestRedis(t)
+	organizationID := uuid.New()
+
+	target, missing, foreign := uuid.New(), uuid.New(), uuid.New()
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1`).WithArgs(target).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(target, organizationID, "93.184.215.14", nil)...))
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1`).WithArgs(missing).
+		WillReturnRows(sqlmock.NewRows(targetColumns))
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1`).WithArgs(foreign).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(foreign, uuid.New(), "93.184.215.15", nil)...))
+
+	// Only the two usable items are stored, together
+	mock.ExpectBegin()
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`INSERT INTO scan_jobs`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+	}
+	mock.ExpectCommit()
+
+	result, err := service.CreateScanBatch(&BulkScanRequest{
+		TargetIDs: []uuid.UUID{target, missing, foreign},
+		// Address literals keep DNS out of the test
+		URLs:   []string{"https://93.184.215.16", "https://10.0.0.1", "https://93.184.215.16"},
+		Checks: []string{"headers"},
+	}, uuid.New(), organizationID)
+	if err != nil {
+		t.Fatalf("CreateScanBatch: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		created bool
+		err     string
+	}{
+		{true, ""},
+		{false, "target not found"},
+		{false, "target not found"},
+		{true, ""},
+		{false, "private"},
+		{false, "duplicate"},
+	}
+	if result.Created != 2 || result.Failed != 4 || len(result.Items) != len(want) {
+		t.Fatalf("created %d, failed %d of %d items; want 2, 4 of %d", result.Created, result.Failed, len(result.Items), len(want))
+	}
+	for i, item := range result.Items {
+		if (item.Scan != nil) != want[i].created || !strings.Contains(item.Error, want[i].err) {
+			t.Errorf("item %d: scan %v, error %q; want created %v, error containing %q", i, item.Scan != nil, item.Error, want[i].created, want[i].err)
+		}
+	}
+
+	// Both created scans were queued
+	if queued, err := service.rdb.LLen(context.Background(), CeleryQueue).Result(); err != nil || queued != 2 {
+		t.Errorf("%d tasks queued (%v), want 2", queued, err)
+	}
+}
+
+func TestScanPolicyEvaluatedOnCompletion(t *testing.T) {
+	service, mock := newTestScanService(t)
+
+	scan := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(),
+		Status: models.ScanStatusRunning, Progress: 50, Checks: []string{"headers"},
+		Config: models.ScanConfig{FailOnSeverity: models.SeverityMedium}}
+	selectScan := `FROM scan_jobs\s+WHERE id = \$1`
+
+	// Completing the scan evaluates the gate against the stored results
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	mock.ExpectQuery(`UPDATE scan_jobs\s+SET progress = GREATEST`).WithArgs(scan.ID, 100, "").
+		WillReturnRows(sqlmock.NewRows([]string{"previous", "progress"}).AddRow(50, 100))
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = \$2::text`).
+		WithArgs(scan.ID, string(models.ScanStatusCompleted), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectResults(mock, scan.ID, map[string]string{"headers": `{}`})
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET policy_passed = \$2`).WithArgs(scan.ID, true, models.SeverityLow).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	completed := models.ScanStatusCompleted
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Status: &completed}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+
+	// Reading the scan shows the stored outcome without writing
+	passed, worst := true, models.SeverityLow
+	scan.Status, scan.PolicyPassed, scan.WorstSeverity = models.ScanStatusCompleted, &passed, &worst
+	mock.ExpectQuery(selectScan).WithArgs(scan.ID).WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	got, err := service.GetScan(scan.ID, scan.OrganizationID)
+	if err != nil {
+		t.Fatalf("GetScan: %v", err)
+	}
+	if got.Policy == nil || got.Policy.Passed == nil || !*got.Policy.Passed ||
+		got.Policy.WorstSeverity == nil || *got.Policy.WorstSeverity != models.SeverityLow {
+		t.Errorf("policy = %+v", got.Policy)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequeueScansCountsOnlyPublishedScans(t *testing.T) {
+	service, mock := newTestScanService(t)
+	service.rdb = newTestRedis(t)
+
+	organizationID := uuid.New()
+	url := "https://93.184.215.14"
+	published := &models.ScanJob{ID: uuid.New(), URL: &url, OrganizationID: organizationID, InitiatedBy: uuid.New(),
+		Status: models.ScanStatusQueued, Checks: []string{"headers"}}
+	// Its target was deleted since the scan failed
+	targetID := uuid.New()
+	orphaned := &models.ScanJob{ID: uuid.New(), TargetID: &targetID, OrganizationID: organizationID, InitiatedBy: uuid.New(),
+		Status: models.ScanStatusQueued, Checks: []string{"headers"}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE scan_jobs\s+SET status = 'queued'`).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(published)...).AddRow(repositorytest.ScanJobRow(orphaned)...))
+	mock.ExpectExec(`DELETE FROM scan_results`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1`).WithArgs(targetID).WillReturnRows(sqlmock.NewRows(targetColumns))
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = 'failed'`).
+		WithArgs(orphaned.ID, string(models.FailureInvalidTarget), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := service.RequeueScans(&RequeueScansRequest{Statuses: []string{"failed"}}, organizationID)
+	if err != nil {
+		t.Fatalf("RequeueScans: %v", err)
+	}
+	if result.Requeued != 1 || result.Failed != 1 || len(result.FailedScanIDs) != 1 || result.FailedScanIDs[0] != orphaned.ID {
+		t.Errorf("result = %+v, want 1 requeued and %s failed", result, orphaned.ID)
+	}
+	if queued, _ := service.rdb.LLen(context.Background(), CeleryQueue).Result(); queued != 1 {
+		t.Errorf("%d tasks queued, want 1", queued)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var ErrScheduleNotFound = errors.New("scheduled scan not found")
+
+// ScheduleScanTag labels the scans started by scheduled scans
+const ScheduleScanTag = "schedule"
+
+// ScheduleService manages recurring scans of targets
+type ScheduleService struct {
+	scheduleRepo *repository.ScheduleRepository
+	targetRepo   *repository.TargetRepository
+	scanService  *ScanService
+}
+
+// NewScheduleService creates a new scheduled scan service
+func NewScheduleService(scheduleRepo *repository.ScheduleRepository, targetRepo *repository.TargetRepository, scanService *ScanService) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo: scheduleRepo,
+		targetRepo:   targetRepo,
+		scanService:  scanService,
+	}
+}
+
+// CreateScheduleRequest represents a scheduled scan creation request
+type CreateScheduleRequest struct {
+	TargetID       uuid.UUID         `json:"target_id" binding:"required"`
+	Checks         []string          `json:"checks" binding:"required"`
+	Config         models.ScanConfig `json:"config"`
+	CronExpression string            `json:"cron_expression" binding:"required"`
+}
+
+// CreateSchedule validates a schedule like a scan of its target and stores
+// it with its first run
+func (s *ScheduleService) CreateSchedule(req *CreateScheduleRequest, userID, organizationID uuid.UUID) (*models.ScheduledScan, error) {
+	problems := scanSettingsProblems(req.Checks, req.Config, nil)
+
+	target, err := s.targetRepo.GetByID(req.TargetID)
+	if err != nil && !errors.Is(err, repository.ErrTargetNotFound) {
+		return nil, err
+	}
+	if err != nil || target.OrganizationID != organizationID {
+		problems.add("target_id", "target not found")
+	}
+
+	if err := s.scanService.validateConfigReferences(req.Config, organizationID); err != nil {
+		if !errors.Is(err, ErrInvalidScanConfig) {
+			return nil, err
+		}
+		problems.add("config", "%v", err)
+	}
+
+	var next time.Time
+	cron, err := ParseCron(req.CronExpression)
+	if err != nil {
+		problems.add("cron_expression", "%v", err)
+	} else if next = cron.Next(time.Now()); next.IsZero() {
+		problems.add("cron_expression", "expression never matches a date")
+	}
+
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.ScheduledScan{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		TargetID:       req.TargetID,
+		Checks:         req.Checks,
+		Config:         req.Config,
+		CronExpression: req.CronExpression,
+		NextRunAt:      next,
+		CreatedBy:      userID,
+	}
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// GetSchedule retrieves a scheduled scan by ID
+func (s *ScheduleService) GetSchedule(scheduleID, organizationID uuid.UUID) (*models.ScheduledScan, error) {
+	schedule, err := s.scheduleRepo.GetByID(scheduleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduleNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+
+	// Verify schedule belongs to organization
+	if schedule.OrganizationID != organizationID {
+		return nil, ErrScheduleNotFound
+	}
+
+	return schedule, nil
+}
+
+// ListSchedules retrieves all scheduled scans for an organization
+func (s *ScheduleService) ListSchedules(organizationID uuid.UUID) ([]*models.ScheduledScan, error) {
+	return s.scheduleRepo.ListByOrganization(organizationID)
+}
+
+// DeleteSchedule deletes a scheduled scan; scans it already started are kept
+func (s *ScheduleService) DeleteSchedule(scheduleID, organizationID uuid.UUID) error {
+	if _, err := s.GetSchedule(scheduleID, organizationID); err != nil {
+		return err
+	}
+
+	if err := s.scheduleRepo.Delete(scheduleID); err != nil {
+		if errors.Is(err, repository.ErrScheduleNotFound) {
+			return ErrScheduleNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// StartDueSchedules starts a scan for every schedule due at now and moves
+// the schedule to its next run. Runs missed while the API was down are not
+// caught up: a schedule runs once and continues from now. Scans that fail
+// validation, e.g. because the wordlist was deleted, are logged and skipped.
+func (s *ScheduleService) StartDueSchedules(now time.Time) (int, error) {
+	schedules, err := s.scheduleRepo.ListDue(now, scheduledBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	started := 0
+	for _, schedule := range schedules {
+		cron, err := ParseCron(schedule.CronExpression)
+		if err != nil {
+			slog.Error("Scheduled scan has an invalid cron expression", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+
+		// Claim the run first so that concurrent schedulers start it once
+		claimed, err := s.scheduleRepo.Claim(schedule.ID, schedule.NextRunAt, cron.Next(now))
+		if err != nil {
+			return started, err
+		}
+		if !claimed {
+			continue
+		}
+
+		targetID := schedule.TargetID
+		req := &CreateScanRequest{
+			TargetID: &targetID,
+			Checks:   schedule.Checks,
+			Config:   schedule.Config,
+			Tags:     []string{ScheduleScanTag},
+		}
+		scan, err := s.scanService.CreateScan(req, schedule.CreatedBy, schedule.OrganizationID)
+		if err != nil {
+			slog.Error("Failed to start scheduled scan", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		started++
+
+		if err := s.scheduleRepo.RecordRun(schedule.ID, scan.ID, now); err != nil {
+			slog.Error("Failed to record run of scheduled scan", "schedule_id", schedule.ID, "scan_id", scan.ID, "error", err)
+		}
+	}
+
+	return started, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrInvalidAPIKey          = errors.New("invalid or expired api key")
+)
+
+// ServiceAccountService handles service accounts and their API keys
+type ServiceAccountService struct {
+	orgService  *OrganizationService
+	accountRepo *repository.ServiceAccountRepository
+}
+
+// NewServiceAccountService creates a new service account service
+func NewServiceAccountService(orgService *OrganizationService, accountRepo *repository.ServiceAccountRepository) *ServiceAccountService {
+	return &ServiceAccountService{
+		orgService:  orgService,
+		accountRepo: accountRepo,
+	}
+}
+
+// CreateServiceAccountRequest represents a service account creation request
+type CreateServiceAccountRequest struct {
+	Name string      `json:"name" binding:"required,min=1,max=100"`
+	Role models.Role `json:"role" binding:"required"`
+}
+
+// CreateServiceAccount creates a service account in the organization. The
+// role cannot be owner nor exceed the caller's own role.
+func (s *ServiceAccountService) CreateServiceAccount(organizationID, userID uuid.UUID, req *CreateServiceAccountRequest) (*models.ServiceAccount, error) {
+	callerRole, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems ValidationErrors
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		problems.add("name", "must not be blank")
+	}
+	if !req.Role.IsValid() || req.Role == models.RoleOwner || req.Role == models.RoleBilling {
+		problems.add("role", "must be one of admin, member, viewer")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+	if !callerRole.AtLeast(req.Role) {
+		return nil, ErrInsufficientRole
+	}
+
+	account := &models.ServiceAccount{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Name:           name,
+		Role:           req.Role,
+	}
+	if err := s.accountRepo.Create(account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// ListServiceAccounts retrieves the organization's service accounts
+func (s *ServiceAccountService) ListServiceAccounts(organizationID, userID uuid.UUID) ([]*models.ServiceAccount, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.ListByOrganization(organizationID)
+}
+
+// getServiceAccount loads a service account of the organization after
+// verifying the caller administers it
+func (s *ServiceAccountService) getServiceAccount(organizationID, userID, accountID uuid.UUID) (*models.ServiceAccount, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(organizationID, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return nil, ErrServiceAccountNotFound
+		}
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// DeactivateServiceAccount disables a service account and revokes its keys.
+// The account is kept so its past actions stay attributed.
+func (s *ServiceAccountService) DeactivateServiceAccount(organizationID, userID, accountID uuid.UUID) error {
+	account, err := s.getServiceAccount(organizationID, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accountRepo.Deactivate(account.ID); err != nil {
+		if errors.Is(err, repository.ErrServiceAccountNotFound) {
+			return ErrServiceAccountNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CreateAPIKeyRequest represents an API key creation request
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+	// ExpiresInDays limits the key's lifetime; omitted keys never expire
+	ExpiresInDays *int `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// IssueAPIKeyRequest names the service account to issue a key for when
+// the organization comes from the caller's token
+type IssueAPIKeyRequest struct {
+	ServiceAccountID uuid.UUID `json:"service_account_id" binding:"required"`
+	CreateAPIKeyRequest
+}
+
+// CreatedAPIKey is a new API key together with its secret, which is only
+// returned once
+type CreatedAPIKey struct {
+	*models.APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey issues a new API key for an active service account
+func (s *ServiceAccountService) CreateAPIKey(organizationID, userID, accountID uuid.UUID, req *CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	account, err := s.getServiceAccount(organizationID, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	secret, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		ID:             uuid.New(),
+		UserID:         account.ID,
+		OrganizationID: organizationID,
+		Name:           strings.TrimSpace(req.Name),
+		KeyHash:        hash,
+	}
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.accountRepo.CreateAPIKey(key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// ListAPIKeys retrieves a service account's API keys
+func (s *ServiceAccountService) ListAPIKeys(organizationID, userID, accountID uuid.UUID) ([]*models.APIKey, error) {
+	account, err := s.getServiceAccount(organizationID, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.ListAPIKeys(account.ID)
+}
+
+// RevokeAPIKey deactivates one of a service account's API keys
+func (s *ServiceAccountService) RevokeAPIKey(organizationID, userID, accountID, keyID uuid.UUID) error {
+	account, err := s.getServiceAccount(organizationID, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accountRepo.RevokeAPIKey(account.ID, keyID); err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey resolves an API key to the claims of its service
+// account, scoped to the key's organization
+func (s *ServiceAccountService) AuthenticateAPIKey(key string) (*auth.TokenClaims, error) {
+	apiKey, err := s.accountRepo.UseAPIKey(auth.HashAPIKey(key), time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	organizationID := apiKey.OrganizationID
+	return &auth.TokenClaims{
+		UserID:         apiKey.UserID,
+		OrganizationID: &organizationID,
+		ServiceAccount: true,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"publicscannerapi/internal/logging"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var ErrSessionIdle = errors.New("session expired due to inactivity")
+
+// sessionPolicyTTL is how long an organization's idle timeout is cached in
+// Redis. Policy changes clear the cache, so it only bounds how long a missed
+// clear can leave a stale timeout in place.
+const sessionPolicyTTL = time.Minute
+
+// SessionTracker enforces per-organization idle timeouts. The last activity
+// of every session is kept in Redis and expires together with the idle
+// window, so a session whose key is gone falls back to the issue time of
+// its token. Idle timeouts are cached in Redis too, so checking a session
+// does not hit the database.
+type SessionTracker struct {
+	rdb     *redis.Client
+	orgRepo *repository.OrganizationRepository
+}
+
+// NewSessionTracker creates a new session tracker
+func NewSessionTracker(rdb *redis.Client, orgRepo *repository.OrganizationRepository) *SessionTracker {
+	return &SessionTracker{
+		rdb:     rdb,
+		orgRepo: orgRepo,
+	}
+}
+
+// sessionPolicyKey is the Redis key caching an organization's idle timeout
+func sessionPolicyKey(organizationID uuid.UUID) string {
+	return fmt.Sprintf("session_policy:%s", organizationID)
+}
+
+// idleTimeout returns an organization's idle timeout, 0 when the check is
+// disabled or the organization is gone, from the cache when possible
+func (t *SessionTracker) idleTimeout(ctx context.Context, organizationID uuid.UUID) (time.Duration, error) {
+	key := sessionPolicyKey(organizationID)
+	cached, err := t.rdb.Get(ctx, key).Int64()
+	if err == nil {
+		return time.Duration(cached) * time.Second, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		logging.FromContext(ctx).Error("Session policy cache lookup failed", "organization_id", organizationID, "error", err)
+	}
+
+	var idleTimeout time.Duration
+	policy, err := t.orgRepo.GetSessionPolicy(organizationID)
+	switch {
+	case err == nil:
+		idleTimeout = policy.IdleTimeout()
+	case !errors.Is(err, repository.ErrOrganizationNotFound):
+		return 0, err
+	}
+
+	if err := t.rdb.Set(ctx, key, int64(idleTimeout/time.Second), sessionPolicyTTL).Err(); err != nil {
+		logging.FromContext(ctx).Error("Failed to cache session policy", "organization_id", organizationID, "error", err)
+	}
+	return idleTimeout, nil
+}
+
+// ForgetPolicy clears the cached idle timeout of an organization, so a
+// changed policy applies from the next request
+func (t *SessionTracker) ForgetPolicy(ctx context.Context, organizationID uuid.UUID) error {
+	return t.rdb.Del(ctx, sessionPolicyKey(organizationID)).Err()
+}
+
+// Touch rejects a session that has been idle longer than its organization
+// allows with ErrSessionIdle, and otherwise records the activity. Tokens
+// without an organization or session are not subject to idle timeouts.
+// Lookup failures are logged and let the request through, like the quota.
+func (t *SessionTracker) Touch(ctx context.Context, claims *auth.TokenClaims) error {
+	if claims.OrganizationID == nil || claims.SessionID == "" {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+
+	idleTimeout, err := t.idleTimeout(ctx, *claims.OrganizationID)
+	if err != nil {
+		logger.Error("Session policy lookup failed", "organization_id", *claims.OrganizationID, "error", err)
+		return nil
+	}
+	if idleTimeout <= 0 {
+		return nil
+	}
+	now := time.Now()
+	key := fmt.Sprintf("session_activity:%s", claims.SessionID)
+
+	lastActivity := time.Time{}
+	if claims.IssuedAt != nil {
+		lastActivity = claims.IssuedAt.Time
+	}
+	value, err := t.rdb.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		if unix, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil && time.Unix(unix, 0).After(lastActivity) {
+			lastActivity = time.Unix(unix, 0)
+		}
+	case !errors.Is(err, redis.Nil):
+		logger.Error("Session activity lookup failed", "session_id", claims.SessionID, "error", err)
+		return nil
+	}
+
+	if now.Sub(lastActivity) > idleTimeout {
+		return ErrSessionIdle
+	}
+
+	if err := t.rdb.Set(ctx, key, now.Unix(), idleTimeout).Err(); err != nil {
+		logger.Error("Failed to record session activity", "session_id", claims.SessionID, "error", err)
+	}
+	return nil
+}
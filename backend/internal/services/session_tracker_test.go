@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/pkg/auth"
+)
+
+// expectSessionPolicy answers the next session policy lookup of
+// organizationID with an idle timeout of minutes
+func expectSessionPolicy(mock sqlmock.Sqlmock, organizationID uuid.UUID, minutes int) {
+	mock.ExpectQuery(`SELECT id, idle_timeout_minutes\s+FROM organizations`).WithArgs(organizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "idle_timeout_minutes"}).AddRow(organizationID.String(), minutes))
+}
+
+func TestSessionTrackerCachesPolicy(t *testing.T) {
+	service, mock := newTestOrganizationService(t)
+	tracker := NewSessionTracker(newTestRedis(t), service.orgRepo)
+	service.sessions = tracker
+
+	organizationID, adminID := uuid.New(), uuid.New()
+	claims := &auth.TokenClaims{
+		OrganizationID:   &organizationID,
+		SessionID:        uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(time.Now().Add(-10 * time.Minute))},
+	}
+	touch := func() error { return tracker.Touch(context.Background(), claims) }
+
+	// Only the first request reads the policy from the database
+	expectSessionPolicy(mock, organizationID, 30)
+	for i := 0; i < 3; i++ {
+		if err := touch(); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the policy applies from the next request
+	expectCaller(mock, organizationID, adminID, models.RoleAdmin)
+	mock.ExpectExec(`UPDATE organizations SET idle_timeout_minutes`).WithArgs(organizationID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	minutes := 1
+	if _, err := service.UpdateSessionPolicy(organizationID, adminID, &UpdateSessionPolicyRequest{IdleTimeoutMinutes: &minutes}); err != nil {
+		t.Fatalf("UpdateSessionPolicy: %v", err)
+	}
+
+	// Without the recorded activity the session was last active when its
+	// token was issued, ten minutes ago
+	expectSessionPolicy(mock, organizationID, 1)
+	tracker.rdb.Del(context.Background(), "session_activity:"+claims.SessionID)
+	if err := touch(); !errors.Is(err, ErrSessionIdle) {
+		t.Fatalf("request after lowering the idle timeout: error = %v, want ErrSessionIdle", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
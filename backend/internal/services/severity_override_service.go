@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrSeverityOverrideNotFound = errors.New("severity override not found")
+	ErrSeverityOverrideExists   = errors.New("a rule for this check and severity already exists")
+)
+
+// SeverityOverrideService manages an organization's severity override rules
+type SeverityOverrideService struct {
+	orgService   *OrganizationService
+	overrideRepo *repository.SeverityOverrideRepository
+}
+
+// NewSeverityOverrideService creates a new severity override service
+func NewSeverityOverrideService(orgService *OrganizationService, overrideRepo *repository.SeverityOverrideRepository) *SeverityOverrideService {
+	return &SeverityOverrideService{
+		orgService:   orgService,
+		overrideRepo: overrideRepo,
+	}
+}
+
+// ListOverrides retrieves the organization's override rules
+func (s *SeverityOverrideService) ListOverrides(organizationID, userID uuid.UUID) (models.SeverityOverrides, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.overrideRepo.ListByOrganization(organizationID)
+}
+
+// CreateSeverityOverrideRequest represents a new override rule. Omitting
+// match_severity overrides every severity the check reports.
+type CreateSeverityOverrideRequest struct {
+	CheckType     string  `json:"check_type" binding:"required"`
+	MatchSeverity *string `json:"match_severity"`
+	Severity      string  `json:"severity" binding:"required"`
+}
+
+// CreateOverride adds an override rule. It applies to results ingested
+// from then on.
+func (s *SeverityOverrideService) CreateOverride(organizationID, userID uuid.UUID, req *CreateSeverityOverrideRequest) (*models.SeverityOverride, error) {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	var problems ValidationErrors
+	if !models.IsValidCheck(req.CheckType) {
+		problems.add("check_type", "unknown check: %s", req.CheckType)
+	}
+	if req.MatchSeverity != nil && !models.IsValidSeverity(*req.MatchSeverity) {
+		problems.add("match_severity", "must be one of critical, high, medium, low, info")
+	}
+	if !models.IsValidSeverity(req.Severity) {
+		problems.add("severity", "must be one of critical, high, medium, low, info")
+	}
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	rule := &models.SeverityOverride{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		CheckType:      req.CheckType,
+		MatchSeverity:  req.MatchSeverity,
+		Severity:       req.Severity,
+		CreatedBy:      &userID,
+	}
+	if err := s.overrideRepo.Create(rule); err != nil {
+		if errors.Is(err, repository.ErrSeverityOverrideExists) {
+			return nil, ErrSeverityOverrideExists
+		}
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteOverride removes an override rule. Results already ingested keep
+// the severity it gave them.
+func (s *SeverityOverrideService) DeleteOverride(organizationID, userID, ruleID uuid.UUID) error {
+	if _, err := s.orgService.requireRole(organizationID, userID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.overrideRepo.Delete(organizationID, ruleID); err != nil {
+		if errors.Is(err, repository.ErrSeverityOverrideNotFound) {
+			return ErrSeverityOverrideNotFound
+		}
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/pkg/auth"
+)
+
+var (
+	ErrShareNotFound   = errors.New("scan share not found")
+	ErrInvalidShareTTL = errors.New("invalid share lifetime")
+)
+
+// Share lifetime bounds
+const (
+	defaultShareTTL = 72 * time.Hour
+	maxShareTTL     = 30 * 24 * time.Hour
+)
+
+// ShareService handles read-only scan share links
+type ShareService struct {
+	shareRepo   *repository.ShareRepository
+	scanRepo    *repository.ScanRepository
+	scanService *ScanService
+	jwtSecret   string
+}
+
+// NewShareService creates a new scan share service
+func NewShareService(shareRepo *repository.ShareRepository, scanRepo *repository.ScanRepository, scanService *ScanService, jwtSecret string) *ShareService {
+	return &ShareService{
+		shareRepo:   shareRepo,
+		scanRepo:    scanRepo,
+		scanService: scanService,
+		jwtSecret:   jwtSecret,
+	}
+}
+
+// CreateShareRequest represents a share link request
+type CreateShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours"` // Defaults to 72, at most 720
+}
+
+// CreateShare mints a share for a scan and returns it with its signed token
+func (s *ShareService) CreateShare(scanID, userID, organizationID uuid.UUID, req *CreateShareRequest) (*models.ScanShare, string, error) {
+	ttl := defaultShareTTL
+	if req.ExpiresInHours != 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	if ttl <= 0 || ttl > maxShareTTL {
+		return nil, "", fmt.Errorf("%w: expires_in_hours must be between 1 and %d", ErrInvalidShareTTL, int(maxShareTTL.Hours()))
+	}
+
+	if _, err := s.scanService.GetScan(scanID, organizationID); err != nil {
+		return nil, "", err
+	}
+
+	share := &models.ScanShare{
+		ID:             uuid.New(),
+		ScanID:         scanID,
+		OrganizationID: organizationID,
+		CreatedBy:      userID,
+		ExpiresAt:      time.Now().Add(ttl).UTC(),
+	}
+
+	if err := s.shareRepo.Create(share); err != nil {
+		return nil, "", err
+	}
+
+	token, err := auth.GenerateShareToken(share.ID, share.ExpiresAt, s.jwtSecret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return share, token, nil
+}
+
+// ListShares retrieves the shares of a scan
+func (s *ShareService) ListShares(scanID, organizationID uuid.UUID) ([]*models.ScanShare, error) {
+	if _, err := s.scanService.GetScan(scanID, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.shareRepo.ListByScan(scanID)
+}
+
+// RevokeShare revokes a share so its token stops working immediately
+func (s *ShareService) RevokeShare(scanID, shareID, organizationID uuid.UUID) error {
+	share, err := s.shareRepo.GetByID(shareID)
+	if err != nil {
+		if errors.Is(err, repository.ErrShareNotFound) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+
+	if share.ScanID != scanID || share.OrganizationID != organizationID {
+		return ErrShareNotFound
+	}
+
+	return s.shareRepo.Revoke(share.ID)
+}
+
+// GetSharedScan resolves a share token to the sanitized scan view. Invalid,
+// expired and revoked tokens are all reported as ErrShareNotFound.
+func (s *ShareService) GetSharedScan(token string) (*models.SharedScanView, error) {
+	shareID, err := auth.ValidateShareToken(token, s.jwtSecret)
+	if err != nil {
+		return nil, ErrShareNotFound
+	}
+
+	share, err := s.shareRepo.GetByID(shareID)
+	if err != nil {
+		if errors.Is(err, repository.ErrShareNotFound) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+	if !share.Active(time.Now()) {
+		return nil, ErrShareNotFound
+	}
+
+	scan, err := s.scanService.GetScan(share.ScanID, share.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.scanService.scanTarget(scan)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.scanRepo.GetResults(scan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.SharedScanView{
+		Target:      target,
+		Status:      scan.Status,
+		Checks:      scan.Checks,
+		CreatedAt:   scan.CreatedAt,
+		StartedAt:   scan.StartedAt,
+		CompletedAt: scan.CompletedAt,
+		Results:     make([]*models.SharedScanResult, 0, len(results)),
+		ExpiresAt:   share.ExpiresAt,
+	}
+	for _, result := range results {
+		view.Results = append(view.Results, &models.SharedScanResult{
+			CheckType: result.CheckType,
+			Status:    result.Status,
+			Data:      result.Data,
+			Findings:  result.Findings,
+			Severity:  result.Severity,
+			CreatedAt: result.CreatedAt,
+		})
+	}
+
+	return view, nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"publicscannerapi/internal/models"
+)
+
+// SyncScanTimeout bounds how long a synchronous scan may run inline before
+// it falls back to the worker queue
+const SyncScanTimeout = 10 * time.Second
+
+// syncCheckFunc runs one check inline against a quick scan target. The
+// result mirrors what the worker reports for the same check. Sync checks
+// connect from the API itself, so every connection goes through the
+// address policy and errors are reported without connection details.
+type syncCheckFunc func(ctx context.Context, addresses AddressPolicy, target string) IngestResult
+
+// syncChecks are the only checks allowed in synchronous mode: single cheap
+// requests whose runtime is bounded by SyncScanTimeout
+var syncChecks = map[string]syncCheckFunc{
+	models.CheckPing:    runPingCheck,
+	models.CheckHeaders: runHeadersCheck,
+}
+
+// SyncCheckNames lists the checks allowed in synchronous mode
+func SyncCheckNames() []string {
+	return []string{models.CheckHeaders, models.CheckPing}
+}
+
+// securityHeaders are the response headers checked by the headers check
+var securityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"X-XSS-Protection",
+	"Referrer-Policy",
+	"Permissions-Policy",
+}
+
+// syncCheckResult builds a result, encoding data as its JSON payload
+func syncCheckResult(checkType, status string, data map[string]interface{}, findings int, severity string) IngestResult {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		payload = json.RawMessage("{}")
+	}
+	return IngestResult{
+		CheckType: checkType,
+		Status:    status,
+		Data:      payload,
+		Findings:  findings,
+		Severity:  severity,
+	}
+}
+
+// runHeadersCheck fetches the target over HTTPS (or the URL's own scheme)
+// and reports missing security headers
+func runHeadersCheck(ctx context.Context, addresses AddressPolicy, target string) IngestResult {
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return syncCheckResult(models.CheckHeaders, "failed", map[string]interface{}{"error": "Invalid target URL"}, 0, models.SeverityInfo)
+	}
+
+	resp, err := addresses.httpClient(SyncScanTimeout).Do(req)
+	if err != nil {
+		return syncCheckResult(models.CheckHeaders, "failed", map[string]interface{}{"error": "Failed to fetch headers"}, 0, models.SeverityInfo)
+	}
+	resp.Body.Close()
+
+	present := make(map[string]string, len(resp.Header))
+	for name := range resp.Header {
+		present[name] = resp.Header.Get(name)
+	}
+
+	missing := []string{}
+	for _, header := range securityHeaders {
+		if resp.Header.Get(header) == "" {
+			missing = append(missing, header)
+		}
+	}
+
+	severity := models.SeverityInfo
+	switch {
+	case len(missing) >= 5:
+		severity = models.SeverityHigh
+	case len(missing) >= 3:
+		severity = models.SeverityMedium
+	case len(missing) > 0:
+		severity = models.SeverityLow
+	}
+
+	server := resp.Header.Get("Server")
+	if server == "" {
+		server = "unknown"
+	}
+
+	return syncCheckResult(models.CheckHeaders, "success", map[string]interface{}{
+		"headers_present": present,
+		"missing_headers": missing,
+		"server":          server,
+	}, len(missing), severity)
+}
+
+// runPingCheck tests reachability with a TCP connect to the target's web
+// ports. ICMP needs raw sockets the API does not have, so unlike the worker
+// check this measures the TCP handshake.
+func runPingCheck(ctx context.Context, addresses AddressPolicy, target string) IngestResult {
+	host, ports := pingTarget(target)
+
+	dialer := addresses.dialer(SyncScanTimeout)
+	for _, port := range ports {
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			continue
+		}
+		elapsed := time.Since(start)
+		conn.Close()
+
+		return syncCheckResult(models.CheckPing, "success", map[string]interface{}{
+			"reachable":        true,
+			"response_time_ms": float64(elapsed.Microseconds()) / 1000,
+			"method":           "tcp",
+			"port":             port,
+		}, 0, models.SeverityInfo)
+	}
+
+	return syncCheckResult(models.CheckPing, "success", map[string]interface{}{
+		"reachable": false,
+		"error":     "Target is not reachable",
+		"method":    "tcp",
+	}, 1, models.SeverityHigh)
+}
+
+// pingTarget returns the host of a quick scan target and the ports to try,
+// honouring an explicit scheme or port
+func pingTarget(target string) (string, []string) {
+	raw := target
+	if !strings.Contains(raw, "://") {
+		raw = "tcp://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return target, []string{"443", "80"}
+	}
+
+	if port := parsed.Port(); port != "" {
+		return parsed.Hostname(), []string{port}
+	}
+	switch parsed.Scheme {
+	case "http":
+		return parsed.Hostname(), []string{"80"}
+	case "https":
+		return parsed.Hostname(), []string{"443"}
+	}
+	return parsed.Hostname(), []string{"443", "80"}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"publicscannerapi/internal/models"
+)
+
+func TestRunHeadersCheckBlocksInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Internal-Secret", "leaked")
+	}))
+	defer server.Close()
+
+	result := runHeadersCheck(context.Background(), AddressPolicy{}, server.URL)
+	if result.Status != "failed" {
+		t.Fatalf("status = %q, want failed", result.Status)
+	}
+	if strings.Contains(string(result.Data), "leaked") || strings.Contains(string(result.Data), "127.0.0.1") {
+		t.Fatalf("result leaks the internal response or address: %s", result.Data)
+	}
+
+	result = runHeadersCheck(context.Background(), AddressPolicy{AllowPrivate: true}, server.URL)
+	if result.Status != "success" {
+		t.Fatalf("with AllowPrivate status = %q, want success", result.Status)
+	}
+}
+
+func TestRunPingCheckHidesDialErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	result := runPingCheck(context.Background(), AddressPolicy{}, "http://"+listener.Addr().String())
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data["reachable"] != false {
+		t.Fatalf("reachable = %v, want false for a loopback target", data["reachable"])
+	}
+	if _, ok := data["detail"]; ok {
+		t.Fatalf("result carries the dial error: %v", data["detail"])
+	}
+	if result.Severity != models.SeverityHigh {
+		t.Fatalf("severity = %q, want %q", result.Severity, models.SeverityHigh)
+	}
+}
+
+func TestAddressPolicyHTTPClientRefusesInternalRedirects(t *testing.T) {
+	client := AddressPolicy{}.httpClient(SyncScanTimeout)
+
+	for _, target := range []string{
+		"http://127.0.0.1:6379/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://localhost/",
+	} {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.CheckRedirect(req, []*http.Request{{}}); err == nil {
+			t.Errorf("redirect to %s was allowed", target)
+		}
+	}
+}
+
+func TestRunSyncChecksStopsWhenContextEnds(t *testing.T) {
+	arrived, abandoned := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(arrived)
+		<-r.Context().Done()
+		close(abandoned)
+	}))
+	defer server.Close()
+
+	// The client disconnects while the check is still waiting on the target
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		cancel()
+	}()
+
+	start := time.Now()
+	if _, ok := runSyncChecks(ctx, []string{models.CheckHeaders}, AddressPolicy{AllowPrivate: true}, server.URL, SyncScanTimeout); ok {
+		t.Fatal("runSyncChecks reported the checks as finished")
+	}
+	if elapsed := time.Since(start); elapsed >= SyncScanTimeout {
+		t.Fatalf("runSyncChecks took %s, want it to stop with ctx", elapsed)
+	}
+	select {
+	case <-abandoned:
+	case <-time.After(time.Second):
+		t.Fatal("the check kept its connection to the target open")
+	}
+}
@@ -1,21 +1,90 @@
 package services
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"publicscannerapi/internal/models"
 	"publicscannerapi/internal/repository"
 )
 
+// TagPolicy bounds the tags a target may carry
+type TagPolicy struct {
+	MaxTags   int
+	MaxLength int
+}
+
+// tagPattern is the character set allowed in a normalized tag
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]*$`)
+
 // TargetService handles target business logic
 type TargetService struct {
 	targetRepo *repository.TargetRepository
+	tagPolicy  TagPolicy
+	addresses  AddressPolicy
 }
 
 // NewTargetService creates a new target service
-func NewTargetService(targetRepo *repository.TargetRepository) *TargetService {
+func NewTargetService(targetRepo *repository.TargetRepository, tagPolicy TagPolicy, addresses AddressPolicy) *TargetService {
 	return &TargetService{
 		targetRepo: targetRepo,
+		tagPolicy:  tagPolicy,
+		addresses:  addresses,
+	}
+}
+
+// normalizeHostnames validates batch hostnames against the address policy.
+// Invalid entries keep their trimmed input so they can be reported back.
+func (s *TargetService) normalizeHostnames(raw []string) ([]string, []error) {
+	hostnames := make([]string, len(raw))
+	errs := make([]error, len(raw))
+	for i, hostname := range raw {
+		hostname = strings.TrimSpace(hostname)
+		if hostname == "" {
+			continue
+		}
+		normalized, err := s.addresses.normalizeHostname(hostname)
+		if err != nil {
+			hostnames[i], errs[i] = hostname, err
+			continue
+		}
+		hostnames[i] = normalized
+	}
+	return hostnames, errs
+}
+
+// normalizeTags trims, lowercases and dedupes tags, then checks them against
+// the tag policy. Empty tags are dropped.
+func (s *TargetService) normalizeTags(tags []string) ([]string, ValidationErrors) {
+	var errs ValidationErrors
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+
+	for i, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+
+		field := fmt.Sprintf("tags[%d]", i)
+		switch {
+		case s.tagPolicy.MaxLength > 0 && len(tag) > s.tagPolicy.MaxLength:
+			errs.add(field, "must be at most %d characters", s.tagPolicy.MaxLength)
+		case !tagPattern.MatchString(tag):
+			errs.add(field, "may only contain letters, digits, '.', '_', ':' and '-', starting with a letter or digit")
+		}
+		normalized = append(normalized, tag)
 	}
+
+	if s.tagPolicy.MaxTags > 0 && len(normalized) > s.tagPolicy.MaxTags {
+		errs.add("tags", "at most %d tags are allowed", s.tagPolicy.MaxTags)
+	}
+
+	return normalized, errs
 }
 
 // CreateTargetRequest represents a target creation request
@@ -33,17 +102,32 @@ type UpdateTargetRequest struct {
 	Description string   `json:"description"`
 	Tags        []string `json:"tags"`
 	IsActive    *bool    `json:"is_active"`
+	// IsMonitored turns periodic monitor scans on or off; turning them on
+	// schedules the first run right away
+	IsMonitored          *bool `json:"is_monitored"`
+	MonitorIntervalHours *int  `json:"monitor_interval_hours"`
 }
 
 // CreateTarget creates a new target
 func (s *TargetService) CreateTarget(req *CreateTargetRequest, userID, organizationID uuid.UUID) (*models.Target, error) {
+	hostname, err := s.addresses.normalizeHostname(req.Hostname)
+	if err != nil {
+		var problems ValidationErrors
+		problems.add("hostname", "%v", err)
+		return nil, problems.err()
+	}
+	tags, errs := s.normalizeTags(req.Tags)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
 	target := &models.Target{
 		ID:             uuid.New(),
 		OrganizationID: organizationID,
 		Name:           req.Name,
-		Hostname:       req.Hostname,
+		Hostname:       hostname,
 		Description:    req.Description,
-		Tags:           req.Tags,
+		Tags:           tags,
 		IsActive:       true,
 		CreatedBy:      userID,
 	}
@@ -55,6 +139,211 @@ func (s *TargetService) CreateTarget(req *CreateTargetRequest, userID, organizat
 	return target, nil
 }
 
+// MaxTargetBatchSize is the most targets accepted by a single batch create
+const MaxTargetBatchSize = 500
+
+// RejectedTarget describes a batch entry that was not created
+type RejectedTarget struct {
+	Index    int    `json:"index"`
+	Hostname string `json:"hostname"`
+	Reason   string `json:"reason"`
+}
+
+// BatchCreateTargetsResult is the outcome of a batch target creation
+type BatchCreateTargetsResult struct {
+	Created  []*models.Target `json:"created"`
+	Rejected []RejectedTarget `json:"rejected"`
+}
+
+// CreateTargetsBatch validates each entry, skips hostnames that already exist
+// in the organization (or repeat within the batch), and creates the rest in a
+// single transaction
+func (s *TargetService) CreateTargetsBatch(reqs []CreateTargetRequest, userID, organizationID uuid.UUID) (*BatchCreateTargetsResult, error) {
+	result := &BatchCreateTargetsResult{
+		Created:  []*models.Target{},
+		Rejected: []RejectedTarget{},
+	}
+
+	raw := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		raw = append(raw, req.Hostname)
+	}
+	hostnames, hostErrs := s.normalizeHostnames(raw)
+
+	existing, err := s.targetRepo.ExistingHostnames(organizationID, hostnames)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var targets []*models.Target
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		hostname := hostnames[i]
+		key := strings.ToLower(hostname)
+		tags, tagErrs := s.normalizeTags(req.Tags)
+
+		reason := targetEntryProblem(name, hostname, hostErrs[i], tagErrs)
+		switch {
+		case reason != "":
+		case existing[key]:
+			reason = "target with this hostname already exists"
+		case seen[key]:
+			reason = "duplicate hostname in batch"
+		}
+		if reason != "" {
+			result.Rejected = append(result.Rejected, RejectedTarget{Index: i, Hostname: hostname, Reason: reason})
+			continue
+		}
+
+		seen[key] = true
+		targets = append(targets, &models.Target{
+			ID:             uuid.New(),
+			OrganizationID: organizationID,
+			Name:           name,
+			Hostname:       hostname,
+			Description:    req.Description,
+			Tags:           tags,
+			IsActive:       true,
+			CreatedBy:      userID,
+		})
+	}
+
+	if len(targets) > 0 {
+		if err := s.targetRepo.CreateBatch(targets); err != nil {
+			return nil, err
+		}
+		result.Created = targets
+	}
+
+	return result, nil
+}
+
+// targetEntryProblem describes why a batch or apply entry is invalid, or
+// returns "" when it is valid
+func targetEntryProblem(name, hostname string, hostErr error, tagErrs ValidationErrors) string {
+	switch {
+	case name == "":
+		return "name is required"
+	case len(name) > 255:
+		return "name must be at most 255 characters"
+	case hostname == "":
+		return "hostname is required"
+	case hostErr != nil:
+		return "hostname " + hostErr.Error()
+	case len(tagErrs) > 0:
+		return tagErrs[0].Field + " " + tagErrs[0].Message
+	}
+	return ""
+}
+
+// ApplyTargetsResult is the outcome of applying target specs
+type ApplyTargetsResult struct {
+	Created   []*models.Target `json:"created"`
+	Updated   []*models.Target `json:"updated"`
+	Unchanged []*models.Target `json:"unchanged"`
+	Rejected  []RejectedTarget `json:"rejected"`
+}
+
+// ApplyTargets upserts targets from portable specs, matching existing
+// targets by hostname (case-insensitively). A matched target takes the
+// spec's name, description and tags; the rest are created. Invalid entries
+// are rejected individually and all writes happen in one transaction.
+func (s *TargetService) ApplyTargets(specs []models.TargetSpec, userID, organizationID uuid.UUID) (*ApplyTargetsResult, error) {
+	result := &ApplyTargetsResult{
+		Created:   []*models.Target{},
+		Updated:   []*models.Target{},
+		Unchanged: []*models.Target{},
+		Rejected:  []RejectedTarget{},
+	}
+
+	raw := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		raw = append(raw, spec.Hostname)
+	}
+	hostnames, hostErrs := s.normalizeHostnames(raw)
+
+	existing, err := s.targetRepo.GetByHostnames(organizationID, hostnames)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var creates, updates []*models.Target
+	var history []*models.TargetHistoryEntry
+	for i, spec := range specs {
+		name := strings.TrimSpace(spec.Name)
+		hostname := hostnames[i]
+		key := strings.ToLower(hostname)
+		tags, tagErrs := s.normalizeTags(spec.Tags)
+
+		reason := targetEntryProblem(name, hostname, hostErrs[i], tagErrs)
+		if reason == "" && seen[key] {
+			reason = "duplicate hostname in document"
+		}
+		if reason != "" {
+			result.Rejected = append(result.Rejected, RejectedTarget{Index: i, Hostname: hostname, Reason: reason})
+			continue
+		}
+		seen[key] = true
+
+		target, found := existing[key]
+		if !found {
+			creates = append(creates, &models.Target{
+				ID:             uuid.New(),
+				OrganizationID: organizationID,
+				Name:           name,
+				Hostname:       hostname,
+				Description:    spec.Description,
+				Tags:           tags,
+				IsActive:       true,
+				CreatedBy:      userID,
+			})
+			continue
+		}
+
+		if target.Name == name && target.Description == spec.Description && equalTags(target.Tags, tags) {
+			result.Unchanged = append(result.Unchanged, target)
+			continue
+		}
+		before := *target
+		target.Name = name
+		target.Description = spec.Description
+		target.Tags = tags
+		updates = append(updates, target)
+		if entry := models.NewTargetHistoryEntry(&before, target, userID); entry != nil {
+			history = append(history, entry)
+		}
+	}
+
+	if len(creates) > 0 || len(updates) > 0 {
+		if err := s.targetRepo.Apply(creates, updates, history); err != nil {
+			return nil, err
+		}
+	}
+	result.Created = append(result.Created, creates...)
+	result.Updated = append(result.Updated, updates...)
+
+	return result, nil
+}
+
+// equalTags reports whether two normalized tag lists hold the same tags
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[tag] = true
+	}
+	for _, tag := range b {
+		if !set[tag] {
+			return false
+		}
+	}
+	return true
+}
+
 // GetTarget retrieves a target by ID
 func (s *TargetService) GetTarget(targetID, organizationID uuid.UUID) (*models.Target, error) {
 	target, err := s.targetRepo.GetByID(targetID)
@@ -70,45 +359,103 @@ func (s *TargetService) GetTarget(targetID, organizationID uuid.UUID) (*models.T
 	return target, nil
 }
 
-// ListTargets retrieves all targets for an organization
-func (s *TargetService) ListTargets(organizationID uuid.UUID) ([]*models.Target, error) {
-	return s.targetRepo.ListByOrganization(organizationID)
+// ListTargets retrieves an organization's targets. query matches the name,
+// hostname or description case-insensitively; tags keeps targets carrying
+// any of them. Both are optional.
+func (s *TargetService) ListTargets(organizationID uuid.UUID, query string, tags []string) ([]*models.Target, error) {
+	query = strings.TrimSpace(query)
+	if len(query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be at most %d characters", ErrInvalidFilter, maxSearchQueryLength)
+	}
+
+	// Tags are stored normalized, so the filter is normalized the same way
+	var tagFilter []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tagFilter = append(tagFilter, tag)
+		}
+	}
+
+	if query == "" && len(tagFilter) == 0 {
+		return s.targetRepo.ListByOrganization(organizationID)
+	}
+	return s.targetRepo.Search(organizationID, query, tagFilter)
 }
 
-// UpdateTarget updates a target
-func (s *TargetService) UpdateTarget(targetID, organizationID uuid.UUID, req *UpdateTargetRequest) (*models.Target, error) {
+// UpdateTarget updates a target, recording the changed fields and userID
+// in the target history
+func (s *TargetService) UpdateTarget(targetID, organizationID, userID uuid.UUID, req *UpdateTargetRequest) (*models.Target, error) {
 	// Get existing target
 	target, err := s.GetTarget(targetID, organizationID)
 	if err != nil {
 		return nil, err
 	}
+	before := *target
 
 	// Update fields if provided
 	if req.Name != "" {
 		target.Name = req.Name
 	}
 	if req.Hostname != "" {
-		target.Hostname = req.Hostname
+		hostname, err := s.addresses.normalizeHostname(req.Hostname)
+		if err != nil {
+			var problems ValidationErrors
+			problems.add("hostname", "%v", err)
+			return nil, problems.err()
+		}
+		target.Hostname = hostname
 	}
 	if req.Description != "" {
 		target.Description = req.Description
 	}
 	if req.Tags != nil {
-		target.Tags = req.Tags
+		tags, errs := s.normalizeTags(req.Tags)
+		if err := errs.err(); err != nil {
+			return nil, err
+		}
+		target.Tags = tags
 	}
 	if req.IsActive != nil {
 		target.IsActive = *req.IsActive
 	}
+	if req.MonitorIntervalHours != nil {
+		hours := *req.MonitorIntervalHours
+		if hours < models.MinMonitorIntervalHours || hours > models.MaxMonitorIntervalHours {
+			var problems ValidationErrors
+			problems.add("monitor_interval_hours", "must be between %d and %d", models.MinMonitorIntervalHours, models.MaxMonitorIntervalHours)
+			return nil, problems.err()
+		}
+		target.MonitorIntervalHours = hours
+	}
+	if req.IsMonitored != nil && *req.IsMonitored != target.IsMonitored {
+		target.IsMonitored = *req.IsMonitored
+		target.NextMonitorRunAt = nil
+		if target.IsMonitored {
+			now := time.Now().UTC()
+			target.NextMonitorRunAt = &now
+		}
+	}
 
 	// Save updates
-	if err := s.targetRepo.Update(target); err != nil {
+	if err := s.targetRepo.Update(target, models.NewTargetHistoryEntry(&before, target, userID)); err != nil {
 		return nil, err
 	}
 
 	return target, nil
 }
 
-// DeleteTarget deletes a target
+// GetTargetHistory retrieves the creation and change history of a target
+func (s *TargetService) GetTargetHistory(targetID, organizationID uuid.UUID) ([]*models.TargetHistoryEntry, error) {
+	target, err := s.GetTarget(targetID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.targetRepo.ListHistory(target.ID)
+}
+
+// DeleteTarget soft-deletes a target. Its scans and reports are kept and
+// still show its name; RestoreTarget brings it back.
 func (s *TargetService) DeleteTarget(targetID, organizationID uuid.UUID) error {
 	// Verify target exists and belongs to organization
 	_, err := s.GetTarget(targetID, organizationID)
@@ -118,3 +465,18 @@ func (s *TargetService) DeleteTarget(targetID, organizationID uuid.UUID) error {
 
 	return s.targetRepo.Delete(targetID)
 }
+
+// RestoreTarget restores a soft-deleted target
+func (s *TargetService) RestoreTarget(targetID, organizationID uuid.UUID) (*models.Target, error) {
+	target, err := s.targetRepo.GetDeletedByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify target belongs to organization
+	if target.OrganizationID != organizationID {
+		return nil, repository.ErrTargetNotFound
+	}
+
+	return s.targetRepo.Restore(target.ID)
+}
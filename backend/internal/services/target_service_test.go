@@ -0,0 +1,138 @@
+package services
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"publicscannerapi/internal/repository"
+)
+
+// targetColumns mirror the repository's targetColumns
+var targetColumns = []string{
+	"id", "organization_id", "name", "hostname", "description", "tags", "is_active",
+	"is_monitored", "monitor_interval_hours", "next_monitor_run_at",
+	"created_by", "created_at", "updated_at", "deleted_at",
+}
+
+// targetRow returns the row of a target of organizationID, soft-deleted at
+// deletedAt unless it is nil
+func targetRow(id, organizationID uuid.UUID, hostname string, deletedAt *time.Time) []driver.Value {
+	now := time.Now()
+	var deleted interface{}
+	if deletedAt != nil {
+		deleted = *deletedAt
+	}
+	return []driver.Value{
+		id.String(), organizationID.String(), hostname, hostname, "", "{}", true,
+		false, 24, nil,
+		uuid.NewString(), now, now, deleted,
+	}
+}
+
+func TestListTargetsNormalizesFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewTargetService(repository.NewTargetRepository(db), TagPolicy{}, AddressPolicy{})
+	organizationID := uuid.New()
+
+	// Blank filters list everything
+	mock.ExpectQuery(`FROM targets\s+WHERE organization_id = \$1 AND deleted_at IS NULL\s+ORDER BY`).
+		WithArgs(organizationID).
+		WillReturnRows(sqlmock.NewRows(nil))
+	if _, err := service.ListTargets(organizationID, "  ", []string{" ", ""}); err != nil {
+		t.Fatalf("ListTargets without filters: %v", err)
+	}
+
+	// Tags match as stored: trimmed and lowercased
+	mock.ExpectQuery(`tags && \$3`).
+		WithArgs(organizationID, "%exam%", pq.Array([]string{"prod", "eu-west"})).
+		WillReturnRows(sqlmock.NewRows(nil))
+	if _, err := service.ListTargets(organizationID, " exam ", []string{" Prod", "", "EU-West "}); err != nil {
+		t.Fatalf("ListTargets with filters: %v", err)
+	}
+
+	if _, err := service.ListTargets(organizationID, strings.Repeat("x", maxSearchQueryLength+1), nil); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("overlong query: error = %v, want ErrInvalidFilter", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeletedTargetCanBeRestored(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	service := NewTargetService(repository.NewTargetRepository(db), TagPolicy{}, AddressPolicy{})
+
+	organizationID, targetID, otherID := uuid.New(), uuid.New(), uuid.New()
+	deletedAt := time.Now()
+
+	// Deleting marks the row instead of removing it
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(targetID, organizationID, "api.example.com", nil)...))
+	mock.ExpectExec(`UPDATE targets SET deleted_at = NOW\(\) WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(targetID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := service.DeleteTarget(targetID, organizationID); err != nil {
+		t.Fatalf("DeleteTarget: %v", err)
+	}
+
+	// From then on lookups and listings skip it
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows(targetColumns))
+	if _, err := service.GetTarget(targetID, organizationID); !errors.Is(err, repository.ErrTargetNotFound) {
+		t.Errorf("GetTarget after delete: error = %v, want ErrTargetNotFound", err)
+	}
+	mock.ExpectQuery(`FROM targets\s+WHERE organization_id = \$1 AND deleted_at IS NULL`).WithArgs(organizationID).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(otherID, organizationID, "shop.example.com", nil)...))
+	targets, err := service.ListTargets(organizationID, "", nil)
+	if err != nil {
+		t.Fatalf("ListTargets: %v", err)
+	}
+	if len(targets) != 1 || targets[0].ID != otherID {
+		t.Errorf("ListTargets after delete = %v, want only %s", targets, otherID)
+	}
+
+	// Another organization cannot restore it
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1 AND deleted_at IS NOT NULL`).WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(targetID, organizationID, "api.example.com", &deletedAt)...))
+	if _, err := service.RestoreTarget(targetID, uuid.New()); !errors.Is(err, repository.ErrTargetNotFound) {
+		t.Errorf("RestoreTarget by another organization: error = %v, want ErrTargetNotFound", err)
+	}
+
+	// Its own organization can
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1 AND deleted_at IS NOT NULL`).WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(targetID, organizationID, "api.example.com", &deletedAt)...))
+	mock.ExpectQuery(`UPDATE targets\s+SET deleted_at = NULL`).WithArgs(targetID).
+		WillReturnRows(sqlmock.NewRows(targetColumns).AddRow(targetRow(targetID, organizationID, "api.example.com", nil)...))
+	target, err := service.RestoreTarget(targetID, organizationID)
+	if err != nil {
+		t.Fatalf("RestoreTarget: %v", err)
+	}
+	if target.ID != targetID || target.DeletedAt != nil {
+		t.Errorf("restored target %s deleted at %v", target.ID, target.DeletedAt)
+	}
+
+	// A target that is not deleted has nothing to restore
+	mock.ExpectQuery(`FROM targets\s+WHERE id = \$1 AND deleted_at IS NOT NULL`).WithArgs(otherID).
+		WillReturnRows(sqlmock.NewRows(targetColumns))
+	if _, err := service.RestoreTarget(otherID, organizationID); !errors.Is(err, repository.ErrTargetNotFound) {
+		t.Errorf("RestoreTarget of an active target: error = %v, want ErrTargetNotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
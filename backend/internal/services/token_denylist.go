@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenylist records access tokens revoked before their expiry, by JWT
+// ID. Entries expire from Redis together with the token they block.
+type TokenDenylist struct {
+	rdb *redis.Client
+}
+
+// NewTokenDenylist creates a new access token denylist
+func NewTokenDenylist(rdb *redis.Client) *TokenDenylist {
+	return &TokenDenylist{rdb: rdb}
+}
+
+// denylistKey is the Redis key marking a token as revoked
+func denylistKey(tokenID string) string {
+	return fmt.Sprintf("revoked_token:%s", tokenID)
+}
+
+// Revoke blocks the token tokenID until it expires at expiresAt. Tokens
+// that already expired need no entry.
+func (d *TokenDenylist) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return d.rdb.Set(ctx, denylistKey(tokenID), 1, ttl).Err()
+}
+
+// IsRevoked reports whether the token tokenID was revoked
+func (d *TokenDenylist) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	err := d.rdb.Get(ctx, denylistKey(tokenID)).Err()
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+)
+
+var (
+	ErrWebhookNotFound        = errors.New("webhook not found")
+	ErrInvalidWebhookEvent    = errors.New("invalid webhook event")
+	ErrInvalidWebhookURL      = errors.New("invalid webhook url")
+	ErrWebhookFailureNotFound = errors.New("webhook failure not found")
+)
+
+// Webhook delivery headers
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// webhookResponseBodyLimit caps how much of a response body is kept per attempt
+const webhookResponseBodyLimit = 4096
+
+// webhookTimeout bounds a single delivery attempt
+const webhookTimeout = 10 * time.Second
+
+// WebhookService handles webhook management and event delivery
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	orgRepo     *repository.OrganizationRepository
+	addresses   AddressPolicy
+	client      *http.Client
+	milestones  []int // progress percentages that fire scan.progress events
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// NewWebhookService creates a new webhook service. Deliveries are attempted
+// maxAttempts times, backing off from retryDelay, before being dead-lettered.
+// Webhook URLs, and every address a delivery connects or is redirected to,
+// must be allowed by addresses. Scans crossing one of milestones fire
+// scan.progress.
+func NewWebhookService(webhookRepo *repository.WebhookRepository, orgRepo *repository.OrganizationRepository, addresses AddressPolicy, milestones []int, maxAttempts int, retryDelay time.Duration) *WebhookService {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		orgRepo:     orgRepo,
+		addresses:   addresses,
+		client:      addresses.httpClient(webhookTimeout),
+		milestones:  milestones,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+	}
+}
+
+// CreateWebhookRequest represents a webhook creation request
+type CreateWebhookRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+	Secret string   `json:"secret"` // Generated when omitted
+}
+
+// CreateWebhook creates a webhook and returns it together with its signing secret
+func (s *WebhookService) CreateWebhook(req *CreateWebhookRequest, userID, organizationID uuid.UUID) (*models.Webhook, error) {
+	for _, event := range req.Events {
+		if !isWebhookEvent(event) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWebhookEvent, event)
+		}
+	}
+
+	// Deliveries are made from inside our network, so the address must be
+	// as public as a scan target's
+	webhookURL, err := s.addresses.normalizeURL(strings.TrimSpace(req.URL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	webhook := &models.Webhook{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Name:           req.Name,
+		URL:            webhookURL,
+		Events:         req.Events,
+		Secret:         secret,
+		IsActive:       true,
+		CreatedBy:      userID,
+	}
+
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (s *WebhookService) GetWebhook(webhookID, organizationID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(webhookID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	// Verify webhook belongs to organization
+	if webhook.OrganizationID != organizationID {
+		return nil, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks retrieves all webhooks for an organization
+func (s *WebhookService) ListWebhooks(organizationID uuid.UUID) ([]*models.Webhook, error) {
+	return s.webhookRepo.ListByOrganization(organizationID)
+}
+
+// DeleteWebhook deletes a webhook
+func (s *WebhookService) DeleteWebhook(webhookID, organizationID uuid.UUID) error {
+	if _, err := s.GetWebhook(webhookID, organizationID); err != nil {
+		return err
+	}
+
+	return s.webhookRepo.Delete(webhookID)
+}
+
+// Dispatch delivers an event to every active webhook of the organization
+// subscribed to it, unless the organization's notification preferences turn
+// the event off. Deliveries run in the background.
+func (s *WebhookService) Dispatch(organizationID uuid.UUID, event string, data interface{}) error {
+	prefs, err := s.orgRepo.GetNotificationPreferences(organizationID)
+	if err != nil {
+		return err
+	}
+	if !prefs.AllowsWebhookEvent(event) {
+		return nil
+	}
+
+	webhooks, err := s.webhookRepo.ListActiveForEvent(organizationID, event)
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		payload := &models.WebhookPayload{
+			ID:             uuid.New(),
+			Event:          event,
+			OrganizationID: organizationID,
+			OccurredAt:     time.Now().UTC(),
+			Data:           data,
+		}
+
+		go func(webhook *models.Webhook) {
+			if err := s.deliver(webhook, payload); err != nil {
+				slog.Warn("Webhook delivery failed", "webhook_id", webhook.ID, "delivery_id", payload.ID, "error", err)
+			}
+		}(webhook)
+	}
+
+	return nil
+}
+
+// ScanProgressed dispatches scan.progress for every milestone a scan's
+// progress crossed going from one percentage to another
+func (s *WebhookService) ScanProgressed(scan *models.ScanJob, from, to int) {
+	for _, milestone := range s.milestones {
+		if milestone <= from || milestone > to {
+			continue
+		}
+		s.notify(scan.OrganizationID, models.WebhookEventScanProgress, &models.ScanProgressEvent{
+			ScanID:    scan.ID,
+			Status:    scan.Status,
+			Milestone: milestone,
+			Progress:  to,
+		})
+	}
+}
+
+// ScanFinished dispatches scan.completed for a scan that reached status,
+// completed or failed with failure
+func (s *WebhookService) ScanFinished(scan *models.ScanJob, status models.ScanStatus, failure *models.ScanFailure) {
+	event := &models.ScanCompletedEvent{ScanID: scan.ID, Status: status}
+	if status == models.ScanStatusFailed && failure != nil {
+		code := failure.Code
+		event.FailureCode = &code
+		event.FailureReason = failure.Reason
+	}
+
+	s.notify(scan.OrganizationID, models.WebhookEventScanCompleted, event)
+}
+
+// notify dispatches an event whose cause is already stored and will not
+// happen again. When the dispatch fails it is retried in the background
+// with the delivery backoff, then logged.
+func (s *WebhookService) notify(organizationID uuid.UUID, event string, data interface{}) {
+	err := s.Dispatch(organizationID, event, data)
+	if err == nil {
+		return
+	}
+
+	go func() {
+		delay := s.retryDelay
+		for attempt := 2; attempt <= s.maxAttempts; attempt++ {
+			time.Sleep(delay)
+			delay *= 2
+			if err = s.Dispatch(organizationID, event, data); err == nil {
+				return
+			}
+		}
+		slog.Warn("Webhook dispatch failed", "event", event, "organization_id", organizationID, "error", err)
+	}()
+}
+
+// deliver posts a signed payload to a webhook, retrying with exponential
+// backoff. When every attempt fails the event is stored as a dead letter.
+func (s *WebhookService) deliver(webhook *models.Webhook, payload *models.WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var attempts []models.WebhookAttempt
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		result := s.send(webhook, payload.Event, payload.ID, body)
+		result.Attempt = attempt
+		attempts = append(attempts, result)
+
+		if result.Error == "" {
+			return nil
+		}
+		if attempt == s.maxAttempts {
+			break
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+
+	failure := &models.WebhookFailure{
+		ID:         uuid.New(),
+		WebhookID:  webhook.ID,
+		DeliveryID: payload.ID,
+		Event:      payload.Event,
+		Payload:    body,
+		Attempts:   attempts,
+		LastError:  attempts[len(attempts)-1].Error,
+	}
+	if err := s.webhookRepo.CreateFailure(failure); err != nil {
+		return fmt.Errorf("%s (dead-letter failed: %v)", failure.LastError, err)
+	}
+
+	return errors.New(failure.LastError)
+}
+
+// send performs a single delivery attempt and records its outcome
+func (s *WebhookService) send(webhook *models.Webhook, event string, deliveryID uuid.UUID, body []byte) models.WebhookAttempt {
+	attempt := models.WebhookAttempt{AttemptedAt: time.Now().UTC()}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookDeliveryHeader, deliveryID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	attempt.StatusCode = resp.StatusCode
+	attempt.ResponseBody = string(responseBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+// ListFailures retrieves the dead-lettered deliveries of a webhook
+func (s *WebhookService) ListFailures(webhookID, organizationID uuid.UUID, includeResolved bool) ([]*models.WebhookFailure, error) {
+	if _, err := s.GetWebhook(webhookID, organizationID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepo.ListFailures(webhookID, includeResolved)
+}
+
+// RetryFailure redelivers a dead-lettered event once, synchronously, with the
+// original payload and delivery ID. The attempt is appended to the failure and
+// the failure is resolved when it succeeds.
+func (s *WebhookService) RetryFailure(webhookID, failureID, organizationID uuid.UUID) (*models.WebhookFailure, error) {
+	webhook, err := s.GetWebhook(webhookID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	failure, err := s.webhookRepo.GetFailure(failureID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookFailureNotFound) {
+			return nil, ErrWebhookFailureNotFound
+		}
+		return nil, err
+	}
+	if failure.WebhookID != webhook.ID {
+		return nil, ErrWebhookFailureNotFound
+	}
+
+	result := s.send(webhook, failure.Event, failure.DeliveryID, failure.Payload)
+	result.Attempt = len(failure.Attempts) + 1
+	failure.Attempts = append(failure.Attempts, result)
+
+	if result.Error == "" {
+		now := time.Now().UTC()
+		failure.ResolvedAt = &now
+	} else {
+		failure.LastError = result.Error
+	}
+
+	if err := s.webhookRepo.UpdateFailure(failure); err != nil {
+		return nil, err
+	}
+
+	return failure, nil
+}
+
+// SignWebhookPayload returns the signature header value for a payload body,
+// an HMAC-SHA256 keyed with the webhook secret
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func isWebhookEvent(event string) bool {
+	for _, known := range models.WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
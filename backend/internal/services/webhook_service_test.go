@@ -0,0 +1,318 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+)
+
+// newTestWebhookService returns a webhook service whose repositories use a
+// mocked database
+func newTestWebhookService(t *testing.T, addresses AddressPolicy) (*WebhookService, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := NewWebhookService(repository.NewWebhookRepository(db), repository.NewOrganizationRepository(db), addresses, []int{25, 50, 75, 100}, 1, time.Millisecond)
+	return service, mock
+}
+
+func TestCreateWebhookRejectsInternalURLs(t *testing.T) {
+	service, mock := newTestWebhookService(t, AddressPolicy{})
+
+	for _, url := range []string{
+		"http://127.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5:8080/hook",
+		"http://[::1]/hook",
+		"http://localhost/hook",
+		"http://redis/hook",
+		"http://metadata.google.internal/computeMetadata/v1/",
+		"ftp://93.184.215.14/hook",
+	} {
+		req := &CreateWebhookRequest{Name: "hook", URL: url, Events: []string{models.WebhookEvents[0]}}
+		if _, err := service.CreateWebhook(req, uuid.New(), uuid.New()); !errors.Is(err, ErrInvalidWebhookURL) {
+			t.Errorf("%s: error = %v, want ErrInvalidWebhookURL", url, err)
+		}
+	}
+	// None of them was stored
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookDeliveryFollowsAddressPolicy(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = r.Header.Get(WebhookSignatureHeader) + " " + string(body)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{ID: uuid.New(), URL: server.URL, Secret: "secret"}
+	body := []byte(`{"event":"scan.completed"}`)
+
+	// A webhook stored before its address became internal is still refused
+	// when the connection is made
+	service, _ := newTestWebhookService(t, AddressPolicy{})
+	attempt := service.send(webhook, "scan.completed", uuid.New(), body)
+	if !strings.Contains(attempt.Error, errBlockedAddress.Error()) || received != "" {
+		t.Errorf("delivery to %s: error = %q, received %q", server.URL, attempt.Error, received)
+	}
+
+	// So is a redirect to one
+	redirect := httptest.NewRequest(http.MethodPost, "http://169.254.169.254/latest/meta-data/", nil)
+	if err := service.client.CheckRedirect(redirect, []*http.Request{{}}); err == nil {
+		t.Error("a redirect to the metadata address would be followed")
+	}
+
+	service, _ = newTestWebhookService(t, AddressPolicy{AllowPrivate: true})
+	attempt = service.send(webhook, "scan.completed", uuid.New(), body)
+	if attempt.Error != "" || received != SignWebhookPayload("secret", body)+" "+string(body) {
+		t.Errorf("delivery with private addresses allowed: error = %q, received %q", attempt.Error, received)
+	}
+}
+
+// webhookReceiver is an endpoint that hands every payload it receives to
+// the test
+func webhookReceiver(t *testing.T) (*httptest.Server, chan models.WebhookPayload) {
+	t.Helper()
+	payloads := make(chan models.WebhookPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload models.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		payloads <- payload
+	}))
+	t.Cleanup(server.Close)
+	return server, payloads
+}
+
+// expectDispatch expects event to be dispatched for organizationID, with
+// default notification preferences, to one webhook at url
+func expectDispatch(mock sqlmock.Sqlmock, organizationID uuid.UUID, event, url string) {
+	mock.ExpectQuery(`FROM organization_notification_preferences`).WithArgs(organizationID).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id"}))
+	mock.ExpectQuery(`FROM webhooks\s+WHERE organization_id = \$1 AND is_active = true`).WithArgs(organizationID, event).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "organization_id", "name", "url", "events", "secret", "is_active", "created_by", "created_at", "updated_at",
+		}).AddRow(uuid.NewString(), organizationID.String(), "hook", url, "{"+event+"}", "secret", true, uuid.NewString(), time.Now(), time.Now()))
+}
+
+// receive waits for the next payload delivered to a webhook receiver
+func receive(t *testing.T, payloads chan models.WebhookPayload) models.WebhookPayload {
+	t.Helper()
+	select {
+	case payload := <-payloads:
+		return payload
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook was delivered")
+	}
+	return models.WebhookPayload{}
+}
+
+// expectIngest expects a worker report of progress on scan, which the
+// database moves from one percentage to another; when reported is 0
+// progress is not reported
+func expectIngest(mock sqlmock.Sqlmock, scan *models.ScanJob, reported, from, to int) {
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	if reported > 0 {
+		mock.ExpectQuery(`UPDATE scan_jobs\s+SET progress = GREATEST`).WithArgs(scan.ID, reported, "").
+			WillReturnRows(sqlmock.NewRows([]string{"previous", "progress"}).AddRow(from, to))
+	}
+}
+
+// receiveEvents waits for n payloads and returns them by event, ordered by
+// milestone for scan.progress
+func receiveEvents(t *testing.T, payloads chan models.WebhookPayload, n int) map[string][]json.RawMessage {
+	t.Helper()
+	events := make(map[string][]json.RawMessage)
+	for i := 0; i < n; i++ {
+		payload := receive(t, payloads)
+		data, _ := json.Marshal(payload.Data)
+		events[payload.Event] = append(events[payload.Event], data)
+	}
+	progress := events[models.WebhookEventScanProgress]
+	sort.Slice(progress, func(i, j int) bool {
+		var a, b models.ScanProgressEvent
+		json.Unmarshal(progress[i], &a)
+		json.Unmarshal(progress[j], &b)
+		return a.Milestone < b.Milestone
+	})
+	select {
+	case payload := <-payloads:
+		t.Fatalf("unexpected %s delivery", payload.Event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return events
+}
+
+// milestones returns the milestones of scan.progress events
+func milestones(t *testing.T, events []json.RawMessage) []int {
+	t.Helper()
+	var got []int
+	for _, data := range events {
+		var event models.ScanProgressEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, event.Milestone)
+	}
+	return got
+}
+
+func TestIngestResultsFiresCrossedMilestones(t *testing.T) {
+	server, payloads := webhookReceiver(t)
+	service, mock := newTestScanService(t)
+	webhooks, webhookMock := newTestWebhookService(t, AddressPolicy{AllowPrivate: true})
+	service.webhooks = webhooks
+
+	scan := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(),
+		Status: models.ScanStatusRunning, Progress: 20, Checks: []string{"headers"}}
+
+	// 20% to 60% crosses 25 and 50
+	expectIngest(mock, scan, 60, 20, 60)
+	for i := 0; i < 2; i++ {
+		expectDispatch(webhookMock, scan.OrganizationID, models.WebhookEventScanProgress, server.URL)
+	}
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	progress := 60
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Progress: &progress}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+	events := receiveEvents(t, payloads, 2)
+	if got := milestones(t, events[models.WebhookEventScanProgress]); !reflect.DeepEqual(got, []int{25, 50}) {
+		t.Errorf("20%% to 60%% fired milestones %v, want [25 50]", got)
+	}
+
+	// A report that does not move progress, e.g. from a slower concurrent
+	// worker thread, fires nothing
+	scan.Progress = 60
+	expectIngest(mock, scan, 40, 60, 60)
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	progress = 40
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Progress: &progress}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+	receiveEvents(t, payloads, 0)
+
+	// Completing from 60% fires 75 and 100 even though progress was never
+	// reported past 60, then scan.completed
+	expectIngest(mock, scan, 100, 60, 100)
+	for i := 0; i < 2; i++ {
+		expectDispatch(webhookMock, scan.OrganizationID, models.WebhookEventScanProgress, server.URL)
+	}
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = \$2::text`).
+		WithArgs(scan.ID, string(models.ScanStatusCompleted), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectDispatch(webhookMock, scan.OrganizationID, models.WebhookEventScanCompleted, server.URL)
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	status := models.ScanStatusCompleted
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Status: &status}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+	events = receiveEvents(t, payloads, 3)
+	if got := milestones(t, events[models.WebhookEventScanProgress]); !reflect.DeepEqual(got, []int{75, 100}) {
+		t.Errorf("completing from 60%% fired milestones %v, want [75 100]", got)
+	}
+	var completed models.ScanCompletedEvent
+	if len(events[models.WebhookEventScanCompleted]) != 1 {
+		t.Fatalf("%d scan.completed events, want 1", len(events[models.WebhookEventScanCompleted]))
+	}
+	json.Unmarshal(events[models.WebhookEventScanCompleted][0], &completed)
+	if completed.ScanID != scan.ID || completed.Status != models.ScanStatusCompleted || completed.FailureCode != nil {
+		t.Errorf("scan.completed = %+v", completed)
+	}
+
+	for _, m := range []sqlmock.Sqlmock{mock, webhookMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIngestResultsDispatchesScanFailed(t *testing.T) {
+	server, payloads := webhookReceiver(t)
+	service, mock := newTestScanService(t)
+	webhooks, webhookMock := newTestWebhookService(t, AddressPolicy{AllowPrivate: true})
+	service.webhooks = webhooks
+
+	scan := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), InitiatedBy: uuid.New(),
+		Status: models.ScanStatusRunning, Progress: 40, Checks: []string{"headers"}}
+	expectIngest(mock, scan, 0, 0, 0)
+	mock.ExpectExec(`UPDATE scan_jobs\s+SET status = \$2::text`).
+		WithArgs(scan.ID, string(models.ScanStatusFailed), string(models.FailureWorkerError), "worker crashed").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectDispatch(webhookMock, scan.OrganizationID, models.WebhookEventScanCompleted, server.URL)
+	mock.ExpectQuery(`FROM scan_jobs\s+WHERE id = \$1`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	status := models.ScanStatusFailed
+	if _, err := service.IngestResults(scan.ID, &IngestResultsRequest{Status: &status, FailureReason: "worker crashed"}); err != nil {
+		t.Fatalf("IngestResults: %v", err)
+	}
+
+	// A failed scan fires no milestones, only scan.completed
+	events := receiveEvents(t, payloads, 1)
+	var failed models.ScanCompletedEvent
+	if len(events[models.WebhookEventScanCompleted]) != 1 {
+		t.Fatalf("events = %v, want one scan.completed", events)
+	}
+	json.Unmarshal(events[models.WebhookEventScanCompleted][0], &failed)
+	if failed.Status != models.ScanStatusFailed || failed.FailureCode == nil ||
+		*failed.FailureCode != models.FailureWorkerError || failed.FailureReason != "worker crashed" {
+		t.Errorf("scan.completed = %+v", failed)
+	}
+
+	for _, m := range []sqlmock.Sqlmock{mock, webhookMock} {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWebhookNotifyRetriesFailedDispatch(t *testing.T) {
+	server, payloads := webhookReceiver(t)
+	service, mock := newTestWebhookService(t, AddressPolicy{AllowPrivate: true})
+	service.maxAttempts = 2
+
+	// The first lookup fails; the event is not lost
+	scan := &models.ScanJob{ID: uuid.New(), OrganizationID: uuid.New(), Status: models.ScanStatusRunning}
+	mock.ExpectQuery(`FROM organization_notification_preferences`).WithArgs(scan.OrganizationID).
+		WillReturnError(sql.ErrConnDone)
+	expectDispatch(mock, scan.OrganizationID, models.WebhookEventScanProgress, server.URL)
+
+	service.ScanProgressed(scan, 40, 50)
+	events := receiveEvents(t, payloads, 1)
+	if got := milestones(t, events[models.WebhookEventScanProgress]); !reflect.DeepEqual(got, []int{50}) {
+		t.Errorf("milestones %v, want [50]", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/storage"
+)
+
+var (
+	ErrWordlistNotFound = errors.New("wordlist not found")
+	ErrWordlistInUse    = errors.New("wordlist is referenced by active scans")
+	ErrWordlistTooLarge = errors.New("wordlist exceeds size limit")
+	ErrInvalidWordlist  = errors.New("invalid wordlist")
+)
+
+// WordlistService manages uploaded bruteforce wordlists
+type WordlistService struct {
+	wordlistRepo *repository.WordlistRepository
+	storage      storage.Storage
+	maxSize      int64
+}
+
+// NewWordlistService creates a new wordlist service
+func NewWordlistService(wordlistRepo *repository.WordlistRepository, store storage.Storage, maxSize int64) *WordlistService {
+	return &WordlistService{
+		wordlistRepo: wordlistRepo,
+		storage:      store,
+		maxSize:      maxSize,
+	}
+}
+
+// Upload stores a plain-text wordlist, counting its lines on the way. The
+// name defaults to the uploaded file name.
+func (s *WordlistService) Upload(organizationID, userID uuid.UUID, name, fileName string, r io.Reader) (*models.Wordlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = sanitizeFileName(fileName)
+	}
+	if len(name) > 100 {
+		return nil, fmt.Errorf("%w: name must be at most 100 characters", ErrInvalidWordlist)
+	}
+
+	// Sniff the content type from the first bytes
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidWordlist)
+	}
+	if contentType := http.DetectContentType(head); contentType != "text/plain; charset=utf-8" {
+		return nil, fmt.Errorf("%w: expected plain text, got %s", ErrInvalidWordlist, contentType)
+	}
+
+	wordlist := &models.Wordlist{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Name:           name,
+		CreatedBy:      &userID,
+	}
+	wordlist.StorageKey = fmt.Sprintf("wordlists/%s", wordlist.ID)
+
+	counter := &lineCounter{r: buffered}
+	size, err := s.storage.Save(wordlist.StorageKey, counter, s.maxSize)
+	if err != nil {
+		if errors.Is(err, storage.ErrTooLarge) {
+			return nil, ErrWordlistTooLarge
+		}
+		return nil, err
+	}
+	wordlist.SizeBytes = size
+	wordlist.LineCount = counter.lines()
+
+	if err := s.wordlistRepo.Create(wordlist); err != nil {
+		// Clean up file if database insert fails
+		_ = s.storage.Delete(wordlist.StorageKey)
+		return nil, err
+	}
+
+	return wordlist, nil
+}
+
+// GetWordlist retrieves a wordlist by ID
+func (s *WordlistService) GetWordlist(wordlistID, organizationID uuid.UUID) (*models.Wordlist, error) {
+	wordlist, err := s.wordlistRepo.GetByID(wordlistID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWordlistNotFound) {
+			return nil, ErrWordlistNotFound
+		}
+		return nil, err
+	}
+
+	// Verify wordlist belongs to organization
+	if wordlist.OrganizationID != organizationID {
+		return nil, ErrWordlistNotFound
+	}
+
+	return wordlist, nil
+}
+
+// Open opens the stored content of one of an organization's wordlists
+func (s *WordlistService) Open(wordlistID, organizationID uuid.UUID) (*models.Wordlist, storage.Object, error) {
+	wordlist, err := s.GetWordlist(wordlistID, organizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, err := s.storage.Open(wordlist.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrWordlistNotFound
+		}
+		return nil, nil, err
+	}
+
+	return wordlist, object, nil
+}
+
+// ListWordlists retrieves all wordlists for an organization with their usage
+func (s *WordlistService) ListWordlists(organizationID uuid.UUID) ([]*models.Wordlist, error) {
+	return s.wordlistRepo.ListByOrganization(organizationID)
+}
+
+// DeleteWordlist deletes a wordlist and its file. A wordlist still used by a
+// scheduled, queued or running scan is refused with ErrWordlistInUse.
+func (s *WordlistService) DeleteWordlist(wordlistID, organizationID uuid.UUID) error {
+	wordlist, err := s.GetWordlist(wordlistID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.wordlistRepo.Delete(wordlistID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWordlistNotFound):
+			return ErrWordlistNotFound
+		case errors.Is(err, repository.ErrWordlistInUse):
+			return ErrWordlistInUse
+		}
+		return err
+	}
+
+	// The row is gone, so a leftover file is only wasted space
+	if err := s.storage.Delete(wordlist.StorageKey); err != nil {
+		slog.Error("Failed to delete wordlist file", "wordlist_id", wordlist.ID, "error", err)
+	}
+
+	return nil
+}
+
+// lineCounter counts the lines of the content read through it; a final line
+// without a trailing newline still counts
+type lineCounter struct {
+	r     io.Reader
+	count int
+	last  byte
+}
+
+func (c *lineCounter) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.count += bytes.Count(p[:n], []byte{'\n'})
+		c.last = p[n-1]
+	}
+	return n, err
+}
+
+// lines returns the number of lines read so far
+func (c *lineCounter) lines() int {
+	if c.last != 0 && c.last != '\n' {
+		return c.count + 1
+	}
+	return c.count
+}
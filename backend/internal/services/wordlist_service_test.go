@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"publicscannerapi/internal/models"
+	"publicscannerapi/internal/repository"
+	"publicscannerapi/internal/repository/repositorytest"
+	"publicscannerapi/internal/storage"
+)
+
+var wordlistRowColumns = []string{
+	"id", "organization_id", "name", "storage_key", "size_bytes", "line_count", "created_by", "created_at", "active_scans",
+}
+
+func TestWorkerWordlistDownload(t *testing.T) {
+	scans, mock := newTestScanService(t)
+	store := storage.NewLocalStorage(t.TempDir())
+
+	db, wordlistMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	wordlists := NewWordlistService(repository.NewWordlistRepository(db), store, 1<<20)
+
+	wordlistID := uuid.New()
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		InitiatedBy:    uuid.New(),
+		Status:         models.ScanStatusQueued,
+		Checks:         []string{"bruteforce"},
+		Config:         models.ScanConfig{WordlistID: &wordlistID},
+	}
+	key := "wordlists/" + wordlistID.String()
+	if _, err := store.Save(key, strings.NewReader("admin\nbackup\n"), 1<<20); err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+	wordlistMock.ExpectQuery(`FROM wordlists w`).WithArgs(wordlistID).
+		WillReturnRows(sqlmock.NewRows(wordlistRowColumns).
+			AddRow(wordlistID.String(), scan.OrganizationID.String(), "paths.txt", key, 13, 2, nil, time.Now(), 1))
+
+	id, organizationID, err := scans.WorkerWordlist(scan.ID)
+	if err != nil {
+		t.Fatalf("WorkerWordlist: %v", err)
+	}
+	_, object, err := wordlists.Open(id, organizationID)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer object.Close()
+
+	content, err := io.ReadAll(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "admin\nbackup\n" {
+		t.Errorf("content = %q", content)
+	}
+}
+
+func TestWorkerWordlistRequiresOne(t *testing.T) {
+	scans, mock := newTestScanService(t)
+
+	scan := &models.ScanJob{
+		ID:             uuid.New(),
+		OrganizationID: uuid.New(),
+		InitiatedBy:    uuid.New(),
+		Status:         models.ScanStatusRunning,
+		Checks:         []string{"bruteforce"},
+	}
+	mock.ExpectQuery(`FROM scan_jobs`).WithArgs(scan.ID).
+		WillReturnRows(sqlmock.NewRows(repositorytest.ScanJobColumns).AddRow(repositorytest.ScanJobRow(scan)...))
+
+	if _, _, err := scans.WorkerWordlist(scan.ID); !errors.Is(err, ErrNoWordlist) {
+		t.Fatalf("error = %v, want ErrNoWordlist", err)
+	}
+}
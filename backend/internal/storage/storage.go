@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrTooLarge   = errors.New("object exceeds size limit")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Object is a stored file opened for reading
+type Object interface {
+	io.ReadSeekCloser
+	Stat() (os.FileInfo, error)
+}
+
+// Storage persists binary objects under slash-separated keys
+type Storage interface {
+	// Save writes r under key, failing with ErrTooLarge (and storing
+	// nothing) if it is longer than maxBytes. It returns the bytes written.
+	Save(key string, r io.Reader, maxBytes int64) (int64, error)
+	// Open opens the object stored under key
+	Open(key string) (Object, error)
+	// Delete removes the object stored under key. Deleting a missing
+	// object is not an error.
+	Delete(key string) error
+}
+
+// LocalStorage stores objects as files below a root directory
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage creates a storage rooted at dir
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{root: dir}
+}
+
+// path resolves a key to a file path, rejecting keys that escape the root
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || strings.Contains(key, "..") || cleaned == "/" {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
+
+// Save writes an object to disk
+func (s *LocalStorage) Save(key string, r io.Reader, maxBytes int64) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Read one byte past the limit to detect oversized input
+	written, err := io.Copy(file, io.LimitReader(r, maxBytes+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > maxBytes {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return 0, err
+	}
+
+	return written, nil
+}
+
+// Open opens an object from disk
+func (s *LocalStorage) Open(key string) (Object, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Delete removes an object from disk
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
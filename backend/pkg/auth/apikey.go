@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key, telling keys apart from JWTs in the
+// Authorization header
+const APIKeyPrefix = "psk_"
+
+// GenerateAPIKey returns a new random API key and the hash to store for it.
+// The key itself is shown once and never stored.
+func GenerateAPIKey() (key, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+
+	key = APIKeyPrefix + hex.EncodeToString(raw)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the stored form of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports whether a bearer credential is an API key rather than a JWT
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
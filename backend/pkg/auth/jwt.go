@@ -15,48 +15,75 @@ var (
 
 // TokenClaims represents the JWT claims
 type TokenClaims struct {
-	UserID         uuid.UUID `json:"user_id"`
-	Email          string    `json:"email"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Email          string     `json:"email"`
 	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	// SessionID ties together every token issued from one login, across refreshes
+	SessionID string `json:"sid,omitempty"`
+	// TokenType is TokenTypeRefresh for refresh tokens and empty for access tokens
+	TokenType string `json:"typ,omitempty"`
+	// ServiceAccount is set for claims of a service account authenticated by
+	// API key; such claims are never issued as JWTs
+	ServiceAccount bool `json:"service_account,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenTypeRefresh marks refresh tokens, which cannot be used as access tokens
+const TokenTypeRefresh = "refresh"
+
 // TokenPair represents access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"`
+	// RefreshTokenID and RefreshExpiresAt identify the refresh token so it
+	// can be persisted for rotation
+	RefreshTokenID   uuid.UUID `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
-// GenerateTokenPair creates both access and refresh tokens
+// GenerateTokenPair creates both access and refresh tokens for a new session
 func GenerateTokenPair(userID uuid.UUID, email string, organizationID *uuid.UUID, jwtSecret string, accessTTL, refreshTTL time.Duration) (*TokenPair, error) {
+	return GenerateSessionTokenPair(uuid.NewString(), userID, email, organizationID, jwtSecret, accessTTL, refreshTTL)
+}
+
+// GenerateSessionTokenPair creates both access and refresh tokens that
+// continue an existing session, e.g. when refreshing
+func GenerateSessionTokenPair(sessionID string, userID uuid.UUID, email string, organizationID *uuid.UUID, jwtSecret string, accessTTL, refreshTTL time.Duration) (*TokenPair, error) {
+	now := time.Now()
+
 	// Generate access token
-	accessToken, err := generateToken(userID, email, organizationID, jwtSecret, accessTTL)
+	accessToken, err := generateToken(uuid.New(), "", sessionID, userID, email, organizationID, jwtSecret, now, accessTTL)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate refresh token (longer TTL)
-	refreshToken, err := generateToken(userID, email, organizationID, jwtSecret, refreshTTL)
+	refreshID := uuid.New()
+	refreshToken, err := generateToken(refreshID, TokenTypeRefresh, sessionID, userID, email, organizationID, jwtSecret, now, refreshTTL)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    int64(accessTTL.Seconds()),
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(accessTTL.Seconds()),
+		RefreshTokenID:   refreshID,
+		RefreshExpiresAt: now.Add(refreshTTL),
 	}, nil
 }
 
-// generateToken creates a JWT token
-func generateToken(userID uuid.UUID, email string, organizationID *uuid.UUID, jwtSecret string, ttl time.Duration) (string, error) {
-	now := time.Now()
+// generateToken creates a JWT token identified by id
+func generateToken(id uuid.UUID, tokenType, sessionID string, userID uuid.UUID, email string, organizationID *uuid.UUID, jwtSecret string, now time.Time, ttl time.Duration) (string, error) {
 	claims := TokenClaims{
 		UserID:         userID,
 		Email:          email,
 		OrganizationID: organizationID,
+		SessionID:      sessionID,
+		TokenType:      tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.String(),
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
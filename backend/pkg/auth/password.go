@@ -1,9 +1,16 @@
 package auth
 
 import (
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
 // HashPassword generates a bcrypt hash from a plain text password
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -18,3 +25,13 @@ func CheckPassword(hashedPassword, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// CheckDummyPassword runs a bcrypt comparison at the default cost against a
+// hash no password matches. Callers without a real hash to check, such as a
+// login for an unknown email, use it so they take as long as CheckPassword.
+func CheckDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-never-matches"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
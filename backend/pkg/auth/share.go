@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// shareTokenAudience marks tokens that grant read-only access to a shared scan
+const shareTokenAudience = "scan-share"
+
+// shareSigningKey derives the key for share tokens from the JWT secret, so a
+// share token can never be accepted as an access token and vice versa
+func shareSigningKey(jwtSecret string) []byte {
+	key := sha256.Sum256([]byte(shareTokenAudience + ":" + jwtSecret))
+	return key[:]
+}
+
+// GenerateShareToken creates a signed token identifying a scan share
+func GenerateShareToken(shareID uuid.UUID, expiresAt time.Time, jwtSecret string) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   shareID.String(),
+		Audience:  jwt.ClaimStrings{shareTokenAudience},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(shareSigningKey(jwtSecret))
+}
+
+// ValidateShareToken validates a share token and returns the share ID it names
+func ValidateShareToken(tokenString, jwtSecret string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		// Verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return shareSigningKey(jwtSecret), nil
+	}, jwt.WithAudience(shareTokenAudience))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return uuid.Nil, ErrExpiredToken
+		}
+		return uuid.Nil, ErrInvalidToken
+	}
+	if !token.Valid {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	shareID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	return shareID, nil
+}
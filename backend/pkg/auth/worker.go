@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+)
+
+// workerTokenPurpose separates worker tokens from every other use of the secret
+const workerTokenPurpose = "scan-worker"
+
+// GenerateWorkerToken returns the credential a worker presents to write
+// results for one scan. It is an HMAC of the scan ID, so it is useless for
+// any other scan and the shared secret itself never leaves the API.
+func GenerateWorkerToken(scanID uuid.UUID, workerSecret string) string {
+	mac := hmac.New(sha256.New, []byte(workerTokenPurpose+":"+workerSecret))
+	mac.Write([]byte(scanID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWorkerToken reports whether token grants write access to the scan
+func ValidateWorkerToken(token string, scanID uuid.UUID, workerSecret string) bool {
+	if workerSecret == "" || token == "" {
+		return false
+	}
+	expected := GenerateWorkerToken(scanID, workerSecret)
+	return hmac.Equal([]byte(token), []byte(expected))
+}
@@ -34,6 +34,11 @@ CREATE TABLE users (
     first_name VARCHAR(100) NOT NULL,
     last_name VARCHAR(100) NOT NULL,
     is_active BOOLEAN DEFAULT true,
+    is_superadmin BOOLEAN NOT NULL DEFAULT false, -- Platform operator (system endpoints)
+    is_service_account BOOLEAN NOT NULL DEFAULT false, -- Non-interactive principal, authenticates with API keys only
+    failed_login_attempts INTEGER NOT NULL DEFAULT 0, -- Consecutive failed logins since the last success or lock
+    locked_until TIMESTAMP WITH TIME ZONE, -- Logins are refused until then
+    email_verified BOOLEAN NOT NULL DEFAULT false, -- Set once the emailed verification link is followed
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -46,6 +51,7 @@ CREATE TABLE organizations (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     name VARCHAR(255) NOT NULL,
     owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idle_timeout_minutes INTEGER CHECK (idle_timeout_minutes > 0), -- NULL = sessions never expire from inactivity
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -57,7 +63,7 @@ CREATE TABLE organization_members (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'viewer')),
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'viewer', 'billing')),
     joined_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(organization_id, user_id)
 );
@@ -65,6 +71,83 @@ CREATE TABLE organization_members (
 CREATE INDEX idx_org_members_org_id ON organization_members(organization_id);
 CREATE INDEX idx_org_members_user_id ON organization_members(user_id);
 
+-- Organization notification preferences (absent row = all notifications enabled)
+CREATE TABLE organization_notification_preferences (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    scan_completed BOOLEAN NOT NULL DEFAULT true,
+    scan_progress BOOLEAN NOT NULL DEFAULT true,
+    high_severity_findings BOOLEAN NOT NULL DEFAULT true,
+    certificate_expiry BOOLEAN NOT NULL DEFAULT true,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Per-organization branding applied to PDF/HTML reports (NULL columns fall back to product branding)
+CREATE TABLE organization_report_branding (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    company_name VARCHAR(100),
+    primary_color CHAR(7),
+    footer_text VARCHAR(500),
+    logo_storage_key VARCHAR(500),
+    logo_content_type VARCHAR(100),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Issued refresh tokens, rotated on every use. Tokens of one login share
+-- session_id; presenting a revoked token revokes the whole session.
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    session_id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_session ON refresh_tokens(user_id, session_id);
+
+-- Password reset tokens (single use; a new request or a reset voids older ones)
+CREATE TABLE password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the token sent by email
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- Email verification tokens (single use; a new request or a verification voids older ones)
+CREATE TABLE email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the token sent by email
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+
+-- Organization invitations (status derived from accepted_at/revoked_at/expires_at)
+CREATE TABLE organization_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'member', 'viewer', 'billing')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL, -- SHA-256 of the acceptance token
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_organization_invitations_org_id ON organization_invitations(organization_id, created_at DESC);
+
 -- Targets table
 CREATE TABLE targets (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -74,16 +157,57 @@ CREATE TABLE targets (
     description TEXT,
     tags TEXT[], -- PostgreSQL array of tags
     is_active BOOLEAN DEFAULT true,
+    is_monitored BOOLEAN NOT NULL DEFAULT false, -- Scanned periodically by the scan scheduler
+    monitor_interval_hours INTEGER NOT NULL DEFAULT 24 CHECK (monitor_interval_hours BETWEEN 1 AND 720),
+    next_monitor_run_at TIMESTAMP WITH TIME ZONE, -- NULL while monitoring is off
     created_by UUID NOT NULL REFERENCES users(id) ON DELETE SET NULL,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE -- Soft delete; the target and its scans are kept and can be restored
 );
 
 CREATE INDEX idx_targets_org_id ON targets(organization_id);
+CREATE INDEX idx_targets_next_monitor_run ON targets(next_monitor_run_at) WHERE is_monitored;
 CREATE INDEX idx_targets_hostname ON targets(hostname);
 CREATE INDEX idx_targets_created_by ON targets(created_by);
 CREATE INDEX idx_targets_tags ON targets USING GIN(tags);
 
+-- Target history table (who created or changed a target, and which fields)
+CREATE TABLE target_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_id UUID NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated')),
+    changes JSONB NOT NULL DEFAULT '{}', -- {"field": {"from": ..., "to": ...}}
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_target_history_target_id ON target_history(target_id, created_at DESC);
+
+-- Campaigns: named engagements that group scans
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_campaigns_org_name ON campaigns(organization_id, name);
+
+-- Targets assigned to a campaign
+CREATE TABLE campaign_targets (
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    target_id UUID NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (campaign_id, target_id)
+);
+
+CREATE INDEX idx_campaign_targets_target_id ON campaign_targets(target_id);
+
 -- Scan jobs table
 CREATE TABLE scan_jobs (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -91,12 +215,24 @@ CREATE TABLE scan_jobs (
     url VARCHAR(500), -- Optional: for quick scans without saved target
     organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
     initiated_by UUID NOT NULL REFERENCES users(id) ON DELETE SET NULL,
-    status VARCHAR(20) NOT NULL CHECK (status IN ('queued', 'running', 'completed', 'failed', 'cancelled')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('scheduled', 'queued', 'running', 'completed', 'failed', 'cancelled')),
     progress INTEGER DEFAULT 0 CHECK (progress >= 0 AND progress <= 100),
+    current_step VARCHAR(50), -- Check the worker is running; cleared when the scan finishes
     checks TEXT[], -- Array of check names
     config JSONB DEFAULT '{}', -- Scan configuration
+    tags TEXT[], -- User-defined labels
+    metadata JSONB DEFAULT '{}', -- Free-form user notes/metadata
+    run_at TIMESTAMP WITH TIME ZONE, -- One-time scheduled start; the scan stays 'scheduled' until then
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
     started_at TIMESTAMP WITH TIME ZONE,
     completed_at TIMESTAMP WITH TIME ZONE,
+    policy_passed BOOLEAN, -- Result of the fail_on_severity gate (NULL until evaluated)
+    worst_severity VARCHAR(20) CHECK (worst_severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    failure_code VARCHAR(30) CHECK (failure_code IN ('unreachable', 'timeout', 'worker_error', 'invalid_target', 'cancelled_by_user')),
+    failure_reason TEXT, -- Detail behind failure_code, e.g. the worker's error message
+    findings_total INTEGER NOT NULL DEFAULT 0, -- Summary of scan_results, recomputed as results are written
+    findings_by_severity JSONB NOT NULL DEFAULT '{}', -- e.g. {"critical": 0, "high": 2, "medium": 0, "low": 5, "info": 1}
+    deleted_at TIMESTAMP WITH TIME ZONE, -- Soft delete; purged after SCAN_RETENTION_DAYS
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     CHECK (target_id IS NOT NULL OR url IS NOT NULL) -- At least one must be provided
@@ -108,6 +244,28 @@ CREATE INDEX idx_scan_jobs_org_id ON scan_jobs(organization_id);
 CREATE INDEX idx_scan_jobs_status ON scan_jobs(status);
 CREATE INDEX idx_scan_jobs_created_at ON scan_jobs(created_at DESC);
 CREATE INDEX idx_scan_jobs_config ON scan_jobs USING GIN(config);
+CREATE INDEX idx_scan_jobs_tags ON scan_jobs USING GIN(tags);
+CREATE INDEX idx_scan_jobs_deleted_at ON scan_jobs(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_scan_jobs_run_at ON scan_jobs(run_at) WHERE status = 'scheduled';
+
+-- Recurring scans of a target; the scheduler starts a scan whenever next_run_at passes
+CREATE TABLE scheduled_scans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    target_id UUID NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    checks TEXT[] NOT NULL,
+    config JSONB DEFAULT '{}',
+    cron_expression VARCHAR(100) NOT NULL, -- Five-field cron expression, evaluated in UTC
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_scan_id UUID REFERENCES scan_jobs(id) ON DELETE SET NULL,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Scans are started on this user's behalf
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scheduled_scans_org_id ON scheduled_scans(organization_id);
+CREATE INDEX idx_scheduled_scans_next_run_at ON scheduled_scans(next_run_at);
+CREATE INDEX idx_scan_jobs_campaign_id ON scan_jobs(campaign_id) WHERE campaign_id IS NOT NULL;
 
 -- Scan results table
 CREATE TABLE scan_results (
@@ -118,6 +276,16 @@ CREATE TABLE scan_results (
     data JSONB NOT NULL DEFAULT '{}', -- Scan result data
     findings INTEGER DEFAULT 0,
     severity VARCHAR(20) CHECK (severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    -- Per-severity breakdown of findings, e.g. {"high": 2, "low": 5}
+    findings_by_severity JSONB CHECK (
+        findings_by_severity IS NULL OR (
+            jsonb_typeof(findings_by_severity) = 'object'
+            AND findings_by_severity - ARRAY['critical', 'high', 'medium', 'low', 'info'] = '{}'::jsonb
+        )
+    ),
+    -- Severity and breakdown as reported by the check, set when an override rule changed them
+    original_severity VARCHAR(20) CHECK (original_severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    original_findings_by_severity JSONB,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -125,6 +293,92 @@ CREATE INDEX idx_scan_results_scan_id ON scan_results(scan_id);
 CREATE INDEX idx_scan_results_check_type ON scan_results(check_type);
 CREATE INDEX idx_scan_results_severity ON scan_results(severity);
 CREATE INDEX idx_scan_results_data ON scan_results USING GIN(data);
+-- Full-text search over string and numeric values (GET /search/results)
+CREATE INDEX idx_scan_results_data_fts ON scan_results USING GIN(jsonb_to_tsvector('simple', data, '["string", "numeric"]'));
+
+-- Per-organization severity override rules, applied to results as they are ingested.
+-- A rule without match_severity applies to every severity the check reports.
+CREATE TABLE severity_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    check_type VARCHAR(50) NOT NULL,
+    match_severity VARCHAR(20) CHECK (match_severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_severity_overrides_rule ON severity_overrides(organization_id, check_type, COALESCE(match_severity, ''));
+
+-- Per-organization acknowledgement rules, applied when results are listed.
+-- Results matching an active rule and without a triage note read as acknowledged.
+CREATE TABLE acknowledgement_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    check_type VARCHAR(50) NOT NULL,
+    match_severity VARCHAR(20) CHECK (match_severity IN ('critical', 'high', 'medium', 'low', 'info')),
+    reason TEXT NOT NULL DEFAULT '',
+    snooze_until TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_acknowledgement_rules_org_check ON acknowledgement_rules(organization_id, check_type);
+
+-- Scan status history (one row per status transition, recorded by trigger)
+CREATE TABLE scan_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    scan_id UUID NOT NULL REFERENCES scan_jobs(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    progress INTEGER DEFAULT 0,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scan_status_history_scan_id ON scan_status_history(scan_id, changed_at);
+
+-- Read-only scan share links (tokens are signed; rows allow revocation)
+CREATE TABLE scan_shares (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scan_id UUID NOT NULL REFERENCES scan_jobs(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scan_shares_scan_id ON scan_shares(scan_id);
+
+-- Scan result attachments table (binary evidence stored outside the database)
+CREATE TABLE scan_result_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    result_id UUID NOT NULL REFERENCES scan_results(id) ON DELETE CASCADE,
+    scan_id UUID NOT NULL REFERENCES scan_jobs(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    file_size BIGINT DEFAULT 0,
+    storage_key VARCHAR(500) NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scan_result_attachments_result_id ON scan_result_attachments(result_id);
+CREATE INDEX idx_scan_result_attachments_scan_id ON scan_result_attachments(scan_id);
+
+-- Scan result notes table (analyst triage; the latest note sets the result's status)
+CREATE TABLE scan_result_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    result_id UUID NOT NULL REFERENCES scan_results(id) ON DELETE CASCADE,
+    scan_id UUID NOT NULL REFERENCES scan_jobs(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    triage_status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (triage_status IN ('open', 'acknowledged', 'resolved', 'false_positive')),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_scan_result_notes_result_id ON scan_result_notes(result_id, created_at DESC);
+CREATE INDEX idx_scan_result_notes_scan_id ON scan_result_notes(scan_id);
 
 -- Reports table
 CREATE TABLE reports (
@@ -136,6 +390,7 @@ CREATE TABLE reports (
     file_name VARCHAR(255) NOT NULL,
     file_path VARCHAR(500) NOT NULL,
     file_size BIGINT DEFAULT 0,
+    deleted_at TIMESTAMP WITH TIME ZONE, -- Set when the scan is soft-deleted
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -170,6 +425,7 @@ CREATE TABLE audit_logs (
     resource_id UUID,
     ip_address INET,
     user_agent TEXT,
+    actor_type VARCHAR(20) NOT NULL DEFAULT 'user', -- user, service_account
     metadata JSONB DEFAULT '{}',
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -197,6 +453,54 @@ CREATE TABLE webhooks (
 CREATE INDEX idx_webhooks_org_id ON webhooks(organization_id);
 CREATE INDEX idx_webhooks_events ON webhooks USING GIN(events);
 
+-- Client certificates for mutual-TLS scans (private key encrypted with ENCRYPTION_KEY)
+CREATE TABLE client_certificates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    subject TEXT NOT NULL,
+    issuer TEXT NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL, -- SHA-256 of the leaf certificate
+    not_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    not_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    certificate_pem TEXT NOT NULL,
+    encrypted_key BYTEA NOT NULL, -- AES-256-GCM, nonce prefixed
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_client_certificates_org_id ON client_certificates(organization_id);
+
+-- Uploaded bruteforce wordlists, referenced from scan_jobs.config->>'wordlist_id'
+CREATE TABLE wordlists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL, -- Key in the file storage, e.g. wordlists/<id>
+    size_bytes BIGINT NOT NULL,
+    line_count INTEGER NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_wordlists_org_id ON wordlists(organization_id);
+
+-- Dead-lettered webhook deliveries (all attempts exhausted)
+CREATE TABLE webhook_failures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    delivery_id UUID NOT NULL, -- Payload ID, sent as X-Webhook-Delivery on every attempt
+    event VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts JSONB NOT NULL DEFAULT '[]', -- Status code / response body / error of each attempt
+    last_error TEXT,
+    resolved_at TIMESTAMP WITH TIME ZONE, -- Set once a manual retry succeeds
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_failures_webhook_id ON webhook_failures(webhook_id, created_at DESC);
+
 -- Function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
@@ -222,14 +526,52 @@ CREATE TRIGGER update_scan_jobs_updated_at BEFORE UPDATE ON scan_jobs
 CREATE TRIGGER update_webhooks_updated_at BEFORE UPDATE ON webhooks
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+CREATE TRIGGER update_webhook_failures_updated_at BEFORE UPDATE ON webhook_failures
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Function to record scan status transitions. Runs in the database so that
+-- transitions made by the workers are captured alongside the API's own.
+CREATE OR REPLACE FUNCTION record_scan_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO scan_status_history (scan_id, from_status, to_status, progress)
+        VALUES (NEW.id, NULL, NEW.status, NEW.progress);
+    ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO scan_status_history (scan_id, from_status, to_status, progress)
+        VALUES (NEW.id, OLD.status, NEW.status, NEW.progress);
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_scan_jobs_status_change AFTER INSERT OR UPDATE OF status ON scan_jobs
+    FOR EACH ROW EXECUTE FUNCTION record_scan_status_change();
+
 -- Comments for documentation
 COMMENT ON TABLE users IS 'User accounts for the platform';
 COMMENT ON TABLE organizations IS 'Organizations/teams that own targets and scans';
+COMMENT ON TABLE refresh_tokens IS 'Issued refresh tokens, revoked on rotation, for reuse detection';
 COMMENT ON TABLE organization_members IS 'Membership relationship between users and organizations with roles';
+COMMENT ON TABLE organization_notification_preferences IS 'Per-organization switches for notification events';
+COMMENT ON TABLE organization_report_branding IS 'Per-organization logo, colors and texts used in generated reports';
+COMMENT ON TABLE organization_invitations IS 'Pending and past invitations to join an organization';
 COMMENT ON TABLE targets IS 'Scan targets (domains, IPs, hostnames)';
+COMMENT ON TABLE target_history IS 'Audit trail of target creations and configuration changes';
+COMMENT ON TABLE campaigns IS 'Named engagements that group scans for reporting';
+COMMENT ON TABLE campaign_targets IS 'Targets assigned to a campaign';
 COMMENT ON TABLE scan_jobs IS 'Security scan jobs with status tracking';
 COMMENT ON TABLE scan_results IS 'Individual check results for each scan job';
+COMMENT ON TABLE severity_overrides IS 'Per-organization rules that replace the severity reported by a check';
+COMMENT ON TABLE acknowledgement_rules IS 'Per-organization rules that acknowledge matching results, optionally until a snooze expires';
+COMMENT ON TABLE scan_status_history IS 'Status transitions of each scan job, used for its timeline';
+COMMENT ON TABLE scan_shares IS 'Expiring, revocable read-only share links for scans';
+COMMENT ON TABLE scan_result_attachments IS 'Binary artifacts attached to scan results';
+COMMENT ON TABLE scan_result_notes IS 'Analyst notes and triage status changes on scan results';
 COMMENT ON TABLE reports IS 'Generated reports metadata with file references';
 COMMENT ON TABLE api_keys IS 'API keys for programmatic access';
 COMMENT ON TABLE audit_logs IS 'Audit trail for compliance and security';
 COMMENT ON TABLE webhooks IS 'Webhook configurations for external integrations';
+COMMENT ON TABLE client_certificates IS 'Client certificate/key pairs presented by workers to mutual-TLS targets';
+COMMENT ON TABLE wordlists IS 'Uploaded bruteforce wordlists stored in the file storage';
+COMMENT ON TABLE webhook_failures IS 'Webhook deliveries that exhausted their retries (dead-letter queue)';
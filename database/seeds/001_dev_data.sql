@@ -3,9 +3,9 @@
 
 -- Insert a test user (password: Test1234!)
 -- Password hash generated with bcrypt (cost 10)
-INSERT INTO users (id, email, password_hash, first_name, last_name, is_active) VALUES
-('123e4567-e89b-12d3-a456-426614174000', 'admin@example.com', '$2a$10$6JCA.2wcm/HQTd/nVM.RaOlHM07/6MxMMiv5XBeca70vBzj4M/01e', 'Admin', 'User', true),
-('123e4567-e89b-12d3-a456-426614174001', 'user@example.com', '$2a$10$6JCA.2wcm/HQTd/nVM.RaOlHM07/6MxMMiv5XBeca70vBzj4M/01e', 'Test', 'User', true);
+INSERT INTO users (id, email, password_hash, first_name, last_name, is_active, is_superadmin, email_verified) VALUES
+('123e4567-e89b-12d3-a456-426614174000', 'admin@example.com', '$2a$10$6JCA.2wcm/HQTd/nVM.RaOlHM07/6MxMMiv5XBeca70vBzj4M/01e', 'Admin', 'User', true, true, true),
+('123e4567-e89b-12d3-a456-426614174001', 'user@example.com', '$2a$10$6JCA.2wcm/HQTd/nVM.RaOlHM07/6MxMMiv5XBeca70vBzj4M/01e', 'Test', 'User', true, false, true);
 
 -- Insert a test organization
 INSERT INTO organizations (id, name, owner_id) VALUES
@@ -0,0 +1,79 @@
+"""Checks that scan targets only reach public addresses"""
+import os
+import socket
+import ipaddress
+from typing import List
+from urllib.parse import urlsplit
+
+# Same switch as the API's ALLOW_PRIVATE_TARGETS, for lab setups
+ALLOW_PRIVATE_TARGETS = os.getenv('ALLOW_PRIVATE_TARGETS', 'false').lower() in ('1', 't', 'true')
+
+# Domains that only resolve inside a network
+INTERNAL_NAME_SUFFIXES = ('.localhost', '.local', '.internal', '.home.arpa')
+
+
+class BlockedTargetError(Exception):
+    """Raised when a target resolves to an address scans may not reach"""
+
+
+def target_host(target: str) -> str:
+    """Host of a target given as a bare host, host:port or URL"""
+    if '://' not in target:
+        target = f"tcp://{target}"
+    host = urlsplit(target).hostname or ''
+    return host.rstrip('.').lower()
+
+
+def is_internal_address(address: str) -> bool:
+    """Whether an address is not routable on the public internet"""
+    ip = ipaddress.ip_address(address)
+    if isinstance(ip, ipaddress.IPv6Address) and ip.ipv4_mapped:
+        ip = ip.ipv4_mapped
+    return not ip.is_global or ip.is_multicast
+
+
+def resolve(host: str) -> List[str]:
+    """All A and AAAA addresses of a host"""
+    infos = socket.getaddrinfo(host, None, proto=socket.IPPROTO_TCP)
+    return sorted({info[4][0].split('%')[0] for info in infos})
+
+
+def check_target(target: str) -> List[str]:
+    """
+    Resolve a target and make sure every address is public
+
+    Args:
+        target: Target hostname, IP or URL
+
+    Returns:
+        The addresses the target resolves to
+
+    Raises:
+        BlockedTargetError: when the target is an internal name or resolves
+            to a private, loopback or link-local address
+    """
+    host = target_host(target)
+    if not host:
+        raise BlockedTargetError(f"Invalid target: {target}")
+    if ALLOW_PRIVATE_TARGETS:
+        return []
+
+    try:
+        ipaddress.ip_address(host)
+        literal = True
+    except ValueError:
+        literal = False
+
+    if not literal:
+        if host == 'localhost' or '.' not in host or host.endswith(INTERNAL_NAME_SUFFIXES):
+            raise BlockedTargetError(f"{host} is an internal name")
+
+    try:
+        addresses = resolve(host)
+    except socket.gaierror:
+        raise BlockedTargetError(f"{host} could not be resolved")
+
+    for address in addresses:
+        if is_internal_address(address):
+            raise BlockedTargetError(f"{host} resolves to a private, loopback or link-local address")
+    return addresses
@@ -0,0 +1,91 @@
+"""Client for the API's internal worker endpoints"""
+import os
+import logging
+from typing import Dict, Any, Optional
+import requests
+
+logger = logging.getLogger(__name__)
+
+API_URL = os.getenv('API_URL', 'http://localhost:8080').rstrip('/')
+REQUEST_TIMEOUT = 30  # seconds
+
+
+def _scan_url(scan_id: str, path: str = '') -> str:
+    """URL of an internal endpoint of one scan"""
+    url = f"{API_URL}/api/v1/internal/scans/{scan_id}"
+    return f"{url}/{path}" if path else url
+
+
+def _headers(worker_token: str) -> Dict[str, str]:
+    """Authorization header carrying the scan's worker token"""
+    return {'Authorization': f"Bearer {worker_token}"}
+
+
+def report_results(scan_id: str, worker_token: str, report: Dict[str, Any]) -> Dict[str, Any]:
+    """
+    Report results, progress and status of a scan
+
+    Args:
+        scan_id: UUID of the scan job
+        worker_token: Token delivered with the task
+        report: Body of the report (results, progress, current_step, status)
+
+    Returns:
+        The scan's status and progress after the report
+    """
+    response = requests.post(
+        _scan_url(scan_id, 'results'),
+        json=report,
+        headers=_headers(worker_token),
+        timeout=REQUEST_TIMEOUT
+    )
+    response.raise_for_status()
+    return response.json()
+
+
+def get_scan(scan_id: str, worker_token: str) -> Optional[Dict[str, Any]]:
+    """
+    Fetch the current status, checks and config of a scan
+
+    Returns:
+        The scan, or None when it no longer exists
+    """
+    response = requests.get(
+        _scan_url(scan_id),
+        headers=_headers(worker_token),
+        timeout=REQUEST_TIMEOUT
+    )
+    if response.status_code == 404:
+        return None
+    response.raise_for_status()
+    return response.json()
+
+
+def get_client_certificate(scan_id: str, worker_token: str) -> Dict[str, str]:
+    """
+    Fetch the decrypted client certificate and key of a scan
+
+    Returns:
+        The PEM blocks as certificate_pem and private_key_pem
+    """
+    response = requests.get(
+        _scan_url(scan_id, 'client-certificate'),
+        headers=_headers(worker_token),
+        timeout=REQUEST_TIMEOUT
+    )
+    response.raise_for_status()
+    return response.json()
+
+
+def download_wordlist(scan_id: str, worker_token: str, path: str) -> None:
+    """Download the uploaded wordlist of a scan to path"""
+    with requests.get(
+        _scan_url(scan_id, 'wordlist'),
+        headers=_headers(worker_token),
+        timeout=REQUEST_TIMEOUT,
+        stream=True
+    ) as response:
+        response.raise_for_status()
+        with open(path, 'wb') as f:
+            for chunk in response.iter_content(chunk_size=65536):
+                f.write(chunk)
@@ -1,12 +1,35 @@
 """Directory brute-force check module"""
 import subprocess
 import logging
-from typing import Dict, Any
+from typing import Dict, Any, List
 import os
+import tempfile
 
 logger = logging.getLogger(__name__)
 
 
+def _is_excluded(path: str, exclude_paths: List[str]) -> bool:
+    """Whether a path equals or lies under one of the excluded paths"""
+    path = '/' + path.strip().lstrip('/')
+    for excluded in exclude_paths:
+        excluded = excluded.rstrip('/') or '/'
+        if excluded == '/' or path == excluded or path.startswith(excluded + '/'):
+            return True
+    return False
+
+
+def _filter_wordlist(wordlist: str, exclude_paths: List[str]) -> str:
+    """Write a copy of the wordlist without excluded entries and return its path"""
+    with open(wordlist, errors='ignore') as f:
+        entries = [line for line in f.read().splitlines()
+                   if line.strip() and not _is_excluded(line, exclude_paths)]
+
+    handle, filtered = tempfile.mkstemp(prefix='wordlist_', suffix='.txt')
+    with os.fdopen(handle, 'w') as f:
+        f.write('\n'.join(entries))
+    return filtered
+
+
 def bruteforce_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
     """
     Perform directory/file brute-forcing using gobuster
@@ -39,6 +62,12 @@ def bruteforce_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
                     'login', 'test', 'upload', '.git', '.env'
                 ]))
 
+        # Excluded paths are never requested; exclusions win over the wordlist
+        exclude_paths = config.get('exclude_paths') or []
+        wordlist_name = config.get('wordlist_id') or os.path.basename(wordlist)
+        if exclude_paths:
+            wordlist = _filter_wordlist(wordlist, exclude_paths)
+
         # Run gobuster
         command = [
             'gobuster', 'dir',
@@ -49,13 +78,20 @@ def bruteforce_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             '--no-error',
             '--timeout', '30s'
         ]
+        if config.get('client_cert_file'):
+            command += ['--client-cert-pem', config['client_cert_file'],
+                        '--client-cert-pem-key', config['client_key_file']]
 
-        result = subprocess.run(
-            command,
-            capture_output=True,
-            text=True,
-            timeout=300  # 5 minutes max
-        )
+        try:
+            result = subprocess.run(
+                command,
+                capture_output=True,
+                text=True,
+                timeout=300  # 5 minutes max
+            )
+        finally:
+            if exclude_paths:
+                os.remove(wordlist)
 
         # Parse gobuster output
         found_dirs = []
@@ -63,7 +99,7 @@ def bruteforce_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             if line.strip() and not line.startswith('='):
                 # Extract URL and status code
                 parts = line.split()
-                if len(parts) >= 2:
+                if len(parts) >= 2 and not _is_excluded(parts[0], exclude_paths):
                     found_dirs.append({
                         'path': parts[0],
                         'status': parts[1] if len(parts) > 1 else 'unknown'
@@ -82,7 +118,8 @@ def bruteforce_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             'data': {
                 'directories_found': found_dirs,
                 'total_found': findings_count,
-                'wordlist_used': os.path.basename(wordlist)
+                'wordlist_used': wordlist_name,
+                'excluded_paths': exclude_paths
             },
             'findings': findings_count,
             'severity': severity
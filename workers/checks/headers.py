@@ -1,7 +1,9 @@
 """HTTP security headers check module"""
 import subprocess
 import logging
-from typing import Dict, Any
+from typing import Dict, Any, List, Optional, Tuple
+from urllib.parse import urljoin, urlsplit
+from address_policy import BlockedTargetError, check_target
 
 logger = logging.getLogger(__name__)
 
@@ -16,6 +18,47 @@ SECURITY_HEADERS = [
     'Permissions-Policy',
 ]
 
+# Redirects followed before giving up
+MAX_REDIRECTS = 5
+
+
+def _fetch_headers(url: str, config: Dict[str, Any]) -> Optional[Tuple[int, List[Tuple[str, str]]]]:
+    """
+    Fetch the response headers of a single request, pinned to an address the
+    policy checked so the name cannot be re-resolved to an internal one.
+    The scan's client certificate is presented when it has one.
+
+    Returns:
+        The status code and header lines, or None when the request failed
+    """
+    addresses = check_target(url)
+    command = ['curl', '-I', '-s', '--max-time', '10', '--proto', '=http,https']
+    if addresses:
+        parts = urlsplit(url)
+        port = parts.port or (443 if parts.scheme == 'https' else 80)
+        address = addresses[0]
+        if ':' in address:
+            address = f"[{address}]"
+        command += ['--resolve', f"{parts.hostname}:{port}:{address}"]
+    if config.get('client_cert_file'):
+        command += ['--cert', config['client_cert_file'], '--key', config['client_key_file']]
+    command.append(url)
+
+    result = subprocess.run(command, capture_output=True, text=True, timeout=15)
+    if result.returncode != 0:
+        return None
+
+    status = 0
+    header_lines = []
+    for line in result.stdout.split('\n'):
+        if line.startswith('HTTP/'):
+            parts = line.split()
+            status = int(parts[1]) if len(parts) > 1 and parts[1].isdigit() else 0
+        elif ':' in line:
+            key, value = line.split(':', 1)
+            header_lines.append((key.strip(), value.strip()))
+    return status, header_lines
+
 
 def headers_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
     """
@@ -35,17 +78,20 @@ def headers_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
         if not target.startswith(('http://', 'https://')):
             target = f"https://{target}"
 
-        # Use curl to fetch headers
-        command = ['curl', '-I', '-s', '-L', '--max-time', '10', target]
-
-        result = subprocess.run(
-            command,
-            capture_output=True,
-            text=True,
-            timeout=15
-        )
-
-        if result.returncode != 0:
+        # Follow redirects one hop at a time, checking each target first
+        url = target
+        response = None
+        for _ in range(MAX_REDIRECTS + 1):
+            response = _fetch_headers(url, config)
+            if response is None:
+                break
+            status, header_lines = response
+            location = next((v for k, v in header_lines if k.lower() == 'location'), None)
+            if not (300 <= status < 400 and location):
+                break
+            url = urljoin(url, location)
+
+        if response is None:
             return {
                 'status': 'failed',
                 'data': {'error': 'Failed to fetch headers'},
@@ -57,14 +103,11 @@ def headers_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
         headers_lower = {}
         missing_headers = []
 
-        # Parse headers
-        for line in result.stdout.split('\n'):
-            if ':' in line:
-                key, value = line.split(':', 1)
-                key_stripped = key.strip()
-                headers[key_stripped] = value.strip()
-                # Store lowercase version for case-insensitive lookup
-                headers_lower[key_stripped.lower()] = value.strip()
+        # Parse headers of the final response
+        for key, value in response[1]:
+            headers[key] = value
+            # Store lowercase version for case-insensitive lookup
+            headers_lower[key.lower()] = value
 
         # Check for security headers (case-insensitive)
         for header in SECURITY_HEADERS:
@@ -95,6 +138,14 @@ def headers_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             'severity': severity
         }
 
+    except BlockedTargetError as e:
+        logger.warning(f"Headers check blocked for {target}: {e}")
+        return {
+            'status': 'failed',
+            'data': {'error': 'Blocked by the address policy'},
+            'findings': 0,
+            'severity': 'info'
+        }
     except subprocess.TimeoutExpired:
         logger.error(f"Headers check timed out for {target}")
         return {
@@ -28,9 +28,15 @@ def port_scan_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             '--open',  # Only show open ports
             '-T4',  # Faster timing
             '-oX', '-',  # XML output to stdout
-            target
         ]
 
+        # Excluded ports are never probed; exclusions win over the full range
+        exclude_ports = sorted({int(p) for p in config.get('exclude_ports') or []})
+        if exclude_ports:
+            command += ['--exclude-ports', ','.join(str(p) for p in exclude_ports)]
+
+        command.append(target)
+
         # Execute nmap
         result = subprocess.run(
             command,
@@ -81,6 +87,7 @@ def port_scan_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
                 'data': {
                     'open_ports': ports,
                     'total_open': findings_count,
+                    'excluded_ports': exclude_ports,
                     'scan_completed': True
                 },
                 'findings': findings_count,
@@ -32,6 +32,8 @@ def ssl_check(target: str, config: Dict[str, Any]) -> Dict[str, Any]:
             '-servername', target,
             '-showcerts'
         ]
+        if config.get('client_cert_file'):
+            command += ['-cert', config['client_cert_file'], '-key', config['client_key_file']]
 
         # Echo quit to close the connection
         echo_process = subprocess.Popen(['echo', 'Q'], stdout=subprocess.PIPE)
@@ -0,0 +1,35 @@
+"""Temporary files holding a scan's client certificate for the check tools"""
+import os
+import tempfile
+from contextlib import contextmanager
+from typing import Dict, Iterator, Tuple
+
+
+def _write_private(prefix: str, content: str) -> str:
+    """Write content to a new file only the worker's user can read"""
+    handle, path = tempfile.mkstemp(prefix=prefix, suffix='.pem')
+    with os.fdopen(handle, 'w') as f:
+        f.write(content)
+    return path
+
+
+@contextmanager
+def client_certificate_files(material: Dict[str, str]) -> Iterator[Tuple[str, str]]:
+    """
+    Write a certificate/key pair to files for curl, openssl and gobuster,
+    removing them when the scan is done
+
+    Yields:
+        Paths of the certificate and the private key
+    """
+    paths = []
+    try:
+        paths.append(_write_private('client_cert_', material['certificate_pem']))
+        paths.append(_write_private('client_key_', material['private_key_pem']))
+        yield paths[0], paths[1]
+    finally:
+        for path in paths:
+            try:
+                os.remove(path)
+            except OSError:
+                pass
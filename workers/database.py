@@ -31,7 +31,7 @@ def update_scan_status(scan_id: str, status: str, completed_at: Optional[datetim
                     cur.execute(
                         """
                         UPDATE scan_jobs
-                        SET status = %s, completed_at = %s, updated_at = CURRENT_TIMESTAMP
+                        SET status = %s, completed_at = %s, current_step = NULL, updated_at = CURRENT_TIMESTAMP
                         WHERE id = %s
                         """,
                         (status, completed_at, scan_id)
@@ -61,18 +61,19 @@ def update_scan_status(scan_id: str, status: str, completed_at: Optional[datetim
         raise
 
 
-def update_scan_progress(scan_id: str, progress: int):
-    """Update scan progress percentage"""
+def update_scan_progress(scan_id: str, progress: int, current_step: Optional[str] = None):
+    """Update scan progress percentage and, when given, the running check"""
     try:
         with get_db_connection() as conn:
             with conn.cursor() as cur:
                 cur.execute(
                     """
                     UPDATE scan_jobs
-                    SET progress = %s, updated_at = CURRENT_TIMESTAMP
+                    SET progress = %s, current_step = COALESCE(%s, current_step),
+                        updated_at = CURRENT_TIMESTAMP
                     WHERE id = %s
                     """,
-                    (progress, scan_id)
+                    (progress, current_step, scan_id)
                 )
                 conn.commit()
                 logger.debug(f"Updated scan {scan_id} progress to {progress}%")
@@ -123,3 +124,17 @@ def get_scan_config(scan_id: str) -> Optional[Dict[str, Any]]:
     except Exception as e:
         logger.error(f"Failed to get scan config: {e}")
         return None
+
+
+def get_scan_job(scan_id: str) -> Optional[Dict[str, Any]]:
+    """Get the current status, checks and config of a scan that is not deleted"""
+    with get_db_connection() as conn:
+        with conn.cursor() as cur:
+            cur.execute(
+                """
+                SELECT status, checks, config FROM scan_jobs
+                WHERE id = %s AND deleted_at IS NULL
+                """,
+                (scan_id,)
+            )
+            return cur.fetchone()
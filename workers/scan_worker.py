@@ -22,6 +22,7 @@ from checks.headers import headers_check
 from checks.ssl import ssl_check
 from checks.dns import dns_check
 from checks.bruteforce import bruteforce_check
+from address_policy import BlockedTargetError, check_target
 
 
 def get_db_connection():
@@ -139,6 +140,13 @@ def process_scan(conn, scan_data):
                 return
             target = result[0]
 
+    try:
+        check_target(target)
+    except BlockedTargetError as e:
+        print(f"❌ Scan {scan_id} blocked: {e}")
+        update_scan_status(conn, scan_id, 'failed')
+        return
+
     print(f"🔍 Processing scan {scan_id} for {target}")
     update_scan_status(conn, scan_id, 'running', 0)
 
@@ -2,10 +2,15 @@
 import os
 import json
 import logging
+import tempfile
+from contextlib import ExitStack
 from datetime import datetime
 from celery import Task
 from celery_app import app
-from database import update_scan_status, update_scan_progress, store_scan_result
+from database import update_scan_status, update_scan_progress, store_scan_result, get_scan_job
+from api_client import report_results, get_scan, get_client_certificate, download_wordlist
+from address_policy import BlockedTargetError, check_target
+from client_certificate import client_certificate_files
 from checks import (
     ping_check,
     port_scan_check,
@@ -18,25 +23,114 @@ from checks import (
 logger = logging.getLogger(__name__)
 
 
+# Statuses of scans a worker must not run again
+FINISHED_STATUSES = ('completed', 'failed', 'cancelled')
+
+
+class DatabaseReporter:
+    """Reads the scan from and writes its progress and results straight to the database"""
+
+    def __init__(self, scan_id: str):
+        self.scan_id = scan_id
+
+    def load(self):
+        return get_scan_job(self.scan_id)
+
+    def start(self):
+        update_scan_status(self.scan_id, 'running')
+        update_scan_progress(self.scan_id, 0)
+
+    def progress(self, progress: int, current_step: str = None):
+        update_scan_progress(self.scan_id, progress, current_step)
+
+    def result(self, check_type: str, status: str, data: dict, findings: int, severity: str):
+        store_scan_result(
+            scan_id=self.scan_id,
+            check_type=check_type,
+            status=status,
+            data=data,
+            findings=findings,
+            severity=severity
+        )
+
+    def complete(self):
+        update_scan_status(self.scan_id, 'completed', datetime.utcnow())
+        update_scan_progress(self.scan_id, 100)
+
+    def fail(self, reason: str, failure_code: str = 'worker_error'):
+        update_scan_status(self.scan_id, 'failed')
+
+
+class ApiReporter:
+    """Reads the scan from and reports its progress and results to the API with the scan's worker token"""
+
+    def __init__(self, scan_id: str, worker_token: str):
+        self.scan_id = scan_id
+        self.worker_token = worker_token
+
+    def load(self):
+        return get_scan(self.scan_id, self.worker_token)
+
+    def start(self):
+        report_results(self.scan_id, self.worker_token, {'status': 'running', 'progress': 0})
+
+    def progress(self, progress: int, current_step: str = None):
+        report = {'progress': progress}
+        if current_step:
+            report['current_step'] = current_step
+        report_results(self.scan_id, self.worker_token, report)
+
+    def result(self, check_type: str, status: str, data: dict, findings: int, severity: str):
+        report_results(self.scan_id, self.worker_token, {
+            'results': [{
+                'check_type': check_type,
+                'status': status,
+                'data': data,
+                'findings': findings,
+                'severity': severity
+            }]
+        })
+
+    def complete(self):
+        report_results(self.scan_id, self.worker_token, {'status': 'completed', 'progress': 100})
+
+    def fail(self, reason: str, failure_code: str = 'worker_error'):
+        report_results(self.scan_id, self.worker_token, {
+            'status': 'failed',
+            'failure_code': failure_code,
+            'failure_reason': reason[:1000]
+        })
+
+
+def get_reporter(scan_id: str, worker_token: str = None):
+    """Reporter for a scan: the API when the task carries a worker token, else the database"""
+    if worker_token:
+        return ApiReporter(scan_id, worker_token)
+    return DatabaseReporter(scan_id)
+
+
 class ScanTask(Task):
     """Base task class with common functionality"""
 
     def on_failure(self, exc, task_id, args, kwargs, einfo):
         """Handle task failure"""
-        scan_id = kwargs.get('scan_id')
+        scan_id = args[0] if args else None
         if scan_id:
-            update_scan_status(scan_id, 'failed')
             logger.error(f"Scan {scan_id} failed: {exc}")
+            try:
+                get_reporter(scan_id, kwargs.get('worker_token')).fail(str(exc))
+            except Exception as e:
+                logger.error(f"Failed to mark scan {scan_id} failed: {e}")
 
     def on_success(self, retval, task_id, args, kwargs):
         """Handle task success"""
-        scan_id = kwargs.get('scan_id')
-        if scan_id:
+        scan_id = args[0] if args else None
+        if scan_id and (retval or {}).get('status') == 'completed':
             logger.info(f"Scan {scan_id} completed successfully")
 
 
 @app.task(base=ScanTask, bind=True, name='tasks.execute_scan')
-def execute_scan(self, scan_id: str, target: str, checks: list, config: dict):
+def execute_scan(self, scan_id: str, target: str, checks: list, config: dict, worker_token: str = None):
     """
     Execute a complete security scan
 
@@ -45,83 +139,118 @@ def execute_scan(self, scan_id: str, target: str, checks: list, config: dict):
         target: Target hostname or IP
         checks: List of checks to run
         config: Scan configuration
+        worker_token: Token for reporting to the API's internal endpoints;
+            without it results are written to the database directly
     """
     logger.info(f"Starting scan {scan_id} for target {target}")
+    reporter = get_reporter(scan_id, worker_token)
 
+    # A scan still queued may have been edited since this task was sent, so
+    # its current checks and config win. A resumed scan is already running
+    # and only re-runs the checks in the task.
+    scan = reporter.load()
+    if scan is None or scan['status'] in FINISHED_STATUSES:
+        logger.info(f"Skipping scan {scan_id}: deleted or already finished")
+        return {'scan_id': scan_id, 'status': 'skipped'}
+    if scan['status'] == 'queued':
+        checks = scan['checks'] or []
+        config = scan['config'] or {}
+
+    # The API checked the target when the scan was created; check again now
+    # that it is about to be connected to, as its DNS records may have changed
     try:
-        # Update status to running
-        update_scan_status(scan_id, 'running')
-        update_scan_progress(scan_id, 0)
-
-        total_checks = len(checks)
-        completed_checks = 0
-
-        # Execute each check
-        check_functions = {
-            'ping': ping_check,
-            'portscan': port_scan_check,
-            'headers': headers_check,
-            'ssl': ssl_check,
-            'dns': dns_check,
-            'bruteforce': bruteforce_check,
-        }
-
-        for check_name in checks:
-            if check_name not in check_functions:
-                logger.warning(f"Unknown check: {check_name}")
-                continue
-
-            logger.info(f"Running {check_name} check for {target}")
+        check_target(target)
+    except BlockedTargetError as e:
+        logger.warning(f"Scan {scan_id} blocked: {e}")
+        reporter.fail(str(e), 'invalid_target')
+        return {'scan_id': scan_id, 'status': 'failed'}
 
-            try:
-                # Execute the check
-                result = check_functions[check_name](target, config)
+    with ExitStack() as stack:
+        # The mTLS pair is fetched with the worker token rather than sent in
+        # the task, and only lives in private temp files while the scan runs
+        if config.get('client_certificate_id'):
+            if not worker_token:
+                reporter.fail('Client certificates require a worker token')
+                return {'scan_id': scan_id, 'status': 'failed'}
+            material = get_client_certificate(scan_id, worker_token)
+            cert_file, key_file = stack.enter_context(client_certificate_files(material))
+            config = {**config, 'client_cert_file': cert_file, 'client_key_file': key_file}
 
-                # Store result in database
-                store_scan_result(
-                    scan_id=scan_id,
-                    check_type=check_name,
-                    status='success',
-                    data=result.get('data', {}),
-                    findings=result.get('findings', 0),
-                    severity=result.get('severity', 'info')
-                )
+        # An uploaded wordlist lives in the API's storage; the bruteforce
+        # check reads a downloaded copy through custom_wordlist
+        if config.get('wordlist_id') and 'bruteforce' in checks:
+            if not worker_token:
+                reporter.fail('Uploaded wordlists require a worker token')
+                return {'scan_id': scan_id, 'status': 'failed'}
+            handle, wordlist = tempfile.mkstemp(prefix='wordlist_', suffix='.txt')
+            os.close(handle)
+            stack.callback(os.remove, wordlist)
+            download_wordlist(scan_id, worker_token, wordlist)
+            config = {**config, 'custom_wordlist': wordlist}
 
-                logger.info(f"{check_name} check completed for {target}")
+        return _run_checks(reporter, scan_id, target, checks, config)
 
-            except Exception as e:
-                logger.error(f"{check_name} check failed for {target}: {e}")
-                store_scan_result(
-                    scan_id=scan_id,
-                    check_type=check_name,
-                    status='failed',
-                    data={'error': str(e)},
-                    findings=0,
-                    severity='info'
-                )
-
-            # Update progress
-            completed_checks += 1
-            progress = int((completed_checks / total_checks) * 100)
-            update_scan_progress(scan_id, progress)
-
-        # Mark scan as completed
-        update_scan_status(scan_id, 'completed', datetime.utcnow())
-        update_scan_progress(scan_id, 100)
-
-        logger.info(f"Scan {scan_id} completed successfully")
-
-        return {
-            'scan_id': scan_id,
-            'status': 'completed',
-            'checks_completed': completed_checks,
-            'checks_total': total_checks
-        }
-
-    except Exception as e:
-        logger.error(f"Scan {scan_id} failed: {e}")
-        update_scan_status(scan_id, 'failed')
-        raise
+
+def _run_checks(reporter, scan_id: str, target: str, checks: list, config: dict) -> dict:
+    """Run the checks of a scan and report each result"""
+    # Update status to running
+    reporter.start()
+
+    total_checks = len(checks)
+    completed_checks = 0
+
+    # Execute each check
+    check_functions = {
+        'ping': ping_check,
+        'portscan': port_scan_check,
+        'headers': headers_check,
+        'ssl': ssl_check,
+        'dns': dns_check,
+        'bruteforce': bruteforce_check,
+    }
+
+    for check_name in checks:
+        if check_name not in check_functions:
+            logger.warning(f"Unknown check: {check_name}")
+            continue
+
+        logger.info(f"Running {check_name} check for {target}")
+        reporter.progress(int((completed_checks / total_checks) * 100), check_name)
+
+        try:
+            # Execute the check
+            result = check_functions[check_name](target, config)
+
+            # Store result
+            reporter.result(
+                check_name,
+                'success',
+                result.get('data', {}),
+                result.get('findings', 0),
+                result.get('severity', 'info')
+            )
+
+            logger.info(f"{check_name} check completed for {target}")
+
+        except Exception as e:
+            logger.error(f"{check_name} check failed for {target}: {e}")
+            reporter.result(check_name, 'failed', {'error': str(e)}, 0, 'info')
+
+        # Update progress
+        completed_checks += 1
+        reporter.progress(int((completed_checks / total_checks) * 100))
+
+    # Mark scan as completed; failures are recorded by ScanTask.on_failure
+    reporter.complete()
+
+    logger.info(f"Scan {scan_id} completed successfully")
+
+    return {
+        'scan_id': scan_id,
+        'status': 'completed',
+        'checks_completed': completed_checks,
+        'checks_total': total_checks
+    }
 
 
 @app.task(name='tasks.test_connection')
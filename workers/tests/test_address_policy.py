@@ -0,0 +1,52 @@
+"""Tests for the worker's address policy"""
+import pytest
+
+import address_policy
+from address_policy import BlockedTargetError, check_target
+
+
+@pytest.fixture
+def records(monkeypatch):
+    """Resolve names from a table instead of DNS"""
+    table = {}
+
+    def resolve(host):
+        if host not in table:
+            raise address_policy.socket.gaierror(host)
+        return table[host]
+
+    monkeypatch.setattr(address_policy, 'resolve', resolve)
+    monkeypatch.setattr(address_policy, 'ALLOW_PRIVATE_TARGETS', False)
+    return table
+
+
+@pytest.mark.parametrize('target', [
+    'redis', 'postgres:5432', 'http://api:8080/health', 'db.internal', 'printer.local', 'localhost',
+])
+def test_internal_names_are_blocked(records, target):
+    with pytest.raises(BlockedTargetError):
+        check_target(target)
+
+
+@pytest.mark.parametrize('addresses', [
+    ['10.0.0.5'], ['93.184.216.34', '127.0.0.1'], ['169.254.169.254'], ['100.64.0.1'], ['::1'],
+])
+def test_names_resolving_to_internal_addresses_are_blocked(records, addresses):
+    records['example.com'] = addresses
+    with pytest.raises(BlockedTargetError):
+        check_target('https://example.com/')
+
+
+def test_public_name_is_allowed(records):
+    records['example.com'] = ['93.184.216.34']
+    assert check_target('https://example.com:8443/path') == ['93.184.216.34']
+
+
+def test_unresolvable_name_is_blocked(records):
+    with pytest.raises(BlockedTargetError):
+        check_target('missing.example.com')
+
+
+def test_allow_private_targets(records, monkeypatch):
+    monkeypatch.setattr(address_policy, 'ALLOW_PRIVATE_TARGETS', True)
+    check_target('redis:6379')
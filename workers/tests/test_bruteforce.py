@@ -0,0 +1,27 @@
+"""Tests for the bruteforce check's wordlist handling"""
+import subprocess
+
+from checks import bruteforce
+from checks.bruteforce import bruteforce_check
+
+
+def test_uploaded_wordlist_is_fed_to_gobuster(tmp_path, monkeypatch):
+    wordlist = tmp_path / 'uploaded.txt'
+    wordlist.write_text('admin\nsecret/keys\nbackup\n')
+    seen = {}
+
+    def run(command, **kwargs):
+        with open(command[command.index('-w') + 1]) as f:
+            seen['entries'] = f.read().split()
+        return subprocess.CompletedProcess(command, 0, stdout='/admin (Status: 200)\n', stderr='')
+
+    monkeypatch.setattr(bruteforce.subprocess, 'run', run)
+    result = bruteforce_check('https://example.com', {
+        'wordlist_id': 'a5f1c3e2-0000-4000-8000-000000000001',
+        'custom_wordlist': str(wordlist),
+        'exclude_paths': ['/secret'],
+    })
+
+    assert seen['entries'] == ['admin', 'backup']
+    assert result['data']['wordlist_used'] == 'a5f1c3e2-0000-4000-8000-000000000001'
+    assert result['findings'] == 1